@@ -0,0 +1,264 @@
+package api
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/db"
+)
+
+// addrDescBalanceAt is the balance of one address descriptor after the block at given height
+type addrDescBalanceAt struct {
+	txs      int
+	received big.Int
+	sent     big.Int
+	utxos    []Utxo
+}
+
+// balanceAtOutput is an output of the address confirmed at or before the height of the balance
+type balanceAtOutput struct {
+	txid   string
+	vout   int32
+	height uint32
+	value  big.Int
+	spent  bool
+}
+
+// balanceAtHeight returns the height of the last block with time less or equal to the timestamp
+// or the height itself if timestamp is not set, the height must not be above the best block
+func (w *Worker) balanceAtHeight(height uint32, timestamp int64) (uint32, error) {
+	bestHeight, _, err := w.db.GetBestBlock()
+	if err != nil {
+		return 0, err
+	}
+	if timestamp != 0 {
+		if timestamp < 0 || timestamp >= int64(maxUint32) {
+			return 0, NewAPIError("Invalid timestamp", true)
+		}
+		h := w.is.GetBlockHeightOfTime(uint32(timestamp) + 1)
+		if h == maxUint32 {
+			return bestHeight, nil
+		}
+		if h == 0 {
+			return 0, NewAPIError(fmt.Sprintf("No block before timestamp %d", timestamp), true)
+		}
+		return h - 1, nil
+	}
+	if height > bestHeight {
+		return 0, NewAPIError(fmt.Sprintf("Height %d is above the best block %d", height, bestHeight), true)
+	}
+	return height, nil
+}
+
+// getAddrDescBalanceAt computes the balance and the unspent outputs of the address descriptor after the block at given height
+// The balance is computed from the txAddresses of the transactions of the address up to the height.
+// The outputs, which are spent now, were either spent at or before the height or later. If the sum of the spent outputs
+// does not match the amount sent up to the height, the outputs spent later are found from the inputs of the transactions
+// on the side of the height with fewer transactions.
+func (w *Worker) getAddrDescBalanceAt(addrDesc bchain.AddressDescriptor, height uint32) (*addrDescBalanceAt, error) {
+	r := &addrDescBalanceAt{}
+	var outputs []balanceAtOutput
+	var spentSum big.Int
+	// txids of the transactions spending the outputs of the address, before (or at) and after the height
+	var spendingBefore, spendingAfter []string
+	err := w.db.GetAddrDescTransactions(addrDesc, 0, maxUint32, func(txid string, h uint32, indexes []int32) error {
+		var ta *db.TxAddresses
+		input := false
+		for _, index := range indexes {
+			if index < 0 {
+				input = true
+			}
+			if h > height {
+				continue
+			}
+			if ta == nil {
+				var err error
+				ta, err = w.db.GetTxAddresses(txid)
+				if err != nil {
+					return err
+				}
+				if ta == nil {
+					glog.Warning("DB inconsistency:  tx ", txid, ": not found in txAddresses")
+					return nil
+				}
+			}
+			if index >= 0 {
+				if int(index) < len(ta.Outputs) {
+					tao := &ta.Outputs[index]
+					r.received.Add(&r.received, &tao.ValueSat)
+					outputs = append(outputs, balanceAtOutput{txid: txid, vout: index, height: h, value: tao.ValueSat, spent: tao.Spent})
+					if tao.Spent {
+						spentSum.Add(&spentSum, &tao.ValueSat)
+					}
+				}
+			} else if int(^index) < len(ta.Inputs) {
+				r.sent.Add(&r.sent, &ta.Inputs[^index].ValueSat)
+			}
+		}
+		if h <= height {
+			r.txs++
+			if input {
+				spendingBefore = append(spendingBefore, txid)
+			}
+		} else if input {
+			spendingAfter = append(spendingAfter, txid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// outputs spent at or before the height sum up to the sent amount, the rest of the spent outputs was unspent at the height
+	if spentSum.Cmp(&r.sent) != 0 {
+		spendingTxs, after := spendingBefore, false
+		if len(spendingAfter) < len(spendingBefore) {
+			spendingTxs, after = spendingAfter, true
+		}
+		spentBy := make(map[string]struct{})
+		for _, txid := range spendingTxs {
+			tx, _, err := w.txCache.GetTransaction(txid)
+			if err != nil {
+				return nil, err
+			}
+			for i := range tx.Vin {
+				spentBy[fmt.Sprint(tx.Vin[i].Txid, ":", tx.Vin[i].Vout)] = struct{}{}
+			}
+		}
+		for i := range outputs {
+			o := &outputs[i]
+			if o.spent {
+				_, found := spentBy[fmt.Sprint(o.txid, ":", o.vout)]
+				// spent after the height means unspent at the height
+				o.spent = found != after
+			}
+		}
+	}
+	var checksum big.Int
+	checksum.Sub(&r.received, &r.sent)
+	// iterate backwards to get the newest first
+	for i := len(outputs) - 1; i >= 0; i-- {
+		o := &outputs[i]
+		if !o.spent {
+			checksum.Sub(&checksum, &o.value)
+			r.utxos = append(r.utxos, Utxo{
+				Txid:          o.txid,
+				Vout:          o.vout,
+				AmountSat:     (*Amount)(&o.value),
+				Height:        int(o.height),
+				Confirmations: int(height-o.height) + 1,
+			})
+		}
+	}
+	if checksum.Sign() != 0 {
+		glog.Warning("DB inconsistency:  ", addrDesc, ": balance at height ", height, " does not match utxos, checksum=", checksum.String())
+	}
+	return r, nil
+}
+
+func (w *Worker) newBalanceAt(descriptor string, height uint32) (*BalanceAt, error) {
+	bi, err := w.db.GetBlockInfo(height)
+	if err != nil {
+		return nil, err
+	}
+	if bi == nil {
+		return nil, NewAPIError(fmt.Sprintf("Block %d not found", height), true)
+	}
+	return &BalanceAt{
+		Address:     descriptor,
+		Height:      height,
+		BlockHash:   bi.Hash,
+		BlockTime:   bi.Time,
+		ReceivedSat: &Amount{},
+		SentSat:     &Amount{},
+		BalanceSat:  &Amount{},
+		Utxos:       Utxos{},
+	}, nil
+}
+
+func (b *BalanceAt) add(ab *addrDescBalanceAt) {
+	b.Txs += ab.txs
+	(*big.Int)(b.ReceivedSat).Add((*big.Int)(b.ReceivedSat), &ab.received)
+	(*big.Int)(b.SentSat).Add((*big.Int)(b.SentSat), &ab.sent)
+	(*big.Int)(b.BalanceSat).Sub((*big.Int)(b.ReceivedSat), (*big.Int)(b.SentSat))
+	b.Utxos = append(b.Utxos, ab.utxos...)
+}
+
+// GetBalanceAt returns the confirmed balance and the unspent outputs of the address after the block at given height
+// or, if timestamp is set, after the last block with time less or equal to the timestamp
+func (w *Worker) GetBalanceAt(address string, height uint32, timestamp int64) (*BalanceAt, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	start := time.Now()
+	addrDesc, address, err := w.getAddrDescAndNormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	height, err = w.balanceAtHeight(height, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	r, err := w.newBalanceAt(address, height)
+	if err != nil {
+		return nil, err
+	}
+	ab, err := w.getAddrDescBalanceAt(addrDesc, height)
+	if err != nil {
+		return nil, err
+	}
+	r.add(ab)
+	glog.Info("GetBalanceAt ", address, ", height ", height, ", ", len(r.Utxos), " utxos, ", time.Since(start))
+	return r, nil
+}
+
+// GetXpubBalanceAt returns the confirmed balance and the unspent outputs of the xpub after the block at given height
+// or, if timestamp is set, after the last block with time less or equal to the timestamp
+func (w *Worker) GetXpubBalanceAt(xpub string, height uint32, timestamp int64, gap int) (*BalanceAt, error) {
+	start := time.Now()
+	xd, err := w.chainParser.ParseXpub(xpub)
+	if err != nil {
+		return nil, err
+	}
+	height, err = w.balanceAtHeight(height, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	data, _, inCache, err := w.getXpubData(xd, 0, 1, AccountDetailsBasic, &AddressFilter{
+		Vout:          AddressFilterVoutOff,
+		OnlyConfirmed: true,
+	}, gap)
+	if err != nil {
+		return nil, err
+	}
+	r, err := w.newBalanceAt(xpub, height)
+	if err != nil {
+		return nil, err
+	}
+	for ci, da := range data.addresses {
+		for i := range da {
+			ad := &da[i]
+			if ad.balance == nil {
+				continue
+			}
+			ab, err := w.getAddrDescBalanceAt(ad.addrDesc, height)
+			if err != nil {
+				return nil, err
+			}
+			if len(ab.utxos) > 0 {
+				t := w.tokenFromXpubAddress(data, ad, ci, i, AccountDetailsTokens)
+				for j := range ab.utxos {
+					ab.utxos[j].Address = t.Name
+					ab.utxos[j].Path = t.Path
+				}
+			}
+			r.add(ab)
+		}
+	}
+	sort.Stable(r.Utxos)
+	glog.Info("GetXpubBalanceAt ", xpub[:xpubLogPrefix], ", cache ", inCache, ", height ", height, ", ", len(r.Utxos), " utxos, ", time.Since(start))
+	return r, nil
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/db"
+)
+
+// maxBlockFilterHeaders is the maximum number of filter headers returned by GetBlockFilterHeaders, the same as in BIP157
+const maxBlockFilterHeaders = 2000
+
+// reversedHex returns the hash in the hex format in the same (reversed) byte order as block hashes
+func reversedHex(b []byte) string {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return hex.EncodeToString(r)
+}
+
+func blockFilterHeaderFromDB(height uint32, hash string, f *db.BlockFilter) BlockFilterHeader {
+	h := BlockFilterHeader{
+		Height:     height,
+		Hash:       hash,
+		FilterHash: reversedHex(f.FilterHash()),
+	}
+	if f.Header != nil {
+		h.Header = reversedHex(f.Header)
+	}
+	return h
+}
+
+// GetBlockFilter returns the BIP158 basic filter of the block given by height or hash
+func (w *Worker) GetBlockFilter(bid string) (*BlockFilter, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	if !w.db.IsBlockFiltersEnabled() {
+		return nil, NewAPIError("Block filters are not enabled", true)
+	}
+	bi, err := w.getBlockInfoFromBlockID(bid)
+	if err != nil {
+		if err == bchain.ErrBlockNotFound {
+			return nil, NewAPIError("Block not found", true)
+		}
+		return nil, NewAPIError(fmt.Sprintf("Block not found, %v", err), true)
+	}
+	// the stored filter must belong to the requested block, not to a block replaced by a reorg
+	if hash, err := w.db.GetBlockHash(bi.Height); err != nil || hash != bi.Hash {
+		return nil, NewAPIError("Block filter not found", true)
+	}
+	f, err := w.db.GetBlockFilter(bi.Height)
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetBlockFilter")
+	}
+	if f == nil {
+		return nil, NewAPIError("Block filter not found", true)
+	}
+	return &BlockFilter{
+		BlockFilterHeader: blockFilterHeaderFromDB(bi.Height, bi.Hash, f),
+		FilterType:        "basic",
+		Filter:            hex.EncodeToString(f.Filter),
+	}, nil
+}
+
+// GetBlockFilterHeaders returns the filter hashes and the filter headers of the blocks in the height range from-to (inclusive)
+// and the filter header of the block preceding the range, blocks without stored filters are omitted
+func (w *Worker) GetBlockFilterHeaders(from, to int) (*BlockFilterHeaders, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	if !w.db.IsBlockFiltersEnabled() {
+		return nil, NewAPIError("Block filters are not enabled", true)
+	}
+	if from < 0 || to < from {
+		return nil, NewAPIError("Invalid block range", true)
+	}
+	if to-from >= maxBlockFilterHeaders {
+		return nil, NewAPIError(fmt.Sprintf("Block range too large, maximum is %d blocks", maxBlockFilterHeaders), true)
+	}
+	r := &BlockFilterHeaders{
+		From:    from,
+		To:      to,
+		Headers: make([]BlockFilterHeader, 0),
+	}
+	if from == 0 {
+		r.PrevHeader = hex.EncodeToString(make([]byte, db.BlockFilterHeaderLen))
+	} else {
+		f, err := w.db.GetBlockFilter(uint32(from - 1))
+		if err != nil {
+			return nil, errors.Annotatef(err, "GetBlockFilter")
+		}
+		if f != nil && f.Header != nil {
+			r.PrevHeader = reversedHex(f.Header)
+		}
+	}
+	err := w.db.GetBlockFilterRange(uint32(from), uint32(to), func(height uint32, f *db.BlockFilter) error {
+		hash, err := w.db.GetBlockHash(height)
+		if err != nil {
+			return err
+		}
+		r.Headers = append(r.Headers, blockFilterHeaderFromDB(height, hash, f))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetBlockFilterRange")
+	}
+	return r, nil
+}
@@ -0,0 +1,824 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcd/wire"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/db"
+)
+
+const (
+	// EsploraChainTxs is the number of confirmed transactions of an address returned in one request
+	EsploraChainTxs = 25
+	// EsploraMempoolTxs is the maximum number of mempool transactions of an address returned in one request
+	EsploraMempoolTxs = 50
+	// EsploraBlockTxs is the number of transactions of a block returned in one request
+	EsploraBlockTxs = 25
+)
+
+// esploraFeeTargets are the confirmation targets of the fee estimates, the same as used by Esplora
+var esploraFeeTargets = []int{
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 144, 504, 1008,
+}
+
+const esploraCoinbaseTxid = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// EsploraNotFoundError is returned by the Esplora methods if the requested object does not exist
+type EsploraNotFoundError struct {
+	Text string
+}
+
+func (e *EsploraNotFoundError) Error() string {
+	return e.Text
+}
+
+// EsploraTxStatus is the confirmation status of a transaction in the Esplora format
+type EsploraTxStatus struct {
+	Confirmed   bool    `json:"confirmed"`
+	BlockHeight *uint32 `json:"block_height,omitempty"`
+	BlockHash   string  `json:"block_hash,omitempty"`
+	BlockTime   int64   `json:"block_time,omitempty"`
+}
+
+// EsploraTxOut is a transaction output in the Esplora format
+type EsploraTxOut struct {
+	ScriptPubKey        string `json:"scriptpubkey"`
+	ScriptPubKeyAsm     string `json:"scriptpubkey_asm"`
+	ScriptPubKeyType    string `json:"scriptpubkey_type"`
+	ScriptPubKeyAddress string `json:"scriptpubkey_address,omitempty"`
+	Value               int64  `json:"value"`
+}
+
+// EsploraTxIn is a transaction input in the Esplora format
+type EsploraTxIn struct {
+	Txid                  string        `json:"txid"`
+	Vout                  uint32        `json:"vout"`
+	Prevout               *EsploraTxOut `json:"prevout"`
+	ScriptSig             string        `json:"scriptsig"`
+	ScriptSigAsm          string        `json:"scriptsig_asm"`
+	Witness               []string      `json:"witness,omitempty"`
+	IsCoinbase            bool          `json:"is_coinbase"`
+	Sequence              uint32        `json:"sequence"`
+	InnerRedeemScriptAsm  string        `json:"inner_redeemscript_asm,omitempty"`
+	InnerWitnessScriptAsm string        `json:"inner_witnessscript_asm,omitempty"`
+}
+
+// EsploraTx is a transaction in the Esplora format
+type EsploraTx struct {
+	Txid     string          `json:"txid"`
+	Version  int32           `json:"version"`
+	Locktime uint32          `json:"locktime"`
+	Vin      []EsploraTxIn   `json:"vin"`
+	Vout     []EsploraTxOut  `json:"vout"`
+	Size     int             `json:"size"`
+	Weight   int64           `json:"weight"`
+	Fee      int64           `json:"fee"`
+	Status   EsploraTxStatus `json:"status"`
+}
+
+// EsploraOutspend is the spending status of a transaction output in the Esplora format
+type EsploraOutspend struct {
+	Spent  bool             `json:"spent"`
+	Txid   string           `json:"txid,omitempty"`
+	Vin    *int             `json:"vin,omitempty"`
+	Status *EsploraTxStatus `json:"status,omitempty"`
+}
+
+// EsploraUtxo is an unspent output of an address in the Esplora format
+type EsploraUtxo struct {
+	Txid   string          `json:"txid"`
+	Vout   int32           `json:"vout"`
+	Status EsploraTxStatus `json:"status"`
+	Value  int64           `json:"value"`
+}
+
+// EsploraAddressStats contains the counts and sums of the funded and spent outputs of an address
+type EsploraAddressStats struct {
+	FundedTxoCount int   `json:"funded_txo_count"`
+	FundedTxoSum   int64 `json:"funded_txo_sum"`
+	SpentTxoCount  int   `json:"spent_txo_count"`
+	SpentTxoSum    int64 `json:"spent_txo_sum"`
+	TxCount        int   `json:"tx_count"`
+}
+
+// EsploraAddress contains the statistics of an address or of a script hash in the Esplora format
+type EsploraAddress struct {
+	Address      string              `json:"address,omitempty"`
+	ScriptHash   string              `json:"scripthash,omitempty"`
+	ChainStats   EsploraAddressStats `json:"chain_stats"`
+	MempoolStats EsploraAddressStats `json:"mempool_stats"`
+}
+
+// EsploraBlock is a block in the Esplora format
+type EsploraBlock struct {
+	ID                string  `json:"id"`
+	Height            uint32  `json:"height"`
+	Version           int64   `json:"version"`
+	Timestamp         int64   `json:"timestamp"`
+	TxCount           int     `json:"tx_count"`
+	Size              int     `json:"size"`
+	MerkleRoot        string  `json:"merkle_root"`
+	PreviousBlockHash *string `json:"previousblockhash"`
+	MedianTime        int64   `json:"mediantime"`
+	Nonce             uint32  `json:"nonce"`
+	Bits              uint32  `json:"bits"`
+	Difficulty        float64 `json:"difficulty"`
+}
+
+// EsploraBlockStatus is the status of a block in the Esplora format
+type EsploraBlockStatus struct {
+	InBestChain bool    `json:"in_best_chain"`
+	Height      *uint32 `json:"height,omitempty"`
+	NextBest    *string `json:"next_best"`
+}
+
+// EsploraMempool contains the statistics of the mempool in the Esplora format
+type EsploraMempool struct {
+	Count        int        `json:"count"`
+	VSize        int64      `json:"vsize"`
+	TotalFee     int64      `json:"total_fee"`
+	FeeHistogram [][2]int64 `json:"fee_histogram"`
+}
+
+func (w *Worker) checkEsploraSupported() error {
+	if w.chainType != bchain.ChainBitcoinType {
+		return NewAPIError("Not supported", true)
+	}
+	return nil
+}
+
+// esploraBlockStatus returns the confirmation status of a transaction in the block at given height, the status is cached in the map
+func (w *Worker) esploraBlockStatus(height uint32, cache map[uint32]*EsploraTxStatus) (*EsploraTxStatus, error) {
+	if s, found := cache[height]; found {
+		return s, nil
+	}
+	bi, err := w.db.GetBlockInfo(height)
+	if err != nil {
+		return nil, err
+	}
+	if bi == nil {
+		return nil, errors.Errorf("Block %d not found", height)
+	}
+	h := height
+	s := &EsploraTxStatus{Confirmed: true, BlockHeight: &h, BlockHash: bi.Hash, BlockTime: bi.Time}
+	if cache != nil {
+		cache[height] = s
+	}
+	return s, nil
+}
+
+func esploraTxStatus(tx *Tx) EsploraTxStatus {
+	if tx.Confirmations == 0 {
+		return EsploraTxStatus{}
+	}
+	h := uint32(tx.Blockheight)
+	return EsploraTxStatus{Confirmed: true, BlockHeight: &h, BlockHash: tx.Blockhash, BlockTime: tx.Blocktime}
+}
+
+// esploraTxOut converts the output script and the value to the Esplora output, the address is shown only for the standard scripts
+func (w *Worker) esploraTxOut(script []byte, value *Amount) *EsploraTxOut {
+	o := &EsploraTxOut{
+		ScriptPubKey:     hex.EncodeToString(script),
+		ScriptPubKeyAsm:  esploraScriptAsm(script),
+		ScriptPubKeyType: esploraScriptType(script),
+		Value:            value.AsInt64(),
+	}
+	switch o.ScriptPubKeyType {
+	case esploraScriptP2PKH, esploraScriptP2SH, esploraScriptP2WPKH, esploraScriptP2WSH, esploraScriptP2TR:
+		addresses, searchable, err := w.chainParser.GetAddressesFromAddrDesc(script)
+		if err == nil && searchable && len(addresses) == 1 {
+			o.ScriptPubKeyAddress = addresses[0]
+		}
+	}
+	return o
+}
+
+// esploraTxHex returns the raw transaction in hex, from the transaction itself or from the coin specific data returned by the backend
+func esploraTxHex(tx *Tx) string {
+	if tx.Hex != "" || len(tx.CoinSpecificData) == 0 {
+		return tx.Hex
+	}
+	var specific struct {
+		Hex string `json:"hex"`
+	}
+	if err := json.Unmarshal(tx.CoinSpecificData, &specific); err != nil {
+		return ""
+	}
+	return specific.Hex
+}
+
+// esploraRawTx returns the parsed raw transaction, nil if it is not available or does not match the transaction
+func esploraRawTx(tx *Tx) *wire.MsgTx {
+	h := esploraTxHex(tx)
+	if h == "" {
+		return nil
+	}
+	raw, err := hex.DecodeString(h)
+	if err != nil {
+		return nil
+	}
+	var mtx wire.MsgTx
+	if err = mtx.Deserialize(bytes.NewReader(raw)); err != nil {
+		glog.V(1).Info("Esplora: cannot parse transaction ", tx.Txid, ": ", err)
+		return nil
+	}
+	if len(mtx.TxIn) != len(tx.Vin) || len(mtx.TxOut) != len(tx.Vout) {
+		return nil
+	}
+	return &mtx
+}
+
+// txToEsplora converts the transaction to the Esplora format, the witness and the exact size are available only with the raw transaction
+func (w *Worker) txToEsplora(tx *Tx) *EsploraTx {
+	mtx := esploraRawTx(tx)
+	et := &EsploraTx{
+		Txid:     tx.Txid,
+		Version:  tx.Version,
+		Locktime: tx.Locktime,
+		Vin:      make([]EsploraTxIn, len(tx.Vin)),
+		Vout:     make([]EsploraTxOut, len(tx.Vout)),
+		Size:     tx.Size,
+		Weight:   tx.Weight,
+		Fee:      tx.FeesSat.AsInt64(),
+		Status:   esploraTxStatus(tx),
+	}
+	if mtx != nil {
+		et.Size = mtx.SerializeSize()
+		et.Weight = int64(mtx.SerializeSizeStripped()*3 + et.Size)
+	} else if et.Weight == 0 {
+		et.Weight = tx.VSize * 4
+	}
+	for i := range tx.Vin {
+		vin := &tx.Vin[i]
+		in := &et.Vin[i]
+		in.Sequence = uint32(vin.Sequence)
+		if vin.Txid == "" {
+			in.Txid = esploraCoinbaseTxid
+			in.Vout = maxUint32
+			in.IsCoinbase = true
+			in.ScriptSig = vin.Coinbase
+		} else {
+			in.Txid = vin.Txid
+			in.Vout = vin.Vout
+			in.ScriptSig = vin.Hex
+			in.Prevout = w.esploraTxOut(vin.AddrDesc, vin.ValueSat)
+		}
+		if mtx != nil {
+			ti := mtx.TxIn[i]
+			if in.ScriptSig == "" {
+				in.ScriptSig = hex.EncodeToString(ti.SignatureScript)
+			}
+			for _, item := range ti.Witness {
+				in.Witness = append(in.Witness, hex.EncodeToString(item))
+			}
+		}
+		scriptSig, _ := hex.DecodeString(in.ScriptSig)
+		in.ScriptSigAsm = esploraScriptAsm(scriptSig)
+		if in.Prevout != nil {
+			prevoutType := in.Prevout.ScriptPubKeyType
+			if prevoutType == esploraScriptP2SH {
+				if redeemScript := esploraLastPush(scriptSig); redeemScript != nil {
+					in.InnerRedeemScriptAsm = esploraScriptAsm(redeemScript)
+					prevoutType = esploraScriptType(redeemScript)
+				}
+			}
+			if prevoutType == esploraScriptP2WSH && mtx != nil {
+				if witness := mtx.TxIn[i].Witness; len(witness) > 0 {
+					in.InnerWitnessScriptAsm = esploraScriptAsm(witness[len(witness)-1])
+				}
+			}
+		}
+	}
+	for i := range tx.Vout {
+		vout := &tx.Vout[i]
+		script, err := hex.DecodeString(vout.Hex)
+		if err != nil || len(script) == 0 {
+			script = vout.AddrDesc
+		}
+		et.Vout[i] = *w.esploraTxOut(script, vout.ValueSat)
+	}
+	return et
+}
+
+func (w *Worker) getEsploraTransaction(txid string, spendingTxs bool) (*Tx, error) {
+	tx, err := w.GetTransaction(txid, spendingTxs, true)
+	if err != nil {
+		if apiErr, ok := err.(*APIError); ok && apiErr.Public {
+			return nil, &EsploraNotFoundError{"Transaction not found"}
+		}
+		return nil, err
+	}
+	return tx, nil
+}
+
+// EsploraGetTransaction returns the transaction in the Esplora format
+func (w *Worker) EsploraGetTransaction(txid string) (*EsploraTx, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	tx, err := w.getEsploraTransaction(txid, false)
+	if err != nil {
+		return nil, err
+	}
+	return w.txToEsplora(tx), nil
+}
+
+// EsploraGetTransactionStatus returns the confirmation status of the transaction
+func (w *Worker) EsploraGetTransactionStatus(txid string) (*EsploraTxStatus, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	tx, err := w.GetTransaction(txid, false, false)
+	if err != nil {
+		if apiErr, ok := err.(*APIError); ok && apiErr.Public {
+			return nil, &EsploraNotFoundError{"Transaction not found"}
+		}
+		return nil, err
+	}
+	s := esploraTxStatus(tx)
+	return &s, nil
+}
+
+// EsploraGetTransactionHex returns the raw transaction in hex
+func (w *Worker) EsploraGetTransactionHex(txid string) (string, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return "", err
+	}
+	tx, err := w.getEsploraTransaction(txid, false)
+	if err != nil {
+		return "", err
+	}
+	h := esploraTxHex(tx)
+	if h == "" {
+		return "", errors.Errorf("Transaction %s hex not available", txid)
+	}
+	return h, nil
+}
+
+// EsploraGetOutspends returns the spending status of all outputs of the transaction
+func (w *Worker) EsploraGetOutspends(txid string) ([]EsploraOutspend, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	tx, err := w.GetTransaction(txid, true, false)
+	if err != nil {
+		if apiErr, ok := err.(*APIError); ok && apiErr.Public {
+			return nil, &EsploraNotFoundError{"Transaction not found"}
+		}
+		return nil, err
+	}
+	statuses := make(map[uint32]*EsploraTxStatus)
+	r := make([]EsploraOutspend, len(tx.Vout))
+	for i := range tx.Vout {
+		vout := &tx.Vout[i]
+		if !vout.Spent {
+			continue
+		}
+		r[i].Spent = true
+		if vout.SpentTxID == "" {
+			continue
+		}
+		r[i].Txid = vout.SpentTxID
+		vin := vout.SpentIndex
+		r[i].Vin = &vin
+		if vout.SpentHeight > 0 {
+			if r[i].Status, err = w.esploraBlockStatus(uint32(vout.SpentHeight), statuses); err != nil {
+				return nil, err
+			}
+		} else {
+			r[i].Status = &EsploraTxStatus{}
+		}
+	}
+	return r, nil
+}
+
+// EsploraGetAddrDescForAddress returns the address descriptor of the address
+func (w *Worker) EsploraGetAddrDescForAddress(address string) (bchain.AddressDescriptor, error) {
+	addrDesc, err := w.chainParser.GetAddrDescFromAddress(address)
+	if err != nil || len(addrDesc) == 0 {
+		return nil, NewAPIError("Invalid Bitcoin address", true)
+	}
+	return addrDesc, nil
+}
+
+// EsploraGetAddrDescForScriptHash returns the address descriptor of the script hash given in hex in the reversed byte order
+// as in the Electrum protocol, nil if the script hash is not known, i.e. the script has no transactions
+func (w *Worker) EsploraGetAddrDescForScriptHash(scriptHash string) (bchain.AddressDescriptor, error) {
+	b, err := hex.DecodeString(scriptHash)
+	if err != nil || len(b) != db.ScriptHashLen {
+		return nil, NewAPIError("Invalid script hash", true)
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	if !w.db.IsScriptHashIndexEnabled() {
+		return nil, NewAPIError("Script hash index is not enabled", true)
+	}
+	return w.db.GetAddrDescForScriptHash(b)
+}
+
+// getEsploraMempoolTxids returns the unique mempool transactions of the address, the newest first
+func (w *Worker) getEsploraMempoolTxids(addrDesc bchain.AddressDescriptor) ([]string, error) {
+	outpoints, err := w.mempool.GetAddrDescTransactions(addrDesc)
+	if err != nil {
+		return nil, err
+	}
+	unique := make(map[string]uint32)
+	txids := make([]string, 0, len(outpoints))
+	for _, o := range outpoints {
+		if _, found := unique[o.Txid]; !found {
+			unique[o.Txid] = w.mempool.GetTransactionTime(o.Txid)
+			txids = append(txids, o.Txid)
+		}
+	}
+	sort.Slice(txids, func(i, j int) bool {
+		ti, tj := unique[txids[i]], unique[txids[j]]
+		if ti == tj {
+			return txids[i] < txids[j]
+		}
+		return ti > tj
+	})
+	return txids, nil
+}
+
+// EsploraGetAddress returns the statistics of the funded and spent outputs of the address, in the chain and in the mempool
+func (w *Worker) EsploraGetAddress(addrDesc bchain.AddressDescriptor) (*EsploraAddress, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	r := &EsploraAddress{}
+	if addrDesc == nil {
+		return r, nil
+	}
+	ba, err := w.db.GetAddrDescBalance(addrDesc, db.AddressBalanceDetailNoUTXO)
+	if err != nil {
+		return nil, err
+	}
+	if ba != nil {
+		r.ChainStats.TxCount = int(ba.Txs)
+		r.ChainStats.FundedTxoSum = ba.ReceivedSat().Int64()
+		r.ChainStats.SpentTxoSum = ba.SentSat.Int64()
+		if err = w.db.GetAddrDescTransactions(addrDesc, 0, maxUint32, func(txid string, height uint32, indexes []int32) error {
+			for _, index := range indexes {
+				if index < 0 {
+					r.ChainStats.SpentTxoCount++
+				} else {
+					r.ChainStats.FundedTxoCount++
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	txids, err := w.getEsploraMempoolTxids(addrDesc)
+	if err != nil {
+		return nil, err
+	}
+	for _, txid := range txids {
+		tx, err := w.GetTransaction(txid, false, false)
+		if err != nil {
+			// the transaction could have been removed from the mempool in the meantime
+			glog.V(1).Info("Esplora: mempool transaction ", txid, ": ", err)
+			continue
+		}
+		r.MempoolStats.TxCount++
+		for i := range tx.Vin {
+			if bytes.Equal(tx.Vin[i].AddrDesc, addrDesc) {
+				r.MempoolStats.SpentTxoCount++
+				r.MempoolStats.SpentTxoSum += tx.Vin[i].ValueSat.AsInt64()
+			}
+		}
+		for i := range tx.Vout {
+			if bytes.Equal(tx.Vout[i].AddrDesc, addrDesc) {
+				r.MempoolStats.FundedTxoCount++
+				r.MempoolStats.FundedTxoSum += tx.Vout[i].ValueSat.AsInt64()
+			}
+		}
+	}
+	return r, nil
+}
+
+// EsploraGetAddressTxs returns the transactions of the address, the newest first. The mempool transactions are returned
+// up to the limit EsploraMempoolTxs, the confirmed transactions in the pages of EsploraChainTxs after the transaction lastSeenTxid.
+func (w *Worker) EsploraGetAddressTxs(addrDesc bchain.AddressDescriptor, mempool bool, chain bool, lastSeenTxid string) ([]*EsploraTx, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	r := make([]*EsploraTx, 0)
+	if addrDesc == nil {
+		return r, nil
+	}
+	var txids []string
+	if mempool {
+		mempoolTxids, err := w.getEsploraMempoolTxids(addrDesc)
+		if err != nil {
+			return nil, err
+		}
+		if len(mempoolTxids) > EsploraMempoolTxs {
+			mempoolTxids = mempoolTxids[:EsploraMempoolTxs]
+		}
+		txids = append(txids, mempoolTxids...)
+	}
+	if chain {
+		chainTxids := make([]string, 0, EsploraChainTxs)
+		seen := lastSeenTxid == ""
+		if err := w.db.GetAddrDescTransactions(addrDesc, 0, maxUint32, func(txid string, height uint32, indexes []int32) error {
+			if !seen {
+				seen = txid == lastSeenTxid
+				return nil
+			}
+			chainTxids = append(chainTxids, txid)
+			if len(chainTxids) >= EsploraChainTxs {
+				return &db.StopIteration{}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		txids = append(txids, chainTxids...)
+	}
+	for _, txid := range txids {
+		tx, err := w.GetTransaction(txid, false, true)
+		if err != nil {
+			glog.V(1).Info("Esplora: transaction ", txid, ": ", err)
+			continue
+		}
+		r = append(r, w.txToEsplora(tx))
+	}
+	return r, nil
+}
+
+// EsploraGetAddressUtxo returns the unspent outputs of the address, the outputs spent in the mempool are not returned
+func (w *Worker) EsploraGetAddressUtxo(addrDesc bchain.AddressDescriptor) ([]EsploraUtxo, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	r := make([]EsploraUtxo, 0)
+	if addrDesc == nil {
+		return r, nil
+	}
+	utxos, err := w.getAddrDescUtxo(addrDesc, nil, false, false, true)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[uint32]*EsploraTxStatus)
+	for i := range utxos {
+		u := &utxos[i]
+		if u.SpentTxID != "" {
+			continue
+		}
+		eu := EsploraUtxo{
+			Txid:  u.Txid,
+			Vout:  u.Vout,
+			Value: u.AmountSat.AsInt64(),
+		}
+		if u.Height > 0 {
+			s, err := w.esploraBlockStatus(uint32(u.Height), statuses)
+			if err != nil {
+				return nil, err
+			}
+			eu.Status = *s
+		}
+		r = append(r, eu)
+	}
+	return r, nil
+}
+
+func (w *Worker) getEsploraBlockInfo(hash string) (*bchain.BlockInfo, error) {
+	bi, err := w.chain.GetBlockInfo(hash)
+	if err != nil {
+		if err == bchain.ErrBlockNotFound {
+			return nil, &EsploraNotFoundError{"Block not found"}
+		}
+		return nil, err
+	}
+	return bi, nil
+}
+
+// esploraMedianTime returns the median of the times of the block at given height and of the 10 preceding blocks
+func (w *Worker) esploraMedianTime(height uint32) (int64, error) {
+	times := make([]int64, 0, 11)
+	for h := int64(height); h >= 0 && h > int64(height)-11; h-- {
+		bi, err := w.db.GetBlockInfo(uint32(h))
+		if err != nil {
+			return 0, err
+		}
+		if bi == nil {
+			break
+		}
+		times = append(times, bi.Time)
+	}
+	if len(times) == 0 {
+		return 0, nil
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	return times[len(times)/2], nil
+}
+
+// EsploraGetBlock returns the block in the Esplora format
+func (w *Worker) EsploraGetBlock(hash string) (*EsploraBlock, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	bi, err := w.getEsploraBlockInfo(hash)
+	if err != nil {
+		return nil, err
+	}
+	b := &EsploraBlock{
+		ID:         bi.Hash,
+		Height:     bi.Height,
+		Timestamp:  bi.Time,
+		TxCount:    len(bi.Txids),
+		Size:       bi.Size,
+		MerkleRoot: bi.MerkleRoot,
+	}
+	if bi.Version != "" {
+		if b.Version, err = bi.Version.Int64(); err != nil {
+			return nil, errors.Annotatef(err, "version")
+		}
+	}
+	if bi.Nonce != "" {
+		nonce, err := strconv.ParseUint(string(bi.Nonce), 10, 32)
+		if err != nil {
+			return nil, errors.Annotatef(err, "nonce")
+		}
+		b.Nonce = uint32(nonce)
+	}
+	if bi.Bits != "" {
+		bits, err := strconv.ParseUint(bi.Bits, 16, 32)
+		if err != nil {
+			return nil, errors.Annotatef(err, "bits")
+		}
+		b.Bits = uint32(bits)
+	}
+	if bi.Difficulty != "" {
+		if b.Difficulty, err = bi.Difficulty.Float64(); err != nil {
+			return nil, errors.Annotatef(err, "difficulty")
+		}
+	}
+	if bi.Prev == "" && bi.Height > 0 {
+		bi.Prev, _ = w.db.GetBlockHash(bi.Height - 1)
+	}
+	if bi.Prev != "" {
+		b.PreviousBlockHash = &bi.Prev
+	}
+	if b.MedianTime, err = w.esploraMedianTime(bi.Height); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// EsploraGetBlockStatus returns the status of the block, if it is in the best chain and the hash of the next block
+func (w *Worker) EsploraGetBlockStatus(hash string) (*EsploraBlockStatus, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	bi, err := w.chain.GetBlockInfo(hash)
+	if err != nil && err != bchain.ErrBlockNotFound {
+		return nil, err
+	}
+	r := &EsploraBlockStatus{}
+	if bi == nil {
+		return r, nil
+	}
+	bestHash, err := w.db.GetBlockHash(bi.Height)
+	if err != nil {
+		return nil, err
+	}
+	if bestHash != hash {
+		return r, nil
+	}
+	height := bi.Height
+	r.InBestChain = true
+	r.Height = &height
+	next, err := w.db.GetBlockHash(height + 1)
+	if err != nil {
+		return nil, err
+	}
+	if next != "" {
+		r.NextBest = &next
+	}
+	return r, nil
+}
+
+// EsploraGetBlockTxids returns the ids of all transactions of the block
+func (w *Worker) EsploraGetBlockTxids(hash string) ([]string, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	bi, err := w.getEsploraBlockInfo(hash)
+	if err != nil {
+		return nil, err
+	}
+	if bi.Txids == nil {
+		return []string{}, nil
+	}
+	return bi.Txids, nil
+}
+
+// EsploraGetBlockTxid returns the id of the transaction at given index in the block
+func (w *Worker) EsploraGetBlockTxid(hash string, index int) (string, error) {
+	txids, err := w.EsploraGetBlockTxids(hash)
+	if err != nil {
+		return "", err
+	}
+	if index < 0 || index >= len(txids) {
+		return "", &EsploraNotFoundError{"Transaction index out of range"}
+	}
+	return txids[index], nil
+}
+
+// EsploraGetBlockTxs returns EsploraBlockTxs transactions of the block starting at given index, the index must be a multiple of EsploraBlockTxs
+func (w *Worker) EsploraGetBlockTxs(hash string, start int) ([]*EsploraTx, error) {
+	if start < 0 || start%EsploraBlockTxs != 0 {
+		return nil, NewAPIError("start index must be a multiple of 25", true)
+	}
+	txids, err := w.EsploraGetBlockTxids(hash)
+	if err != nil {
+		return nil, err
+	}
+	if start >= len(txids) {
+		return nil, NewAPIError("start index out of range", true)
+	}
+	end := start + EsploraBlockTxs
+	if end > len(txids) {
+		end = len(txids)
+	}
+	r := make([]*EsploraTx, 0, end-start)
+	for _, txid := range txids[start:end] {
+		tx, err := w.GetTransaction(txid, false, true)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, w.txToEsplora(tx))
+	}
+	return r, nil
+}
+
+// EsploraGetFeeEstimates returns the estimated fee rates in sat/vB for the confirmation targets used by Esplora,
+// the targets which cannot be estimated are not returned
+func (w *Worker) EsploraGetFeeEstimates() (map[string]float64, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	r := make(map[string]float64, len(esploraFeeTargets))
+	for _, target := range esploraFeeTargets {
+		fee, err := w.BitcoinTypeEstimateFee(target, false)
+		if err != nil {
+			return nil, err
+		}
+		if fee.Sign() > 0 {
+			// the estimates are in sat/kB
+			r[strconv.Itoa(target)] = feePerVByte(&fee, 1000)
+		}
+	}
+	return r, nil
+}
+
+// EsploraGetMempool returns the statistics of the mempool
+func (w *Worker) EsploraGetMempool() (*EsploraMempool, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	r := &EsploraMempool{FeeHistogram: make([][2]int64, 0)}
+	if stats := w.mempool.GetStats(); stats != nil {
+		r.Count = stats.Size
+		r.VSize = stats.VSize
+		r.TotalFee = stats.FeesSat
+		r.FeeHistogram = FeeHistogram(stats)
+	}
+	return r, nil
+}
+
+// EsploraGetMempoolTxids returns the ids of all mempool transactions
+func (w *Worker) EsploraGetMempoolTxids() ([]string, error) {
+	if err := w.checkEsploraSupported(); err != nil {
+		return nil, err
+	}
+	entries := w.mempool.GetAllEntries()
+	r := make([]string, len(entries))
+	for i := range entries {
+		r[i] = entries[i].Txid
+	}
+	return r, nil
+}
+
+// EsploraGetBlockHash returns the hash of the block at given height in the best chain
+func (w *Worker) EsploraGetBlockHash(height uint32) (string, error) {
+	hash, err := w.db.GetBlockHash(height)
+	if err != nil {
+		return "", err
+	}
+	if hash == "" {
+		return "", &EsploraNotFoundError{"Block not found"}
+	}
+	return hash, nil
+}
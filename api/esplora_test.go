@@ -0,0 +1,140 @@
+//go:build unittest
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/bchain/coins/btc"
+)
+
+func Test_esploraScriptAsmAndType(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		wantAsm  string
+		wantType string
+	}{
+		{
+			name:     "empty",
+			script:   "",
+			wantAsm:  "",
+			wantType: "empty",
+		},
+		{
+			name:     "p2pkh",
+			script:   "76a914a08eae93007f22668ab5e4a9c83c8cd1c325e3e088ac",
+			wantAsm:  "OP_DUP OP_HASH160 OP_PUSHBYTES_20 a08eae93007f22668ab5e4a9c83c8cd1c325e3e0 OP_EQUALVERIFY OP_CHECKSIG",
+			wantType: "p2pkh",
+		},
+		{
+			name:     "p2sh",
+			script:   "a914e921fc4912a315078f370d959f2c4f7b6d2a683c87",
+			wantAsm:  "OP_HASH160 OP_PUSHBYTES_20 e921fc4912a315078f370d959f2c4f7b6d2a683c OP_EQUAL",
+			wantType: "p2sh",
+		},
+		{
+			name:     "p2wpkh",
+			script:   "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+			wantAsm:  "OP_0 OP_PUSHBYTES_20 751e76e8199196d454941c45d1b3a323f1433bd6",
+			wantType: "v0_p2wpkh",
+		},
+		{
+			name:     "p2wsh",
+			script:   "002069dae530beb09a05d46d0b2aee98645b15bb5d1e808a386b5ef0c48aed5531cb",
+			wantAsm:  "OP_0 OP_PUSHBYTES_32 69dae530beb09a05d46d0b2aee98645b15bb5d1e808a386b5ef0c48aed5531cb",
+			wantType: "v0_p2wsh",
+		},
+		{
+			name:     "p2tr",
+			script:   "5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+			wantAsm:  "OP_PUSHNUM_1 OP_PUSHBYTES_32 a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+			wantType: "v1_p2tr",
+		},
+		{
+			name:     "p2pk",
+			script:   "2102b1e6d8187f54d83d1ffd70508e24c5bd3603bccb2346d8c6677434169de8bc26ac",
+			wantAsm:  "OP_PUSHBYTES_33 02b1e6d8187f54d83d1ffd70508e24c5bd3603bccb2346d8c6677434169de8bc26 OP_CHECKSIG",
+			wantType: "p2pk",
+		},
+		{
+			name:     "op_return pushdata1",
+			script:   "6a4c0401020304",
+			wantAsm:  "OP_RETURN OP_PUSHDATA1 01020304",
+			wantType: "op_return",
+		},
+		{
+			name:     "timelock",
+			script:   "03a08601b17551b2",
+			wantAsm:  "OP_PUSHBYTES_3 a08601 OP_CLTV OP_DROP OP_PUSHNUM_1 OP_CSV",
+			wantType: "unknown",
+		},
+		{
+			name:     "unspendable",
+			script:   "ba4f",
+			wantAsm:  "OP_CHECKSIGADD OP_PUSHNUM_NEG1",
+			wantType: "provably_unspendable",
+		},
+		{
+			name:     "push past end",
+			script:   "0401",
+			wantAsm:  "OP_PUSHBYTES_4 <push past end>",
+			wantType: "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := hex.DecodeString(tt.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := esploraScriptAsm(script); got != tt.wantAsm {
+				t.Errorf("esploraScriptAsm() = %v, want %v", got, tt.wantAsm)
+			}
+			if got := esploraScriptType(script); got != tt.wantType {
+				t.Errorf("esploraScriptType() = %v, want %v", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func Test_txToEsplora(t *testing.T) {
+	w := &Worker{
+		chainParser: btc.NewBitcoinParser(btc.GetChainParams("test"), &btc.Configuration{}),
+		chainType:   bchain.ChainBitcoinType,
+	}
+	prevout, _ := hex.DecodeString("a91465dfc5c16e80b86b589df3f85dacd43f5c5b4a8f87")
+	// P2SH-P2WSH 2 of 2 multisig spend, the witness is taken from the raw transaction
+	tx := &Tx{
+		Txid:     "fe9c89d5b75aa4e5cb34508b1b18acad2d7fe1d8a21011027bcf47614277fdb2",
+		Version:  1,
+		Locktime: 0,
+		Vin: []Vin{
+			{
+				Txid:     "6423aaca160211f58272014256fdb5aa45bc0b2b346059935c0406ef97b52027",
+				Vout:     1,
+				Sequence: 4294967295,
+				AddrDesc: prevout,
+				ValueSat: (*Amount)(big.NewInt(2546256)),
+			},
+		},
+		Vout: []Vout{
+			{ValueSat: (*Amount)(big.NewInt(246814)), Hex: "a914203c9dbd3ffbd1a790fc1609fb430efa5cbe516d87"},
+			{ValueSat: (*Amount)(big.NewInt(2299142)), Hex: "a91465dfc5c16e80b86b589df3f85dacd43f5c5b4a8f87"},
+		},
+		FeesSat:          (*Amount)(big.NewInt(300)),
+		CoinSpecificData: json.RawMessage(`{"txid":"fe9c89d5b75aa4e5cb34508b1b18acad2d7fe1d8a21011027bcf47614277fdb2","hex":"010000000001012720b597ef06045c935960342b0bbc45aab5fd5642017282f5110216caaa2364010000002322002069dae530beb09a05d46d0b2aee98645b15bb5d1e808a386b5ef0c48aed5531cbffffffff021ec403000000000017a914203c9dbd3ffbd1a790fc1609fb430efa5cbe516d87061523000000000017a91465dfc5c16e80b86b589df3f85dacd43f5c5b4a8f8704004730440220783e9349fc48f22aa0064acf32bc255eafa761eb9fa8f90a504986713c52dc3702206fc6a1a42f74ea0b416b35671770c0d26fc453668e6107edc271f11e629cda1001483045022100b82ef510c7eec61f39bee3e73a19df451fb8cca842b66bc94696d6a095dd8e96022071767bf8e4859de06cd5caf75e833e284328570ea1caa88bc93478a8d0fa9ac90147522103958c08660082c9ce90399ded0da7c3b39ed20a7767160f12428191e005aa42572102b1e6d8187f54d83d1ffd70508e24c5bd3603bccb2346d8c6677434169de8bc2652ae00000000"}`),
+	}
+	b, err := json.Marshal(w.txToEsplora(tx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"txid":"fe9c89d5b75aa4e5cb34508b1b18acad2d7fe1d8a21011027bcf47614277fdb2","version":1,"locktime":0,"vin":[{"txid":"6423aaca160211f58272014256fdb5aa45bc0b2b346059935c0406ef97b52027","vout":1,"prevout":{"scriptpubkey":"a91465dfc5c16e80b86b589df3f85dacd43f5c5b4a8f87","scriptpubkey_asm":"OP_HASH160 OP_PUSHBYTES_20 65dfc5c16e80b86b589df3f85dacd43f5c5b4a8f OP_EQUAL","scriptpubkey_type":"p2sh","scriptpubkey_address":"2N2XtJeGzoR2RrS4Zaq6EHcMCoyhT7iptBw","value":2546256},"scriptsig":"22002069dae530beb09a05d46d0b2aee98645b15bb5d1e808a386b5ef0c48aed5531cb","scriptsig_asm":"OP_PUSHBYTES_34 002069dae530beb09a05d46d0b2aee98645b15bb5d1e808a386b5ef0c48aed5531cb","witness":["","30440220783e9349fc48f22aa0064acf32bc255eafa761eb9fa8f90a504986713c52dc3702206fc6a1a42f74ea0b416b35671770c0d26fc453668e6107edc271f11e629cda1001","3045022100b82ef510c7eec61f39bee3e73a19df451fb8cca842b66bc94696d6a095dd8e96022071767bf8e4859de06cd5caf75e833e284328570ea1caa88bc93478a8d0fa9ac901","522103958c08660082c9ce90399ded0da7c3b39ed20a7767160f12428191e005aa42572102b1e6d8187f54d83d1ffd70508e24c5bd3603bccb2346d8c6677434169de8bc2652ae"],"is_coinbase":false,"sequence":4294967295,"inner_redeemscript_asm":"OP_0 OP_PUSHBYTES_32 69dae530beb09a05d46d0b2aee98645b15bb5d1e808a386b5ef0c48aed5531cb","inner_witnessscript_asm":"OP_PUSHNUM_2 OP_PUSHBYTES_33 03958c08660082c9ce90399ded0da7c3b39ed20a7767160f12428191e005aa4257 OP_PUSHBYTES_33 02b1e6d8187f54d83d1ffd70508e24c5bd3603bccb2346d8c6677434169de8bc26 OP_PUSHNUM_2 OP_CHECKMULTISIG"}],"vout":[{"scriptpubkey":"a914203c9dbd3ffbd1a790fc1609fb430efa5cbe516d87","scriptpubkey_asm":"OP_HASH160 OP_PUSHBYTES_20 203c9dbd3ffbd1a790fc1609fb430efa5cbe516d OP_EQUAL","scriptpubkey_type":"p2sh","scriptpubkey_address":"2MvBgE7CqCmvk2rBirRBcq7SdRhQGKjsmFu","value":246814},{"scriptpubkey":"a91465dfc5c16e80b86b589df3f85dacd43f5c5b4a8f87","scriptpubkey_asm":"OP_HASH160 OP_PUSHBYTES_20 65dfc5c16e80b86b589df3f85dacd43f5c5b4a8f OP_EQUAL","scriptpubkey_type":"p2sh","scriptpubkey_address":"2N2XtJeGzoR2RrS4Zaq6EHcMCoyhT7iptBw","value":2299142}],"size":371,"weight":821,"fee":300,"status":{"confirmed":false}}`
+	if got := string(b); got != want {
+		t.Errorf("txToEsplora() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/martinboehm/btcd/txscript"
+)
+
+// script types as named by Esplora
+const (
+	esploraScriptEmpty               = "empty"
+	esploraScriptOpReturn            = "op_return"
+	esploraScriptP2PK                = "p2pk"
+	esploraScriptP2PKH               = "p2pkh"
+	esploraScriptP2SH                = "p2sh"
+	esploraScriptP2WPKH              = "v0_p2wpkh"
+	esploraScriptP2WSH               = "v0_p2wsh"
+	esploraScriptP2TR                = "v1_p2tr"
+	esploraScriptProvablyUnspendable = "provably_unspendable"
+	esploraScriptUnknown             = "unknown"
+)
+
+// esploraOpcodeNames contains the names of the opcodes used by Esplora in the script asm, the push opcodes are handled separately
+var esploraOpcodeNames [256]string
+
+func init() {
+	aliases := map[string]struct{}{"OP_FALSE": {}, "OP_TRUE": {}, "OP_NOP2": {}, "OP_NOP3": {}}
+	for name, op := range txscript.OpcodeByName {
+		if _, alias := aliases[name]; !alias {
+			esploraOpcodeNames[op] = name
+		}
+	}
+	esploraOpcodeNames[txscript.OP_0] = "OP_0"
+	esploraOpcodeNames[txscript.OP_1NEGATE] = "OP_PUSHNUM_NEG1"
+	for i := 1; i <= 16; i++ {
+		esploraOpcodeNames[txscript.OP_1+i-1] = fmt.Sprint("OP_PUSHNUM_", i)
+	}
+	esploraOpcodeNames[txscript.OP_CHECKLOCKTIMEVERIFY] = "OP_CLTV"
+	esploraOpcodeNames[txscript.OP_CHECKSEQUENCEVERIFY] = "OP_CSV"
+	// BIP342 opcode, the rest of the unassigned opcodes make the script fail
+	esploraOpcodeNames[0xba] = "OP_CHECKSIGADD"
+	for i := 0xbb; i < 0xff; i++ {
+		esploraOpcodeNames[i] = fmt.Sprint("OP_RETURN_", i)
+	}
+	esploraOpcodeNames[0xff] = "OP_INVALIDOPCODE"
+}
+
+// esploraScriptAsm disassembles the script to the format used by Esplora, i.e. OP_PUSHBYTES_20 followed by the pushed data in hex
+func esploraScriptAsm(script []byte) string {
+	var sb strings.Builder
+	for i := 0; i < len(script); {
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		op := script[i]
+		i++
+		var l int
+		switch {
+		case op == txscript.OP_0:
+			sb.WriteString(esploraOpcodeNames[op])
+			continue
+		case op < txscript.OP_PUSHDATA1:
+			l = int(op)
+			sb.WriteString(fmt.Sprint("OP_PUSHBYTES_", l))
+		case op <= txscript.OP_PUSHDATA4:
+			n := 1 << uint(op-txscript.OP_PUSHDATA1)
+			if i+n > len(script) {
+				sb.WriteString("<unexpected end>")
+				return sb.String()
+			}
+			for j := n - 1; j >= 0; j-- {
+				l = l<<8 | int(script[i+j])
+			}
+			i += n
+			sb.WriteString(esploraOpcodeNames[op])
+		default:
+			sb.WriteString(esploraOpcodeNames[op])
+			continue
+		}
+		if l > len(script)-i {
+			sb.WriteString(" <push past end>")
+			return sb.String()
+		}
+		sb.WriteByte(' ')
+		sb.WriteString(hex.EncodeToString(script[i : i+l]))
+		i += l
+	}
+	return sb.String()
+}
+
+// esploraScriptType returns the type of the output script as named by Esplora
+func esploraScriptType(script []byte) string {
+	l := len(script)
+	switch {
+	case l == 0:
+		return esploraScriptEmpty
+	case script[0] == txscript.OP_RETURN:
+		return esploraScriptOpReturn
+	case (l == 35 && script[0] == 33 || l == 67 && script[0] == 65) && script[l-1] == txscript.OP_CHECKSIG:
+		return esploraScriptP2PK
+	case l == 25 && script[0] == txscript.OP_DUP && script[1] == txscript.OP_HASH160 && script[2] == 20 &&
+		script[23] == txscript.OP_EQUALVERIFY && script[24] == txscript.OP_CHECKSIG:
+		return esploraScriptP2PKH
+	case l == 23 && script[0] == txscript.OP_HASH160 && script[1] == 20 && script[22] == txscript.OP_EQUAL:
+		return esploraScriptP2SH
+	case l == 22 && script[0] == txscript.OP_0 && script[1] == 20:
+		return esploraScriptP2WPKH
+	case l == 34 && script[0] == txscript.OP_0 && script[1] == 32:
+		return esploraScriptP2WSH
+	case l == 34 && script[0] == txscript.OP_1 && script[1] == 32:
+		return esploraScriptP2TR
+	case script[0] > 0xb9:
+		// an invalid opcode at the beginning makes the output unspendable
+		return esploraScriptProvablyUnspendable
+	}
+	return esploraScriptUnknown
+}
+
+// esploraLastPush returns the data pushed by the last opcode of the script, nil if the last opcode is not a push
+func esploraLastPush(script []byte) []byte {
+	var last []byte
+	for i := 0; i < len(script); {
+		op := script[i]
+		i++
+		var l int
+		switch {
+		case op == txscript.OP_0:
+			last = []byte{}
+			continue
+		case op < txscript.OP_PUSHDATA1:
+			l = int(op)
+		case op <= txscript.OP_PUSHDATA4:
+			n := 1 << uint(op-txscript.OP_PUSHDATA1)
+			if i+n > len(script) {
+				return nil
+			}
+			for j := n - 1; j >= 0; j-- {
+				l = l<<8 | int(script[i+j])
+			}
+			i += n
+		default:
+			last = nil
+			continue
+		}
+		if l > len(script)-i {
+			return nil
+		}
+		last = script[i : i+l]
+		i += l
+	}
+	return last
+}
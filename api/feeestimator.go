@@ -0,0 +1,281 @@
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// FeeEstimator estimates the fee per kB (in satoshi) needed for a confirmation within the given number of blocks
+type FeeEstimator interface {
+	EstimateFee(blocks int, conservative bool) (big.Int, error)
+}
+
+// FeeEstimatorFactory creates a fee estimator for the worker, params are passed from the blockchain config
+type FeeEstimatorFactory func(w *Worker, params string) (FeeEstimator, error)
+
+// FeeEstimatorFactories is a map of fee estimators selectable by the fee_estimator blockchain config option
+var FeeEstimatorFactories = map[string]FeeEstimatorFactory{
+	"backend":    newBackendFeeEstimator,
+	"blockstats": newBlockStatsFeeEstimator,
+}
+
+// sharedFeeEstimator is the fee estimator used by all workers, nil selects the backend estimator
+var sharedFeeEstimator FeeEstimator
+
+// NewFeeEstimator creates the fee estimator of given name using the worker w, empty name selects the backend estimator
+func NewFeeEstimator(w *Worker, name, params string) (FeeEstimator, error) {
+	if name == "" {
+		name = "backend"
+	}
+	factory, found := FeeEstimatorFactories[name]
+	if !found {
+		return nil, errors.Errorf("Unknown fee estimator %s", name)
+	}
+	fe, err := factory(w, params)
+	if err != nil {
+		return nil, errors.Annotatef(err, "fee estimator %s", name)
+	}
+	return fe, nil
+}
+
+// SetFeeEstimator sets the fee estimator shared by the workers created after the call, nil selects the backend estimator
+func SetFeeEstimator(fe FeeEstimator) {
+	sharedFeeEstimator = fe
+}
+
+func (w *Worker) initFeeEstimator() error {
+	if sharedFeeEstimator != nil {
+		w.feeEstimator = sharedFeeEstimator
+		return nil
+	}
+	fe, err := newBackendFeeEstimator(w, "")
+	if err != nil {
+		return err
+	}
+	w.feeEstimator = fe
+	return nil
+}
+
+// BitcoinTypeEstimateFee returns a fee estimation for given number of blocks using the configured fee estimator
+func (w *Worker) BitcoinTypeEstimateFee(blocks int, conservative bool) (big.Int, error) {
+	return w.feeEstimator.EstimateFee(blocks, conservative)
+}
+
+// backendFeeEstimator gets the estimates from the backend, it uses 10 second cache to reduce calls to the backend
+type backendFeeEstimator struct {
+	chain bchain.BlockChain
+}
+
+func newBackendFeeEstimator(w *Worker, params string) (FeeEstimator, error) {
+	return &backendFeeEstimator{chain: w.chain}, nil
+}
+
+type bitcoinTypeEstimatedFee struct {
+	timestamp int64
+	fee       big.Int
+	lock      sync.Mutex
+}
+
+const bitcoinTypeEstimatedFeeCacheSize = 300
+
+var bitcoinTypeEstimatedFeeCache [bitcoinTypeEstimatedFeeCacheSize]bitcoinTypeEstimatedFee
+var bitcoinTypeEstimatedFeeConservativeCache [bitcoinTypeEstimatedFeeCacheSize]bitcoinTypeEstimatedFee
+
+func (e *backendFeeEstimator) cachedEstimateFee(blocks int, conservative bool, s *bitcoinTypeEstimatedFee) (big.Int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// 10 seconds cache
+	threshold := time.Now().Unix() - 10
+	if s.timestamp >= threshold {
+		return s.fee, nil
+	}
+	fee, err := e.chain.EstimateSmartFee(blocks, conservative)
+	if err == nil {
+		s.timestamp = time.Now().Unix()
+		s.fee = fee
+	}
+	return fee, err
+}
+
+func (e *backendFeeEstimator) EstimateFee(blocks int, conservative bool) (big.Int, error) {
+	if blocks >= bitcoinTypeEstimatedFeeCacheSize {
+		return e.chain.EstimateSmartFee(blocks, conservative)
+	}
+	if conservative {
+		return e.cachedEstimateFee(blocks, conservative, &bitcoinTypeEstimatedFeeConservativeCache[blocks])
+	}
+	return e.cachedEstimateFee(blocks, conservative, &bitcoinTypeEstimatedFeeCache[blocks])
+}
+
+// projected mempool block with at least this virtual size is considered full
+const projectedBlockFullVSize = 950000
+
+type blockStatsFeeEstimatorParams struct {
+	// number of the recent blocks from which the fee rate deciles are taken
+	Blocks int `json:"blocks"`
+	// the lowest returned fee per kB
+	MinFeePerKb int64 `json:"minFeePerKb"`
+}
+
+// blockStatsFeeEstimator estimates the fees locally from the fee rate deciles of the recent blocks
+// and from the fee rate distribution of the current mempool, without calls to the backend
+type blockStatsFeeEstimator struct {
+	w      *Worker
+	params blockStatsFeeEstimatorParams
+	// the deciles of the recent blocks are cached, computation of the block fee stats is expensive
+	lock   sync.Mutex
+	blocks []blockFeeDeciles
+}
+
+type blockFeeDeciles struct {
+	hash    string
+	deciles [11]int64
+}
+
+func newBlockStatsFeeEstimator(w *Worker, params string) (FeeEstimator, error) {
+	e := &blockStatsFeeEstimator{
+		w: w,
+		params: blockStatsFeeEstimatorParams{
+			Blocks:      6,
+			MinFeePerKb: 1000,
+		},
+	}
+	if params != "" {
+		if err := json.Unmarshal([]byte(params), &e.params); err != nil {
+			return nil, err
+		}
+	}
+	if e.params.Blocks < 1 {
+		return nil, errors.New("Parameter blocks must be positive")
+	}
+	return e, nil
+}
+
+// recentBlockDeciles returns the fee rate deciles of the recent blocks containing transactions,
+// fee stats are computed only for the blocks not yet in the cache
+func (e *blockStatsFeeEstimator) recentBlockDeciles() ([][11]int64, error) {
+	bestHeight, _, err := e.w.db.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	cached := make(map[string]*blockFeeDeciles, len(e.blocks))
+	for i := range e.blocks {
+		cached[e.blocks[i].hash] = &e.blocks[i]
+	}
+	blocks := make([]blockFeeDeciles, 0, e.params.Blocks)
+	for height := bestHeight; height+uint32(e.params.Blocks) > bestHeight; height-- {
+		hash, err := e.w.db.GetBlockHash(height)
+		if err != nil {
+			return nil, err
+		}
+		if hash == "" {
+			break
+		}
+		if b, found := cached[hash]; found {
+			blocks = append(blocks, *b)
+		} else {
+			fs, err := e.w.GetFeeStats(hash)
+			if err != nil {
+				return nil, err
+			}
+			b := blockFeeDeciles{hash: hash}
+			// block without transactions other than coinbase, deciles are not defined
+			if fs.TxCount > 0 {
+				b.deciles = fs.DecilesFeePerKb
+			} else {
+				b.deciles[0] = -1
+			}
+			blocks = append(blocks, b)
+		}
+		if height == 0 {
+			break
+		}
+	}
+	e.blocks = blocks
+	r := make([][11]int64, 0, len(blocks))
+	for i := range blocks {
+		if blocks[i].deciles[0] >= 0 {
+			r = append(r, blocks[i].deciles)
+		}
+	}
+	return r, nil
+}
+
+// feeDecileForTarget returns the decile of the block fee rates used for the target, longer targets are satisfied by lower fee rates
+func feeDecileForTarget(blocks int, conservative bool) int {
+	var d int
+	switch {
+	case blocks <= 1:
+		d = 5
+	case blocks == 2:
+		d = 4
+	case blocks == 3:
+		d = 3
+	case blocks <= 6:
+		d = 2
+	default:
+		d = 1
+	}
+	if conservative {
+		d++
+	}
+	return d
+}
+
+// estimateFeeFromStats computes the fee per kB as the maximum of the median of the target decile of the recent blocks
+// and the fee rate needed to get into the projected mempool block of the target, if the projected block is full
+func estimateFeeFromStats(blocks int, conservative bool, deciles [][11]int64, stats *bchain.MempoolStats, minFeePerKb int64) int64 {
+	if blocks < 1 {
+		blocks = 1
+	}
+	fee := minFeePerKb
+	if len(deciles) > 0 {
+		d := feeDecileForTarget(blocks, conservative)
+		values := make([]int64, len(deciles))
+		for i := range deciles {
+			values[i] = deciles[i][d]
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		if median := values[len(values)/2]; median > fee {
+			fee = median
+		}
+	}
+	if stats != nil && blocks <= len(stats.ProjectedBlocks) {
+		pb := &stats.ProjectedBlocks[blocks-1]
+		if pb.VSize >= projectedBlockFullVSize {
+			rate := pb.MinFeePerVByte
+			if conservative {
+				rate = pb.MedianFeePerVByte
+			}
+			if mempoolFee := int64(rate * 1000); mempoolFee > fee {
+				fee = mempoolFee
+			}
+		}
+	}
+	return fee
+}
+
+func (e *blockStatsFeeEstimator) EstimateFee(blocks int, conservative bool) (big.Int, error) {
+	var r big.Int
+	deciles, err := e.recentBlockDeciles()
+	if err != nil {
+		return r, errors.Annotatef(err, "recentBlockDeciles")
+	}
+	var stats *bchain.MempoolStats
+	if e.w.mempool != nil {
+		stats = e.w.mempool.GetStats()
+	}
+	fee := estimateFeeFromStats(blocks, conservative, deciles, stats, e.params.MinFeePerKb)
+	glog.V(1).Info("blockstats fee estimate ", blocks, ", conservative ", conservative, ": ", fee, " from ", len(deciles), " blocks")
+	r.SetInt64(fee)
+	return r, nil
+}
@@ -0,0 +1,80 @@
+//go:build unittest
+
+package api
+
+import (
+	"testing"
+
+	"github.com/trezor/blockbook/bchain"
+)
+
+func Test_estimateFeeFromStats(t *testing.T) {
+	deciles := make([][11]int64, 3)
+	for i := range deciles {
+		for k := range deciles[i] {
+			deciles[i][k] = int64(i+1)*1000 + int64(k)*1000
+		}
+	}
+	stats := &bchain.MempoolStats{
+		ProjectedBlocks: []bchain.MempoolProjectedBlock{
+			{TxCount: 2500, VSize: 999000, MinFeePerVByte: 12.5, MedianFeePerVByte: 20, MaxFeePerVByte: 300},
+			{TxCount: 3100, VSize: 999000, MinFeePerVByte: 4.2, MedianFeePerVByte: 4.5, MaxFeePerVByte: 12.5},
+			{TxCount: 40, VSize: 10000, MinFeePerVByte: 1, MedianFeePerVByte: 1, MaxFeePerVByte: 4.2},
+		},
+	}
+	tests := []struct {
+		name         string
+		blocks       int
+		conservative bool
+		deciles      [][11]int64
+		stats        *bchain.MempoolStats
+		want         int64
+	}{
+		{name: "no data", blocks: 1, want: 1000},
+		{name: "only blocks, 1", blocks: 1, deciles: deciles, want: 7000},
+		{name: "only blocks, 1 conservative", blocks: 1, conservative: true, deciles: deciles, want: 8000},
+		{name: "only blocks, 3", blocks: 3, deciles: deciles, want: 5000},
+		{name: "only blocks, 10", blocks: 10, deciles: deciles, want: 3000},
+		{name: "only mempool, 1", blocks: 1, stats: stats, want: 12500},
+		{name: "only mempool, 0", blocks: 0, stats: stats, want: 12500},
+		{name: "full mempool block", blocks: 1, deciles: deciles, stats: stats, want: 12500},
+		{name: "full mempool block conservative", blocks: 1, conservative: true, deciles: deciles, stats: stats, want: 20000},
+		{name: "full mempool block below blocks", blocks: 2, deciles: deciles, stats: stats, want: 6000},
+		{name: "mempool block not full", blocks: 3, deciles: deciles, stats: stats, want: 5000},
+		{name: "beyond mempool", blocks: 10, deciles: deciles, stats: stats, want: 3000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateFeeFromStats(tt.blocks, tt.conservative, tt.deciles, tt.stats, 1000); got != tt.want {
+				t.Errorf("estimateFeeFromStats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFeeEstimator(t *testing.T) {
+	w := &Worker{}
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{name: "", wantErr: false},
+		{name: "backend", wantErr: false},
+		{name: "blockstats", params: `{"blocks": 3, "minFeePerKb": 2000}`, wantErr: false},
+		{name: "blockstats", params: `{"blocks": 0}`, wantErr: true},
+		{name: "blockstats", params: `{"blocks":`, wantErr: true},
+		{name: "unknown", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+tt.params, func(t *testing.T) {
+			fe, err := NewFeeEstimator(w, tt.name, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewFeeEstimator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && fe == nil {
+				t.Error("NewFeeEstimator() returned nil estimator")
+			}
+		})
+	}
+}
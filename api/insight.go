@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcd/btcec"
+	"github.com/martinboehm/btcd/chaincfg/chainhash"
+	"github.com/martinboehm/btcd/txscript"
+	"github.com/martinboehm/btcd/wire"
+	"github.com/martinboehm/btcutil"
+	"github.com/trezor/blockbook/bchain"
+)
+
+const (
+	// TxsOnPageV1 is the number of transactions returned in one page by the legacy api v1 txs request
+	TxsOnPageV1 = 10
+	// MaxAddressesTxsV1 is the maximum range of transactions returned by the legacy api v1 addrs/txs request
+	MaxAddressesTxsV1 = 50
+	// relayFeeV1Sat is the default minimum relay fee per kB of the backend, reported by status?q=getInfo
+	relayFeeV1Sat = 1000
+)
+
+// numberV1 converts the number returned by the backend as string to json.Number, zero if it is not a number
+func numberV1(s string) json.Number {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "0"
+	}
+	if json.Valid([]byte(s)) {
+		return json.Number(s)
+	}
+	// for example leading zeros are not allowed in json
+	return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func (w *Worker) getAddrDescV1(address string) (bchain.AddressDescriptor, error) {
+	addrDesc, err := w.chainParser.GetAddrDescFromAddress(address)
+	if err != nil {
+		return nil, NewAPIError(fmt.Sprintf("Invalid address '%v', %v", address, err), true)
+	}
+	return addrDesc, nil
+}
+
+// GetAddressesUtxoV1 returns unspent outputs of the addresses in the legacy api v1 format
+func (w *Worker) GetAddressesUtxoV1(addresses []string) ([]AddressesUtxoV1, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	d := w.chainParser.AmountDecimals()
+	v1 := make([]AddressesUtxoV1, 0)
+	for _, address := range addresses {
+		addrDesc, err := w.getAddrDescV1(address)
+		if err != nil {
+			return nil, err
+		}
+		utxos, err := w.getAddrDescUtxo(addrDesc, nil, false, false, true)
+		if err != nil {
+			return nil, err
+		}
+		for i := range utxos {
+			utxo := &utxos[i]
+			// legacy API does not report outputs spent in mempool
+			if utxo.SpentTxID != "" {
+				continue
+			}
+			u := AddressesUtxoV1{
+				Address:       address,
+				Txid:          utxo.Txid,
+				Vout:          uint32(utxo.Vout),
+				ScriptPubKey:  hex.EncodeToString(addrDesc),
+				Amount:        json.Number(utxo.AmountSat.DecimalString(d)),
+				AmountSat:     utxo.AmountSat.AsBigInt(),
+				Height:        utxo.Height,
+				Confirmations: utxo.Confirmations,
+			}
+			if utxo.Confirmations == 0 {
+				u.Ts = int64(w.mempool.GetTransactionTime(utxo.Txid))
+			}
+			v1 = append(v1, u)
+		}
+	}
+	return v1, nil
+}
+
+type txidHeightV1 struct {
+	txid   string
+	height uint32
+	time   uint32
+}
+
+// GetAddressesTxsV1 returns the transactions of the addresses with index in the range [from, to), the newest first
+func (w *Worker) GetAddressesTxsV1(addresses []string, from, to int) (*AddressesTxsV1, error) {
+	if from < 0 || to <= from {
+		return nil, NewAPIError(fmt.Sprintf("\"from\" (%d) is expected to be less than \"to\" (%d)", from, to), true)
+	}
+	if to-from > MaxAddressesTxsV1 {
+		return nil, NewAPIError(fmt.Sprintf("\"from\" (%d) and \"to\" (%d) range should be less than or equal to %d", from, to, MaxAddressesTxsV1), true)
+	}
+	unique := make(map[string]struct{})
+	var txs []txidHeightV1
+	for _, address := range addresses {
+		addrDesc, err := w.getAddrDescV1(address)
+		if err != nil {
+			return nil, err
+		}
+		outpoints, err := w.mempool.GetAddrDescTransactions(addrDesc)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range outpoints {
+			if _, found := unique[o.Txid]; !found {
+				unique[o.Txid] = struct{}{}
+				txs = append(txs, txidHeightV1{txid: o.Txid, time: w.mempool.GetTransactionTime(o.Txid)})
+			}
+		}
+		err = w.db.GetAddrDescTransactions(addrDesc, 0, maxUint32, func(txid string, height uint32, indexes []int32) error {
+			if _, found := unique[txid]; !found {
+				unique[txid] = struct{}{}
+				txs = append(txs, txidHeightV1{txid: txid, height: height})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Annotatef(err, "GetAddrDescTransactions %v", addrDesc)
+		}
+	}
+	// mempool transactions first, ordered by time, then the confirmed transactions ordered by height
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].height == 0 || txs[j].height == 0 {
+			if txs[i].height != txs[j].height {
+				return txs[i].height == 0
+			}
+			return txs[i].time > txs[j].time
+		}
+		return txs[i].height > txs[j].height
+	})
+	if to > len(txs) {
+		to = len(txs)
+	}
+	r := &AddressesTxsV1{
+		TotalItems: len(txs),
+		From:       from,
+		To:         to,
+		Items:      []*TxV1{},
+	}
+	if from >= to {
+		return r, nil
+	}
+	bestheight, _, err := w.db.GetBestBlock()
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetBestBlock")
+	}
+	for i := from; i < to; i++ {
+		tx, err := w.txFromTxid(txs[i].txid, bestheight, AccountDetailsTxHistory, nil)
+		if err != nil {
+			return nil, err
+		}
+		r.Items = append(r.Items, w.TxToV1(tx))
+	}
+	return r, nil
+}
+
+// GetBlockTxsV1 returns one page of the transactions of the block, the pages are numbered from zero
+func (w *Worker) GetBlockTxsV1(bid string, pageNum int) (*TxsV1, error) {
+	b, err := w.GetBlock(bid, pageNum+1, TxsOnPageV1)
+	if err != nil {
+		return nil, err
+	}
+	return &TxsV1{
+		PagesTotal: b.TotalPages,
+		Txs:        w.transactionsToV1(b.Transactions),
+	}, nil
+}
+
+// GetAddressTxsV1 returns one page of the transactions of the address, the pages are numbered from zero
+func (w *Worker) GetAddressTxsV1(address string, pageNum int) (*TxsV1, error) {
+	a, err := w.GetAddress(address, pageNum+1, TxsOnPageV1, AccountDetailsTxHistory, &AddressFilter{Vout: AddressFilterVoutOff})
+	if err != nil {
+		return nil, err
+	}
+	return &TxsV1{
+		PagesTotal: a.TotalPages,
+		Txs:        w.transactionsToV1(a.Transactions),
+	}, nil
+}
+
+// GetStatusV1 returns the result of the legacy api v1 status query
+func (w *Worker) GetStatusV1(q string) (interface{}, error) {
+	bi := w.is.GetBackendInfo()
+	switch q {
+	case "getDifficulty":
+		return &StatusDifficultyV1{Difficulty: numberV1(bi.Difficulty)}, nil
+	case "getBestBlockHash":
+		return &StatusBestBlockHashV1{BestBlockHash: bi.BestBlockHash}, nil
+	case "getLastBlockHash":
+		_, hash, err := w.db.GetBestBlock()
+		if err != nil {
+			return nil, errors.Annotatef(err, "GetBestBlock")
+		}
+		return &StatusLastBlockHashV1{SyncTipHash: hash, LastBlockHash: hash}, nil
+	}
+	return &StatusInfoV1{
+		Info: InfoV1{
+			Version:         numberV1(bi.Version),
+			ProtocolVersion: numberV1(bi.ProtocolVersion),
+			Blocks:          bi.Blocks,
+			TimeOffset:      bi.Timeoffset,
+			Difficulty:      numberV1(bi.Difficulty),
+			Testnet:         w.chain.IsTestnet(),
+			RelayFee:        json.Number(w.chainParser.AmountToDecimalString(big.NewInt(relayFeeV1Sat))),
+			Errors:          bi.Warnings,
+			Network:         w.chain.GetNetworkName(),
+		},
+	}, nil
+}
+
+// GetSyncV1 returns the synchronization status of the index in the legacy api v1 format
+func (w *Worker) GetSyncV1() *SyncV1 {
+	inSync, bestHeight, _ := w.is.GetSyncState()
+	blocks := w.is.GetBackendInfo().Blocks
+	s := &SyncV1{
+		Status:           "syncing",
+		BlockChainHeight: blocks,
+		Height:           int(bestHeight),
+		Type:             "bitcore node",
+	}
+	if blocks > 0 {
+		s.SyncPercentage = int(math.Round(float64(bestHeight) * 100 / float64(blocks)))
+		if s.SyncPercentage > 100 {
+			s.SyncPercentage = 100
+		}
+	}
+	if inSync && int(bestHeight) >= blocks {
+		s.Status = "finished"
+		s.SyncPercentage = 100
+	}
+	return s
+}
+
+// GetRawTxV1 returns the serialized transaction in hex
+func (w *Worker) GetRawTxV1(txid string) (*RawTxV1, error) {
+	tx, err := w.GetTransaction(txid, false, true)
+	if err != nil {
+		return nil, err
+	}
+	h := esploraTxHex(tx)
+	if h == "" {
+		return nil, errors.Errorf("Transaction %s hex not available", txid)
+	}
+	return &RawTxV1{RawTx: h}, nil
+}
+
+// VerifyMessageV1 verifies the compact signature of the message made by the key of the P2PKH address,
+// the message is prefixed by the message signature magic of the coin
+func (w *Worker) VerifyMessageV1(address, signature, message string) (*MessageVerifyV1, error) {
+	magic := w.chainParser.GetMessageSignatureMagic()
+	if magic == "" {
+		return nil, NewAPIError("Not supported", true)
+	}
+	addrDesc, err := w.getAddrDescV1(address)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, NewAPIError("Invalid signature, it must be base64 encoded", true)
+	}
+	var buf bytes.Buffer
+	if err := wire.WriteVarString(&buf, 0, magic); err != nil {
+		return nil, err
+	}
+	if err := wire.WriteVarString(&buf, 0, message); err != nil {
+		return nil, err
+	}
+	pubKey, compressed, err := btcec.RecoverCompact(btcec.S256(), sig, chainhash.DoubleHashB(buf.Bytes()))
+	if err != nil {
+		glog.V(1).Info("VerifyMessageV1 ", address, ", ", err)
+		return &MessageVerifyV1{Result: false}, nil
+	}
+	var pk []byte
+	if compressed {
+		pk = pubKey.SerializeCompressed()
+	} else {
+		pk = pubKey.SerializeUncompressed()
+	}
+	script := []byte{txscript.OP_DUP, txscript.OP_HASH160, txscript.OP_DATA_20}
+	script = append(script, btcutil.Hash160(pk)...)
+	script = append(script, txscript.OP_EQUALVERIFY, txscript.OP_CHECKSIG)
+	return &MessageVerifyV1{Result: bytes.Equal(script, addrDesc)}, nil
+}
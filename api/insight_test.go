@@ -0,0 +1,94 @@
+//go:build unittest
+
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/martinboehm/btcd/btcec"
+	"github.com/martinboehm/btcd/chaincfg/chainhash"
+	"github.com/martinboehm/btcd/wire"
+	"github.com/martinboehm/btcutil"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/bchain/coins/btc"
+	"github.com/trezor/blockbook/bchain/coins/litecoin"
+)
+
+func signMessageV1(t *testing.T, key *btcec.PrivateKey, magic, message string) string {
+	var buf bytes.Buffer
+	if err := wire.WriteVarString(&buf, 0, magic); err != nil {
+		t.Fatal(err)
+	}
+	if err := wire.WriteVarString(&buf, 0, message); err != nil {
+		t.Fatal(err)
+	}
+	sig, err := btcec.SignCompact(btcec.S256(), key, chainhash.DoubleHashB(buf.Bytes()), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestWorker_VerifyMessageV1(t *testing.T) {
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), bytes.Repeat([]byte{1}, 32))
+	pkHash := btcutil.Hash160(key.PubKey().SerializeCompressed())
+	btcParser := btc.NewBitcoinParser(btc.GetChainParams("main"), &btc.Configuration{})
+	ltcParser := litecoin.NewLitecoinParser(litecoin.GetChainParams("main"), &btc.Configuration{})
+	address := func(parser *btc.BitcoinLikeParser) string {
+		a, err := btcutil.NewAddressPubKeyHash(pkHash, parser.Params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a.EncodeAddress()
+	}
+	const message = "blockbook"
+	tests := []struct {
+		name      string
+		parser    bchain.BlockChainParser
+		address   string
+		signature string
+		want      bool
+	}{
+		{
+			name:      "bitcoin",
+			parser:    btcParser,
+			address:   address(btcParser.BitcoinLikeParser),
+			signature: signMessageV1(t, key, "Bitcoin Signed Message:\n", message),
+			want:      true,
+		},
+		{
+			name:      "litecoin",
+			parser:    ltcParser,
+			address:   address(ltcParser.BitcoinLikeParser),
+			signature: signMessageV1(t, key, "Litecoin Signed Message:\n", message),
+			want:      true,
+		},
+		{
+			name:      "litecoin signed with bitcoin magic",
+			parser:    ltcParser,
+			address:   address(ltcParser.BitcoinLikeParser),
+			signature: signMessageV1(t, key, "Bitcoin Signed Message:\n", message),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Worker{chainParser: tt.parser}
+			got, err := w.VerifyMessageV1(tt.address, tt.signature, message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Result != tt.want {
+				t.Errorf("VerifyMessageV1() = %v, want %v", got.Result, tt.want)
+			}
+		})
+	}
+	noMagicParser := btc.NewBitcoinParser(btc.GetChainParams("main"), &btc.Configuration{})
+	noMagicParser.MessageSignatureMagic = ""
+	w := &Worker{chainParser: noMagicParser}
+	if _, err := w.VerifyMessageV1(address(btcParser.BitcoinLikeParser), signMessageV1(t, key, "Bitcoin Signed Message:\n", message), message); err == nil {
+		t.Error("VerifyMessageV1() without message signature magic, want error")
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/bchain"
+)
+
+func feePerVByte(fee *big.Int, vsize uint32) float64 {
+	if vsize == 0 {
+		return 0
+	}
+	f, _ := new(big.Float).SetInt(fee).Float64()
+	return math.Round(f/float64(vsize)*100) / 100
+}
+
+// getMempoolTxFees returns fee information of the mempool transaction from the backend, nil if not available
+func (w *Worker) getMempoolTxFees(txid string) *MempoolTxFees {
+	entry, err := w.chain.GetMempoolEntry(txid)
+	if err != nil {
+		glog.V(1).Info("GetMempoolEntry ", txid, ": ", err)
+		return nil
+	}
+	r := &MempoolTxFees{
+		VSize:             int(entry.VSize),
+		FeePerVByte:       feePerVByte(&entry.FeeSat, entry.VSize),
+		AncestorCount:     int(entry.AncestorCount),
+		AncestorVSize:     int(entry.AncestorSize),
+		AncestorFeesSat:   (*Amount)(&entry.AncestorFeesSat),
+		DescendantCount:   int(entry.DescendantCount),
+		DescendantVSize:   int(entry.DescendantSize),
+		DescendantFeesSat: (*Amount)(&entry.DescendantFeesSat),
+	}
+	// the transaction is mined together with its unconfirmed ancestors, a package with a lower fee rate holds it back,
+	// on the other hand its descendants with a higher fee rate pull it (CPFP)
+	r.EffectiveFeePerVByte = r.FeePerVByte
+	if entry.AncestorCount > 1 {
+		if ancestorRate := feePerVByte(&entry.AncestorFeesSat, entry.AncestorSize); ancestorRate < r.EffectiveFeePerVByte {
+			r.EffectiveFeePerVByte = ancestorRate
+		}
+	}
+	if entry.DescendantCount > 1 {
+		if descendantRate := feePerVByte(&entry.DescendantFeesSat, entry.DescendantSize); descendantRate > r.EffectiveFeePerVByte {
+			r.EffectiveFeePerVByte = descendantRate
+		}
+	}
+	return r
+}
+
+func (w *Worker) mempoolInfoFromStats(stats *bchain.MempoolStats) MempoolInfo {
+	var fees big.Int
+	fees.SetInt64(stats.FeesSat)
+	r := MempoolInfo{
+		Size:            stats.Size,
+		VSize:           stats.VSize,
+		TotalFeesSat:    (*Amount)(&fees),
+		UnknownFeeCount: stats.UnknownFeeCount,
+		FeeHistogram:    make([]MempoolFeeRateBucket, len(stats.FeeHistogram)),
+	}
+	for i := range stats.FeeHistogram {
+		b := &stats.FeeHistogram[i]
+		r.FeeHistogram[i] = MempoolFeeRateBucket{
+			FeePerVByte: b.FeePerVByte,
+			Count:       b.Count,
+			VSize:       b.VSize,
+		}
+	}
+	return r
+}
+
+func (w *Worker) getMempoolStats() (*bchain.MempoolStats, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	stats := w.mempool.GetStats()
+	if stats == nil {
+		return nil, NewAPIError("Mempool statistics not available yet", true)
+	}
+	return stats, nil
+}
+
+// GetMempoolInfo returns summary of the mempool with the histogram of fee rates of the mempool transactions
+func (w *Worker) GetMempoolInfo() (*MempoolInfo, error) {
+	stats, err := w.getMempoolStats()
+	if err != nil {
+		return nil, err
+	}
+	r := w.mempoolInfoFromStats(stats)
+	return &r, nil
+}
+
+// MempoolStatsFromBchain converts mempool statistics computed in mempool resync to MempoolStats
+func (w *Worker) MempoolStatsFromBchain(stats *bchain.MempoolStats) *MempoolStats {
+	r := &MempoolStats{
+		Time:            stats.Time,
+		MempoolInfo:     w.mempoolInfoFromStats(stats),
+		ProjectedBlocks: make([]MempoolProjectedBlock, len(stats.ProjectedBlocks)),
+	}
+	for i := range stats.ProjectedBlocks {
+		b := &stats.ProjectedBlocks[i]
+		var fees big.Int
+		fees.SetInt64(b.FeesSat)
+		r.ProjectedBlocks[i] = MempoolProjectedBlock{
+			TxCount:           b.TxCount,
+			VSize:             b.VSize,
+			FeesSat:           (*Amount)(&fees),
+			MinFeePerVByte:    b.MinFeePerVByte,
+			MedianFeePerVByte: b.MedianFeePerVByte,
+			MaxFeePerVByte:    b.MaxFeePerVByte,
+		}
+	}
+	return r
+}
+
+// GetMempoolStats returns the fee rate histogram and the projection of the mempool transactions into the next blocks
+func (w *Worker) GetMempoolStats() (*MempoolStats, error) {
+	stats, err := w.getMempoolStats()
+	if err != nil {
+		return nil, err
+	}
+	return w.MempoolStatsFromBchain(stats), nil
+}
+
+// FeeHistogram returns the pairs of fee rate in sat/vB and virtual size of the mempool transactions in the bucket,
+// in the descending order of the fee rate, the empty buckets are omitted
+func FeeHistogram(stats *bchain.MempoolStats) [][2]int64 {
+	histogram := make([][2]int64, 0, len(stats.FeeHistogram))
+	for i := len(stats.FeeHistogram) - 1; i >= 0; i-- {
+		if b := &stats.FeeHistogram[i]; b.VSize > 0 {
+			histogram = append(histogram, [2]int64{b.FeePerVByte, b.VSize})
+		}
+	}
+	return histogram
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// maxOpReturnOutputs is the number of the OP_RETURN outputs with the same prefix which can be listed
+const maxOpReturnOutputs = 10000
+
+// maxOpReturnPrefix is the maximum length of the searched prefix in bytes, the same as the length of the indexed payload
+const maxOpReturnPrefix = 256
+
+// GetOpReturns returns a page of the OP_RETURN outputs with the payload starting with the prefix given in hex
+func (w *Worker) GetOpReturns(hexPrefix string, page int, itemsOnPage int) (*OpReturns, error) {
+	if w.chainType != bchain.ChainBitcoinType || !w.db.IsOpReturnIndexEnabled() {
+		return nil, NewAPIError("OP_RETURN index is not enabled", true)
+	}
+	start := time.Now()
+	prefix, err := hex.DecodeString(hexPrefix)
+	if err != nil {
+		return nil, NewAPIError("Prefix is not a hex string", true)
+	}
+	if len(prefix) == 0 || len(prefix) > maxOpReturnPrefix {
+		return nil, NewAPIError(fmt.Sprintf("Prefix must have 1 to %d bytes", maxOpReturnPrefix), true)
+	}
+	page--
+	if page < 0 {
+		page = 0
+	}
+	// the outputs are counted up to the limit in the same pass in which the page is read
+	entries, total, err := w.db.GetOpReturns(prefix, page*itemsOnPage, itemsOnPage, maxOpReturnOutputs)
+	if err != nil {
+		return nil, err
+	}
+	pg, from, _, _ := computePaging(total, page, itemsOnPage)
+	// the requested page is after the last page, read the last page
+	if from != page*itemsOnPage {
+		if entries, _, err = w.db.GetOpReturns(prefix, from, itemsOnPage, maxOpReturnOutputs); err != nil {
+			return nil, err
+		}
+	}
+	bestHeight, _, err := w.db.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	r := &OpReturns{
+		Paging:  pg,
+		Prefix:  hex.EncodeToString(prefix),
+		Outputs: make([]OpReturnOutput, len(entries)),
+	}
+	for i := range entries {
+		e := &entries[i]
+		r.Outputs[i] = OpReturnOutput{
+			Txid:          e.Txid,
+			Vout:          e.Vout,
+			Height:        e.Height,
+			Confirmations: int(bestHeight) - int(e.Height) + 1,
+			Data:          hex.EncodeToString(e.Payload),
+		}
+	}
+	glog.Info("GetOpReturns ", r.Prefix, ", page ", page+1, ", ", len(r.Outputs), " outputs, ", time.Since(start))
+	return r, nil
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/db"
+)
+
+// maxRichListAddresses is the number of the top addresses which can be listed
+const maxRichListAddresses = 10000
+
+// GetRichList returns a page of the addresses with the highest balance, their share of the total balance
+// of all addresses, the number of their transactions and the height of their last transaction
+func (w *Worker) GetRichList(page int, itemsOnPage int) (*RichList, error) {
+	if w.chainType != bchain.ChainBitcoinType || !w.db.IsRichListEnabled() {
+		return nil, NewAPIError("Rich list is not enabled", true)
+	}
+	start := time.Now()
+	page--
+	if page < 0 {
+		page = 0
+	}
+	count, total, err := w.db.GetRichListTotal()
+	if err != nil {
+		return nil, err
+	}
+	listed := int(count)
+	if listed > maxRichListAddresses {
+		listed = maxRichListAddresses
+	}
+	pg, from, to, _ := computePaging(listed, page, itemsOnPage)
+	entries, err := w.db.GetRichList(from, to-from)
+	if err != nil {
+		return nil, err
+	}
+	r := &RichList{
+		Paging:          pg,
+		TotalAddresses:  int(count),
+		TotalBalanceSat: (*Amount)(total),
+		Addresses:       make([]RichListAddress, len(entries)),
+	}
+	var totalFloat *big.Float
+	if total.Sign() > 0 {
+		totalFloat = new(big.Float).SetInt(total)
+	}
+	for i := range entries {
+		e := &entries[i]
+		a := &r.Addresses[i]
+		a.Rank = from + i + 1
+		a.BalanceSat = (*Amount)(&e.BalanceSat)
+		addresses, _, err := w.chainParser.GetAddressesFromAddrDesc(e.AddrDesc)
+		if err != nil || len(addresses) == 0 {
+			a.Address = e.AddrDesc.String()
+		} else {
+			a.Address = addresses[0]
+		}
+		if totalFloat != nil {
+			share, _ := new(big.Float).Quo(new(big.Float).SetInt(&e.BalanceSat), totalFloat).Float64()
+			a.SharePercent = share * 100
+		}
+		ba, err := w.db.GetAddrDescBalance(e.AddrDesc, db.AddressBalanceDetailNoUTXO)
+		if err != nil {
+			return nil, err
+		}
+		if ba != nil {
+			a.Txs = ba.Txs
+		}
+		// the transactions of the address are iterated from the newest
+		err = w.db.GetAddrDescTransactions(e.AddrDesc, 0, maxUint32, func(txid string, height uint32, indexes []int32) error {
+			a.LastHeight = height
+			return &db.StopIteration{}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	glog.Info("GetRichList page ", page+1, ", ", len(r.Addresses), " addresses, ", time.Since(start))
+	return r, nil
+}
+
+// GetTokenHolders returns a page of the holders of the ERC20 token with the highest balance computed from the transfers
+// of the token, their share of the total balance of all holders, the number of their transfers of the token
+// and the height of their last transaction
+func (w *Worker) GetTokenHolders(contract string, page int, itemsOnPage int) (*TokenHolders, error) {
+	if w.chainType != bchain.ChainEthereumType || !w.db.IsTokenHoldersEnabled() {
+		return nil, NewAPIError("Token holders are not enabled", true)
+	}
+	start := time.Now()
+	page--
+	if page < 0 {
+		page = 0
+	}
+	contractDesc, err := w.chainParser.GetAddrDescFromAddress(contract)
+	if err != nil {
+		return nil, NewAPIError(fmt.Sprintf("Invalid contract, %v", err), true)
+	}
+	ci, err := w.chain.EthereumTypeGetErc20ContractInfo(contractDesc)
+	if err != nil {
+		return nil, errors.Annotatef(err, "EthereumTypeGetErc20ContractInfo %v", contract)
+	}
+	if ci == nil {
+		return nil, NewAPIError("Contract not found", true)
+	}
+	count, total, err := w.db.GetTokenHoldersTotal(contractDesc)
+	if err != nil {
+		return nil, err
+	}
+	listed := int(count)
+	if listed > maxRichListAddresses {
+		listed = maxRichListAddresses
+	}
+	pg, from, to, _ := computePaging(listed, page, itemsOnPage)
+	entries, err := w.db.GetTokenHolders(contractDesc, from, to-from)
+	if err != nil {
+		return nil, err
+	}
+	r := &TokenHolders{
+		Paging:          pg,
+		Contract:        ci.Contract,
+		Name:            ci.Name,
+		Symbol:          ci.Symbol,
+		Decimals:        ci.Decimals,
+		TotalHolders:    int(count),
+		TotalBalanceSat: (*Amount)(total),
+		Holders:         make([]RichListAddress, len(entries)),
+	}
+	var totalFloat *big.Float
+	if total.Sign() > 0 {
+		totalFloat = new(big.Float).SetInt(total)
+	}
+	for i := range entries {
+		e := &entries[i]
+		a := &r.Holders[i]
+		a.Rank = from + i + 1
+		a.BalanceSat = (*Amount)(&e.BalanceSat)
+		addresses, _, err := w.chainParser.GetAddressesFromAddrDesc(e.AddrDesc)
+		if err != nil || len(addresses) == 0 {
+			a.Address = e.AddrDesc.String()
+		} else {
+			a.Address = addresses[0]
+		}
+		if totalFloat != nil {
+			share, _ := new(big.Float).Quo(new(big.Float).SetInt(&e.BalanceSat), totalFloat).Float64()
+			a.SharePercent = share * 100
+		}
+		ca, err := w.db.GetAddrDescContracts(e.AddrDesc)
+		if err != nil {
+			return nil, err
+		}
+		if ca != nil {
+			if j, found := findContract(ca.Contracts, contractDesc); found {
+				a.Txs = uint32(ca.Contracts[j].Txs)
+			}
+		}
+		// the transactions of the address are iterated from the newest
+		err = w.db.GetAddrDescTransactions(e.AddrDesc, 0, maxUint32, func(txid string, height uint32, indexes []int32) error {
+			a.LastHeight = height
+			return &db.StopIteration{}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	glog.Info("GetTokenHolders ", contract, " page ", page+1, ", ", len(r.Holders), " holders, ", time.Since(start))
+	return r, nil
+}
+
+func findContract(contracts []db.AddrContract, contract bchain.AddressDescriptor) (int, bool) {
+	for i := range contracts {
+		if bytes.Equal(contract, contracts[i].Contract) {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/db"
+)
+
+// maxSupplyStatsRange is the maximum number of points returned by GetSupplyStatsRange
+const maxSupplyStatsRange = 10000
+
+func supplyStatsFromDB(bi *db.BlockInfo) *SupplyStats {
+	s := bi.Supply
+	return &SupplyStats{
+		Height:       bi.Height,
+		Time:         bi.Time,
+		IssuedSat:    (*Amount)(&s.IssuedSat),
+		BurnedSat:    (*Amount)(&s.BurnedSat),
+		UtxoSetSat:   (*Amount)(&s.UtxoSetSat),
+		Utxos:        s.Utxos(),
+		Outputs:      s.Outputs,
+		SpentOutputs: s.SpentOutputs,
+	}
+}
+
+// getSupplyStats returns the supply statistics after the indexed block at given height
+// or nil if the block is not indexed, its hash does not match or it has no statistics
+func (w *Worker) getSupplyStats(height uint32, hash string) (*SupplyStats, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, nil
+	}
+	bi, err := w.db.GetBlockInfo(height)
+	if err != nil {
+		return nil, err
+	}
+	if bi == nil || bi.Supply == nil || (hash != "" && bi.Hash != hash) {
+		return nil, nil
+	}
+	bi.Height = height
+	return supplyStatsFromDB(bi), nil
+}
+
+// GetSupplyStatsRange returns the supply statistics of every step-th block in the height range from-to (inclusive)
+// as a time series, blocks without statistics are omitted
+func (w *Worker) GetSupplyStatsRange(from, to, step int) (*SupplyStatsRange, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	if from < 0 || to < from {
+		return nil, NewAPIError("Invalid block range", true)
+	}
+	if step <= 0 {
+		return nil, NewAPIError("Invalid step", true)
+	}
+	if (to-from)/step >= maxSupplyStatsRange {
+		return nil, NewAPIError(fmt.Sprintf("Block range too large, maximum is %d points", maxSupplyStatsRange), true)
+	}
+	r := &SupplyStatsRange{
+		From:        from,
+		To:          to,
+		Step:        step,
+		SupplyStats: make([]SupplyStats, 0),
+	}
+	err := w.db.GetSupplyStatsRange(uint32(from), uint32(to), uint32(step), func(bi *db.BlockInfo) error {
+		r.SupplyStats = append(r.SupplyStats, *supplyStatsFromDB(bi))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetSupplyStatsRange")
+	}
+	return r, nil
+}
@@ -116,6 +116,7 @@ type Vin struct {
 	Hex       string                   `json:"hex,omitempty"`
 	Asm       string                   `json:"asm,omitempty"`
 	Coinbase  string                   `json:"coinbase,omitempty"`
+	Taproot   *bchain.TaprootSpend     `json:"taproot,omitempty"`
 }
 
 // Vout contains information about single transaction output
@@ -193,22 +194,179 @@ type Tx struct {
 	Confirmations    uint32            `json:"confirmations"`
 	Blocktime        int64             `json:"blockTime"`
 	Size             int               `json:"size,omitempty"`
+	VSize            int64             `json:"vsize,omitempty"`
+	Weight           int64             `json:"weight,omitempty"`
+	FeeRate          float64           `json:"feeRate,omitempty"`
 	ValueOutSat      *Amount           `json:"value"`
 	ValueInSat       *Amount           `json:"valueIn,omitempty"`
 	FeesSat          *Amount           `json:"fees,omitempty"`
 	Hex              string            `json:"hex,omitempty"`
 	Rbf              bool              `json:"rbf,omitempty"`
+	ReplacedBy       string            `json:"replacedBy,omitempty"`
+	Replaces         []string          `json:"replaces,omitempty"`
+	MempoolFees      *MempoolTxFees    `json:"mempoolFees,omitempty"`
 	CoinSpecificData json.RawMessage   `json:"coinSpecificData,omitempty"`
 	TokenTransfers   []TokenTransfer   `json:"tokenTransfers,omitempty"`
 	EthereumSpecific *EthereumSpecific `json:"ethereumSpecific,omitempty"`
 }
 
+// MempoolTxFees contains fee information of a mempool transaction considering its unconfirmed ancestors and descendants (package)
+type MempoolTxFees struct {
+	VSize                int     `json:"vsize"`
+	FeePerVByte          float64 `json:"feePerVByte"`
+	AncestorCount        int     `json:"ancestorCount"`
+	AncestorVSize        int     `json:"ancestorVSize"`
+	AncestorFeesSat      *Amount `json:"ancestorFees"`
+	DescendantCount      int     `json:"descendantCount"`
+	DescendantVSize      int     `json:"descendantVSize"`
+	DescendantFeesSat    *Amount `json:"descendantFees"`
+	EffectiveFeePerVByte float64 `json:"effectiveFeePerVByte"`
+}
+
+// MempoolFeeRateBucket contains number and total virtual size of mempool transactions
+// with fee rate from FeePerVByte up to the FeePerVByte of the next bucket
+type MempoolFeeRateBucket struct {
+	FeePerVByte int64 `json:"feePerVByte"`
+	Count       int   `json:"count"`
+	VSize       int64 `json:"vsize"`
+}
+
+// MempoolInfo contains summary of mempool transactions with histogram of their fee rates
+type MempoolInfo struct {
+	Size            int                    `json:"size"`
+	VSize           int64                  `json:"vsize"`
+	TotalFeesSat    *Amount                `json:"totalFees"`
+	UnknownFeeCount int                    `json:"unknownFeeCount,omitempty"`
+	FeeHistogram    []MempoolFeeRateBucket `json:"feeHistogram"`
+}
+
+// MempoolProjectedBlock contains statistics of a block that would be mined from the mempool transactions
+type MempoolProjectedBlock struct {
+	TxCount           int     `json:"txCount"`
+	VSize             int64   `json:"vsize"`
+	FeesSat           *Amount `json:"fees"`
+	MinFeePerVByte    float64 `json:"minFeePerVByte"`
+	MedianFeePerVByte float64 `json:"medianFeePerVByte"`
+	MaxFeePerVByte    float64 `json:"maxFeePerVByte"`
+}
+
+// MempoolStats contains histogram of fee rates of mempool transactions and their projection into the next blocks
+type MempoolStats struct {
+	Time int64 `json:"time"`
+	MempoolInfo
+	ProjectedBlocks []MempoolProjectedBlock `json:"projectedBlocks"`
+}
+
 // FeeStats contains detailed block fee statistics
 type FeeStats struct {
+	Height          uint32    `json:"height"`
+	Time            int64     `json:"time,omitempty"`
 	TxCount         int       `json:"txCount"`
 	TotalFeesSat    *Amount   `json:"totalFeesSat"`
+	TotalVSize      int64     `json:"totalVSize"`
 	AverageFeePerKb int64     `json:"averageFeePerKb"`
+	MinFeePerKb     int64     `json:"minFeePerKb"`
+	MaxFeePerKb     int64     `json:"maxFeePerKb"`
 	DecilesFeePerKb [11]int64 `json:"decilesFeePerKb"`
+	SegwitTxCount   int       `json:"segwitTxCount"`
+	TaprootTxCount  int       `json:"taprootTxCount"`
+}
+
+// FeeStatsRange contains fee statistics of the blocks in the height range
+type FeeStatsRange struct {
+	From     int        `json:"from"`
+	To       int        `json:"to"`
+	FeeStats []FeeStats `json:"feeStats"`
+}
+
+// SupplyStats contains the cumulative coin supply and UTXO set statistics after a block
+type SupplyStats struct {
+	Height       uint32  `json:"height"`
+	Time         int64   `json:"time,omitempty"`
+	IssuedSat    *Amount `json:"issuedSat"`
+	BurnedSat    *Amount `json:"burnedSat"`
+	UtxoSetSat   *Amount `json:"utxoSetSat"`
+	Utxos        uint64  `json:"utxos"`
+	Outputs      uint64  `json:"outputs"`
+	SpentOutputs uint64  `json:"spentOutputs"`
+}
+
+// SupplyStatsRange contains supply statistics of the blocks in the height range
+type SupplyStatsRange struct {
+	From        int           `json:"from"`
+	To          int           `json:"to"`
+	Step        int           `json:"step"`
+	SupplyStats []SupplyStats `json:"supplyStats"`
+}
+
+// BlockFilterHeader contains the hash of the BIP158 filter of a block and its BIP157 filter header
+type BlockFilterHeader struct {
+	Height     uint32 `json:"height"`
+	Hash       string `json:"hash"`
+	FilterHash string `json:"filterHash"`
+	Header     string `json:"header,omitempty"`
+}
+
+// BlockFilter contains the BIP158 filter of a block
+type BlockFilter struct {
+	BlockFilterHeader
+	FilterType string `json:"filterType"`
+	Filter     string `json:"filter"`
+}
+
+// BlockFilterHeaders contains the filter headers of the blocks in the height range
+type BlockFilterHeaders struct {
+	From       int                 `json:"from"`
+	To         int                 `json:"to"`
+	PrevHeader string              `json:"prevHeader,omitempty"`
+	Headers    []BlockFilterHeader `json:"headers"`
+}
+
+// OpReturnOutput is an OP_RETURN output found by the payload prefix
+type OpReturnOutput struct {
+	Txid          string `json:"txid"`
+	Vout          int32  `json:"vout"`
+	Height        uint32 `json:"height"`
+	Confirmations int    `json:"confirmations"`
+	Data          string `json:"data"`
+}
+
+// OpReturns contains a page of the OP_RETURN outputs with the payload starting with the prefix
+type OpReturns struct {
+	Paging
+	Prefix  string           `json:"prefix"`
+	Outputs []OpReturnOutput `json:"outputs"`
+}
+
+// ChainReorg contains the blocks and transactions disconnected by a chain reorganization
+type ChainReorg struct {
+	ForkHeight         uint32   `json:"forkHeight"`
+	DisconnectedHashes []string `json:"disconnectedHashes,omitempty"`
+	Txids              []string `json:"txids"`
+}
+
+// StaleBlock contains information about a block disconnected by a chain reorganization
+type StaleBlock struct {
+	Height uint32 `json:"height"`
+	Hash   string `json:"hash"`
+	Time   int64  `json:"time,omitempty"`
+	Txs    uint32 `json:"txCount"`
+	Size   uint32 `json:"size"`
+}
+
+// ChainTip contains the tip of the active chain or of a branch of stale blocks
+type ChainTip struct {
+	Height           uint32       `json:"height"`
+	Hash             string       `json:"hash"`
+	BranchLen        uint32       `json:"branchLen"`
+	Status           string       `json:"status"`
+	DisconnectedTime int64        `json:"disconnectedTime,omitempty"`
+	Blocks           []StaleBlock `json:"blocks,omitempty"`
+}
+
+// ChainTips contains the tip of the active chain followed by the tips of the stale branches
+type ChainTips struct {
+	Tips []ChainTip `json:"tips"`
 }
 
 // Paging contains information about paging for address, blocks and block
@@ -283,6 +441,7 @@ type Utxo struct {
 	Path          string  `json:"path,omitempty"`
 	Locktime      uint32  `json:"lockTime,omitempty"`
 	Coinbase      bool    `json:"coinbase,omitempty"`
+	SpentTxID     string  `json:"spentTxId,omitempty"`
 }
 
 // Utxos is array of Utxo
@@ -314,6 +473,19 @@ type BalanceHistory struct {
 	Txid          string             `json:"txid,omitempty"`
 }
 
+// BalanceAt contains the confirmed balance and unspent outputs of an address or xpub after the block at given height
+type BalanceAt struct {
+	Address     string  `json:"address"`
+	Height      uint32  `json:"height"`
+	BlockHash   string  `json:"blockHash"`
+	BlockTime   int64   `json:"blockTime"`
+	Txs         int     `json:"txs"`
+	ReceivedSat *Amount `json:"received"`
+	SentSat     *Amount `json:"sent"`
+	BalanceSat  *Amount `json:"balance"`
+	Utxos       Utxos   `json:"utxos"`
+}
+
 // BalanceHistories is array of BalanceHistory
 type BalanceHistories []BalanceHistory
 
@@ -397,8 +569,9 @@ type BlockInfo struct {
 type Block struct {
 	Paging
 	BlockInfo
-	TxCount      int   `json:"txCount"`
-	Transactions []*Tx `json:"txs,omitempty"`
+	TxCount      int          `json:"txCount"`
+	Supply       *SupplyStats `json:"supply,omitempty"`
+	Transactions []*Tx        `json:"txs,omitempty"`
 }
 
 // BlockRaw contains raw block in hex
@@ -425,6 +598,7 @@ type BlockbookInfo struct {
 	DbSize            int64                        `json:"dbSize"`
 	DbSizeFromColumns int64                        `json:"dbSizeFromColumns,omitempty"`
 	DbColumns         []common.InternalStateColumn `json:"dbColumns,omitempty"`
+	Supply            *SupplyStats                 `json:"supply,omitempty"`
 	About             string                       `json:"about"`
 }
 
@@ -440,6 +614,36 @@ type MempoolTxid struct {
 	Txid string `json:"txid"`
 }
 
+// RichListAddress is an address in the list of the addresses with the highest balance
+type RichListAddress struct {
+	Rank         int     `json:"rank"`
+	Address      string  `json:"address"`
+	BalanceSat   *Amount `json:"balance"`
+	SharePercent float64 `json:"sharePercent"`
+	Txs          uint32  `json:"txs"`
+	LastHeight   uint32  `json:"lastHeight"`
+}
+
+// RichList contains the addresses with the highest balance with paging information
+type RichList struct {
+	Paging
+	TotalAddresses  int               `json:"totalAddresses"`
+	TotalBalanceSat *Amount           `json:"totalBalance"`
+	Addresses       []RichListAddress `json:"addresses"`
+}
+
+// TokenHolders contains the holders of an ERC20 token with the highest balance with paging information
+type TokenHolders struct {
+	Paging
+	Contract        string            `json:"contract"`
+	Name            string            `json:"name,omitempty"`
+	Symbol          string            `json:"symbol,omitempty"`
+	Decimals        int               `json:"decimals"`
+	TotalHolders    int               `json:"totalHolders"`
+	TotalBalanceSat *Amount           `json:"totalBalance"`
+	Holders         []RichListAddress `json:"holders"`
+}
+
 // MempoolTxids contains a list of mempool txids with paging information
 type MempoolTxids struct {
 	Paging
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"math/big"
 
 	"github.com/trezor/blockbook/bchain"
@@ -195,17 +196,21 @@ func (w *Worker) AddressToV1(a *Address) *AddressV1 {
 // AddressUtxoToV1 converts []AddressUtxo to []AddressUtxoV1
 func (w *Worker) AddressUtxoToV1(au Utxos) []AddressUtxoV1 {
 	d := w.chainParser.AmountDecimals()
-	v1 := make([]AddressUtxoV1, len(au))
+	v1 := make([]AddressUtxoV1, 0, len(au))
 	for i := range au {
 		utxo := &au[i]
-		v1[i] = AddressUtxoV1{
+		// legacy API does not report outputs spent in mempool
+		if utxo.SpentTxID != "" {
+			continue
+		}
+		v1 = append(v1, AddressUtxoV1{
 			AmountSat:     utxo.AmountSat.AsBigInt(),
 			Amount:        utxo.AmountSat.DecimalString(d),
 			Confirmations: utxo.Confirmations,
 			Height:        utxo.Height,
 			Txid:          utxo.Txid,
 			Vout:          uint32(utxo.Vout),
-		}
+		})
 	}
 	return v1
 }
@@ -219,3 +224,86 @@ func (w *Worker) BlockToV1(b *Block) *BlockV1 {
 		TxCount:      b.TxCount,
 	}
 }
+
+// AddressesUtxoV1 is used for legacy api v1, it is the unspent output of one of the requested addresses
+type AddressesUtxoV1 struct {
+	Address       string      `json:"address"`
+	Txid          string      `json:"txid"`
+	Vout          uint32      `json:"vout"`
+	ScriptPubKey  string      `json:"scriptPubKey"`
+	Amount        json.Number `json:"amount"`
+	AmountSat     big.Int     `json:"satoshis"`
+	Height        int         `json:"height,omitempty"`
+	Confirmations int         `json:"confirmations"`
+	Ts            int64       `json:"ts,omitempty"`
+}
+
+// AddressesTxsV1 is used for legacy api v1, it contains the transactions of multiple addresses
+type AddressesTxsV1 struct {
+	TotalItems int     `json:"totalItems"`
+	From       int     `json:"from"`
+	To         int     `json:"to"`
+	Items      []*TxV1 `json:"items"`
+}
+
+// TxsV1 is used for legacy api v1, it contains a page of transactions of a block or of an address
+type TxsV1 struct {
+	PagesTotal int     `json:"pagesTotal"`
+	Txs        []*TxV1 `json:"txs"`
+}
+
+// InfoV1 is used for legacy api v1, it contains the information about the backend
+type InfoV1 struct {
+	Version         json.Number `json:"version"`
+	ProtocolVersion json.Number `json:"protocolversion"`
+	Blocks          int         `json:"blocks"`
+	TimeOffset      float64     `json:"timeoffset"`
+	Connections     int         `json:"connections"`
+	Proxy           string      `json:"proxy"`
+	Difficulty      json.Number `json:"difficulty"`
+	Testnet         bool        `json:"testnet"`
+	RelayFee        json.Number `json:"relayfee"`
+	Errors          string      `json:"errors"`
+	Network         string      `json:"network"`
+}
+
+// StatusInfoV1 is used for legacy api v1, it is returned by status?q=getInfo
+type StatusInfoV1 struct {
+	Info InfoV1 `json:"info"`
+}
+
+// StatusDifficultyV1 is used for legacy api v1, it is returned by status?q=getDifficulty
+type StatusDifficultyV1 struct {
+	Difficulty json.Number `json:"difficulty"`
+}
+
+// StatusBestBlockHashV1 is used for legacy api v1, it is returned by status?q=getBestBlockHash
+type StatusBestBlockHashV1 struct {
+	BestBlockHash string `json:"bestblockhash"`
+}
+
+// StatusLastBlockHashV1 is used for legacy api v1, it is returned by status?q=getLastBlockHash
+type StatusLastBlockHashV1 struct {
+	SyncTipHash   string `json:"syncTipHash"`
+	LastBlockHash string `json:"lastblockhash"`
+}
+
+// SyncV1 is used for legacy api v1, it contains the synchronization status
+type SyncV1 struct {
+	Status           string  `json:"status"`
+	BlockChainHeight int     `json:"blockChainHeight"`
+	SyncPercentage   int     `json:"syncPercentage"`
+	Height           int     `json:"height"`
+	Error            *string `json:"error"`
+	Type             string  `json:"type"`
+}
+
+// RawTxV1 is used for legacy api v1, it contains the serialized transaction
+type RawTxV1 struct {
+	RawTx string `json:"rawtx"`
+}
+
+// MessageVerifyV1 is used for legacy api v1, it contains the result of the signed message verification
+type MessageVerifyV1 struct {
+	Result bool `json:"result"`
+}
@@ -0,0 +1,360 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcutil/base58"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/db"
+)
+
+const watchGroupIDBytes = 16
+const maxWatchGroupDescriptors = 1000
+
+const (
+	// maxWatchGroups is the maximum number of stored watch groups
+	maxWatchGroups = 100000
+	// maxWatchGroupsPerClient is the number of watch groups one client can create in watchGroupQuotaPeriod
+	maxWatchGroupsPerClient = 20
+	watchGroupQuotaPeriod   = time.Hour
+	// watchGroupTTL is the time after which a watch group which is not used is deleted
+	watchGroupTTL = 90 * 24 * time.Hour
+	// watchGroupTouchPeriod limits how often the last use time of a watch group is stored
+	watchGroupTouchPeriod = 24 * time.Hour
+	// watchGroupPurgePeriod is the period of the deletion of the expired watch groups
+	watchGroupPurgePeriod = time.Hour
+)
+
+type watchGroupQuota struct {
+	since   time.Time
+	created int
+}
+
+// watchGroupLimits is shared by all workers, the watch groups are created by both the REST and the websocket interface
+var watchGroupLimits struct {
+	mux       sync.Mutex
+	count     int
+	lastPurge time.Time
+	clients   map[string]*watchGroupQuota
+}
+
+// purgeWatchGroups deletes the expired watch groups and counts the remaining ones, must be called with the lock held
+func (w *Worker) purgeWatchGroups(now time.Time) error {
+	count, purged, err := w.db.PurgeWatchGroups(now.Add(-watchGroupTTL).Unix())
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		glog.Info("purgeWatchGroups: deleted ", purged, " expired watch groups, ", count, " remaining")
+	}
+	watchGroupLimits.count = count
+	watchGroupLimits.lastPurge = now
+	for client, q := range watchGroupLimits.clients {
+		if now.Sub(q.since) > watchGroupQuotaPeriod {
+			delete(watchGroupLimits.clients, client)
+		}
+	}
+	return nil
+}
+
+// reserveWatchGroup checks the global and the per client limit of the watch groups and reserves a place for a new group
+func (w *Worker) reserveWatchGroup(client string) error {
+	if host, _, err := net.SplitHostPort(client); err == nil {
+		client = host
+	}
+	now := time.Now()
+	watchGroupLimits.mux.Lock()
+	defer watchGroupLimits.mux.Unlock()
+	if watchGroupLimits.clients == nil {
+		watchGroupLimits.clients = make(map[string]*watchGroupQuota)
+	}
+	if now.Sub(watchGroupLimits.lastPurge) > watchGroupPurgePeriod {
+		if err := w.purgeWatchGroups(now); err != nil {
+			return err
+		}
+	}
+	q := watchGroupLimits.clients[client]
+	if q == nil || now.Sub(q.since) > watchGroupQuotaPeriod {
+		q = &watchGroupQuota{since: now}
+		watchGroupLimits.clients[client] = q
+	}
+	if q.created >= maxWatchGroupsPerClient {
+		return NewAPIError(fmt.Sprintf("Too many watch groups created, try again in %v", q.since.Add(watchGroupQuotaPeriod).Sub(now).Round(time.Minute)), true)
+	}
+	if watchGroupLimits.count >= maxWatchGroups {
+		return NewAPIError("Too many watch groups", true)
+	}
+	q.created++
+	watchGroupLimits.count++
+	return nil
+}
+
+// watchGroupDescriptorKey returns a key identifying the addresses described by the descriptor,
+// so that the same xpub given in different forms (version prefix, descriptor syntax, key order of sortedmulti) is stored only once
+func (w *Worker) watchGroupDescriptorKey(d string) (string, error) {
+	xd, err := w.chainParser.ParseXpub(d)
+	if err != nil {
+		addrDesc, err := w.chainParser.GetAddrDescFromAddress(d)
+		if err != nil {
+			return "", err
+		}
+		return "a:" + string(addrDesc), nil
+	}
+	xpubs := xd.Xpubs
+	if len(xpubs) == 0 {
+		xpubs = []string{xd.Xpub}
+	}
+	keys := make([]string, len(xpubs))
+	for i, x := range xpubs {
+		// strip the version and the checksum, the rest identifies the extended key
+		b := base58.Decode(x)
+		if len(b) <= 8 {
+			return "", errors.Errorf("Invalid xpub %v", x)
+		}
+		keys[i] = string(b[4 : len(b)-4])
+	}
+	if xd.SortedKeys {
+		sort.Strings(keys)
+	}
+	return fmt.Sprintf("x:%d:%d:%t:%v:%q", xd.Type, xd.RequiredSigs, xd.SortedKeys, xd.ChangeIndexes, keys), nil
+}
+
+// CreateWatchGroup validates the descriptors (addresses or xpubs) and stores them as a new watch group,
+// the number of groups created by one client is limited
+func (w *Worker) CreateWatchGroup(descriptors []string, client string) (*db.WatchGroup, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	if len(descriptors) == 0 {
+		return nil, NewAPIError("Missing descriptors", true)
+	}
+	if len(descriptors) > maxWatchGroupDescriptors {
+		return nil, NewAPIError(fmt.Sprintf("Too many descriptors, maximum is %d", maxWatchGroupDescriptors), true)
+	}
+	unique := make(map[string]struct{}, len(descriptors))
+	now := time.Now().Unix()
+	group := db.WatchGroup{
+		Descriptors: make([]string, 0, len(descriptors)),
+		Created:     now,
+		LastUsed:    now,
+	}
+	for _, d := range descriptors {
+		key, err := w.watchGroupDescriptorKey(d)
+		if err != nil {
+			return nil, NewAPIError(fmt.Sprintf("Invalid descriptor '%v'", d), true)
+		}
+		if _, found := unique[key]; found {
+			continue
+		}
+		unique[key] = struct{}{}
+		group.Descriptors = append(group.Descriptors, d)
+	}
+	if err := w.reserveWatchGroup(client); err != nil {
+		return nil, err
+	}
+	b := make([]byte, watchGroupIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	group.ID = hex.EncodeToString(b)
+	if err := w.db.StoreWatchGroup(&group); err != nil {
+		return nil, err
+	}
+	glog.Info("CreateWatchGroup ", group.ID, ", ", len(group.Descriptors), " descriptors, client ", client)
+	return &group, nil
+}
+
+// GetWatchGroup returns the stored watch group, the expired group is deleted and reported as not found
+func (w *Worker) GetWatchGroup(id string) (*db.WatchGroup, error) {
+	group, err := w.db.GetWatchGroup(id)
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetWatchGroup %v", id)
+	}
+	now := time.Now()
+	if group != nil && now.Sub(time.Unix(group.LastUsedTime(), 0)) > watchGroupTTL {
+		if err = w.db.DeleteWatchGroup(id); err != nil {
+			glog.Warning("GetWatchGroup ", id, ", delete expired group: ", err)
+		}
+		group = nil
+	}
+	if group == nil {
+		return nil, NewAPIError(fmt.Sprintf("Watch group '%v' not found", id), true)
+	}
+	if now.Sub(time.Unix(group.LastUsedTime(), 0)) > watchGroupTouchPeriod {
+		group.LastUsed = now.Unix()
+		// the read only replica cannot store the group, the group expires unless it is used on the primary
+		if err = w.db.StoreWatchGroup(group); err != nil {
+			glog.Warning("GetWatchGroup ", id, ", store last use: ", err)
+		}
+	}
+	return group, nil
+}
+
+// DeleteWatchGroup removes the watch group
+func (w *Worker) DeleteWatchGroup(id string) error {
+	if _, err := w.GetWatchGroup(id); err != nil {
+		return err
+	}
+	if err := w.db.DeleteWatchGroup(id); err != nil {
+		return err
+	}
+	watchGroupLimits.mux.Lock()
+	if watchGroupLimits.count > 0 {
+		watchGroupLimits.count--
+	}
+	watchGroupLimits.mux.Unlock()
+	return nil
+}
+
+// getWatchGroupData returns the xpub data of all xpubs of the group and one additional set of the group addresses,
+// the addresses which are also derived from any of the xpubs of the group are not added again
+func (w *Worker) getWatchGroupData(group *db.WatchGroup, page int, txsOnPage int, option AccountDetails, filter *AddressFilter, gap int) ([]*xpubData, uint32, error) {
+	var (
+		bestheight uint32
+		err        error
+		addrDescs  []bchain.AddressDescriptor
+	)
+	datas := make([]*xpubData, 0, len(group.Descriptors)+1)
+	for _, d := range group.Descriptors {
+		xd, errXpub := w.chainParser.ParseXpub(d)
+		if errXpub == nil {
+			data, bh, _, err := w.getXpubData(xd, page, txsOnPage, option, filter, gap)
+			if err != nil {
+				return nil, 0, err
+			}
+			if bh > bestheight {
+				bestheight = bh
+			}
+			datas = append(datas, data)
+			continue
+		}
+		addrDesc, err := w.chainParser.GetAddrDescFromAddress(d)
+		if err != nil {
+			return nil, 0, NewAPIError(fmt.Sprintf("Invalid descriptor '%v' in watch group", d), true)
+		}
+		addrDescs = append(addrDescs, addrDesc)
+	}
+	if len(datas) == 0 {
+		if bestheight, _, err = w.db.GetBestBlock(); err != nil {
+			return nil, 0, errors.Annotatef(err, "GetBestBlock")
+		}
+	}
+	if len(addrDescs) > 0 {
+		known := make(map[string]struct{})
+		for _, data := range datas {
+			for _, da := range data.addresses {
+				for i := range da {
+					known[string(da[i].addrDesc)] = struct{}{}
+				}
+			}
+		}
+		addresses := make([]xpubAddress, 0, len(addrDescs))
+		data := xpubData{
+			dataHeight: bestheight,
+			balanceSat: *new(big.Int),
+			sentSat:    *new(big.Int),
+		}
+		for _, addrDesc := range addrDescs {
+			if _, found := known[string(addrDesc)]; found {
+				continue
+			}
+			known[string(addrDesc)] = struct{}{}
+			ad := xpubAddress{addrDesc: addrDesc}
+			if _, err = w.xpubDerivedAddressBalance(&data, &ad); err != nil {
+				return nil, 0, err
+			}
+			if option >= AccountDetailsTxidHistory {
+				if err = w.xpubCheckAndLoadTxids(&ad, filter, bestheight, (page+1)*txsOnPage); err != nil {
+					return nil, 0, err
+				}
+			}
+			addresses = append(addresses, ad)
+		}
+		data.addresses = [][]xpubAddress{addresses}
+		datas = append(datas, &data)
+	}
+	return datas, bestheight, nil
+}
+
+// GetWatchGroupAddress computes the aggregated value and gets transactions of all addresses and xpubs in the watch group
+func (w *Worker) GetWatchGroupAddress(id string, page int, txsOnPage int, option AccountDetails, filter *AddressFilter, gap int) (*Address, error) {
+	start := time.Now()
+	page--
+	if page < 0 {
+		page = 0
+	}
+	group, err := w.GetWatchGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	datas, bestheight, err := w.getWatchGroupData(group, page, txsOnPage, option, filter, gap)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := w.getXpubsAddress(id, datas, bestheight, page, txsOnPage, option, filter)
+	if err != nil {
+		return nil, err
+	}
+	glog.Info("GetWatchGroupAddress ", id, ", ", len(group.Descriptors), " descriptors, ", addr.Txs, " txs, ", time.Since(start))
+	return addr, nil
+}
+
+// GetWatchGroupUtxo returns unspent outputs of all addresses and xpubs in the watch group,
+// the outputs spent in mempool are returned only if pendingSpent is true
+func (w *Worker) GetWatchGroupUtxo(id string, onlyConfirmed, pendingSpent bool, gap int) (Utxos, error) {
+	start := time.Now()
+	group, err := w.GetWatchGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	datas, _, err := w.getWatchGroupData(group, 0, 1, AccountDetailsBasic, &AddressFilter{
+		Vout:          AddressFilterVoutOff,
+		OnlyConfirmed: onlyConfirmed,
+	}, gap)
+	if err != nil {
+		return nil, err
+	}
+	r, err := w.getXpubsUtxo(datas, onlyConfirmed, pendingSpent)
+	if err != nil {
+		return nil, err
+	}
+	glog.Info("GetWatchGroupUtxo ", id, ", ", len(r), " utxos, ", time.Since(start))
+	return r, nil
+}
+
+// GetWatchGroupBalanceHistory returns history of the aggregated balance of the watch group
+func (w *Worker) GetWatchGroupBalanceHistory(id string, fromTimestamp, toTimestamp int64, currencies []string, gap int, groupBy uint32) (BalanceHistories, error) {
+	start := time.Now()
+	group, err := w.GetWatchGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	fromUnix, fromHeight, toUnix, toHeight := w.balanceHistoryHeightsFromTo(fromTimestamp, toTimestamp)
+	if fromHeight >= toHeight {
+		return make(BalanceHistories, 0), nil
+	}
+	datas, _, err := w.getWatchGroupData(group, 0, 1, AccountDetailsTxidHistory, &AddressFilter{
+		Vout:          AddressFilterVoutOff,
+		OnlyConfirmed: true,
+		FromHeight:    fromHeight,
+		ToHeight:      toHeight,
+	}, gap)
+	if err != nil {
+		return nil, err
+	}
+	bha, err := w.getXpubsBalanceHistory(datas, fromUnix, toUnix, currencies, groupBy)
+	if err != nil {
+		return nil, err
+	}
+	glog.Info("GetWatchGroupBalanceHistory ", id, ", blocks ", fromHeight, "-", toHeight, ", count ", len(bha), ", ", time.Since(start))
+	return bha, nil
+}
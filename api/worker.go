@@ -10,7 +10,6 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/golang/glog"
@@ -21,16 +20,20 @@ import (
 	"github.com/trezor/blockbook/db"
 )
 
+// maximum number of blocks returned by GetFeeStatsRange
+const maxFeeStatsRange = 10000
+
 // Worker is handle to api worker
 type Worker struct {
-	db          *db.RocksDB
-	txCache     *db.TxCache
-	chain       bchain.BlockChain
-	chainParser bchain.BlockChainParser
-	chainType   bchain.ChainType
-	mempool     bchain.Mempool
-	is          *common.InternalState
-	metrics     *common.Metrics
+	db           *db.RocksDB
+	txCache      *db.TxCache
+	chain        bchain.BlockChain
+	chainParser  bchain.BlockChainParser
+	chainType    bchain.ChainType
+	mempool      bchain.Mempool
+	is           *common.InternalState
+	metrics      *common.Metrics
+	feeEstimator FeeEstimator
 }
 
 // NewWorker creates new api worker
@@ -47,6 +50,9 @@ func NewWorker(db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool,
 	}
 	if w.chainType == bchain.ChainBitcoinType {
 		w.initXpubCache()
+		if err := w.initFeeEstimator(); err != nil {
+			return nil, err
+		}
 	}
 	return w, nil
 }
@@ -97,6 +103,29 @@ func (w *Worker) setSpendingTxToVout(vout *Vout, txid string, height uint32) err
 	return err
 }
 
+// setMempoolSpendingTxToVout is helper function, that finds mempool transaction that spends given output and sets it to the output
+// returns false if the output is not spent in mempool
+func (w *Worker) setMempoolSpendingTxToVout(vout *Vout, txid string) bool {
+	spendingTxid := w.mempool.GetSpendingTxid(bchain.Outpoint{Txid: txid, Vout: int32(vout.N)})
+	if spendingTxid == "" {
+		return false
+	}
+	vout.Spent = true
+	vout.SpentTxID = spendingTxid
+	spendingTx, _, err := w.txCache.GetTransaction(spendingTxid)
+	if err != nil {
+		glog.Warning("Mempool tx ", spendingTxid, ": not found")
+		return true
+	}
+	for i := range spendingTx.Vin {
+		if spendingTx.Vin[i].Txid == txid && spendingTx.Vin[i].Vout == uint32(vout.N) {
+			vout.SpentIndex = i
+			break
+		}
+	}
+	return true
+}
+
 // GetSpendingTxid returns transaction id of transaction that spent given output
 func (w *Worker) GetSpendingTxid(txid string, n int) (string, error) {
 	start := time.Now()
@@ -107,9 +136,11 @@ func (w *Worker) GetSpendingTxid(txid string, n int) (string, error) {
 	if n >= len(tx.Vout) || n < 0 {
 		return "", NewAPIError(fmt.Sprintf("Passed incorrect vout index %v for tx %v, len vout %v", n, tx.Txid, len(tx.Vout)), false)
 	}
-	err = w.setSpendingTxToVout(&tx.Vout[n], tx.Txid, uint32(tx.Blockheight))
-	if err != nil {
-		return "", err
+	if w.chainType != bchain.ChainBitcoinType || !w.setMempoolSpendingTxToVout(&tx.Vout[n], tx.Txid) {
+		err = w.setSpendingTxToVout(&tx.Vout[n], tx.Txid, uint32(tx.Blockheight))
+		if err != nil {
+			return "", err
+		}
 	}
 	glog.Info("GetSpendingTxid ", txid, " ", n, ", ", time.Since(start))
 	return tx.Vout[n].SpentTxID, nil
@@ -119,6 +150,9 @@ func (w *Worker) GetSpendingTxid(txid string, n int) (string, error) {
 func (w *Worker) GetTransaction(txid string, spendingTxs bool, specificJSON bool) (*Tx, error) {
 	bchainTx, height, err := w.txCache.GetTransaction(txid)
 	if err != nil {
+		if replacedBy, _ := w.mempool.GetTxReplacement(txid); replacedBy != "" {
+			return nil, NewAPIError(fmt.Sprintf("Transaction '%v' not found, replaced by '%v'", txid, replacedBy), true)
+		}
 		if err == bchain.ErrTxNotFound {
 			return nil, NewAPIError(fmt.Sprintf("Transaction '%v' not found", txid), true)
 		}
@@ -127,6 +161,19 @@ func (w *Worker) GetTransaction(txid string, spendingTxs bool, specificJSON bool
 	return w.GetTransactionFromBchainTx(bchainTx, height, spendingTxs, specificJSON)
 }
 
+// GetTransactionWithMempoolFees reads transaction data from txid, the mempool transaction of Bitcoin-type coin gets also its fee information;
+// it costs an additional backend call, therefore it is meant only for the endpoints returning a single transaction
+func (w *Worker) GetTransactionWithMempoolFees(txid string, spendingTxs bool) (*Tx, error) {
+	tx, err := w.GetTransaction(txid, spendingTxs, false)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Confirmations == 0 && w.chainType == bchain.ChainBitcoinType {
+		tx.MempoolFees = w.getMempoolTxFees(txid)
+	}
+	return tx, nil
+}
+
 // GetTransactionFromBchainTx reads transaction data from txid
 func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spendingTxs bool, specificJSON bool) (*Tx, error) {
 	var err error
@@ -148,6 +195,9 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 	}
 	var valInSat, valOutSat, feesSat big.Int
 	var pValInSat *big.Int
+	var size int
+	var vsize, weight int64
+	var feeRate float64
 	vins := make([]Vin, len(bchainTx.Vin))
 	rbf := false
 	for i := range bchainTx.Vin {
@@ -225,6 +275,9 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 			}
 		}
 	}
+	if w.chainType == bchain.ChainBitcoinType {
+		w.setTaprootToVins(bchainTx, vins)
+	}
 	vouts := make([]Vout, len(bchainTx.Vout))
 	for i := range bchainTx.Vout {
 		bchainVout := &bchainTx.Vout[i]
@@ -246,6 +299,10 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 				}
 			}
 		}
+		// output can be spent by a mempool transaction
+		if spendingTxs && !vout.Spent && w.chainType == bchain.ChainBitcoinType {
+			w.setMempoolSpendingTxToVout(vout, bchainTx.Txid)
+		}
 	}
 	if w.chainType == bchain.ChainBitcoinType {
 		// for coinbase transactions valIn is 0
@@ -254,6 +311,15 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 			feesSat.SetUint64(0)
 		}
 		pValInSat = &valInSat
+		size = len(bchainTx.Hex) / 2
+		vsize, weight = bchainTx.VSize, bchainTx.Weight
+		if vsize == 0 && weight > 0 {
+			vsize = (weight + 3) / 4
+		}
+		// the fee rate in sat/vB, not defined for coinbase transactions
+		if vsize > 0 && valInSat.Sign() > 0 {
+			feeRate = feePerVByte(&feesSat, uint32(vsize))
+		}
 	} else if w.chainType == bchain.ChainEthereumType {
 		ets, err := w.chainParser.EthereumTypeGetErc20FromTx(bchainTx)
 		if err != nil {
@@ -277,8 +343,6 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 			Data:     ethTxData.Data,
 		}
 	}
-	// for now do not return size, we would have to compute vsize of segwit transactions
-	// size:=len(bchainTx.Hex) / 2
 	var sj json.RawMessage
 	// return CoinSpecificData for all mempool transactions or if requested
 	if specificJSON || bchainTx.Confirmations == 0 {
@@ -287,15 +351,22 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 			return nil, err
 		}
 	}
-	// for mempool transaction get first seen time
+	// for mempool transaction get first seen time and replacements
+	var replacedBy string
+	var replaces []string
 	if bchainTx.Confirmations == 0 {
 		bchainTx.Blocktime = int64(w.mempool.GetTransactionTime(bchainTx.Txid))
+		replacedBy, replaces = w.mempool.GetTxReplacement(bchainTx.Txid)
 	}
 	r := &Tx{
 		Blockhash:        blockhash,
 		Blockheight:      height,
 		Blocktime:        bchainTx.Blocktime,
 		Confirmations:    bchainTx.Confirmations,
+		Size:             size,
+		VSize:            vsize,
+		Weight:           weight,
+		FeeRate:          feeRate,
 		FeesSat:          (*Amount)(&feesSat),
 		Locktime:         bchainTx.LockTime,
 		Txid:             bchainTx.Txid,
@@ -304,6 +375,8 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 		Version:          bchainTx.Version,
 		Hex:              bchainTx.Hex,
 		Rbf:              rbf,
+		ReplacedBy:       replacedBy,
+		Replaces:         replaces,
 		Vin:              vins,
 		Vout:             vouts,
 		CoinSpecificData: sj,
@@ -313,6 +386,25 @@ func (w *Worker) GetTransactionFromBchainTx(bchainTx *bchain.Tx, height int, spe
 	return r, nil
 }
 
+// setTaprootToVins decodes the witness of the inputs spending taproot outputs, the vins must have the AddrDesc set
+func (w *Worker) setTaprootToVins(bchainTx *bchain.Tx, vins []Vin) {
+	if bchainTx.Hex == "" {
+		return
+	}
+	addrDescs := make([]bchain.AddressDescriptor, len(vins))
+	for i := range vins {
+		addrDescs[i] = vins[i].AddrDesc
+	}
+	ts, err := w.chainParser.DecodeTaprootSpends(bchainTx, addrDescs)
+	if err != nil {
+		glog.V(1).Info("DecodeTaprootSpends error ", err)
+		return
+	}
+	for i := range ts {
+		vins[i].Taproot = ts[i]
+	}
+}
+
 // GetTransactionFromMempoolTx converts bchain.MempoolTx to Tx, with limited amount of data
 // it is not doing any request to backend or to db
 func (w *Worker) GetTransactionFromMempoolTx(mempoolTx *bchain.MempoolTx) (*Tx, error) {
@@ -392,6 +484,7 @@ func (w *Worker) GetTransactionFromMempoolTx(mempoolTx *bchain.MempoolTx) (*Tx,
 			Data:     ethTxData.Data,
 		}
 	}
+	replacedBy, replaces := w.mempool.GetTxReplacement(mempoolTx.Txid)
 	r := &Tx{
 		Blocktime:        mempoolTx.Blocktime,
 		FeesSat:          (*Amount)(&feesSat),
@@ -402,6 +495,8 @@ func (w *Worker) GetTransactionFromMempoolTx(mempoolTx *bchain.MempoolTx) (*Tx,
 		Version:          mempoolTx.Version,
 		Hex:              mempoolTx.Hex,
 		Rbf:              rbf,
+		ReplacedBy:       replacedBy,
+		Replaces:         replaces,
 		Vin:              vins,
 		Vout:             vouts,
 		TokenTransfers:   tokens,
@@ -1202,14 +1297,14 @@ func (w *Worker) waitForBackendSync() {
 	}
 }
 
-func (w *Worker) getAddrDescUtxo(addrDesc bchain.AddressDescriptor, ba *db.AddrBalance, onlyConfirmed bool, onlyMempool bool) (Utxos, error) {
+// getAddrDescUtxo returns unspent outputs of the address descriptor, the outputs spent by mempool transactions
+// are returned with SpentTxID set only if pendingSpent is true, otherwise they are left out
+func (w *Worker) getAddrDescUtxo(addrDesc bchain.AddressDescriptor, ba *db.AddrBalance, onlyConfirmed, onlyMempool, pendingSpent bool) (Utxos, error) {
 	w.waitForBackendSync()
 	var err error
 	utxos := make(Utxos, 0, 8)
 	// store txids from mempool so that they are not added twice in case of import of new block while processing utxos, issue #275
 	inMempool := make(map[string]struct{})
-	// outputs could be spent in mempool, record and check mempool spends
-	spentInMempool := make(map[string]struct{})
 	if !onlyConfirmed {
 		// get utxo from mempool
 		txm, err := w.getAddressTxids(addrDesc, true, &AddressFilter{Vout: AddressFilterVoutOff}, maxInt)
@@ -1217,45 +1312,34 @@ func (w *Worker) getAddrDescUtxo(addrDesc bchain.AddressDescriptor, ba *db.AddrB
 			return nil, err
 		}
 		if len(txm) > 0 {
-			mc := make([]*bchain.Tx, len(txm))
-			for i, txid := range txm {
-				// get mempool txs and process their inputs to detect spends between mempool txs
+			for _, txid := range txm {
 				bchainTx, _, err := w.txCache.GetTransaction(txid)
 				// mempool transaction may fail
 				if err != nil {
 					glog.Error("GetTransaction in mempool ", txid, ": ", err)
-				} else {
-					mc[i] = bchainTx
-					// get outputs spent by the mempool tx
-					for i := range bchainTx.Vin {
-						vin := &bchainTx.Vin[i]
-						spentInMempool[vin.Txid+strconv.Itoa(int(vin.Vout))] = struct{}{}
-					}
+					continue
 				}
-			}
-			for _, bchainTx := range mc {
-				if bchainTx != nil {
-					for i := range bchainTx.Vout {
-						vout := &bchainTx.Vout[i]
-						vad, err := w.chainParser.GetAddrDescFromVout(vout)
-						if err == nil && bytes.Equal(addrDesc, vad) {
-							// report only outpoints that are not spent in mempool
-							_, e := spentInMempool[bchainTx.Txid+strconv.Itoa(i)]
-							if !e {
-								coinbase := false
-								if len(bchainTx.Vin) == 1 && len(bchainTx.Vin[0].Coinbase) > 0 {
-									coinbase = true
-								}
-								utxos = append(utxos, Utxo{
-									Txid:      bchainTx.Txid,
-									Vout:      int32(i),
-									AmountSat: (*Amount)(&vout.ValueSat),
-									Locktime:  bchainTx.LockTime,
-									Coinbase:  coinbase,
-								})
-								inMempool[bchainTx.Txid] = struct{}{}
-							}
+				for i := range bchainTx.Vout {
+					vout := &bchainTx.Vout[i]
+					vad, err := w.chainParser.GetAddrDescFromVout(vout)
+					if err == nil && bytes.Equal(addrDesc, vad) {
+						coinbase := false
+						if len(bchainTx.Vin) == 1 && len(bchainTx.Vin[0].Coinbase) > 0 {
+							coinbase = true
 						}
+						// outpoints spent in mempool are reported as pending spent, with the spending txid
+						spentTxID := w.mempool.GetSpendingTxid(bchain.Outpoint{Txid: bchainTx.Txid, Vout: int32(i)})
+						if spentTxID == "" || pendingSpent {
+							utxos = append(utxos, Utxo{
+								Txid:      bchainTx.Txid,
+								Vout:      int32(i),
+								AmountSat: (*Amount)(&vout.ValueSat),
+								Locktime:  bchainTx.LockTime,
+								Coinbase:  coinbase,
+								SpentTxID: spentTxID,
+							})
+						}
+						inMempool[bchainTx.Txid] = struct{}{}
 					}
 				}
 			}
@@ -1285,32 +1369,35 @@ func (w *Worker) getAddrDescUtxo(addrDesc bchain.AddressDescriptor, ba *db.AddrB
 				if err != nil {
 					return nil, err
 				}
-				_, e := spentInMempool[txid+strconv.Itoa(int(utxo.Vout))]
-				if !e {
-					confirmations := bestheight - int(utxo.Height) + 1
-					coinbase := false
-					// for performance reasons, check coinbase transactions only in minimum confirmantion range
-					if confirmations < w.chainParser.MinimumCoinbaseConfirmations() {
-						ta, err := w.db.GetTxAddresses(txid)
-						if err != nil {
-							return nil, err
-						}
-						if len(ta.Inputs) == 1 && len(ta.Inputs[0].AddrDesc) == 0 && IsZeroBigInt(&ta.Inputs[0].ValueSat) {
-							coinbase = true
-						}
+				confirmations := bestheight - int(utxo.Height) + 1
+				coinbase := false
+				// for performance reasons, check coinbase transactions only in minimum confirmantion range
+				if confirmations < w.chainParser.MinimumCoinbaseConfirmations() {
+					ta, err := w.db.GetTxAddresses(txid)
+					if err != nil {
+						return nil, err
 					}
-					_, e = inMempool[txid]
-					if !e {
-						utxos = append(utxos, Utxo{
-							Txid:          txid,
-							Vout:          utxo.Vout,
-							AmountSat:     (*Amount)(&utxo.ValueSat),
-							Height:        int(utxo.Height),
-							Confirmations: confirmations,
-							Coinbase:      coinbase,
-						})
+					if len(ta.Inputs) == 1 && len(ta.Inputs[0].AddrDesc) == 0 && IsZeroBigInt(&ta.Inputs[0].ValueSat) {
+						coinbase = true
 					}
 				}
+				_, e := inMempool[txid]
+				var spentTxID string
+				// outputs could be spent in mempool, report them as pending spent
+				if !e && !onlyConfirmed {
+					spentTxID = w.mempool.GetSpendingTxid(bchain.Outpoint{Txid: txid, Vout: utxo.Vout})
+				}
+				if !e && (spentTxID == "" || pendingSpent) {
+					utxos = append(utxos, Utxo{
+						Txid:          txid,
+						Vout:          utxo.Vout,
+						AmountSat:     (*Amount)(&utxo.ValueSat),
+						Height:        int(utxo.Height),
+						Confirmations: confirmations,
+						Coinbase:      coinbase,
+						SpentTxID:     spentTxID,
+					})
+				}
 				checksum.Sub(&checksum, &utxo.ValueSat)
 			}
 			if checksum.Uint64() != 0 {
@@ -1321,8 +1408,8 @@ func (w *Worker) getAddrDescUtxo(addrDesc bchain.AddressDescriptor, ba *db.AddrB
 	return utxos, nil
 }
 
-// GetAddressUtxo returns unspent outputs for given address
-func (w *Worker) GetAddressUtxo(address string, onlyConfirmed bool) (Utxos, error) {
+// GetAddressUtxo returns unspent outputs for given address, the outputs spent in mempool are returned only if pendingSpent is true
+func (w *Worker) GetAddressUtxo(address string, onlyConfirmed, pendingSpent bool) (Utxos, error) {
 	if w.chainType != bchain.ChainBitcoinType {
 		return nil, NewAPIError("Not supported", true)
 	}
@@ -1331,7 +1418,7 @@ func (w *Worker) GetAddressUtxo(address string, onlyConfirmed bool) (Utxos, erro
 	if err != nil {
 		return nil, NewAPIError(fmt.Sprintf("Invalid address '%v', %v", address, err), true)
 	}
-	r, err := w.getAddrDescUtxo(addrDesc, nil, onlyConfirmed, false)
+	r, err := w.getAddrDescUtxo(addrDesc, nil, onlyConfirmed, false, pendingSpent)
 	if err != nil {
 		return nil, err
 	}
@@ -1339,6 +1426,14 @@ func (w *Worker) GetAddressUtxo(address string, onlyConfirmed bool) (Utxos, erro
 	return r, nil
 }
 
+// GetAddrDescUtxo returns unspent outputs of given address descriptor, the outputs spent in mempool have SpentTxID set
+func (w *Worker) GetAddrDescUtxo(addrDesc bchain.AddressDescriptor, onlyConfirmed bool) (Utxos, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	return w.getAddrDescUtxo(addrDesc, nil, onlyConfirmed, false, true)
+}
+
 // GetBlocks returns BlockInfo for blocks on given page
 func (w *Worker) GetBlocks(page int, blocksOnPage int) (*Blocks, error) {
 	start := time.Now()
@@ -1369,6 +1464,60 @@ func (w *Worker) GetBlocks(page int, blocksOnPage int) (*Blocks, error) {
 	return r, nil
 }
 
+// maxStaleBlocks is the maximum number of stale blocks returned by GetChainTips
+const maxStaleBlocks = 1000
+
+// GetChainTips returns the tip of the active chain and the tips of the branches of stale blocks,
+// disconnected by the chain reorganizations, ordered by the height of the tip
+func (w *Worker) GetChainTips() (*ChainTips, error) {
+	bestHeight, bestHash, err := w.db.GetBestBlock()
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetBestBlock")
+	}
+	staleBlocks, err := w.db.GetStaleBlocks(maxStaleBlocks)
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetStaleBlocks")
+	}
+	r := &ChainTips{
+		Tips: []ChainTip{{
+			Height: bestHeight,
+			Hash:   bestHash,
+			Status: "active",
+		}},
+	}
+	// blocks disconnected by one reorganization share the fork height and the time of disconnection
+	type branchKey struct {
+		forkHeight       uint32
+		disconnectedTime int64
+	}
+	branches := make(map[branchKey]int)
+	for i := range staleBlocks {
+		sb := &staleBlocks[i]
+		k := branchKey{sb.ForkHeight, sb.DisconnectedTime}
+		t, found := branches[k]
+		if !found {
+			// stale blocks are sorted by height in descending order, the first block of the branch is its tip
+			t = len(r.Tips)
+			branches[k] = t
+			r.Tips = append(r.Tips, ChainTip{
+				Height:           sb.Height,
+				Hash:             sb.Hash,
+				BranchLen:        sb.Height - sb.ForkHeight,
+				Status:           "stale",
+				DisconnectedTime: sb.DisconnectedTime,
+			})
+		}
+		r.Tips[t].Blocks = append(r.Tips[t].Blocks, StaleBlock{
+			Height: sb.Height,
+			Hash:   sb.Hash,
+			Time:   sb.Time,
+			Txs:    sb.Txs,
+			Size:   sb.Size,
+		})
+	}
+	return r, nil
+}
+
 // removeEmpty removes empty strings from a slice
 func removeEmpty(stringSlice []string) []string {
 	var ret []string
@@ -1537,16 +1686,25 @@ func (w *Worker) getBlockInfoFromBlockID(bid string) (*bchain.BlockInfo, error)
 	return bi, err
 }
 
-// GetFeeStats returns statistics about block fees
-func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
-	// txSpecific extends Tx with an additional Size and Vsize info
-	type txSpecific struct {
-		*bchain.Tx
-		Vsize int `json:"vsize,omitempty"`
-		Size  int `json:"size,omitempty"`
+func feeStatsFromDB(height uint32, blockTime int64, s *db.BlockFeeStats) *FeeStats {
+	return &FeeStats{
+		Height:          height,
+		Time:            blockTime,
+		TxCount:         s.TxCount,
+		TotalFeesSat:    (*Amount)(&s.TotalFeesSat),
+		TotalVSize:      s.TotalVSize,
+		AverageFeePerKb: s.AverageFeePerKb,
+		MinFeePerKb:     s.MinFeePerKb,
+		MaxFeePerKb:     s.MaxFeePerKb,
+		DecilesFeePerKb: s.DecilesFeePerKb,
+		SegwitTxCount:   s.SegwitTxCount,
+		TaprootTxCount:  s.TaprootTxCount,
 	}
+}
 
-	start := time.Now()
+// GetFeeStats returns statistics about block fees, stored in the index during the block connect
+// or computed from the block transactions for blocks indexed without the fee statistics
+func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
 	bi, err := w.getBlockInfoFromBlockID(bid)
 	if err != nil {
 		if err == bchain.ErrBlockNotFound {
@@ -1554,9 +1712,32 @@ func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
 		}
 		return nil, NewAPIError(fmt.Sprintf("Block not found, %v", err), true)
 	}
+	stored, err := w.db.GetBlockFeeStats(bi.Height)
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetBlockFeeStats")
+	}
+	if stored != nil {
+		// the stored statistics must belong to the requested block, not to a block replaced by a reorg
+		if hash, err := w.db.GetBlockHash(bi.Height); err == nil && hash == bi.Hash {
+			return feeStatsFromDB(bi.Height, bi.Time, stored), nil
+		}
+	}
+	return w.computeFeeStats(bi)
+}
+
+// computeFeeStats computes the fee statistics of the block from the transaction data of the backend
+func (w *Worker) computeFeeStats(bi *bchain.BlockInfo) (*FeeStats, error) {
+	// txSpecific extends Tx with an additional Size info, vsize and weight are part of Tx
+	type txSpecific struct {
+		*bchain.Tx
+		Size int `json:"size,omitempty"`
+	}
+
+	start := time.Now()
 
 	feesPerKb := make([]int64, 0, len(bi.Txids))
 	totalFeesSat := big.NewInt(0)
+	totalVSize := int64(0)
 	averageFeePerKb := int64(0)
 
 	for _, txid := range bi.Txids {
@@ -1567,16 +1748,18 @@ func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
 		}
 
 		// Serialize the raw JSON into TxSpecific struct
-		var txSpec txSpecific
+		txSpec := txSpecific{Tx: &bchain.Tx{}}
 		err = json.Unmarshal(txSpecificJSON, &txSpec)
 		if err != nil {
 			return nil, errors.Annotatef(err, "Unmarshal")
 		}
 
-		// Calculate the TX size in bytes
+		// Calculate the TX virtual size in vbytes, the raw size is used only for backends not reporting vsize nor weight
 		txSize := 0
-		if txSpec.Vsize > 0 {
-			txSize = txSpec.Vsize
+		if txSpec.VSize > 0 {
+			txSize = int(txSpec.VSize)
+		} else if txSpec.Weight > 0 {
+			txSize = int((txSpec.Weight + 3) / 4)
 		} else if txSpec.Size > 0 {
 			txSize = txSpec.Size
 		} else if txSpec.Hex != "" {
@@ -1607,6 +1790,7 @@ func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
 			feeSat = feeSat.Sub(feeSat, &output.ValueSat)
 		}
 		totalFeesSat.Add(totalFeesSat, feeSat)
+		totalVSize += int64(txSize)
 
 		// Convert feeSat to fee per kilobyte and add to an array for decile calculation
 		feePerKb := int64(float64(feeSat.Int64()) / float64(txSize) * 1000)
@@ -1615,6 +1799,7 @@ func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
 	}
 
 	var deciles [11]int64
+	var minFeePerKb, maxFeePerKb int64
 	n := len(feesPerKb)
 
 	if n > 0 {
@@ -1622,6 +1807,8 @@ func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
 
 		// Sort fees and calculate the deciles
 		sort.Slice(feesPerKb, func(i, j int) bool { return feesPerKb[i] < feesPerKb[j] })
+		minFeePerKb = feesPerKb[0]
+		maxFeePerKb = feesPerKb[n-1]
 		for k := 0; k <= 10; k++ {
 			index := int(math.Floor(0.5+float64(k)*float64(n+1)/10)) - 1
 			if index < 0 {
@@ -1633,16 +1820,48 @@ func (w *Worker) GetFeeStats(bid string) (*FeeStats, error) {
 		}
 	}
 
-	glog.Info("GetFeeStats ", bid, " (", len(feesPerKb), " txs), ", time.Since(start))
+	glog.Info("computeFeeStats ", bi.Hash, " (", len(feesPerKb), " txs), ", time.Since(start))
 
 	return &FeeStats{
+		Height:          bi.Height,
+		Time:            bi.Time,
 		TxCount:         len(feesPerKb),
-		AverageFeePerKb: averageFeePerKb,
 		TotalFeesSat:    (*Amount)(totalFeesSat),
+		TotalVSize:      totalVSize,
+		AverageFeePerKb: averageFeePerKb,
+		MinFeePerKb:     minFeePerKb,
+		MaxFeePerKb:     maxFeePerKb,
 		DecilesFeePerKb: deciles,
 	}, nil
 }
 
+// GetFeeStatsRange returns the stored fee statistics of the blocks in the height range from-to (inclusive) as a time series,
+// blocks without stored statistics are omitted
+func (w *Worker) GetFeeStatsRange(from, to int) (*FeeStatsRange, error) {
+	if w.chainType != bchain.ChainBitcoinType {
+		return nil, NewAPIError("Not supported", true)
+	}
+	if from < 0 || to < from {
+		return nil, NewAPIError("Invalid block range", true)
+	}
+	if to-from >= maxFeeStatsRange {
+		return nil, NewAPIError(fmt.Sprintf("Block range too large, maximum is %d blocks", maxFeeStatsRange), true)
+	}
+	r := &FeeStatsRange{
+		From:     from,
+		To:       to,
+		FeeStats: make([]FeeStats, 0),
+	}
+	err := w.db.GetBlockFeeStatsRange(uint32(from), uint32(to), func(height uint32, s *db.BlockFeeStats) error {
+		r.FeeStats = append(r.FeeStats, *feeStatsFromDB(height, int64(w.is.GetBlockTime(height)), s))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetBlockFeeStatsRange")
+	}
+	return r, nil
+}
+
 // GetBlock returns paged data about block
 func (w *Worker) GetBlock(bid string, page int, txsOnPage int) (*Block, error) {
 	start := time.Now()
@@ -1685,6 +1904,10 @@ func (w *Worker) GetBlock(bid string, page int, txsOnPage int) (*Block, error) {
 	}
 	txs = txs[:txi]
 	bi.Txids = nil
+	supply, err := w.getSupplyStats(bi.Height, bi.Hash)
+	if err != nil {
+		return nil, err
+	}
 	glog.Info("GetBlock ", bid, ", page ", page, ", ", time.Since(start))
 	return &Block{
 		Paging: pg,
@@ -1704,6 +1927,7 @@ func (w *Worker) GetBlock(bid string, page int, txsOnPage int) (*Block, error) {
 			Version:       bi.Version,
 		},
 		TxCount:      txCount,
+		Supply:       supply,
 		Transactions: txs,
 	}, nil
 }
@@ -1724,62 +1948,42 @@ func (w *Worker) GetBlockRaw(bid string) (*BlockRaw, error) {
 	return &BlockRaw{Hex: hex}, err
 }
 
-// ComputeFeeStats computes fee distribution in defined blocks and logs them to log
+// ComputeFeeStats computes fee statistics of the blocks in the defined range, stores them to the index and logs them
 func (w *Worker) ComputeFeeStats(blockFrom, blockTo int, stopCompute chan os.Signal) error {
-	bestheight, _, err := w.db.GetBestBlock()
-	if err != nil {
-		return errors.Annotatef(err, "GetBestBlock")
+	if w.chainType != bchain.ChainBitcoinType {
+		return errors.New("Fee stats are supported only for Bitcoin type")
 	}
 	for block := blockFrom; block <= blockTo; block++ {
+		select {
+		case <-stopCompute:
+			glog.Info("ComputeFeeStats interrupted at height ", block)
+			return db.ErrOperationInterrupted
+		default:
+		}
 		hash, err := w.db.GetBlockHash(uint32(block))
 		if err != nil {
 			return err
 		}
-		bi, err := w.chain.GetBlockInfo(hash)
+		b, err := w.chain.GetBlock(hash, uint32(block))
 		if err != nil {
 			return err
 		}
-		// process only blocks with enough transactions
-		if len(bi.Txids) > 20 {
-			dbi := &db.BlockInfo{
-				Hash:   bi.Hash,
-				Height: bi.Height,
-				Time:   bi.Time,
-			}
-			txids := bi.Txids
-			if w.chainType == bchain.ChainBitcoinType {
-				// skip the coinbase transaction
-				txids = txids[1:]
-			}
-			fees := make([]int64, len(txids))
-			sum := int64(0)
-			for i, txid := range txids {
-				select {
-				case <-stopCompute:
-					glog.Info("ComputeFeeStats interrupted at height ", block)
-					return db.ErrOperationInterrupted
-				default:
-					tx, err := w.txFromTxid(txid, bestheight, AccountDetailsTxHistoryLight, dbi)
-					if err != nil {
-						return err
-					}
-					fee := tx.FeesSat.AsInt64()
-					fees[i] = fee
-					sum += fee
-				}
-			}
-			sort.Slice(fees, func(i, j int) bool { return fees[i] < fees[j] })
-			step := float64(len(fees)) / 10
-			percentils := ""
-			for i := float64(0); i < float64(len(fees)+1); i += step {
-				ii := int(math.Round(i))
-				if ii >= len(fees) {
-					ii = len(fees) - 1
-				}
-				percentils += "," + strconv.FormatInt(fees[ii], 10)
+		blockTxAddresses := make([]*db.TxAddresses, len(b.Txs))
+		for i := range b.Txs {
+			blockTxAddresses[i], err = w.db.GetTxAddresses(b.Txs[i].Txid)
+			if err != nil {
+				return err
 			}
-			glog.Info(block, ",", time.Unix(bi.Time, 0).Format(time.RFC3339), ",", len(bi.Txids), ",", sum, ",", float64(sum)/float64(len(bi.Txids)), percentils)
 		}
+		fs := db.ComputeBlockFeeStats(b, blockTxAddresses)
+		if err = w.db.StoreBlockFeeStats(uint32(block), fs); err != nil {
+			return err
+		}
+		percentils := ""
+		for _, d := range fs.DecilesFeePerKb {
+			percentils += "," + strconv.FormatInt(d, 10)
+		}
+		glog.Info(block, ",", time.Unix(b.Time, 0).Format(time.RFC3339), ",", len(b.Txs), ",", fs.TotalFeesSat.String(), ",", fs.AverageFeePerKb, percentils)
 	}
 	return nil
 }
@@ -1806,6 +2010,10 @@ func (w *Worker) GetSystemInfo(internal bool) (*SystemInfo, error) {
 		columnStats = w.is.GetAllDBColumnStats()
 		internalDBSize = w.is.DBSizeTotal()
 	}
+	supply, err := w.getSupplyStats(bestHeight, "")
+	if err != nil {
+		glog.Error("getSupplyStats error ", err)
+	}
 	blockbookInfo := &BlockbookInfo{
 		Coin:              w.is.Coin,
 		Host:              w.is.Host,
@@ -1824,6 +2032,7 @@ func (w *Worker) GetSystemInfo(internal bool) (*SystemInfo, error) {
 		DbSize:            w.db.DatabaseSizeOnDisk(),
 		DbSizeFromColumns: internalDBSize,
 		DbColumns:         columnStats,
+		Supply:            supply,
 		About:             Text.BlockbookAbout,
 	}
 	backendInfo := &common.BackendInfo{
@@ -1868,42 +2077,3 @@ func (w *Worker) GetMempool(page int, itemsOnPage int) (*MempoolTxids, error) {
 	}
 	return r, nil
 }
-
-type bitcoinTypeEstimatedFee struct {
-	timestamp int64
-	fee       big.Int
-	lock      sync.Mutex
-}
-
-const bitcoinTypeEstimatedFeeCacheSize = 300
-
-var bitcoinTypeEstimatedFeeCache [bitcoinTypeEstimatedFeeCacheSize]bitcoinTypeEstimatedFee
-var bitcoinTypeEstimatedFeeConservativeCache [bitcoinTypeEstimatedFeeCacheSize]bitcoinTypeEstimatedFee
-
-func (w *Worker) cachedBitcoinTypeEstimateFee(blocks int, conservative bool, s *bitcoinTypeEstimatedFee) (big.Int, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-	// 10 seconds cache
-	threshold := time.Now().Unix() - 10
-	if s.timestamp >= threshold {
-		return s.fee, nil
-	}
-	fee, err := w.chain.EstimateSmartFee(blocks, conservative)
-	if err == nil {
-		s.timestamp = time.Now().Unix()
-		s.fee = fee
-	}
-	return fee, err
-}
-
-// BitcoinTypeEstimateFee returns a fee estimation for given number of blocks
-// it uses 10 second cache to reduce calls to the backend
-func (w *Worker) BitcoinTypeEstimateFee(blocks int, conservative bool) (big.Int, error) {
-	if blocks >= bitcoinTypeEstimatedFeeCacheSize {
-		return w.chain.EstimateSmartFee(blocks, conservative)
-	}
-	if conservative {
-		return w.cachedBitcoinTypeEstimateFee(blocks, conservative, &bitcoinTypeEstimatedFeeConservativeCache[blocks])
-	}
-	return w.cachedBitcoinTypeEstimateFee(blocks, conservative, &bitcoinTypeEstimatedFeeCache[blocks])
-}
@@ -249,6 +249,23 @@ func (w *Worker) xpubScanAddresses(xd *bchain.XpubDescriptor, data *xpubData, ad
 	return lastUsed, addresses, nil
 }
 
+// xpubNonRangedAddress gets the balance of the only address of the non-ranged descriptor, there is no gap to scan
+func (w *Worker) xpubNonRangedAddress(xd *bchain.XpubDescriptor, data *xpubData, addresses []xpubAddress, fork bool) ([]xpubAddress, error) {
+	if len(addresses) == 0 {
+		descriptors, err := w.chainParser.DeriveAddressDescriptors(xd, 0, []uint32{0})
+		if err != nil {
+			return nil, err
+		}
+		addresses = []xpubAddress{{addrDesc: descriptors[0]}}
+	} else if fork {
+		addresses[0] = xpubAddress{addrDesc: addresses[0].addrDesc}
+	}
+	if _, err := w.xpubDerivedAddressBalance(data, &addresses[0]); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
 func (w *Worker) tokenFromXpubAddress(data *xpubData, ad *xpubAddress, changeIndex int, index int, option AccountDetails) Token {
 	a, _, _ := w.chainParser.GetAddressesFromAddrDesc(ad.addrDesc)
 	var address string
@@ -257,6 +274,15 @@ func (w *Worker) tokenFromXpubAddress(data *xpubData, ad *xpubAddress, changeInd
 	}
 	var balance, totalReceived, totalSent *big.Int
 	var transfers int
+	var path string
+	// addresses of a watch group which are not derived from an xpub do not have a path
+	if data.descriptor != nil {
+		if data.descriptor.NonRanged {
+			path = data.basePath
+		} else {
+			path = fmt.Sprintf("%s/%d/%d", data.basePath, changeIndex, index)
+		}
+	}
 	if ad.balance != nil {
 		transfers = int(ad.balance.Txs)
 		if option >= AccountDetailsTokenBalances {
@@ -273,7 +299,7 @@ func (w *Worker) tokenFromXpubAddress(data *xpubData, ad *xpubAddress, changeInd
 		TotalReceivedSat: (*Amount)(totalReceived),
 		TotalSentSat:     (*Amount)(totalSent),
 		Transfers:        transfers,
-		Path:             fmt.Sprintf("%s/%d/%d", data.basePath, changeIndex, index),
+		Path:             path,
 	}
 }
 
@@ -337,8 +363,12 @@ func (w *Worker) getXpubData(xd *bchain.XpubDescriptor, page int, txsOnPage int,
 		fork := false
 		if !inCache || data.gap != gap {
 			data = xpubData{
-				gap:       gap,
-				addresses: make([][]xpubAddress, len(xd.ChangeIndexes)),
+				descriptor: xd,
+				gap:        gap,
+				addresses:  make([][]xpubAddress, len(xd.ChangeIndexes)),
+			}
+			if xd.NonRanged {
+				data.addresses = make([][]xpubAddress, 1)
 			}
 			data.basePath, err = w.chainParser.DerivationBasePath(xd)
 			if err != nil {
@@ -362,6 +392,12 @@ func (w *Worker) getXpubData(xd *bchain.XpubDescriptor, page int, txsOnPage int,
 			data.sentSat = *new(big.Int)
 			data.txCountEstimate = 0
 			var minDerivedIndex int
+			if xd.NonRanged {
+				data.addresses[0], err = w.xpubNonRangedAddress(xd, &data, data.addresses[0], fork)
+				if err != nil {
+					return nil, 0, inCache, err
+				}
+			}
 			for i, change := range xd.ChangeIndexes {
 				minDerivedIndex, data.addresses[i], err = w.xpubScanAddresses(xd, &data, data.addresses[i], gap, change, minDerivedIndex, fork)
 				if err != nil {
@@ -393,10 +429,25 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 	if page < 0 {
 		page = 0
 	}
-	type mempoolMap struct {
-		tx          *Tx
-		inputOutput byte
+	xd, err := w.chainParser.ParseXpub(xpub)
+	if err != nil {
+		return nil, err
+	}
+	data, bestheight, inCache, err := w.getXpubData(xd, page, txsOnPage, option, filter, gap)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := w.getXpubsAddress(xpub, []*xpubData{data}, bestheight, page, txsOnPage, option, filter)
+	if err != nil {
+		return nil, err
 	}
+	glog.Info("GetXpubAddress ", xpub[:xpubLogPrefix], ", cache ", inCache, ", ", addr.Txs, " txs, ", time.Since(start))
+	return addr, nil
+}
+
+// getXpubsAddress aggregates the data of one or more xpubs (and address sets) to a single Address,
+// page is expected to be already zero based
+func (w *Worker) getXpubsAddress(addrStr string, datas []*xpubData, bestheight uint32, page int, txsOnPage int, option AccountDetails, filter *AddressFilter) (*Address, error) {
 	var (
 		txc            xpubTxids
 		txmMap         map[string]*Tx
@@ -407,15 +458,9 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 		filtered       bool
 		uBalSat        big.Int
 		unconfirmedTxs int
+		balanceSat     big.Int
+		sentSat        big.Int
 	)
-	xd, err := w.chainParser.ParseXpub(xpub)
-	if err != nil {
-		return nil, err
-	}
-	data, bestheight, inCache, err := w.getXpubData(xd, page, txsOnPage, option, filter, gap)
-	if err != nil {
-		return nil, err
-	}
 	// setup filtering of txids
 	var txidFilter func(txid *xpubTxid, ad *xpubAddress) bool
 	if !(filter.FromHeight == 0 && filter.ToHeight == 0 && filter.Vout == AddressFilterVoutOff) {
@@ -441,35 +486,37 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 	if filter.ToHeight == 0 && !filter.OnlyConfirmed {
 		txmMap = make(map[string]*Tx)
 		mempoolEntries := make(bchain.MempoolTxidEntries, 0)
-		for _, da := range data.addresses {
-			for i := range da {
-				ad := &da[i]
-				newTxids, _, err := w.xpubGetAddressTxids(ad.addrDesc, true, 0, 0, maxInt)
-				if err != nil {
-					return nil, err
-				}
-				for _, txid := range newTxids {
-					// the same tx can have multiple addresses from the same xpub, get it from backend it only once
-					tx, foundTx := txmMap[txid.txid]
-					if !foundTx {
-						tx, err = w.GetTransaction(txid.txid, false, true)
-						// mempool transaction may fail
-						if err != nil || tx == nil {
-							glog.Warning("GetTransaction in mempool: ", err)
-							continue
-						}
-						txmMap[txid.txid] = tx
+		for _, data := range datas {
+			for _, da := range data.addresses {
+				for i := range da {
+					ad := &da[i]
+					newTxids, _, err := w.xpubGetAddressTxids(ad.addrDesc, true, 0, 0, maxInt)
+					if err != nil {
+						return nil, err
 					}
-					// skip already confirmed txs, mempool may be out of sync
-					if tx.Confirmations == 0 {
+					for _, txid := range newTxids {
+						// the same tx can have multiple addresses from the same xpub, get it from backend it only once
+						tx, foundTx := txmMap[txid.txid]
 						if !foundTx {
-							unconfirmedTxs++
+							tx, err = w.GetTransaction(txid.txid, false, true)
+							// mempool transaction may fail
+							if err != nil || tx == nil {
+								glog.Warning("GetTransaction in mempool: ", err)
+								continue
+							}
+							txmMap[txid.txid] = tx
 						}
-						uBalSat.Add(&uBalSat, tx.getAddrVoutValue(ad.addrDesc))
-						uBalSat.Sub(&uBalSat, tx.getAddrVinValue(ad.addrDesc))
-						// mempool txs are returned only on the first page, uniquely and filtered
-						if page == 0 && !foundTx && (txidFilter == nil || txidFilter(&txid, ad)) {
-							mempoolEntries = append(mempoolEntries, bchain.MempoolTxidEntry{Txid: txid.txid, Time: uint32(tx.Blocktime)})
+						// skip already confirmed txs, mempool may be out of sync
+						if tx.Confirmations == 0 {
+							if !foundTx {
+								unconfirmedTxs++
+							}
+							uBalSat.Add(&uBalSat, tx.getAddrVoutValue(ad.addrDesc))
+							uBalSat.Sub(&uBalSat, tx.getAddrVinValue(ad.addrDesc))
+							// mempool txs are returned only on the first page, uniquely and filtered
+							if page == 0 && !foundTx && (txidFilter == nil || txidFilter(&txid, ad)) {
+								mempoolEntries = append(mempoolEntries, bchain.MempoolTxidEntry{Txid: txid.txid, Time: uint32(tx.Blocktime)})
+							}
 						}
 					}
 				}
@@ -488,21 +535,23 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 	if option >= AccountDetailsTxidHistory {
 		txcMap := make(map[string]bool)
 		txc = make(xpubTxids, 0, 32)
-		for _, da := range data.addresses {
-			for i := range da {
-				ad := &da[i]
-				for _, txid := range ad.txids {
-					added, foundTx := txcMap[txid.txid]
-					// count txs regardless of filter but only once
-					if !foundTx {
-						txCount++
-					}
-					// add tx only once
-					if !added {
-						add := txidFilter == nil || txidFilter(&txid, ad)
-						txcMap[txid.txid] = add
-						if add {
-							txc = append(txc, txid)
+		for _, data := range datas {
+			for _, da := range data.addresses {
+				for i := range da {
+					ad := &da[i]
+					for _, txid := range ad.txids {
+						added, foundTx := txcMap[txid.txid]
+						// count txs regardless of filter but only once
+						if !foundTx {
+							txCount++
+						}
+						// add tx only once
+						if !added {
+							add := txidFilter == nil || txidFilter(&txid, ad)
+							txcMap[txid.txid] = add
+							if add {
+								txc = append(txc, txid)
+							}
 						}
 					}
 				}
@@ -537,7 +586,9 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 			}
 		}
 	} else {
-		txCount = int(data.txCountEstimate)
+		for _, data := range datas {
+			txCount += int(data.txCountEstimate)
+		}
 	}
 	usedTokens := 0
 	var tokens []Token
@@ -546,32 +597,36 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 		tokens = make([]Token, 0, 4)
 		xpubAddresses = make(map[string]struct{})
 	}
-	for ci, da := range data.addresses {
-		for i := range da {
-			ad := &da[i]
-			if ad.balance != nil {
-				usedTokens++
-			}
-			if option > AccountDetailsBasic {
-				token := w.tokenFromXpubAddress(data, ad, ci, i, option)
-				if filter.TokensToReturn == TokensToReturnDerived ||
-					filter.TokensToReturn == TokensToReturnUsed && ad.balance != nil ||
-					filter.TokensToReturn == TokensToReturnNonzeroBalance && ad.balance != nil && !IsZeroBigInt(&ad.balance.BalanceSat) {
-					tokens = append(tokens, token)
+	for _, data := range datas {
+		balanceSat.Add(&balanceSat, &data.balanceSat)
+		sentSat.Add(&sentSat, &data.sentSat)
+		for ci, da := range data.addresses {
+			for i := range da {
+				ad := &da[i]
+				if ad.balance != nil {
+					usedTokens++
+				}
+				if option > AccountDetailsBasic {
+					token := w.tokenFromXpubAddress(data, ad, ci, i, option)
+					if filter.TokensToReturn == TokensToReturnDerived ||
+						filter.TokensToReturn == TokensToReturnUsed && ad.balance != nil ||
+						filter.TokensToReturn == TokensToReturnNonzeroBalance && ad.balance != nil && !IsZeroBigInt(&ad.balance.BalanceSat) {
+						tokens = append(tokens, token)
+					}
+					xpubAddresses[token.Name] = struct{}{}
 				}
-				xpubAddresses[token.Name] = struct{}{}
 			}
 		}
 	}
 	setIsOwnAddresses(txs, xpubAddresses)
 	var totalReceived big.Int
-	totalReceived.Add(&data.balanceSat, &data.sentSat)
+	totalReceived.Add(&balanceSat, &sentSat)
 	addr := Address{
 		Paging:                pg,
-		AddrStr:               xpub,
-		BalanceSat:            (*Amount)(&data.balanceSat),
+		AddrStr:               addrStr,
+		BalanceSat:            (*Amount)(&balanceSat),
 		TotalReceivedSat:      (*Amount)(&totalReceived),
-		TotalSentSat:          (*Amount)(&data.sentSat),
+		TotalSentSat:          (*Amount)(&sentSat),
 		Txs:                   txCount,
 		UnconfirmedBalanceSat: (*Amount)(&uBalSat),
 		UnconfirmedTxs:        unconfirmedTxs,
@@ -581,12 +636,11 @@ func (w *Worker) GetXpubAddress(xpub string, page int, txsOnPage int, option Acc
 		Tokens:                tokens,
 		XPubAddresses:         xpubAddresses,
 	}
-	glog.Info("GetXpubAddress ", xpub[:xpubLogPrefix], ", cache ", inCache, ", ", txCount, " txs, ", time.Since(start))
 	return &addr, nil
 }
 
-// GetXpubUtxo returns unspent outputs for given xpub
-func (w *Worker) GetXpubUtxo(xpub string, onlyConfirmed bool, gap int) (Utxos, error) {
+// GetXpubUtxo returns unspent outputs for given xpub, the outputs spent in mempool are returned only if pendingSpent is true
+func (w *Worker) GetXpubUtxo(xpub string, onlyConfirmed, pendingSpent bool, gap int) (Utxos, error) {
 	start := time.Now()
 	xd, err := w.chainParser.ParseXpub(xpub)
 	if err != nil {
@@ -599,34 +653,44 @@ func (w *Worker) GetXpubUtxo(xpub string, onlyConfirmed bool, gap int) (Utxos, e
 	if err != nil {
 		return nil, err
 	}
+	r, err := w.getXpubsUtxo([]*xpubData{data}, onlyConfirmed, pendingSpent)
+	if err != nil {
+		return nil, err
+	}
+	glog.Info("GetXpubUtxo ", xpub[:xpubLogPrefix], ", cache ", inCache, ", ", len(r), " utxos,  ", time.Since(start))
+	return r, nil
+}
+
+func (w *Worker) getXpubsUtxo(datas []*xpubData, onlyConfirmed, pendingSpent bool) (Utxos, error) {
 	r := make(Utxos, 0, 8)
-	for ci, da := range data.addresses {
-		for i := range da {
-			ad := &da[i]
-			onlyMempool := false
-			if ad.balance == nil {
-				if onlyConfirmed {
-					continue
+	for _, data := range datas {
+		for ci, da := range data.addresses {
+			for i := range da {
+				ad := &da[i]
+				onlyMempool := false
+				if ad.balance == nil {
+					if onlyConfirmed {
+						continue
+					}
+					onlyMempool = true
 				}
-				onlyMempool = true
-			}
-			utxos, err := w.getAddrDescUtxo(ad.addrDesc, ad.balance, onlyConfirmed, onlyMempool)
-			if err != nil {
-				return nil, err
-			}
-			if len(utxos) > 0 {
-				t := w.tokenFromXpubAddress(data, ad, ci, i, AccountDetailsTokens)
-				for j := range utxos {
-					a := &utxos[j]
-					a.Address = t.Name
-					a.Path = t.Path
+				utxos, err := w.getAddrDescUtxo(ad.addrDesc, ad.balance, onlyConfirmed, onlyMempool, pendingSpent)
+				if err != nil {
+					return nil, err
+				}
+				if len(utxos) > 0 {
+					t := w.tokenFromXpubAddress(data, ad, ci, i, AccountDetailsTokens)
+					for j := range utxos {
+						a := &utxos[j]
+						a.Address = t.Name
+						a.Path = t.Path
+					}
+					r = append(r, utxos...)
 				}
-				r = append(r, utxos...)
 			}
 		}
 	}
 	sort.Stable(r)
-	glog.Info("GetXpubUtxo ", xpub[:xpubLogPrefix], ", cache ", inCache, ", ", len(r), " utxos,  ", time.Since(start))
 	return r, nil
 }
 
@@ -651,32 +715,44 @@ func (w *Worker) GetXpubBalanceHistory(xpub string, fromTimestamp, toTimestamp i
 	if err != nil {
 		return nil, err
 	}
+	bha, err := w.getXpubsBalanceHistory([]*xpubData{data}, fromUnix, toUnix, currencies, groupBy)
+	if err != nil {
+		return nil, err
+	}
+	glog.Info("GetUtxoBalanceHistory ", xpub[:xpubLogPrefix], ", cache ", inCache, ", blocks ", fromHeight, "-", toHeight, ", count ", len(bha), ",  ", time.Since(start))
+	return bha, nil
+}
+
+func (w *Worker) getXpubsBalanceHistory(datas []*xpubData, fromUnix, toUnix uint32, currencies []string, groupBy uint32) (BalanceHistories, error) {
+	bhs := make(BalanceHistories, 0)
 	selfAddrDesc := make(map[string]struct{})
-	for _, da := range data.addresses {
-		for i := range da {
-			selfAddrDesc[string(da[i].addrDesc)] = struct{}{}
+	for _, data := range datas {
+		for _, da := range data.addresses {
+			for i := range da {
+				selfAddrDesc[string(da[i].addrDesc)] = struct{}{}
+			}
 		}
 	}
-	for _, da := range data.addresses {
-		for i := range da {
-			ad := &da[i]
-			txids := ad.txids
-			for txi := len(txids) - 1; txi >= 0; txi-- {
-				bh, err := w.balanceHistoryForTxid(ad.addrDesc, txids[txi].txid, fromUnix, toUnix, selfAddrDesc)
-				if err != nil {
-					return nil, err
-				}
-				if bh != nil {
-					bhs = append(bhs, *bh)
+	for _, data := range datas {
+		for _, da := range data.addresses {
+			for i := range da {
+				ad := &da[i]
+				txids := ad.txids
+				for txi := len(txids) - 1; txi >= 0; txi-- {
+					bh, err := w.balanceHistoryForTxid(ad.addrDesc, txids[txi].txid, fromUnix, toUnix, selfAddrDesc)
+					if err != nil {
+						return nil, err
+					}
+					if bh != nil {
+						bhs = append(bhs, *bh)
+					}
 				}
 			}
 		}
 	}
 	bha := bhs.SortAndAggregate(groupBy)
-	err = w.setFiatRateToBalanceHistories(bha, currencies)
-	if err != nil {
+	if err := w.setFiatRateToBalanceHistories(bha, currencies); err != nil {
 		return nil, err
 	}
-	glog.Info("GetUtxoBalanceHistory ", xpub[:xpubLogPrefix], ", cache ", inCache, ", blocks ", fromHeight, "-", toHeight, ", count ", len(bha), ",  ", time.Since(start))
 	return bha, nil
 }
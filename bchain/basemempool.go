@@ -1,6 +1,7 @@
 package bchain
 
 import (
+	"crypto/sha256"
 	"sort"
 	"sync"
 	"time"
@@ -14,11 +15,18 @@ type addrIndex struct {
 type txEntry struct {
 	addrIndexes []addrIndex
 	time        uint32
+	inputs      []Outpoint
+	// fee in satoshis, -1 if not known
+	fee   int64
+	vsize int32
 }
 
 type txidio struct {
-	txid string
-	io   []addrIndex
+	txid   string
+	io     []addrIndex
+	inputs []Outpoint
+	fee    int64
+	vsize  int32
 }
 
 // BaseMempool is mempool base handle
@@ -27,8 +35,19 @@ type BaseMempool struct {
 	mux          sync.Mutex
 	txEntries    map[string]txEntry
 	addrDescToTx map[string][]Outpoint
+	// spentOutpoints maps outpoints spent by mempool transactions to the spending txid
+	spentOutpoints map[Outpoint]string
+	// replacedBy maps txid of a transaction evicted from mempool by a conflicting transaction to the txid of the replacing transaction
+	replacedBy map[string]string
+	// replaces maps txid of a replacing transaction to txids of the transactions it replaced
+	replaces map[string][]string
+	// scriptHashes maps the SHA256 hash of the address descriptor (the script hash of the Electrum protocol) to the address
+	// descriptor for all addresses in addrDescToTx, it is nil until the first call of GetAddrDescForScriptHash
+	scriptHashes map[string]string
+	stats        *MempoolStats
 	OnNewTxAddr  OnNewTxAddrFunc
 	OnNewTx      OnNewTxFunc
+	OnReplacedTx OnReplacedTxFunc
 }
 
 // GetTransactions returns slice of mempool transactions for given address
@@ -70,6 +89,11 @@ func (a MempoolTxidEntries) Less(i, j int) bool {
 // removeEntryFromMempool removes entry from mempool structs. The caller is responsible for locking!
 func (m *BaseMempool) removeEntryFromMempool(txid string, entry txEntry) {
 	delete(m.txEntries, txid)
+	for _, o := range entry.inputs {
+		if m.spentOutpoints[o] == txid {
+			delete(m.spentOutpoints, o)
+		}
+	}
 	for _, si := range entry.addrIndexes {
 		outpoints, found := m.addrDescToTx[si.addrDesc]
 		if found {
@@ -83,9 +107,108 @@ func (m *BaseMempool) removeEntryFromMempool(txid string, entry txEntry) {
 				m.addrDescToTx[si.addrDesc] = newOutpoints
 			} else {
 				delete(m.addrDescToTx, si.addrDesc)
+				if m.scriptHashes != nil {
+					delete(m.scriptHashes, addrDescScriptHash(si.addrDesc))
+				}
+			}
+		}
+	}
+}
+
+// addEntryToMempool adds entry to mempool structs, evicting the mempool transactions which spend the same outpoints (double spends).
+// Returns the evicted transactions. The caller is responsible for locking!
+func (m *BaseMempool) addEntryToMempool(txid string, entry txEntry) []*ReplacedMempoolTx {
+	var replaced []*ReplacedMempoolTx
+	for _, o := range entry.inputs {
+		conflictTxid, found := m.spentOutpoints[o]
+		if !found || conflictTxid == txid {
+			continue
+		}
+		if conflict, found := m.txEntries[conflictTxid]; found {
+			m.removeEntryFromMempool(conflictTxid, conflict)
+			r := ReplacedMempoolTx{Txid: conflictTxid, ReplacedBy: txid}
+			unique := make(map[string]struct{}, len(conflict.addrIndexes))
+			for _, si := range conflict.addrIndexes {
+				if _, found := unique[si.addrDesc]; !found {
+					unique[si.addrDesc] = struct{}{}
+					r.AddrDescs = append(r.AddrDescs, AddressDescriptor(si.addrDesc))
+				}
 			}
+			replaced = append(replaced, &r)
+			m.replacedBy[conflictTxid] = txid
+			m.replaces[txid] = append(m.replaces[txid], conflictTxid)
 		}
 	}
+	m.txEntries[txid] = entry
+	m.addAddrDescTxs(txid, entry)
+	for _, o := range entry.inputs {
+		m.spentOutpoints[o] = txid
+	}
+	return replaced
+}
+
+// addAddrDescTxs adds the outpoints of the entry to the transactions of its addresses. The caller is responsible for locking!
+func (m *BaseMempool) addAddrDescTxs(txid string, entry txEntry) {
+	for _, si := range entry.addrIndexes {
+		outpoints, found := m.addrDescToTx[si.addrDesc]
+		if !found && m.scriptHashes != nil {
+			m.scriptHashes[addrDescScriptHash(si.addrDesc)] = si.addrDesc
+		}
+		m.addrDescToTx[si.addrDesc] = append(outpoints, Outpoint{txid, si.n})
+	}
+}
+
+func addrDescScriptHash(addrDesc string) string {
+	h := sha256.Sum256([]byte(addrDesc))
+	return string(h[:])
+}
+
+// GetAddrDescForScriptHash returns the address descriptor of the address in mempool with the given SHA256 hash
+// of the address descriptor (the script hash of the Electrum protocol in the internal byte order)
+// or nil if there is no such address in mempool. The map of the script hashes is built on the first call
+// and then it is maintained together with the mempool.
+func (m *BaseMempool) GetAddrDescForScriptHash(scriptHash []byte) AddressDescriptor {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.scriptHashes == nil {
+		m.scriptHashes = make(map[string]string, len(m.addrDescToTx))
+		for addrDesc := range m.addrDescToTx {
+			m.scriptHashes[addrDescScriptHash(addrDesc)] = addrDesc
+		}
+	}
+	if addrDesc, found := m.scriptHashes[string(scriptHash)]; found {
+		return AddressDescriptor(addrDesc)
+	}
+	return nil
+}
+
+// removeReplacements removes the replacement chain of the transaction which left the mempool. The caller is responsible for locking!
+func (m *BaseMempool) removeReplacements(txid string) {
+	for _, r := range m.replaces[txid] {
+		delete(m.replacedBy, r)
+		m.removeReplacements(r)
+	}
+	delete(m.replaces, txid)
+}
+
+// GetSpendingTxid returns txid of the mempool transaction spending given outpoint or empty string if the outpoint is not spent in mempool
+func (m *BaseMempool) GetSpendingTxid(outpoint Outpoint) string {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.spentOutpoints[outpoint]
+}
+
+// GetTxReplacement returns txid of the transaction which replaced given transaction
+// and txids of the transactions replaced by given transaction
+func (m *BaseMempool) GetTxReplacement(txid string) (string, []string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	var replaces []string
+	if r := m.replaces[txid]; len(r) > 0 {
+		replaces = make([]string, len(r))
+		copy(replaces, r)
+	}
+	return m.replacedBy[txid], replaces
 }
 
 // GetAllEntries returns all mempool entries sorted by fist seen time in descending order
@@ -95,8 +218,10 @@ func (m *BaseMempool) GetAllEntries() MempoolTxidEntries {
 	entries := make(MempoolTxidEntries, len(m.txEntries))
 	for txid, entry := range m.txEntries {
 		entries[i] = MempoolTxidEntry{
-			Txid: txid,
-			Time: entry.time,
+			Txid:   txid,
+			Time:   entry.time,
+			FeeSat: entry.fee,
+			VSize:  entry.vsize,
 		}
 		i++
 	}
@@ -116,6 +241,24 @@ func (m *BaseMempool) GetTransactionTime(txid string) uint32 {
 	return e.time
 }
 
+// GetTxEntry returns the mempool entry of a transaction with the outpoints spent by it, false if the transaction is not in mempool
+func (m *BaseMempool) GetTxEntry(txid string) (MempoolTxidEntry, []Outpoint, bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	e, found := m.txEntries[txid]
+	if !found {
+		return MempoolTxidEntry{}, nil, false
+	}
+	inputs := make([]Outpoint, len(e.inputs))
+	copy(inputs, e.inputs)
+	return MempoolTxidEntry{
+		Txid:   txid,
+		Time:   e.time,
+		FeeSat: e.fee,
+		VSize:  e.vsize,
+	}, inputs, true
+}
+
 func (m *BaseMempool) txToMempoolTx(tx *Tx) *MempoolTx {
 	mtx := MempoolTx{
 		Hex:              tx.Hex,
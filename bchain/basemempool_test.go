@@ -0,0 +1,197 @@
+//go:build unittest
+
+package bchain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestBaseMempool() *BaseMempool {
+	return &BaseMempool{
+		txEntries:      make(map[string]txEntry),
+		addrDescToTx:   make(map[string][]Outpoint),
+		spentOutpoints: make(map[Outpoint]string),
+		replacedBy:     make(map[string]string),
+		replaces:       make(map[string][]string),
+	}
+}
+
+func TestBaseMempool_addEntryToMempool(t *testing.T) {
+	m := newTestBaseMempool()
+	in1 := Outpoint{"aaaa", 0}
+	in2 := Outpoint{"bbbb", 1}
+	in3 := Outpoint{"cccc", 2}
+
+	// tx1 spends in1 and in2
+	replaced := m.addEntryToMempool("tx1", txEntry{
+		addrIndexes: []addrIndex{{"addr1", 0}, {"addr2", ^int32(0)}, {"addr2", ^int32(1)}},
+		time:        1,
+		inputs:      []Outpoint{in1, in2},
+	})
+	if len(replaced) != 0 {
+		t.Fatalf("tx1 replaced %+v", replaced)
+	}
+	// tx2 spends only in3, no conflict
+	replaced = m.addEntryToMempool("tx2", txEntry{
+		addrIndexes: []addrIndex{{"addr3", 0}},
+		time:        2,
+		inputs:      []Outpoint{in3},
+	})
+	if len(replaced) != 0 {
+		t.Fatalf("tx2 replaced %+v", replaced)
+	}
+	// tx3 double spends in2, tx1 must be evicted
+	replaced = m.addEntryToMempool("tx3", txEntry{
+		addrIndexes: []addrIndex{{"addr4", 0}},
+		time:        3,
+		inputs:      []Outpoint{in2},
+	})
+	want := []*ReplacedMempoolTx{{Txid: "tx1", ReplacedBy: "tx3", AddrDescs: []AddressDescriptor{AddressDescriptor("addr1"), AddressDescriptor("addr2")}}}
+	if !reflect.DeepEqual(replaced, want) {
+		t.Errorf("tx3 replaced = %+v, want %+v", replaced, want)
+	}
+	if _, found := m.txEntries["tx1"]; found {
+		t.Error("tx1 not evicted from txEntries")
+	}
+	if _, found := m.addrDescToTx["addr1"]; found {
+		t.Error("tx1 not evicted from addrDescToTx")
+	}
+	if _, found := m.spentOutpoints[in1]; found {
+		t.Error("spent outpoint of tx1 not removed")
+	}
+	if got := m.GetSpendingTxid(in2); got != "tx3" {
+		t.Errorf("GetSpendingTxid(in2) = %v, want tx3", got)
+	}
+	if got := m.GetSpendingTxid(in1); got != "" {
+		t.Errorf("GetSpendingTxid(in1) = %v, want empty", got)
+	}
+	// tx4 replaces tx3, forming the replacement chain tx1 -> tx3 -> tx4
+	m.addEntryToMempool("tx4", txEntry{
+		addrIndexes: []addrIndex{{"addr4", 0}},
+		time:        4,
+		inputs:      []Outpoint{in2},
+	})
+	if replacedBy, replaces := m.GetTxReplacement("tx3"); replacedBy != "tx4" || !reflect.DeepEqual(replaces, []string{"tx1"}) {
+		t.Errorf("GetTxReplacement(tx3) = %v, %v", replacedBy, replaces)
+	}
+	if replacedBy, _ := m.GetTxReplacement("tx1"); replacedBy != "tx3" {
+		t.Errorf("GetTxReplacement(tx1) = %v, want tx3", replacedBy)
+	}
+	if replacedBy, replaces := m.GetTxReplacement("tx2"); replacedBy != "" || replaces != nil {
+		t.Errorf("GetTxReplacement(tx2) = %v, %v", replacedBy, replaces)
+	}
+	// tx4 leaves the mempool (is mined), the whole replacement chain is removed
+	m.removeEntryFromMempool("tx4", m.txEntries["tx4"])
+	m.removeReplacements("tx4")
+	if len(m.replacedBy) != 0 || len(m.replaces) != 0 {
+		t.Errorf("replacement chain not removed: %v, %v", m.replacedBy, m.replaces)
+	}
+	if len(m.spentOutpoints) != 1 || m.spentOutpoints[in3] != "tx2" {
+		t.Errorf("spentOutpoints = %v", m.spentOutpoints)
+	}
+}
+
+func TestBaseMempool_GetTxEntry(t *testing.T) {
+	m := newTestBaseMempool()
+	inputs := []Outpoint{{"aaaa", 0}, {"bbbb", 1}}
+	m.addEntryToMempool("tx1", txEntry{
+		addrIndexes: []addrIndex{{"addr1", 0}},
+		time:        1,
+		inputs:      inputs,
+		fee:         1234,
+		vsize:       141,
+	})
+	entry, gotInputs, found := m.GetTxEntry("tx1")
+	if !found {
+		t.Fatal("GetTxEntry(tx1) not found")
+	}
+	if want := (MempoolTxidEntry{Txid: "tx1", Time: 1, FeeSat: 1234, VSize: 141}); entry != want {
+		t.Errorf("GetTxEntry(tx1) = %+v, want %+v", entry, want)
+	}
+	if !reflect.DeepEqual(gotInputs, inputs) {
+		t.Errorf("GetTxEntry(tx1) inputs = %+v, want %+v", gotInputs, inputs)
+	}
+	// the returned inputs must not share the memory with the mempool entry
+	gotInputs[0].Txid = "cccc"
+	if m.txEntries["tx1"].inputs[0].Txid != "aaaa" {
+		t.Error("GetTxEntry(tx1) returned the inputs of the mempool entry")
+	}
+	if _, _, found := m.GetTxEntry("tx2"); found {
+		t.Error("GetTxEntry(tx2) found")
+	}
+}
+
+func Test_computeMempoolStats(t *testing.T) {
+	entries := []mempoolFeeEntry{
+		{fee: 1000, vsize: 1000},      // 1 sat/vB
+		{fee: 25000, vsize: 500},      // 50 sat/vB
+		{fee: 1100, vsize: 100},       // 11 sat/vB
+		{fee: 1800000, vsize: 600000}, // 3 sat/vB
+		{fee: 2500000, vsize: 500000}, // 5 sat/vB
+	}
+	s := computeMempoolStats(entries, 2)
+	if s.Size != 7 || s.UnknownFeeCount != 2 || s.VSize != 1101600 || s.FeesSat != 4327100 {
+		t.Errorf("computeMempoolStats() = size %d, unknown %d, vsize %d, fees %d", s.Size, s.UnknownFeeCount, s.VSize, s.FeesSat)
+	}
+	if len(s.FeeHistogram) != len(MempoolFeeRateBuckets) {
+		t.Fatalf("len(FeeHistogram) = %d, want %d", len(s.FeeHistogram), len(MempoolFeeRateBuckets))
+	}
+	for _, b := range s.FeeHistogram {
+		var want MempoolFeeRateBucket
+		switch b.FeePerVByte {
+		case 1:
+			want = MempoolFeeRateBucket{1, 1, 1000}
+		case 3:
+			want = MempoolFeeRateBucket{3, 1, 600000}
+		case 5:
+			want = MempoolFeeRateBucket{5, 1, 500000}
+		case 10:
+			want = MempoolFeeRateBucket{10, 1, 100}
+		case 50:
+			want = MempoolFeeRateBucket{50, 1, 500}
+		default:
+			want = MempoolFeeRateBucket{FeePerVByte: b.FeePerVByte}
+		}
+		if b != want {
+			t.Errorf("FeeHistogram bucket = %+v, want %+v", b, want)
+		}
+	}
+	want := []MempoolProjectedBlock{
+		{TxCount: 3, VSize: 500600, FeesSat: 2526100, MinFeePerVByte: 5, MedianFeePerVByte: 11, MaxFeePerVByte: 50},
+		{TxCount: 2, VSize: 601000, FeesSat: 1801000, MinFeePerVByte: 1, MedianFeePerVByte: 1, MaxFeePerVByte: 3},
+	}
+	if !reflect.DeepEqual(s.ProjectedBlocks, want) {
+		t.Errorf("ProjectedBlocks = %+v, want %+v", s.ProjectedBlocks, want)
+	}
+}
+
+func TestBaseMempool_GetAddrDescForScriptHash(t *testing.T) {
+	m := newTestBaseMempool()
+	scriptHash := func(addrDesc string) []byte {
+		return []byte(addrDescScriptHash(addrDesc))
+	}
+	m.addEntryToMempool("tx1", txEntry{addrIndexes: []addrIndex{{"addr1", 0}, {"addr2", ^0}}, time: 1})
+	// the map is built from the addresses already in mempool on the first call
+	if got := m.GetAddrDescForScriptHash(scriptHash("addr1")); string(got) != "addr1" {
+		t.Errorf("GetAddrDescForScriptHash(addr1) = %v, want addr1", got)
+	}
+	if got := m.GetAddrDescForScriptHash(scriptHash("addr3")); got != nil {
+		t.Errorf("GetAddrDescForScriptHash(addr3) = %v, want nil", got)
+	}
+	// and then maintained with the added and removed transactions
+	m.addEntryToMempool("tx2", txEntry{addrIndexes: []addrIndex{{"addr2", 0}, {"addr3", 1}}, time: 2})
+	if got := m.GetAddrDescForScriptHash(scriptHash("addr3")); string(got) != "addr3" {
+		t.Errorf("GetAddrDescForScriptHash(addr3) = %v, want addr3", got)
+	}
+	m.removeEntryFromMempool("tx1", m.txEntries["tx1"])
+	if got := m.GetAddrDescForScriptHash(scriptHash("addr1")); got != nil {
+		t.Errorf("GetAddrDescForScriptHash(addr1) = %v, want nil", got)
+	}
+	if got := m.GetAddrDescForScriptHash(scriptHash("addr2")); string(got) != "addr2" {
+		t.Errorf("GetAddrDescForScriptHash(addr2) = %v, want addr2", got)
+	}
+	if len(m.scriptHashes) != 2 {
+		t.Errorf("scriptHashes = %v, want 2 entries", m.scriptHashes)
+	}
+}
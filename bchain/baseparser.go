@@ -167,12 +167,17 @@ func (p *BaseParser) MinimumCoinbaseConfirmations() int {
 	return 0
 }
 
+// GetMessageSignatureMagic returns empty string, the signed messages are not supported
+func (p *BaseParser) GetMessageSignatureMagic() string {
+	return ""
+}
+
 // PackTx packs transaction to byte array using protobuf
 func (p *BaseParser) PackTx(tx *Tx, height uint32, blockTime int64) ([]byte, error) {
 	var err error
 	pti := make([]*ProtoTransaction_VinType, len(tx.Vin))
 	for i, vi := range tx.Vin {
-		hex, err := hex.DecodeString(vi.ScriptSig.Hex)
+		scriptSigHex, err := hex.DecodeString(vi.ScriptSig.Hex)
 		if err != nil {
 			return nil, errors.Annotatef(err, "Vin %v Hex %v", i, vi.ScriptSig.Hex)
 		}
@@ -184,11 +189,19 @@ func (p *BaseParser) PackTx(tx *Tx, height uint32, blockTime int64) ([]byte, err
 		pti[i] = &ProtoTransaction_VinType{
 			Addresses:    vi.Addresses,
 			Coinbase:     vi.Coinbase,
-			ScriptSigHex: hex,
+			ScriptSigHex: scriptSigHex,
 			Sequence:     vi.Sequence,
 			Txid:         itxid,
 			Vout:         vi.Vout,
 		}
+		if len(vi.Witness) > 0 {
+			pti[i].Witness = make([][]byte, len(vi.Witness))
+			for j, w := range vi.Witness {
+				if pti[i].Witness[j], err = hex.DecodeString(w); err != nil {
+					return nil, errors.Annotatef(err, "Vin %v Witness %v", i, w)
+				}
+			}
+		}
 	}
 	pto := make([]*ProtoTransaction_VoutType, len(tx.Vout))
 	for i, vo := range tx.Vout {
@@ -210,6 +223,8 @@ func (p *BaseParser) PackTx(tx *Tx, height uint32, blockTime int64) ([]byte, err
 		Vin:       pti,
 		Vout:      pto,
 		Version:   tx.Version,
+		VSize:     uint32(tx.VSize),
+		Weight:    uint32(tx.Weight),
 	}
 	if pt.Hex, err = hex.DecodeString(tx.Hex); err != nil {
 		return nil, errors.Annotatef(err, "Hex %v", tx.Hex)
@@ -247,6 +262,12 @@ func (p *BaseParser) UnpackTx(buf []byte) (*Tx, uint32, error) {
 			Txid:     itxid,
 			Vout:     pti.Vout,
 		}
+		if len(pti.Witness) > 0 {
+			vin[i].Witness = make([]string, len(pti.Witness))
+			for j, w := range pti.Witness {
+				vin[i].Witness[j] = hex.EncodeToString(w)
+			}
+		}
 	}
 	vout := make([]Vout, len(pt.Vout))
 	for i, pto := range pt.Vout {
@@ -270,6 +291,8 @@ func (p *BaseParser) UnpackTx(buf []byte) (*Tx, uint32, error) {
 		Vin:       vin,
 		Vout:      vout,
 		Version:   pt.Version,
+		VSize:     int64(pt.VSize),
+		Weight:    int64(pt.Weight),
 	}
 	return &tx, pt.Height, nil
 }
@@ -300,6 +323,11 @@ func (p *BaseParser) DeriveAddressDescriptorsFromTo(descriptor *XpubDescriptor,
 	return nil, errors.New("Not supported")
 }
 
+// DecodeTaprootSpends is unsupported
+func (p *BaseParser) DecodeTaprootSpends(tx *Tx, prevAddrDescs []AddressDescriptor) ([]*TaprootSpend, error) {
+	return nil, errors.New("Not supported")
+}
+
 // EthereumTypeGetErc20FromTx is unsupported
 func (p *BaseParser) EthereumTypeGetErc20FromTx(tx *Tx) ([]Erc20Transfer, error) {
 	return nil, errors.New("Not supported")
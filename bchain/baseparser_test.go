@@ -4,6 +4,7 @@ package bchain
 
 import (
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/trezor/blockbook/common"
@@ -58,3 +59,52 @@ func TestBaseParser_AmountToBigInt(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseParser_PackTx_UnpackTx(t *testing.T) {
+	p := NewBaseParser(8)
+	tx := &Tx{
+		Hex:       "02000000000101",
+		Txid:      "474e6795760ebe81cb4023dc227e5a0efe340e1771c89a0035276361ed733de7",
+		Blocktime: 1519053802,
+		Time:      1519053802,
+		LockTime:  512115,
+		Version:   2,
+		VSize:     166,
+		Weight:    661,
+		Vin: []Vin{
+			{
+				ScriptSig: ScriptSig{
+					Hex: "160014550da1f5d25a9dae2eafd6902b4194c4c6500af6",
+				},
+				Txid:     "c13e32a4428e31f85d7aee4ec7344504b12e72aaffcbde0160200d2ac7f0649d",
+				Sequence: 4294967295,
+				Witness: []string{
+					"3044022076aba4ad559616905fa51d4ddd357fc1fdb428d40cb388e042cdd1da4a1b7357022011916f90c712ead9a66d5f058252efd280439ad8956a967e95d437d246710bc901",
+					"02a80a5964c5612bb769ef73147b2cf3c149bc0fd4ecb02f8097629c94ab013ffd",
+				},
+			},
+		},
+		Vout: []Vout{
+			{
+				ValueSat: *big.NewInt(10000000),
+				ScriptPubKey: ScriptPubKey{
+					Hex: "a914cd668d781ece600efa4b2404dc91fd26b8b8aed887",
+				},
+			},
+		},
+	}
+	b, err := p.PackTx(tx, 510234, 1519053802)
+	if err != nil {
+		t.Fatalf("PackTx() error = %v", err)
+	}
+	got, height, err := p.UnpackTx(b)
+	if err != nil {
+		t.Fatalf("UnpackTx() error = %v", err)
+	}
+	if height != 510234 {
+		t.Errorf("UnpackTx() height = %v, want %v", height, 510234)
+	}
+	if !reflect.DeepEqual(got, tx) {
+		t.Errorf("UnpackTx() = %+v, want %+v", got, tx)
+	}
+}
@@ -0,0 +1,507 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package bchain
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BlockbookClient is the client API for Blockbook service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BlockbookClient interface {
+	GetAccountInfo(ctx context.Context, in *AccountInfoRequest, opts ...grpc.CallOption) (*AccountInfo, error)
+	GetAccountUtxo(ctx context.Context, in *AccountUtxoRequest, opts ...grpc.CallOption) (*AccountUtxo, error)
+	GetTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Transaction, error)
+	GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockDetail, error)
+	GetBalanceHistory(ctx context.Context, in *BalanceHistoryRequest, opts ...grpc.CallOption) (*BalanceHistory, error)
+	EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error)
+	SendTransaction(ctx context.Context, in *SendTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error)
+	SubscribeNewBlock(ctx context.Context, in *SubscribeNewBlockRequest, opts ...grpc.CallOption) (Blockbook_SubscribeNewBlockClient, error)
+	SubscribeAddresses(ctx context.Context, in *SubscribeAddressesRequest, opts ...grpc.CallOption) (Blockbook_SubscribeAddressesClient, error)
+	SubscribeFiatRates(ctx context.Context, in *SubscribeFiatRatesRequest, opts ...grpc.CallOption) (Blockbook_SubscribeFiatRatesClient, error)
+}
+
+type blockbookClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlockbookClient(cc grpc.ClientConnInterface) BlockbookClient {
+	return &blockbookClient{cc}
+}
+
+func (c *blockbookClient) GetAccountInfo(ctx context.Context, in *AccountInfoRequest, opts ...grpc.CallOption) (*AccountInfo, error) {
+	out := new(AccountInfo)
+	err := c.cc.Invoke(ctx, "/bchain.Blockbook/GetAccountInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetAccountUtxo(ctx context.Context, in *AccountUtxoRequest, opts ...grpc.CallOption) (*AccountUtxo, error) {
+	out := new(AccountUtxo)
+	err := c.cc.Invoke(ctx, "/bchain.Blockbook/GetAccountUtxo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Transaction, error) {
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, "/bchain.Blockbook/GetTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockDetail, error) {
+	out := new(BlockDetail)
+	err := c.cc.Invoke(ctx, "/bchain.Blockbook/GetBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) GetBalanceHistory(ctx context.Context, in *BalanceHistoryRequest, opts ...grpc.CallOption) (*BalanceHistory, error) {
+	out := new(BalanceHistory)
+	err := c.cc.Invoke(ctx, "/bchain.Blockbook/GetBalanceHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error) {
+	out := new(EstimateFeeResponse)
+	err := c.cc.Invoke(ctx, "/bchain.Blockbook/EstimateFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) SendTransaction(ctx context.Context, in *SendTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error) {
+	out := new(SendTransactionResponse)
+	err := c.cc.Invoke(ctx, "/bchain.Blockbook/SendTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockbookClient) SubscribeNewBlock(ctx context.Context, in *SubscribeNewBlockRequest, opts ...grpc.CallOption) (Blockbook_SubscribeNewBlockClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Blockbook_ServiceDesc.Streams[0], "/bchain.Blockbook/SubscribeNewBlock", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockbookSubscribeNewBlockClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Blockbook_SubscribeNewBlockClient interface {
+	Recv() (*NewBlock, error)
+	grpc.ClientStream
+}
+
+type blockbookSubscribeNewBlockClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockbookSubscribeNewBlockClient) Recv() (*NewBlock, error) {
+	m := new(NewBlock)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blockbookClient) SubscribeAddresses(ctx context.Context, in *SubscribeAddressesRequest, opts ...grpc.CallOption) (Blockbook_SubscribeAddressesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Blockbook_ServiceDesc.Streams[1], "/bchain.Blockbook/SubscribeAddresses", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockbookSubscribeAddressesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Blockbook_SubscribeAddressesClient interface {
+	Recv() (*AddressTransaction, error)
+	grpc.ClientStream
+}
+
+type blockbookSubscribeAddressesClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockbookSubscribeAddressesClient) Recv() (*AddressTransaction, error) {
+	m := new(AddressTransaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blockbookClient) SubscribeFiatRates(ctx context.Context, in *SubscribeFiatRatesRequest, opts ...grpc.CallOption) (Blockbook_SubscribeFiatRatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Blockbook_ServiceDesc.Streams[2], "/bchain.Blockbook/SubscribeFiatRates", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockbookSubscribeFiatRatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Blockbook_SubscribeFiatRatesClient interface {
+	Recv() (*FiatRates, error)
+	grpc.ClientStream
+}
+
+type blockbookSubscribeFiatRatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockbookSubscribeFiatRatesClient) Recv() (*FiatRates, error) {
+	m := new(FiatRates)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BlockbookServer is the server API for Blockbook service.
+// All implementations must embed UnimplementedBlockbookServer
+// for forward compatibility
+type BlockbookServer interface {
+	GetAccountInfo(context.Context, *AccountInfoRequest) (*AccountInfo, error)
+	GetAccountUtxo(context.Context, *AccountUtxoRequest) (*AccountUtxo, error)
+	GetTransaction(context.Context, *TransactionRequest) (*Transaction, error)
+	GetBlock(context.Context, *BlockRequest) (*BlockDetail, error)
+	GetBalanceHistory(context.Context, *BalanceHistoryRequest) (*BalanceHistory, error)
+	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
+	SendTransaction(context.Context, *SendTransactionRequest) (*SendTransactionResponse, error)
+	SubscribeNewBlock(*SubscribeNewBlockRequest, Blockbook_SubscribeNewBlockServer) error
+	SubscribeAddresses(*SubscribeAddressesRequest, Blockbook_SubscribeAddressesServer) error
+	SubscribeFiatRates(*SubscribeFiatRatesRequest, Blockbook_SubscribeFiatRatesServer) error
+	mustEmbedUnimplementedBlockbookServer()
+}
+
+// UnimplementedBlockbookServer must be embedded to have forward compatible implementations.
+type UnimplementedBlockbookServer struct {
+}
+
+func (UnimplementedBlockbookServer) GetAccountInfo(context.Context, *AccountInfoRequest) (*AccountInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountInfo not implemented")
+}
+func (UnimplementedBlockbookServer) GetAccountUtxo(context.Context, *AccountUtxoRequest) (*AccountUtxo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountUtxo not implemented")
+}
+func (UnimplementedBlockbookServer) GetTransaction(context.Context, *TransactionRequest) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (UnimplementedBlockbookServer) GetBlock(context.Context, *BlockRequest) (*BlockDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedBlockbookServer) GetBalanceHistory(context.Context, *BalanceHistoryRequest) (*BalanceHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalanceHistory not implemented")
+}
+func (UnimplementedBlockbookServer) EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateFee not implemented")
+}
+func (UnimplementedBlockbookServer) SendTransaction(context.Context, *SendTransactionRequest) (*SendTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendTransaction not implemented")
+}
+func (UnimplementedBlockbookServer) SubscribeNewBlock(*SubscribeNewBlockRequest, Blockbook_SubscribeNewBlockServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeNewBlock not implemented")
+}
+func (UnimplementedBlockbookServer) SubscribeAddresses(*SubscribeAddressesRequest, Blockbook_SubscribeAddressesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAddresses not implemented")
+}
+func (UnimplementedBlockbookServer) SubscribeFiatRates(*SubscribeFiatRatesRequest, Blockbook_SubscribeFiatRatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeFiatRates not implemented")
+}
+func (UnimplementedBlockbookServer) mustEmbedUnimplementedBlockbookServer() {}
+
+// UnsafeBlockbookServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BlockbookServer will
+// result in compilation errors.
+type UnsafeBlockbookServer interface {
+	mustEmbedUnimplementedBlockbookServer()
+}
+
+func RegisterBlockbookServer(s grpc.ServiceRegistrar, srv BlockbookServer) {
+	s.RegisterService(&Blockbook_ServiceDesc, srv)
+}
+
+func _Blockbook_GetAccountInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetAccountInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bchain.Blockbook/GetAccountInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetAccountInfo(ctx, req.(*AccountInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetAccountUtxo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountUtxoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetAccountUtxo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bchain.Blockbook/GetAccountUtxo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetAccountUtxo(ctx, req.(*AccountUtxoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bchain.Blockbook/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetTransaction(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bchain.Blockbook/GetBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetBlock(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_GetBalanceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).GetBalanceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bchain.Blockbook/GetBalanceHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).GetBalanceHistory(ctx, req.(*BalanceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).EstimateFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bchain.Blockbook/EstimateFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).EstimateFee(ctx, req.(*EstimateFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_SendTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockbookServer).SendTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bchain.Blockbook/SendTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockbookServer).SendTransaction(ctx, req.(*SendTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockbook_SubscribeNewBlock_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeNewBlockRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockbookServer).SubscribeNewBlock(m, &blockbookSubscribeNewBlockServer{stream})
+}
+
+type Blockbook_SubscribeNewBlockServer interface {
+	Send(*NewBlock) error
+	grpc.ServerStream
+}
+
+type blockbookSubscribeNewBlockServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockbookSubscribeNewBlockServer) Send(m *NewBlock) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Blockbook_SubscribeAddresses_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAddressesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockbookServer).SubscribeAddresses(m, &blockbookSubscribeAddressesServer{stream})
+}
+
+type Blockbook_SubscribeAddressesServer interface {
+	Send(*AddressTransaction) error
+	grpc.ServerStream
+}
+
+type blockbookSubscribeAddressesServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockbookSubscribeAddressesServer) Send(m *AddressTransaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Blockbook_SubscribeFiatRates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeFiatRatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockbookServer).SubscribeFiatRates(m, &blockbookSubscribeFiatRatesServer{stream})
+}
+
+type Blockbook_SubscribeFiatRatesServer interface {
+	Send(*FiatRates) error
+	grpc.ServerStream
+}
+
+type blockbookSubscribeFiatRatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockbookSubscribeFiatRatesServer) Send(m *FiatRates) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Blockbook_ServiceDesc is the grpc.ServiceDesc for Blockbook service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Blockbook_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bchain.Blockbook",
+	HandlerType: (*BlockbookServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAccountInfo",
+			Handler:    _Blockbook_GetAccountInfo_Handler,
+		},
+		{
+			MethodName: "GetAccountUtxo",
+			Handler:    _Blockbook_GetAccountUtxo_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _Blockbook_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _Blockbook_GetBlock_Handler,
+		},
+		{
+			MethodName: "GetBalanceHistory",
+			Handler:    _Blockbook_GetBalanceHistory_Handler,
+		},
+		{
+			MethodName: "EstimateFee",
+			Handler:    _Blockbook_EstimateFee_Handler,
+		},
+		{
+			MethodName: "SendTransaction",
+			Handler:    _Blockbook_SendTransaction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeNewBlock",
+			Handler:       _Blockbook_SubscribeNewBlock_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeAddresses",
+			Handler:       _Blockbook_SubscribeAddresses_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeFiatRates",
+			Handler:       _Blockbook_SubscribeFiatRates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "blockbook.proto",
+}
@@ -233,6 +233,8 @@ func init() {
 		Txid:      "056e3d82e5ffd0e915fb9b62797d76263508c34fe3e5dbed30dd3e943930f204",
 		LockTime:  512115,
 		Version:   1,
+		VSize:     189,
+		Weight:    756,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -263,6 +265,8 @@ func init() {
 		Txid:      "474e6795760ebe81cb4023dc227e5a0efe340e1771c89a0035276361ed733de7",
 		LockTime:  0,
 		Version:   1,
+		VSize:     166,
+		Weight:    661,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -271,6 +275,10 @@ func init() {
 				Txid:     "c13e32a4428e31f85d7aee4ec7344504b12e72aaffcbde0160200d2ac7f0649d",
 				Vout:     0,
 				Sequence: 4294967295,
+				Witness: []string{
+					"3044022076aba4ad559616905fa51d4ddd357fc1fdb428d40cb388e042cdd1da4a1b7357022011916f90c712ead9a66d5f058252efd280439ad8956a967e95d437d246710bc901",
+					"02a80a5964c5612bb769ef73147b2cf3c149bc0fd4ecb02f8097629c94ab013ffd",
+				},
 			},
 		},
 		Vout: []bchain.Vout{
@@ -172,6 +172,8 @@ func init() {
 		Txid:      "e7ef52bbf3d9cb1ca5dfdb02eabf108e2b0b7757b009d1cfb24a06e4126e67f2",
 		LockTime:  205706,
 		Version:   2,
+		VSize:     417,
+		Weight:    1667,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -180,6 +182,10 @@ func init() {
 				Txid:     "5d13b4069a3e076b4a047dca8f19920faab280e7d22cd15483d409dbc431366f",
 				Vout:     2,
 				Sequence: 4294967294,
+				Witness: []string{
+					"3045022100ed4b0e9b140850951ffbc10349e3ac56a18b80c600a77b95cfac274e10228eb602207c876b9b134e63b8a01ba28720dc4c1c2c67bb7e547fb71d31440cd365c6742601",
+					"027aa4243e82c73c9c15d544a0b61a828eed1128464952bfbdc9235d4380f2767d",
+				},
 			},
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -173,6 +173,8 @@ func init() {
 		Txid:      "f81c34b300961877328c3aaa7cd5e69068457868309fbf1e92544e3a6a915bcb",
 		LockTime:  1851161,
 		Version:   1,
+		VSize:     225,
+		Weight:    900,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
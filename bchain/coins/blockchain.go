@@ -199,8 +199,8 @@ func (c *blockChainWithMetrics) CreateMempool(chain bchain.BlockChain) (bchain.M
 	return c.b.CreateMempool(chain)
 }
 
-func (c *blockChainWithMetrics) InitializeMempool(addrDescForOutpoint bchain.AddrDescForOutpointFunc, onNewTxAddr bchain.OnNewTxAddrFunc, onNewTx bchain.OnNewTxFunc) error {
-	return c.b.InitializeMempool(addrDescForOutpoint, onNewTxAddr, onNewTx)
+func (c *blockChainWithMetrics) InitializeMempool(addrDescForOutpoint bchain.AddrDescForOutpointFunc, onNewTxAddr bchain.OnNewTxAddrFunc, onNewTx bchain.OnNewTxFunc, onReplacedTx bchain.OnReplacedTxFunc) error {
+	return c.b.InitializeMempool(addrDescForOutpoint, onNewTxAddr, onNewTx, onReplacedTx)
 }
 
 func (c *blockChainWithMetrics) Shutdown(ctx context.Context) error {
@@ -372,3 +372,23 @@ func (c *mempoolWithMetrics) GetAllEntries() (v bchain.MempoolTxidEntries) {
 func (c *mempoolWithMetrics) GetTransactionTime(txid string) uint32 {
 	return c.mempool.GetTransactionTime(txid)
 }
+
+func (c *mempoolWithMetrics) GetTxEntry(txid string) (bchain.MempoolTxidEntry, []bchain.Outpoint, bool) {
+	return c.mempool.GetTxEntry(txid)
+}
+
+func (c *mempoolWithMetrics) GetTxReplacement(txid string) (string, []string) {
+	return c.mempool.GetTxReplacement(txid)
+}
+
+func (c *mempoolWithMetrics) GetSpendingTxid(outpoint bchain.Outpoint) string {
+	return c.mempool.GetSpendingTxid(outpoint)
+}
+
+func (c *mempoolWithMetrics) GetAddrDescForScriptHash(scriptHash []byte) bchain.AddressDescriptor {
+	return c.mempool.GetAddrDescForScriptHash(scriptHash)
+}
+
+func (c *mempoolWithMetrics) GetStats() *bchain.MempoolStats {
+	return c.mempool.GetStats()
+}
@@ -26,6 +26,9 @@ import (
 // OutputScriptToAddressesFunc converts ScriptPubKey to bitcoin addresses
 type OutputScriptToAddressesFunc func(script []byte) ([]string, bool, error)
 
+// BitcoinMessageSignatureMagic is the prefix of the messages signed by Bitcoin Core, the coins with their own prefix override it
+const BitcoinMessageSignatureMagic = "Bitcoin Signed Message:\n"
+
 // BitcoinLikeParser handle
 type BitcoinLikeParser struct {
 	*bchain.BaseParser
@@ -35,6 +38,7 @@ type BitcoinLikeParser struct {
 	XPubMagicSegwitP2sh          uint32
 	XPubMagicSegwitNative        uint32
 	Slip44                       uint32
+	MessageSignatureMagic        string
 	minimumCoinbaseConfirmations int
 }
 
@@ -50,6 +54,7 @@ func NewBitcoinLikeParser(params *chaincfg.Params, c *Configuration) *BitcoinLik
 		XPubMagicSegwitP2sh:          c.XPubMagicSegwitP2sh,
 		XPubMagicSegwitNative:        c.XPubMagicSegwitNative,
 		Slip44:                       c.Slip44,
+		MessageSignatureMagic:        BitcoinMessageSignatureMagic,
 		minimumCoinbaseConfirmations: c.MinimumCoinbaseConfirmations,
 	}
 	p.OutputScriptToAddressesFunc = p.outputScriptToAddresses
@@ -222,6 +227,12 @@ func (p *BitcoinLikeParser) TxFromMsgTx(t *wire.MsgTx, parseAddresses bool) bcha
 			Sequence:  in.Sequence,
 			ScriptSig: s,
 		}
+		if len(in.Witness) > 0 {
+			vin[i].Witness = make([]string, len(in.Witness))
+			for j, w := range in.Witness {
+				vin[i].Witness[j] = hex.EncodeToString(w)
+			}
+		}
 	}
 	vout := make([]bchain.Vout, len(t.TxOut))
 	for i, out := range t.TxOut {
@@ -249,11 +260,13 @@ func (p *BitcoinLikeParser) TxFromMsgTx(t *wire.MsgTx, parseAddresses bool) bcha
 		LockTime: t.LockTime,
 		Vin:      vin,
 		Vout:     vout,
+		Weight:   msgTxWeight(t),
 		// skip: BlockHash,
 		// skip: Confirmations,
 		// skip: Time,
 		// skip: Blocktime,
 	}
+	tx.VSize = (tx.Weight + 3) / 4
 	return tx
 }
 
@@ -292,6 +305,16 @@ func (p *BitcoinLikeParser) ParseBlock(b []byte) (*bchain.Block, error) {
 	}, nil
 }
 
+// msgTxWeight returns weight of the transaction as defined by BIP141
+func msgTxWeight(t *wire.MsgTx) int64 {
+	return int64(t.SerializeSizeStripped()*3 + t.SerializeSize())
+}
+
+// msgTxVSize returns virtual size of the transaction as defined by BIP141, weight divided by 4, rounded up
+func msgTxVSize(t *wire.MsgTx) int64 {
+	return (msgTxWeight(t) + 3) / 4
+}
+
 // PackTx packs transaction to byte array
 func (p *BitcoinLikeParser) PackTx(tx *bchain.Tx, height uint32, blockTime int64) ([]byte, error) {
 	buf := make([]byte, 4+vlq.MaxLen64+len(tx.Hex)/2)
@@ -319,6 +342,11 @@ func (p *BitcoinLikeParser) MinimumCoinbaseConfirmations() int {
 	return p.minimumCoinbaseConfirmations
 }
 
+// GetMessageSignatureMagic returns the prefix prepended to a message before it is hashed and signed
+func (p *BitcoinLikeParser) GetMessageSignatureMagic() string {
+	return p.MessageSignatureMagic
+}
+
 var tapTweakTagHash = sha256.Sum256([]byte("TapTweak"))
 
 func tapTweakHash(msg []byte) []byte {
@@ -435,8 +463,44 @@ func init() {
 	}
 }
 
+// parseChangeIndexes parses the change part of the descriptor, in the form of a single index, {0,1,...} or <0;1;...>
+func parseChangeIndexes(change, changeList1, changeList2 string) ([]uint32, error) {
+	if len(change) > 0 {
+		c, err := strconv.ParseUint(change, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		return []uint32{uint32(c)}, nil
+	}
+	if len(changeList1) > 0 || len(changeList2) > 0 {
+		var changes []string
+		if len(changeList1) > 0 {
+			changes = strings.Split(changeList1, ",")
+		} else {
+			changes = strings.Split(changeList2, ";")
+		}
+		if len(changes) == 0 {
+			return nil, errors.New("Invalid xpub descriptor, cannot parse change")
+		}
+		changeIndexes := make([]uint32, len(changes))
+		for i, ch := range changes {
+			c, err := strconv.ParseUint(ch, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			changeIndexes[i] = uint32(c)
+		}
+		return changeIndexes, nil
+	}
+	// default to {0,1}
+	return []uint32{0, 1}, nil
+}
+
 // ParseXpub parses xpub (or xpub descriptor) and returns XpubDescriptor
 func (p *BitcoinLikeParser) ParseXpub(xpub string) (*bchain.XpubDescriptor, error) {
+	if strings.Contains(xpub, "multi(") {
+		return p.parseMultisigXpub(xpub)
+	}
 	match := xpubDesriptorRegex.FindStringSubmatch(xpub)
 	if len(match) > changeSubexpIndex {
 		var descriptor bchain.XpubDescriptor
@@ -467,37 +531,9 @@ func (p *BitcoinLikeParser) ParseXpub(xpub string) (*bchain.XpubDescriptor, erro
 			return nil, err
 		}
 		descriptor.ExtKey = extKey
-		if len(match[changeSubexpIndex]) > 0 {
-			change, err := strconv.ParseUint(match[changeSubexpIndex], 10, 32)
-			if err != nil {
-				return nil, err
-			}
-			descriptor.ChangeIndexes = []uint32{uint32(change)}
-		} else {
-			if len(match[changeList1SubexpIndex]) > 0 || len(match[changeList2SubexpIndex]) > 0 {
-				var changes []string
-				if len(match[changeList1SubexpIndex]) > 0 {
-					changes = strings.Split(match[changeList1SubexpIndex], ",")
-				} else {
-					changes = strings.Split(match[changeList2SubexpIndex], ";")
-				}
-				if len(changes) == 0 {
-					return nil, errors.New("Invalid xpub descriptor, cannot parse change")
-				}
-				descriptor.ChangeIndexes = make([]uint32, len(changes))
-				for i, ch := range changes {
-					change, err := strconv.ParseUint(ch, 10, 32)
-					if err != nil {
-						return nil, err
-					}
-					descriptor.ChangeIndexes[i] = uint32(change)
-
-				}
-			} else {
-				// default to {0,1}
-				descriptor.ChangeIndexes = []uint32{0, 1}
-			}
-
+		descriptor.ChangeIndexes, err = parseChangeIndexes(match[changeSubexpIndex], match[changeList1SubexpIndex], match[changeList2SubexpIndex])
+		if err != nil {
+			return nil, err
 		}
 		return &descriptor, nil
 	}
@@ -507,6 +543,9 @@ func (p *BitcoinLikeParser) ParseXpub(xpub string) (*bchain.XpubDescriptor, erro
 
 // DeriveAddressDescriptors derives address descriptors from given xpub for listed indexes
 func (p *BitcoinLikeParser) DeriveAddressDescriptors(descriptor *bchain.XpubDescriptor, change uint32, indexes []uint32) ([]bchain.AddressDescriptor, error) {
+	if len(descriptor.ExtKeys) > 0 {
+		return p.deriveMultisigAddressDescriptors(descriptor, change, indexes)
+	}
 	ad := make([]bchain.AddressDescriptor, len(indexes))
 	changeExtKey, err := descriptor.ExtKey.(*hdkeychain.ExtendedKey).Derive(change)
 	if err != nil {
@@ -530,6 +569,13 @@ func (p *BitcoinLikeParser) DeriveAddressDescriptorsFromTo(descriptor *bchain.Xp
 	if toIndex <= fromIndex {
 		return nil, errors.New("toIndex<=fromIndex")
 	}
+	if len(descriptor.ExtKeys) > 0 {
+		indexes := make([]uint32, toIndex-fromIndex)
+		for i := range indexes {
+			indexes[i] = fromIndex + uint32(i)
+		}
+		return p.deriveMultisigAddressDescriptors(descriptor, change, indexes)
+	}
 	changeExtKey, err := descriptor.ExtKey.(*hdkeychain.ExtendedKey).Derive(change)
 	if err != nil {
 		return nil, err
@@ -558,6 +604,13 @@ func (p *BitcoinLikeParser) DerivationBasePath(descriptor *bchain.XpubDescriptor
 		c = "'"
 	}
 	c = strconv.Itoa(int(cn)) + c
+	if len(descriptor.ExtKeys) > 0 {
+		// multisig xpubs are usually not at depth 3 (e.g. BIP48), use the key origin from the descriptor
+		if len(descriptor.OriginPath) > 0 {
+			return "m" + descriptor.OriginPath, nil
+		}
+		return "unknown/" + c, nil
+	}
 	if extKey.Depth() != 3 {
 		return "unknown/" + c, nil
 	}
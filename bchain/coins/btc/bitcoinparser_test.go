@@ -3,12 +3,14 @@
 package btc
 
 import (
+	"bytes"
 	"encoding/hex"
 	"math/big"
 	"os"
 	"reflect"
 	"testing"
 
+	"github.com/martinboehm/btcd/wire"
 	"github.com/martinboehm/btcutil/chaincfg"
 	"github.com/trezor/blockbook/bchain"
 )
@@ -481,6 +483,8 @@ func init() {
 		Txid:      "056e3d82e5ffd0e915fb9b62797d76263508c34fe3e5dbed30dd3e943930f204",
 		LockTime:  512115,
 		Version:   1,
+		VSize:     189,
+		Weight:    756,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -511,6 +515,8 @@ func init() {
 		Txid:      "474e6795760ebe81cb4023dc227e5a0efe340e1771c89a0035276361ed733de7",
 		LockTime:  0,
 		Version:   1,
+		VSize:     166,
+		Weight:    661,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -519,6 +525,10 @@ func init() {
 				Txid:     "c13e32a4428e31f85d7aee4ec7344504b12e72aaffcbde0160200d2ac7f0649d",
 				Vout:     0,
 				Sequence: 4294967295,
+				Witness: []string{
+					"3044022076aba4ad559616905fa51d4ddd357fc1fdb428d40cb388e042cdd1da4a1b7357022011916f90c712ead9a66d5f058252efd280439ad8956a967e95d437d246710bc901",
+					"02a80a5964c5612bb769ef73147b2cf3c149bc0fd4ecb02f8097629c94ab013ffd",
+				},
 			},
 		},
 		Vout: []bchain.Vout{
@@ -551,6 +561,8 @@ func init() {
 		Txid:      "24551a58a1d1fb89d7052e2bbac7cb69a7825ee1e39439befbec8c32148cf735",
 		LockTime:  15745,
 		Version:   2,
+		VSize:     208,
+		Weight:    832,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -559,6 +571,10 @@ func init() {
 				Txid:     "9d8b6a98d942ce077574fff2e5dd9e405ba75ed9fb126b3da1b07a859a99b1de",
 				Vout:     0,
 				Sequence: 4294967293,
+				Witness: []string{
+					"304402207d67d320a8e813f986b35e9791935fcb736754812b7038686f5de6cfdcda99cd02201c3bb2c178e0056016437ecfe365a7eef84aa9d293ebdc566177af82e22fcdd301",
+					"03abb30c1bbe878b07b58dc169b1d061d48c60be8107f632a59778b38bf7ceea5a",
+				},
 			},
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -567,6 +583,10 @@ func init() {
 				Txid:     "98227ffe94726bc77c3c587e1e5375305beffb8e43a6eb75233b201e36d3d29f",
 				Vout:     0,
 				Sequence: 4294967293,
+				Witness: []string{
+					"3044022044f54a478cfe086e870cb026c9dcd4e14e63778bef569a4d55a6332725cd9a9802202f0e94c04e6f328fc64ad9efe552888c299750d1b8d033324825a3ff29920e0301",
+					"036fcd433428aa7dc65c4f5408fa31f208c54fe4b4c6c1ae9c39a825ed4f1ac039",
+				},
 			},
 		},
 		Vout: []bchain.Vout{
@@ -883,6 +903,77 @@ func TestParseXpubDescriptors(t *testing.T) {
 				ChangeIndexes:  []uint32{0, 1},
 			},
 		},
+		{
+			name:   "wsh(sortedmulti(2,xpub1,xpub2,xpub3))",
+			xpub:   "wsh(sortedmulti(2,[00000001/48'/0'/0'/2']xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/<0;1>/*,[00000002/48'/0'/0'/2']xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/<0;1>/*,[00000003/48'/0'/0'/2']xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/<0;1>/*))#22lzdkdz",
+			parser: btcMainParser,
+			want: &bchain.XpubDescriptor{
+				XpubDescriptor: "wsh(sortedmulti(2,[00000001/48'/0'/0'/2']xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/<0;1>/*,[00000002/48'/0'/0'/2']xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/<0;1>/*,[00000003/48'/0'/0'/2']xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/<0;1>/*))#22lzdkdz",
+				Xpub:           "xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS",
+				Type:           bchain.P2WSHMULTISIG,
+				Bip:            "48",
+				ChangeIndexes:  []uint32{0, 1},
+				RequiredSigs:   2,
+				SortedKeys:     true,
+				Xpubs:          []string{"xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS", "xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt", "xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP"},
+				OriginPath:     "/48'/0'/0'/2'",
+			},
+		},
+		{
+			name:   "sh(wsh(sortedmulti(2,[00000001/48h/0h/0h/1h]xpub1/0/*,xpub2/0/*,xpub3/0/*)))",
+			xpub:   "sh(wsh(sortedmulti(2,[00000001/48h/0h/0h/1h]xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/0/*,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/0/*,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/0/*)))#dny5seq7",
+			parser: btcMainParser,
+			want: &bchain.XpubDescriptor{
+				XpubDescriptor: "sh(wsh(sortedmulti(2,[00000001/48h/0h/0h/1h]xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/0/*,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/0/*,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/0/*)))#dny5seq7",
+				Xpub:           "xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS",
+				Type:           bchain.P2SHWSHMULTISIG,
+				Bip:            "48",
+				ChangeIndexes:  []uint32{0},
+				RequiredSigs:   2,
+				SortedKeys:     true,
+				Xpubs:          []string{"xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS", "xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt", "xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP"},
+				OriginPath:     "/48'/0'/0'/1'",
+			},
+		},
+		{
+			name:   "sh(sortedmulti(2,xpub1,xpub2,xpub3))",
+			xpub:   "sh(sortedmulti(2,xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP))#ktuetn6a",
+			parser: btcMainParser,
+			want: &bchain.XpubDescriptor{
+				XpubDescriptor: "sh(sortedmulti(2,xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP))#ktuetn6a",
+				Xpub:           "xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS",
+				Type:           bchain.P2SHMULTISIG,
+				Bip:            "45",
+				RequiredSigs:   2,
+				SortedKeys:     true,
+				NonRanged:      true,
+				Xpubs:          []string{"xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS", "xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt", "xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP"},
+			},
+		},
+		{
+			name:    "wsh(sortedmulti(2,xpub1,xpub2,xpub3)) error - invalid checksum",
+			xpub:    "wsh(sortedmulti(2,[00000001/48'/0'/0'/2']xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/<0;1>/*,[00000002/48'/0'/0'/2']xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/<0;1>/*,[00000003/48'/0'/0'/2']xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/<0;1>/*))#22lzdkdq",
+			parser:  btcMainParser,
+			wantErr: true,
+		},
+		{
+			name:    "wsh(multi(4,xpub1,xpub2,xpub3)) error - too many required signatures",
+			xpub:    "wsh(multi(4,xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/0/*,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/0/*,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/0/*))",
+			parser:  btcMainParser,
+			wantErr: true,
+		},
+		{
+			name:    "wsh(multi(2,xpub1/0/*,xpub2/1/*)) error - different change derivation",
+			xpub:    "wsh(multi(2,xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/0/*,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/1/*))",
+			parser:  btcMainParser,
+			wantErr: true,
+		},
+		{
+			name:    "wsh(multi(2,xpub1/0/*,xpub2)) error - ranged and non-ranged keys",
+			xpub:    "wsh(multi(2,xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/0/*,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt))",
+			parser:  btcMainParser,
+			wantErr: true,
+		},
 		{
 			name:    "xxx(xpub) error - unknown output script",
 			xpub:    "xxx(xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ)",
@@ -915,6 +1006,11 @@ func TestParseXpubDescriptors(t *testing.T) {
 					return
 				}
 				got.ExtKey = nil
+				if len(got.ExtKeys) != len(got.Xpubs) {
+					t.Errorf("ParseXpub() got %d ExtKeys, want %d", len(got.ExtKeys), len(got.Xpubs))
+					return
+				}
+				got.ExtKeys = nil
 				if !reflect.DeepEqual(got, tt.want) {
 					t.Errorf("ParseXpub() = %+v, want %+v", got, tt.want)
 				}
@@ -1087,6 +1183,73 @@ func TestDeriveAddressDescriptorsFromTo(t *testing.T) {
 			},
 			want: []string{"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"},
 		},
+		{
+			name: "wsh(sortedmulti) m/48'/0'/0'/2'",
+			args: args{
+				xpub:      "wsh(sortedmulti(2,[00000001/48'/0'/0'/2']xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/<0;1>/*,[00000002/48'/0'/0'/2']xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/<0;1>/*,[00000003/48'/0'/0'/2']xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/<0;1>/*))#22lzdkdz",
+				change:    0,
+				fromIndex: 0,
+				toIndex:   2,
+				parser:    btcMainParser,
+			},
+			want: []string{"bc1qx8937xy0sfx6hpctgj26fktppey88y8tknj83dvh4gqtjr7ez6sqdmjwtf", "bc1qx6pwgsxavf8t0jqjz75m7xhyx2wj70q8cczzhe4rugvkt9x0e2wsgl22zf"},
+		},
+		{
+			name: "wsh(sortedmulti) m/48'/0'/0'/2'/1",
+			args: args{
+				xpub:      "wsh(sortedmulti(2,[00000001/48'/0'/0'/2']xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/<0;1>/*,[00000002/48'/0'/0'/2']xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/<0;1>/*,[00000003/48'/0'/0'/2']xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/<0;1>/*))#22lzdkdz",
+				change:    1,
+				fromIndex: 0,
+				toIndex:   1,
+				parser:    btcMainParser,
+			},
+			want: []string{"bc1qw0uxjc2vfvuct4030m59m784vegadapnf8qnvw06ds5jxkumgk6q3yr00z"},
+		},
+		{
+			name: "wsh(multi)",
+			args: args{
+				xpub:      "wsh(multi(2,xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/{0,1}/*,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/{0,1}/*,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/{0,1}/*))#cjwfp3yc",
+				change:    0,
+				fromIndex: 0,
+				toIndex:   2,
+				parser:    btcMainParser,
+			},
+			want: []string{"bc1qxw6qhmy2d7y7vgqaq9zchfutr09t0dmcf3qtjppmh8xddpk8wyjqfm9mlu", "bc1q5ff9lswdke0zcxs4z30vw60nazd9t5vq5jyayg2e238j4dyxztvqh5mxsg"},
+		},
+		{
+			name: "sh(sortedmulti)",
+			args: args{
+				xpub:      "sh(sortedmulti(2,xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP))#ktuetn6a",
+				change:    0,
+				fromIndex: 0,
+				toIndex:   1,
+				parser:    btcMainParser,
+			},
+			want: []string{"3QLYfNxyE96JaFW3gpEHf9DAEDfV2LXZ8F"},
+		},
+		{
+			name: "sh(sortedmulti) error - non-ranged descriptor has only one address",
+			args: args{
+				xpub:      "sh(sortedmulti(2,xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP))#ktuetn6a",
+				change:    0,
+				fromIndex: 0,
+				toIndex:   2,
+				parser:    btcMainParser,
+			},
+			want:    []string{},
+			wantErr: true,
+		},
+		{
+			name: "sh(wsh(sortedmulti))",
+			args: args{
+				xpub:      "sh(wsh(sortedmulti(2,[00000001/48h/0h/0h/1h]xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/0/*,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/0/*,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/0/*)))#dny5seq7",
+				change:    0,
+				fromIndex: 0,
+				toIndex:   2,
+				parser:    btcMainParser,
+			},
+			want: []string{"33YCams8n5JHjGCzf18kPsHG6dj9U3Pu8P", "321ThANk6HsA3A6fbbYpnrQtmPWSKxrrgQ"},
+		},
 		{
 			name: "m/49'/1'/0'",
 			args: args{
@@ -1189,6 +1352,22 @@ func TestBitcoinParser_DerivationBasePath(t *testing.T) {
 			},
 			want: "m/84'/0'/0'",
 		},
+		{
+			name: "m/48'/0'/0'/2' - multisig",
+			args: args{
+				xpub:   "wsh(sortedmulti(2,[00000001/48'/0'/0'/2']xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS/<0;1>/*,[00000002/48'/0'/0'/2']xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt/<0;1>/*,[00000003/48'/0'/0'/2']xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP/<0;1>/*))#22lzdkdz",
+				parser: btcMainParser,
+			},
+			want: "m/48'/0'/0'/2'",
+		},
+		{
+			name: "multisig without key origin",
+			args: args{
+				xpub:   "sh(sortedmulti(2,xpub6DknhdAsmeDQc7uaCcTBvPM5HJ2sN2gaBmNiJJtpczK3hMQWdKeodaBUSgi9qJrMKqPLqPuNFa7egPzCn8oJ7uU1zzhgAeHvzgYpxqchsQS,xpub6FAQRNJPfe8DZextv3BwkyE9GovxWr6NPx5DFosrY4WDdAeu96gcry37PJrV9agkn2pRsLieS487vaom77nSinfuerwfz926ZaNwkjUbhdt,xpub6Ewx2N9hNSArJyF35CUGhaZLuZxQPNmJzWVwmpoV9U7Xu5wqka93nd3zEzokew9MzkNV4u6TCVDkHHR6QHQuYEFaasKzWkrkncXHMXGNdZP))#ktuetn6a",
+				parser: btcMainParser,
+			},
+			want: "unknown/2'",
+		},
 		{
 			name: "m/49'/0'/55 - not hardened account",
 			args: args{
@@ -1240,3 +1419,86 @@ func TestBitcoinParser_DerivationBasePath(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeTaprootSpends(t *testing.T) {
+	parser := NewBitcoinParser(GetChainParams("main"), &Configuration{})
+	mustDecode := func(s string) []byte {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	sig := bytes.Repeat([]byte{0x44}, 64)
+	internalKey := "2222222222222222222222222222222222222222222222222222222222222222"
+	controlBlock := "c1" + internalKey + "3333333333333333333333333333333333333333333333333333333333333333"
+	// <pubkey> OP_CHECKSIG OP_FALSE OP_IF "ord" 01 "text/plain;charset=utf-8" OP_0 "Hello, world!" OP_ENDIF
+	leafScript := "201111111111111111111111111111111111111111111111111111111111111111ac0063036f7264010118746578742f706c61696e3b636861727365743d7574662d38000d48656c6c6f2c20776f726c642168"
+	msgTx := wire.MsgTx{
+		Version: 2,
+		TxIn: []*wire.TxIn{
+			{Witness: wire.TxWitness{sig}, Sequence: 0xffffffff},
+			{Witness: wire.TxWitness{sig, mustDecode("021111111111111111111111111111111111111111111111111111111111111111")}, Sequence: 0xffffffff},
+			{Witness: wire.TxWitness{sig, mustDecode(leafScript), mustDecode(controlBlock), mustDecode("50aa")}, Sequence: 0xffffffff},
+		},
+		TxOut: []*wire.TxOut{{Value: 1000, PkScript: mustDecode("00141111111111111111111111111111111111111111")}},
+	}
+	var buf bytes.Buffer
+	if err := msgTx.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	tx := &bchain.Tx{
+		Hex: hex.EncodeToString(buf.Bytes()),
+		Vin: make([]bchain.Vin, 3),
+	}
+	p2tr := bchain.AddressDescriptor(mustDecode("51201111111111111111111111111111111111111111111111111111111111111111"))
+	p2wpkh := bchain.AddressDescriptor(mustDecode("00141111111111111111111111111111111111111111"))
+	want := []*bchain.TaprootSpend{
+		{
+			WitnessSize: 66,
+		},
+		nil,
+		{
+			ScriptPath:       true,
+			WitnessSize:      219,
+			Annex:            "50aa",
+			ControlBlock:     controlBlock,
+			LeafVersion:      0xc0,
+			InternalKey:      internalKey,
+			MerklePathLength: 1,
+			LeafScript:       leafScript,
+			LeafScriptAsm:    "1111111111111111111111111111111111111111111111111111111111111111 OP_CHECKSIG 0 OP_IF 6f7264 01 746578742f706c61696e3b636861727365743d7574662d38 0 48656c6c6f2c20776f726c6421 OP_ENDIF",
+			Inscriptions: []bchain.TaprootInscription{
+				{ContentType: "text/plain;charset=utf-8", ContentSize: 13},
+			},
+		},
+	}
+	got, err := parser.DecodeTaprootSpends(tx, []bchain.AddressDescriptor{p2tr, p2wpkh, p2tr})
+	if err != nil {
+		t.Fatalf("DecodeTaprootSpends() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		for i := range got {
+			t.Errorf("DecodeTaprootSpends()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	// malformed witness of one input (invalid control block), the other inputs are decoded
+	msgTx.TxIn[0].Witness = wire.TxWitness{sig, mustDecode(leafScript), mustDecode("c1")}
+	buf.Reset()
+	if err := msgTx.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	tx.Hex = hex.EncodeToString(buf.Bytes())
+	got, err = parser.DecodeTaprootSpends(tx, []bchain.AddressDescriptor{p2tr, p2wpkh, p2tr})
+	if err != nil {
+		t.Fatalf("DecodeTaprootSpends() error = %v", err)
+	}
+	if len(got) != 3 || got[0] != nil || !reflect.DeepEqual(got[2], want[2]) {
+		t.Errorf("DecodeTaprootSpends() with malformed input = %+v, want [nil nil %+v]", got, want[2])
+	}
+	// no taproot inputs, the witness is not decoded
+	got, err = parser.DecodeTaprootSpends(tx, []bchain.AddressDescriptor{p2wpkh, p2wpkh, p2wpkh})
+	if err != nil || got != nil {
+		t.Errorf("DecodeTaprootSpends() = %v, %v, want nil, nil", got, err)
+	}
+}
@@ -155,13 +155,14 @@ func (b *BitcoinRPC) CreateMempool(chain bchain.BlockChain) (bchain.Mempool, err
 }
 
 // InitializeMempool creates ZeroMQ subscription and sets AddrDescForOutpointFunc to the Mempool
-func (b *BitcoinRPC) InitializeMempool(addrDescForOutpoint bchain.AddrDescForOutpointFunc, onNewTxAddr bchain.OnNewTxAddrFunc, onNewTx bchain.OnNewTxFunc) error {
+func (b *BitcoinRPC) InitializeMempool(addrDescForOutpoint bchain.AddrDescForOutpointFunc, onNewTxAddr bchain.OnNewTxAddrFunc, onNewTx bchain.OnNewTxFunc, onReplacedTx bchain.OnReplacedTxFunc) error {
 	if b.Mempool == nil {
 		return errors.New("Mempool not created")
 	}
 	b.Mempool.AddrDescForOutpoint = addrDescForOutpoint
 	b.Mempool.OnNewTxAddr = onNewTxAddr
 	b.Mempool.OnNewTx = onNewTx
+	b.Mempool.OnReplacedTx = onReplacedTx
 	if b.mq == nil {
 		mq, err := bchain.NewMQ(b.ChainConfig.MessageQueueBinding, b.pushHandler)
 		if err != nil {
@@ -691,6 +692,17 @@ func IsMissingTx(err *bchain.RPCError) bool {
 	return err.Code == -5
 }
 
+// txVSize returns virtual size of the raw transaction as defined by BIP141 (weight divided by 4, rounded up),
+// or the size of the raw data if the transaction cannot be deserialized as bitcoin transaction
+func txVSize(data []byte) int64 {
+	t := wire.MsgTx{}
+	r := bytes.NewReader(data)
+	if err := t.Deserialize(r); err != nil || r.Len() > 0 {
+		return int64(len(data))
+	}
+	return msgTxVSize(&t)
+}
+
 // GetTransactionForMempool returns a transaction by the transaction ID
 // It could be optimized for mempool, i.e. without block time and confirmations
 func (b *BitcoinRPC) GetTransactionForMempool(txid string) (*bchain.Tx, error) {
@@ -718,6 +730,10 @@ func (b *BitcoinRPC) GetTransactionForMempool(txid string) (*bchain.Tx, error) {
 	if err != nil {
 		return nil, errors.Annotatef(err, "txid %v", txid)
 	}
+	// parsers of some coins do not compute the virtual size
+	if tx.VSize == 0 {
+		tx.VSize = txVSize(data)
+	}
 	return tx, nil
 }
 
@@ -860,15 +876,35 @@ func (b *BitcoinRPC) GetMempoolEntry(txid string) (*bchain.MempoolEntry, error)
 	if res.Error != nil {
 		return nil, res.Error
 	}
-	res.Result.FeeSat, err = b.Parser.AmountToBigInt(res.Result.Fee)
-	if err != nil {
-		return nil, err
+	e := res.Result
+	if e.Fees != nil {
+		// newer backends return fees in the "fees" object, the legacy fee fields are deprecated
+		if e.FeeSat, err = b.Parser.AmountToBigInt(e.Fees.Base); err != nil {
+			return nil, err
+		}
+		if e.ModifiedFeeSat, err = b.Parser.AmountToBigInt(e.Fees.Modified); err != nil {
+			return nil, err
+		}
+		if e.AncestorFeesSat, err = b.Parser.AmountToBigInt(e.Fees.Ancestor); err != nil {
+			return nil, err
+		}
+		if e.DescendantFeesSat, err = b.Parser.AmountToBigInt(e.Fees.Descendant); err != nil {
+			return nil, err
+		}
+	} else {
+		if e.FeeSat, err = b.Parser.AmountToBigInt(e.Fee); err != nil {
+			return nil, err
+		}
+		if e.ModifiedFeeSat, err = b.Parser.AmountToBigInt(e.ModifiedFee); err != nil {
+			return nil, err
+		}
+		e.AncestorFeesSat.SetUint64(uint64(e.AncestorFees))
+		e.DescendantFeesSat.SetUint64(uint64(e.DescendantFees))
 	}
-	res.Result.ModifiedFeeSat, err = b.Parser.AmountToBigInt(res.Result.ModifiedFee)
-	if err != nil {
-		return nil, err
+	if e.VSize == 0 {
+		e.VSize = e.Size
 	}
-	return res.Result, nil
+	return e, nil
 }
 
 func safeDecodeResponse(body io.ReadCloser, res interface{}) (err error) {
@@ -0,0 +1,322 @@
+package btc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcutil"
+	"github.com/martinboehm/btcutil/hdkeychain"
+	"github.com/martinboehm/btcutil/txscript"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// output script descriptor checksum, see https://github.com/bitcoin/bips/blob/master/bip-0380.mediawiki
+const descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+	"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+	"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+const descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+const descriptorChecksumLength = 8
+
+// maximum number of keys in multisig script, limited by the max size of the redeem script for p2sh
+const maxMultisigKeysP2SH = 15
+const maxMultisigKeysP2WSH = 20
+
+var multisigKeyRegex = regexp.MustCompile(`^(\[[0-9a-fA-F]{8}(?P<path>(/\d+['h]?)*)\])?(?P<xpub>\w+)(/(({(?P<changelist1>\d+(,\d+)*)})|(<(?P<changelist2>\d+(;\d+)*)>)|(?P<change>\d+))/\*)?$`)
+
+func descriptorPolyMod(c uint64, val uint64) uint64 {
+	c0 := c >> 35
+	c = ((c & 0x7ffffffff) << 5) ^ val
+	if c0&1 != 0 {
+		c ^= 0xf5dee51989
+	}
+	if c0&2 != 0 {
+		c ^= 0xa9fdca3312
+	}
+	if c0&4 != 0 {
+		c ^= 0x1bab10e32d
+	}
+	if c0&8 != 0 {
+		c ^= 0x3706b1677a
+	}
+	if c0&16 != 0 {
+		c ^= 0x644d626ffd
+	}
+	return c
+}
+
+// descriptorChecksum computes the checksum of the descriptor, returns error if the descriptor contains invalid characters
+func descriptorChecksum(desc string) (string, error) {
+	c := uint64(1)
+	cls := uint64(0)
+	clsCount := 0
+	for _, ch := range desc {
+		pos := strings.IndexRune(descriptorInputCharset, ch)
+		if pos < 0 {
+			return "", errors.Errorf("Invalid character '%c' in descriptor", ch)
+		}
+		c = descriptorPolyMod(c, uint64(pos)&31)
+		cls = cls*3 + uint64(pos>>5)
+		clsCount++
+		if clsCount == 3 {
+			c = descriptorPolyMod(c, cls)
+			cls = 0
+			clsCount = 0
+		}
+	}
+	if clsCount > 0 {
+		c = descriptorPolyMod(c, cls)
+	}
+	for i := 0; i < descriptorChecksumLength; i++ {
+		c = descriptorPolyMod(c, 0)
+	}
+	c ^= 1
+	checksum := make([]byte, descriptorChecksumLength)
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharset[(c>>(5*(7-i)))&31]
+	}
+	return string(checksum), nil
+}
+
+// stripDescriptorChecksum validates the checksum of the descriptor, if present, and returns the descriptor without it
+func stripDescriptorChecksum(desc string) (string, error) {
+	i := strings.IndexByte(desc, '#')
+	if i < 0 {
+		return desc, nil
+	}
+	checksum, err := descriptorChecksum(desc[:i])
+	if err != nil {
+		return "", err
+	}
+	if desc[i+1:] != checksum {
+		return "", errors.Errorf("Invalid descriptor checksum '%s', expected '%s'", desc[i+1:], checksum)
+	}
+	return desc[:i], nil
+}
+
+// splitDescriptorArgs splits the arguments of the descriptor function by commas, ignoring commas inside brackets
+func splitDescriptorArgs(s string) []string {
+	var args []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(args, s[start:])
+}
+
+// parseMultisigXpub parses descriptors sh(multi(...)), wsh(multi(...)) and sh(wsh(multi(...))), including sortedmulti variants
+// all keys must be xpubs with the same change derivation, e.g. wsh(sortedmulti(2,[fp/48'/0'/0'/2']xpub1/<0;1>/*,[fp/48'/0'/0'/2']xpub2/<0;1>/*))
+// or all keys must be without the derivation suffix, in which case the descriptor is non-ranged and describes a single script
+func (p *BitcoinLikeParser) parseMultisigXpub(xpub string) (*bchain.XpubDescriptor, error) {
+	desc, err := stripDescriptorChecksum(xpub)
+	if err != nil {
+		return nil, err
+	}
+	var descriptor bchain.XpubDescriptor
+	descriptor.XpubDescriptor = xpub
+	var closing string
+	maxKeys := maxMultisigKeysP2WSH
+	if strings.HasPrefix(desc, "sh(wsh(") {
+		descriptor.Type = bchain.P2SHWSHMULTISIG
+		descriptor.Bip = "48"
+		desc = desc[7:]
+		closing = "))"
+	} else if strings.HasPrefix(desc, "wsh(") {
+		descriptor.Type = bchain.P2WSHMULTISIG
+		descriptor.Bip = "48"
+		desc = desc[4:]
+		closing = ")"
+	} else if strings.HasPrefix(desc, "sh(") {
+		descriptor.Type = bchain.P2SHMULTISIG
+		descriptor.Bip = "45"
+		desc = desc[3:]
+		closing = ")"
+		maxKeys = maxMultisigKeysP2SH
+	} else {
+		return nil, errors.Errorf("Xpub descriptor %s is not supported", xpub)
+	}
+	if strings.HasPrefix(desc, "sortedmulti(") {
+		descriptor.SortedKeys = true
+		desc = desc[12:]
+	} else if strings.HasPrefix(desc, "multi(") {
+		desc = desc[6:]
+	} else {
+		return nil, errors.Errorf("Xpub descriptor %s is not supported", xpub)
+	}
+	if !strings.HasSuffix(desc, ")"+closing) {
+		return nil, errors.New("Invalid xpub descriptor, unbalanced parentheses")
+	}
+	args := splitDescriptorArgs(desc[:len(desc)-len(closing)-1])
+	if len(args) < 2 {
+		return nil, errors.New("Invalid xpub descriptor, missing multisig keys")
+	}
+	keys := args[1:]
+	descriptor.RequiredSigs, err = strconv.Atoi(args[0])
+	if err != nil || descriptor.RequiredSigs < 1 || descriptor.RequiredSigs > len(keys) {
+		return nil, errors.Errorf("Invalid xpub descriptor, invalid number of required signatures %s", args[0])
+	}
+	if len(keys) > maxKeys {
+		return nil, errors.Errorf("Invalid xpub descriptor, too many keys %d, maximum is %d", len(keys), maxKeys)
+	}
+	descriptor.Xpubs = make([]string, len(keys))
+	descriptor.ExtKeys = make([]interface{}, len(keys))
+	for i, k := range keys {
+		match := multisigKeyRegex.FindStringSubmatch(k)
+		if match == nil {
+			return nil, errors.Errorf("Invalid xpub descriptor, cannot parse key %s", k)
+		}
+		xpubKey := match[multisigKeyRegex.SubexpIndex("xpub")]
+		extKey, err := hdkeychain.NewKeyFromString(xpubKey, p.Params.Base58CksumHasher)
+		if err != nil {
+			return nil, err
+		}
+		change := match[multisigKeyRegex.SubexpIndex("change")]
+		changeList1 := match[multisigKeyRegex.SubexpIndex("changelist1")]
+		changeList2 := match[multisigKeyRegex.SubexpIndex("changelist2")]
+		// the key without the /*, /<change>/*, /{...}/* or /<...>/* suffix is not ranged, its change indexes are left nil
+		var changeIndexes []uint32
+		if change != "" || changeList1 != "" || changeList2 != "" {
+			changeIndexes, err = parseChangeIndexes(change, changeList1, changeList2)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if i == 0 {
+			descriptor.ChangeIndexes = changeIndexes
+			descriptor.OriginPath = strings.ReplaceAll(match[multisigKeyRegex.SubexpIndex("path")], "h", "'")
+			if len(descriptor.OriginPath) > 1 {
+				bip := strings.SplitN(descriptor.OriginPath[1:], "/", 2)[0]
+				descriptor.Bip = strings.TrimSuffix(bip, "'")
+			}
+		} else if !equalChangeIndexes(descriptor.ChangeIndexes, changeIndexes) {
+			return nil, errors.New("Invalid xpub descriptor, all keys must have the same change derivation")
+		}
+		descriptor.Xpubs[i] = xpubKey
+		descriptor.ExtKeys[i] = extKey
+	}
+	descriptor.Xpub = descriptor.Xpubs[0]
+	descriptor.ExtKey = descriptor.ExtKeys[0]
+	descriptor.NonRanged = descriptor.ChangeIndexes == nil
+	return &descriptor, nil
+}
+
+func equalChangeIndexes(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *BitcoinLikeParser) deriveMultisigAddressDescriptors(descriptor *bchain.XpubDescriptor, change uint32, indexes []uint32) ([]bchain.AddressDescriptor, error) {
+	if descriptor.NonRanged {
+		return p.nonRangedMultisigAddressDescriptors(descriptor, change, indexes)
+	}
+	changeExtKeys := make([]*hdkeychain.ExtendedKey, len(descriptor.ExtKeys))
+	for i, k := range descriptor.ExtKeys {
+		var err error
+		changeExtKeys[i], err = k.(*hdkeychain.ExtendedKey).Derive(change)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ad := make([]bchain.AddressDescriptor, len(indexes))
+	pubKeys := make([][]byte, len(changeExtKeys))
+	for i, index := range indexes {
+		for j, changeExtKey := range changeExtKeys {
+			indexExtKey, err := changeExtKey.Derive(index)
+			if err != nil {
+				return nil, err
+			}
+			pubKeys[j] = indexExtKey.PubKeyBytes()
+		}
+		var err error
+		ad[i], err = p.multisigAddrDesc(descriptor, pubKeys)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ad, nil
+}
+
+// nonRangedMultisigAddressDescriptors returns the single script of the non-ranged descriptor, built from the keys themselves,
+// the descriptor has only the address with change 0 and index 0
+func (p *BitcoinLikeParser) nonRangedMultisigAddressDescriptors(descriptor *bchain.XpubDescriptor, change uint32, indexes []uint32) ([]bchain.AddressDescriptor, error) {
+	if change != 0 {
+		return nil, errors.New("Non-ranged xpub descriptor has only one address")
+	}
+	pubKeys := make([][]byte, len(descriptor.ExtKeys))
+	for i, k := range descriptor.ExtKeys {
+		pubKeys[i] = k.(*hdkeychain.ExtendedKey).PubKeyBytes()
+	}
+	ad := make([]bchain.AddressDescriptor, len(indexes))
+	for i, index := range indexes {
+		if index != 0 {
+			return nil, errors.New("Non-ranged xpub descriptor has only one address")
+		}
+		var err error
+		if ad[i], err = p.multisigAddrDesc(descriptor, pubKeys); err != nil {
+			return nil, err
+		}
+	}
+	return ad, nil
+}
+
+func (p *BitcoinLikeParser) multisigAddrDesc(descriptor *bchain.XpubDescriptor, pubKeys [][]byte) (bchain.AddressDescriptor, error) {
+	if descriptor.SortedKeys {
+		// BIP67 - sort the public keys lexicographically
+		sorted := make([][]byte, len(pubKeys))
+		copy(sorted, pubKeys)
+		sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+		pubKeys = sorted
+	}
+	builder := txscript.NewScriptBuilder().AddInt64(int64(descriptor.RequiredSigs))
+	for _, pk := range pubKeys {
+		builder.AddData(pk)
+	}
+	script, err := builder.AddInt64(int64(len(pubKeys))).AddOp(txscript.OP_CHECKMULTISIG).Script()
+	if err != nil {
+		return nil, err
+	}
+	var a btcutil.Address
+	switch descriptor.Type {
+	case bchain.P2SHMULTISIG:
+		a, err = btcutil.NewAddressScriptHash(script, p.Params)
+	case bchain.P2WSHMULTISIG:
+		hash := sha256.Sum256(script)
+		a, err = btcutil.NewAddressWitnessScriptHash(hash[:], p.Params)
+	case bchain.P2SHWSHMULTISIG:
+		// redeemScript <witness version: OP_0><len scriptHash: 32><32-byte-scriptHash>
+		hash := sha256.Sum256(script)
+		redeemScript := make([]byte, len(hash)+2)
+		redeemScript[0] = txscript.OP_0
+		redeemScript[1] = byte(len(hash))
+		copy(redeemScript[2:], hash[:])
+		a, err = btcutil.NewAddressScriptHash(redeemScript, p.Params)
+	default:
+		return nil, errors.New("Unsupported xpub descriptor type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return txscript.PayToAddrScript(a)
+}
@@ -0,0 +1,219 @@
+package btc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/martinboehm/btcd/wire"
+	"github.com/martinboehm/btcutil/txscript"
+	"github.com/trezor/blockbook/bchain"
+)
+
+const (
+	taprootAnnexTag              = 0x50
+	taprootControlBlockBaseSize  = 33
+	taprootControlBlockNodeSize  = 32
+	taprootControlBlockMaxLength = taprootControlBlockBaseSize + 128*taprootControlBlockNodeSize
+	// leaf version of tapscript defined by BIP342
+	tapscriptLeafVersion = 0xc0
+)
+
+var inscriptionProtocolID = []byte("ord")
+
+// isP2TRAddrDesc returns true if the address descriptor is a witness v1 program of 32 bytes
+func isP2TRAddrDesc(addrDesc bchain.AddressDescriptor) bool {
+	return len(addrDesc) == 34 && addrDesc[0] == txscript.OP_1 && addrDesc[1] == txscript.OP_DATA_32
+}
+
+// DecodeTaprootSpends decodes the witness of the inputs spending P2TR outputs
+// the witness is taken from the raw transaction in tx.Hex, inputs with a malformed witness are skipped and logged
+func (p *BitcoinLikeParser) DecodeTaprootSpends(tx *bchain.Tx, prevAddrDescs []bchain.AddressDescriptor) ([]*bchain.TaprootSpend, error) {
+	if len(prevAddrDescs) != len(tx.Vin) {
+		return nil, errors.New("Number of address descriptors does not match number of inputs")
+	}
+	found := false
+	for _, ad := range prevAddrDescs {
+		if isP2TRAddrDesc(ad) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	b, err := hex.DecodeString(tx.Hex)
+	if err != nil {
+		return nil, errors.Annotatef(err, "tx %v", tx.Txid)
+	}
+	t := wire.MsgTx{}
+	if err := t.Deserialize(bytes.NewReader(b)); err != nil {
+		return nil, errors.Annotatef(err, "tx %v", tx.Txid)
+	}
+	if len(t.TxIn) != len(tx.Vin) {
+		return nil, errors.Errorf("tx %v: number of inputs does not match", tx.Txid)
+	}
+	r := make([]*bchain.TaprootSpend, len(tx.Vin))
+	for i, ad := range prevAddrDescs {
+		if isP2TRAddrDesc(ad) {
+			// a malformed witness of one input does not prevent decoding of the others, the input is left nil
+			r[i], err = decodeTaprootWitness(t.TxIn[i].Witness)
+			if err != nil {
+				glog.Warningf("DecodeTaprootSpends: tx %v, input %d: %v", tx.Txid, i, err)
+			}
+		}
+	}
+	return r, nil
+}
+
+// decodeTaprootWitness decodes the witness of a P2TR input according to BIP341
+func decodeTaprootWitness(witness wire.TxWitness) (*bchain.TaprootSpend, error) {
+	if len(witness) == 0 {
+		return nil, errors.New("Empty witness")
+	}
+	ts := &bchain.TaprootSpend{
+		WitnessSize: witness.SerializeSize(),
+	}
+	// the last element starting with 0x50 is the annex if there are at least two elements
+	if len(witness) >= 2 {
+		last := witness[len(witness)-1]
+		if len(last) > 0 && last[0] == taprootAnnexTag {
+			ts.Annex = hex.EncodeToString(last)
+			witness = witness[:len(witness)-1]
+		}
+	}
+	// single element is the signature of the key path spend
+	if len(witness) == 1 {
+		return ts, nil
+	}
+	ts.ScriptPath = true
+	cb := witness[len(witness)-1]
+	if len(cb) < taprootControlBlockBaseSize || len(cb) > taprootControlBlockMaxLength ||
+		(len(cb)-taprootControlBlockBaseSize)%taprootControlBlockNodeSize != 0 {
+		return nil, errors.Errorf("Invalid control block length %d", len(cb))
+	}
+	ts.ControlBlock = hex.EncodeToString(cb)
+	ts.LeafVersion = int(cb[0] & 0xfe)
+	ts.InternalKey = hex.EncodeToString(cb[1:taprootControlBlockBaseSize])
+	ts.MerklePathLength = (len(cb) - taprootControlBlockBaseSize) / taprootControlBlockNodeSize
+	script := witness[len(witness)-2]
+	ts.LeafScript = hex.EncodeToString(script)
+	if ts.LeafVersion == tapscriptLeafVersion {
+		ts.LeafScriptAsm = tapscriptDisasm(script)
+		ts.Inscriptions = findInscriptions(script)
+	}
+	return ts, nil
+}
+
+// tapscriptDisasm returns the disassembly of the tapscript, on a parse error the disassembly ends with [error]
+// OP_CHECKSIGADD is valid only in tapscript, txscript knows it as OP_UNKNOWN186
+func tapscriptDisasm(script []byte) string {
+	asm, _ := txscript.DisasmString(script)
+	return strings.Replace(asm, "OP_UNKNOWN186", "OP_CHECKSIGADD", -1)
+}
+
+// nextScriptOp returns the opcode at the position pos of the script, data pushed by the opcode and the position of the next opcode
+func nextScriptOp(script []byte, pos int) (byte, []byte, int, error) {
+	op := script[pos]
+	pos++
+	var l int
+	switch {
+	case op >= txscript.OP_DATA_1 && op <= txscript.OP_DATA_75:
+		l = int(op)
+	case op == txscript.OP_PUSHDATA1:
+		if pos+1 > len(script) {
+			return op, nil, pos, errors.New("Truncated script")
+		}
+		l = int(script[pos])
+		pos++
+	case op == txscript.OP_PUSHDATA2:
+		if pos+2 > len(script) {
+			return op, nil, pos, errors.New("Truncated script")
+		}
+		l = int(binary.LittleEndian.Uint16(script[pos:]))
+		pos += 2
+	case op == txscript.OP_PUSHDATA4:
+		if pos+4 > len(script) {
+			return op, nil, pos, errors.New("Truncated script")
+		}
+		l = int(binary.LittleEndian.Uint32(script[pos:]))
+		pos += 4
+	default:
+		return op, nil, pos, nil
+	}
+	if l < 0 || pos+l > len(script) {
+		return op, nil, pos, errors.New("Truncated script")
+	}
+	return op, script[pos : pos+l], pos + l, nil
+}
+
+// findInscriptions detects the inscription envelopes in the leaf script
+// the envelope is OP_FALSE OP_IF "ord" [tag value]... OP_0 [body push]... OP_ENDIF, tag 1 is the content type
+func findInscriptions(script []byte) []bchain.TaprootInscription {
+	var r []bchain.TaprootInscription
+	var prev, prev2 byte = 0xff, 0xff
+	for pos := 0; pos < len(script); {
+		op, data, next, err := nextScriptOp(script, pos)
+		if err != nil {
+			break
+		}
+		if prev2 == txscript.OP_FALSE && prev == txscript.OP_IF && bytes.Equal(data, inscriptionProtocolID) {
+			var ins *bchain.TaprootInscription
+			if ins, next = parseInscription(script, next); ins != nil {
+				r = append(r, *ins)
+			}
+			prev2, prev = 0xff, 0xff
+		} else {
+			prev2, prev = prev, op
+		}
+		pos = next
+	}
+	return r
+}
+
+// parseInscription parses the fields and the body of an inscription envelope starting after the protocol id
+// returns nil if the envelope is not terminated by OP_ENDIF
+func parseInscription(script []byte, pos int) (*bchain.TaprootInscription, int) {
+	ins := &bchain.TaprootInscription{}
+	body := false
+	for pos < len(script) {
+		op, data, next, err := nextScriptOp(script, pos)
+		if err != nil {
+			return nil, len(script)
+		}
+		pos = next
+		if op == txscript.OP_ENDIF {
+			return ins, pos
+		}
+		if body {
+			ins.ContentSize += len(data)
+			continue
+		}
+		if op == txscript.OP_0 {
+			body = true
+			continue
+		}
+		// field tag followed by its value
+		tag := -1
+		if op >= txscript.OP_1 && op <= txscript.OP_16 {
+			tag = int(op - txscript.OP_1 + 1)
+		} else if len(data) == 1 {
+			tag = int(data[0])
+		}
+		if pos >= len(script) {
+			break
+		}
+		_, value, next, err := nextScriptOp(script, pos)
+		if err != nil {
+			return nil, len(script)
+		}
+		pos = next
+		if tag == 1 {
+			ins.ContentType = string(value)
+		}
+	}
+	return nil, pos
+}
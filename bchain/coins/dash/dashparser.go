@@ -56,10 +56,12 @@ type DashParser struct {
 
 // NewDashParser returns new DashParser instance
 func NewDashParser(params *chaincfg.Params, c *btc.Configuration) *DashParser {
-	return &DashParser{
+	p := &DashParser{
 		BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c),
 		baseparser:        &bchain.BaseParser{},
 	}
+	p.MessageSignatureMagic = "DarkCoin Signed Message:\n"
+	return p
 }
 
 // GetChainParams contains network parameters for the main Dash network,
@@ -91,6 +91,8 @@ func init() {
 		Txid:      "0dcf2530419b9ef525a69f6a15e4d699be1dc9a4ac643c9581b6c57acf25eabf",
 		LockTime:  7000000,
 		Version:   1,
+		VSize:     226,
+		Weight:    904,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -41,7 +41,9 @@ type DogecoinParser struct {
 
 // NewDogecoinParser returns new DogecoinParser instance
 func NewDogecoinParser(params *chaincfg.Params, c *btc.Configuration) *DogecoinParser {
-	return &DogecoinParser{BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c)}
+	p := &DogecoinParser{BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c)}
+	p.MessageSignatureMagic = "Dogecoin Signed Message:\n"
+	return p
 }
 
 // GetChainParams contains network parameters for the main Dogecoin network,
@@ -331,6 +331,8 @@ func init() {
 		Txid:      "097ea09ba284f3f2a9e880e11f837edf7e5cea81c8da2238f5bc7c2c4c407943",
 		LockTime:  0,
 		Version:   1,
+		VSize:     227,
+		Weight:    908,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -371,6 +373,8 @@ func init() {
 		Txid:      "b276545af246e3ed5a4e3e5b60d359942a1808579effc53ff4f343e4f6cfc5a0",
 		LockTime:  0,
 		Version:   1,
+		VSize:     226,
+		Weight:    904,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -411,6 +415,8 @@ func init() {
 		Txid:      "43cfbc6db77a8e9aad25913c2298da81421e513e216420b8af2562e744a030c9",
 		LockTime:  0,
 		Version:   1,
+		VSize:     226,
+		Weight:    904,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -451,6 +457,8 @@ func init() {
 		Txid:      "91e2f3a9dde1e2da53f29c73033084b3d1a3b0c0ba6737d6418cfa9cad62be3c",
 		LockTime:  3200005,
 		Version:   1,
+		VSize:     517,
+		Weight:    2068,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -91,6 +91,8 @@ func init() {
 		Txid:      "7367db1a3073146f0b6060ce4a6dbb96b67b7aadcbaa450bff8d7dedda52ec13",
 		LockTime:  2356066,
 		Version:   1,
+		VSize:     223,
+		Weight:    892,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
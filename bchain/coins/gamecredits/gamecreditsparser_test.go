@@ -113,6 +113,8 @@ func init() {
 		Txid:      "983da8317fff45afb17290d4dd8da6ec1cd8ffbbfa98e53a0754e9b60f8cc0f9",
 		LockTime:  2183109,
 		Version:   1,
+		VSize:     192,
+		Weight:    768,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -40,7 +40,9 @@ type LitecoinParser struct {
 
 // NewLitecoinParser returns new LitecoinParser instance
 func NewLitecoinParser(params *chaincfg.Params, c *btc.Configuration) *LitecoinParser {
-	return &LitecoinParser{BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c)}
+	p := &LitecoinParser{BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c)}
+	p.MessageSignatureMagic = "Litecoin Signed Message:\n"
+	return p
 }
 
 // GetChainParams contains network parameters for the main Litecoin network,
@@ -234,6 +234,8 @@ func init() {
 		Txid:      "1c50c1770374d7de2f81a87463a5225bb620d25fd467536223a5b715a47c9e32",
 		LockTime:  0,
 		Version:   2,
+		VSize:     201,
+		Weight:    804,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -39,7 +39,9 @@ type MonacoinParser struct {
 
 // NewMonacoinParser returns new MonacoinParser instance
 func NewMonacoinParser(params *chaincfg.Params, c *btc.Configuration) *MonacoinParser {
-	return &MonacoinParser{BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c)}
+	p := &MonacoinParser{BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c)}
+	p.MessageSignatureMagic = "Monacoin Signed Message:\n"
+	return p
 }
 
 // GetChainParams contains network parameters for the main Monacoin network,
@@ -218,6 +218,8 @@ func init() {
 		Txid:      "7533fa6651cc96762e27bf496e00262671312244ff0c8bfe56a3c0ef688a49b5",
 		LockTime:  1375525,
 		Version:   2,
+		VSize:     520,
+		Weight:    2080,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
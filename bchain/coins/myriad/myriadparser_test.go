@@ -139,6 +139,8 @@ func init() {
 		Txid:      "b01e2eb866ed101ed117b4ad18b753929e85c42e3d8add76bdd16e5c00519dcc",
 		LockTime:  0,
 		Version:   1,
+		VSize:     226,
+		Weight:    904,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
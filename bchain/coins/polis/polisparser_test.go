@@ -191,6 +191,8 @@ func init() {
 		Txid:      "6882e77c916c5442d09e295b88fbb8a2fac6dbb988975bb00dbded088e0229a9",
 		LockTime:  320389,
 		Version:   2,
+		VSize:     226,
+		Weight:    904,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -45,9 +45,11 @@ type QtumParser struct {
 
 // NewQtumParser returns new DashParser instance
 func NewQtumParser(params *chaincfg.Params, c *btc.Configuration) *QtumParser {
-	return &QtumParser{
+	p := &QtumParser{
 		BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c),
 	}
+	p.MessageSignatureMagic = "Qtum Signed Message:\n"
+	return p
 }
 
 // GetChainParams contains network parameters for the main Qtum network,
@@ -171,6 +171,8 @@ func init() {
 		Txid:      "40cc76f3d9747472c49a7c162628d5794e1fb3e5c28e5787b3c6c1178c794e8c",
 		LockTime:  0,
 		Version:   1,
+		VSize:     522,
+		Weight:    2088,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
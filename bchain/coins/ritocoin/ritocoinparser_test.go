@@ -81,6 +81,8 @@ func init() {
 		Txid:      "535e470daf1a4eb2097e6adaddd81972b010e33417747536f19ed29371f9713f",
 		LockTime:  250182,
 		Version:   2,
+		VSize:     226,
+		Weight:    904,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -121,6 +123,8 @@ func init() {
 		Txid:      "7f0745611b4cf48f611a26873cc3c5c01eff7bdf8df7427f379bc7963792f966",
 		LockTime:  250124,
 		Version:   2,
+		VSize:     374,
+		Weight:    1496,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -72,6 +72,8 @@ func init() {
 		Txid:      "9888815899d3b2e0f26b1eab51229082cf1faf4cd03a12fea2c8afa66701541f",
 		LockTime:  0,
 		Version:   1,
+		VSize:     374,
+		Weight:    1496,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
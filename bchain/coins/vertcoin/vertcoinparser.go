@@ -40,7 +40,9 @@ type VertcoinParser struct {
 
 // NewVertcoinParser returns new VertcoinParser instance
 func NewVertcoinParser(params *chaincfg.Params, c *btc.Configuration) *VertcoinParser {
-	return &VertcoinParser{BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c)}
+	p := &VertcoinParser{BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c)}
+	p.MessageSignatureMagic = "Vertcoin Signed Message:\n"
+	return p
 }
 
 // GetChainParams contains network parameters for the main Vertcoin network,
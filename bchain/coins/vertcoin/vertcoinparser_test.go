@@ -91,6 +91,8 @@ func init() {
 		Txid:      "d58c11aa970449c3e0ee5e0cdf78532435a9d2b28a2da284a8dd4dd6bdd0331c",
 		LockTime:  952180,
 		Version:   1,
+		VSize:     223,
+		Weight:    892,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -172,6 +172,8 @@ func init() {
 		Txid:      "93aae65e87ec46cd13b3032e1588c7db75e2b712514696efca5f2bfd80c16632",
 		LockTime:  536910,
 		Version:   2,
+		VSize:     462,
+		Weight:    1847,
 		Vin: []bchain.Vin{
 			{
 				ScriptSig: bchain.ScriptSig{
@@ -180,6 +182,10 @@ func init() {
 				Txid:     "59734383dab92ed1c817b40c783fe877608bab01a2d517da807dae98162833a7",
 				Vout:     0,
 				Sequence: 4294967293,
+				Witness: []string{
+					"304402207a2a1cc2f314c8c659a4bcbce099c5adfb217c03fa2b0cfc95bef48c1507901a0220324ab06cf2fe4c9e446a3a12c00fa611a479b5734f62c20b66e919e173a2c69901",
+					"02bbe6f37b4c44303b2186de6784d02cc5b86a65ca1203821b06a98e243b44c764",
+				},
 			},
 			{
 				ScriptSig: bchain.ScriptSig{
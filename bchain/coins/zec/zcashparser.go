@@ -54,10 +54,12 @@ type ZCashParser struct {
 
 // NewZCashParser returns new ZCashParser instance
 func NewZCashParser(params *chaincfg.Params, c *btc.Configuration) *ZCashParser {
-	return &ZCashParser{
+	p := &ZCashParser{
 		BitcoinLikeParser: btc.NewBitcoinLikeParser(params, c),
 		baseparser:        &bchain.BaseParser{},
 	}
+	p.MessageSignatureMagic = "Zcash Signed Message:\n"
+	return p
 }
 
 // GetChainParams contains network parameters for the main ZCash network,
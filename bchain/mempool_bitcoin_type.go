@@ -25,9 +25,13 @@ type MempoolBitcoinType struct {
 func NewMempoolBitcoinType(chain BlockChain, workers int, subworkers int) *MempoolBitcoinType {
 	m := &MempoolBitcoinType{
 		BaseMempool: BaseMempool{
-			chain:        chain,
-			txEntries:    make(map[string]txEntry),
-			addrDescToTx: make(map[string][]Outpoint),
+			chain:          chain,
+			txEntries:      make(map[string]txEntry),
+			addrDescToTx:   make(map[string][]Outpoint),
+			spentOutpoints: make(map[Outpoint]string),
+			replacedBy:     make(map[string]string),
+			replaces:       make(map[string][]string),
+			stats:          computeMempoolStats(nil, 0),
 		},
 		chanTxid:      make(chan string, 1),
 		chanAddrIndex: make(chan txidio, 1),
@@ -45,11 +49,11 @@ func NewMempoolBitcoinType(chain BlockChain, workers int, subworkers int) *Mempo
 				}(j)
 			}
 			for txid := range m.chanTxid {
-				io, ok := m.getTxAddrs(txid, chanInput, chanResult)
+				tio, ok := m.getTxAddrs(txid, chanInput, chanResult)
 				if !ok {
-					io = []addrIndex{}
+					tio = txidio{txid: txid, io: []addrIndex{}, fee: -1}
 				}
-				m.chanAddrIndex <- txidio{txid, io}
+				m.chanAddrIndex <- tio
 			}
 		}(i)
 	}
@@ -87,11 +91,11 @@ func (m *MempoolBitcoinType) getInputAddress(payload *chanInputPayload) *addrInd
 
 }
 
-func (m *MempoolBitcoinType) getTxAddrs(txid string, chanInput chan chanInputPayload, chanResult chan *addrIndex) ([]addrIndex, bool) {
+func (m *MempoolBitcoinType) getTxAddrs(txid string, chanInput chan chanInputPayload, chanResult chan *addrIndex) (txidio, bool) {
 	tx, err := m.chain.GetTransactionForMempool(txid)
 	if err != nil {
 		glog.Error("cannot get transaction ", txid, ": ", err)
-		return nil, false
+		return txidio{}, false
 	}
 	glog.V(2).Info("mempool: gettxaddrs ", txid, ", ", len(tx.Vin), " inputs")
 	mtx := m.txToMempoolTx(tx)
@@ -109,12 +113,15 @@ func (m *MempoolBitcoinType) getTxAddrs(txid string, chanInput chan chanInputPay
 			m.OnNewTxAddr(tx, addrDesc)
 		}
 	}
+	inputs := make([]Outpoint, 0, len(tx.Vin))
 	dispatched := 0
+	resolved := 0
 	for i := range tx.Vin {
 		input := &tx.Vin[i]
 		if input.Coinbase != "" {
 			continue
 		}
+		inputs = append(inputs, Outpoint{input.Txid, int32(input.Vout)})
 		payload := chanInputPayload{mtx, i}
 	loop:
 		for {
@@ -123,6 +130,7 @@ func (m *MempoolBitcoinType) getTxAddrs(txid string, chanInput chan chanInputPay
 			case ai := <-chanResult:
 				if ai != nil {
 					io = append(io, *ai)
+					resolved++
 				}
 				dispatched--
 			// send input to be processed
@@ -136,12 +144,30 @@ func (m *MempoolBitcoinType) getTxAddrs(txid string, chanInput chan chanInputPay
 		ai := <-chanResult
 		if ai != nil {
 			io = append(io, *ai)
+			resolved++
+		}
+	}
+	tio := txidio{txid: txid, io: io, inputs: inputs, fee: -1, vsize: int32(tx.VSize)}
+	if tio.vsize == 0 {
+		tio.vsize = int32(len(tx.Hex) / 2)
+	}
+	// the fee can be computed only if values of all inputs are known
+	if resolved == len(inputs) {
+		var fee big.Int
+		for i := range mtx.Vin {
+			fee.Add(&fee, &mtx.Vin[i].ValueSat)
+		}
+		for i := range mtx.Vout {
+			fee.Sub(&fee, &mtx.Vout[i].ValueSat)
+		}
+		if fee.IsInt64() && fee.Sign() >= 0 {
+			tio.fee = fee.Int64()
 		}
 	}
 	if m.OnNewTx != nil {
 		m.OnNewTx(mtx)
 	}
-	return io, true
+	return tio, true
 }
 
 // Resync gets mempool transactions and maps outputs to transactions.
@@ -155,19 +181,30 @@ func (m *MempoolBitcoinType) Resync() (int, error) {
 		return 0, err
 	}
 	glog.V(2).Info("mempool: resync ", len(txs), " txs")
-	onNewEntry := func(txid string, entry txEntry) {
-		if len(entry.addrIndexes) > 0 {
-			m.mux.Lock()
-			m.txEntries[txid] = entry
-			for _, si := range entry.addrIndexes {
-				m.addrDescToTx[si.addrDesc] = append(m.addrDescToTx[si.addrDesc], Outpoint{txid, si.n})
+	txTime := uint32(time.Now().Unix())
+	onNewEntry := func(tio txidio) {
+		if len(tio.io) > 0 {
+			entry := txEntry{
+				addrIndexes: tio.io,
+				time:        txTime,
+				inputs:      tio.inputs,
+				fee:         tio.fee,
+				vsize:       tio.vsize,
 			}
+			m.mux.Lock()
+			// transactions double spending the inputs of the new entry were replaced (RBF), evict them immediately
+			replaced := m.addEntryToMempool(tio.txid, entry)
 			m.mux.Unlock()
+			for _, r := range replaced {
+				glog.V(1).Info("mempool: tx ", r.Txid, " replaced by ", r.ReplacedBy)
+				if m.OnReplacedTx != nil {
+					m.OnReplacedTx(r)
+				}
+			}
 		}
 	}
 	txsMap := make(map[string]struct{}, len(txs))
 	dispatched := 0
-	txTime := uint32(time.Now().Unix())
 	// get transaction in parallel using goroutines created in NewUTXOMempool
 	for _, txid := range txs {
 		txsMap[txid] = struct{}{}
@@ -178,7 +215,7 @@ func (m *MempoolBitcoinType) Resync() (int, error) {
 				select {
 				// store as many processed transactions as possible
 				case tio := <-m.chanAddrIndex:
-					onNewEntry(tio.txid, txEntry{tio.io, txTime})
+					onNewEntry(tio)
 					dispatched--
 				// send transaction to be processed
 				case m.chanTxid <- txid:
@@ -190,16 +227,18 @@ func (m *MempoolBitcoinType) Resync() (int, error) {
 	}
 	for i := 0; i < dispatched; i++ {
 		tio := <-m.chanAddrIndex
-		onNewEntry(tio.txid, txEntry{tio.io, txTime})
+		onNewEntry(tio)
 	}
 
 	for txid, entry := range m.txEntries {
 		if _, exists := txsMap[txid]; !exists {
 			m.mux.Lock()
 			m.removeEntryFromMempool(txid, entry)
+			m.removeReplacements(txid)
 			m.mux.Unlock()
 		}
 	}
+	m.updateStats()
 	glog.Info("mempool: resync finished in ", time.Since(start), ", ", len(m.txEntries), " transactions in mempool")
 	return len(m.txEntries), nil
 }
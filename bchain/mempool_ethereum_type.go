@@ -96,7 +96,7 @@ func (m *MempoolEthereumType) createTxEntry(txid string, txTime uint32) (txEntry
 	if m.OnNewTx != nil {
 		m.OnNewTx(mtx)
 	}
-	return txEntry{addrIndexes: addrIndexes, time: txTime}, true
+	return txEntry{addrIndexes: addrIndexes, time: txTime, fee: -1}, true
 }
 
 // Resync ethereum type removes timed out transactions and returns number of transactions in mempool.
@@ -146,9 +146,7 @@ func (m *MempoolEthereumType) AddTransactionToMempool(txid string) {
 		}
 		m.mux.Lock()
 		m.txEntries[txid] = entry
-		for _, si := range entry.addrIndexes {
-			m.addrDescToTx[si.addrDesc] = append(m.addrDescToTx[si.addrDesc], Outpoint{txid, si.n})
-		}
+		m.addAddrDescTxs(txid, entry)
 		m.mux.Unlock()
 	}
 }
@@ -0,0 +1,140 @@
+package bchain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MempoolFeeRateBuckets are the lower bounds of the fee rate buckets of the mempool histogram, in sat/vByte
+var MempoolFeeRateBuckets = []int64{0, 1, 2, 3, 4, 5, 6, 8, 10, 12, 15, 20, 30, 40, 50, 60, 70, 80, 90, 100,
+	125, 150, 175, 200, 250, 300, 350, 400, 500, 600, 700, 800, 900, 1000, 1200, 1400, 1700, 2000}
+
+// maximum virtual size of a block, 4M weight units
+const maxBlockVSize = 1000000
+
+// number of projected blocks, the last block contains all remaining transactions
+const mempoolProjectedBlocks = 8
+
+// MempoolFeeRateBucket contains number and total virtual size of mempool transactions
+// with fee rate from FeePerVByte up to the FeePerVByte of the next bucket
+type MempoolFeeRateBucket struct {
+	FeePerVByte int64
+	Count       int
+	VSize       int64
+}
+
+// MempoolProjectedBlock contains statistics of a block that would be mined from the mempool transactions
+type MempoolProjectedBlock struct {
+	TxCount           int
+	VSize             int64
+	FeesSat           int64
+	MinFeePerVByte    float64
+	MedianFeePerVByte float64
+	MaxFeePerVByte    float64
+}
+
+// MempoolStats contains fee statistics of mempool transactions, computed on each mempool resync
+type MempoolStats struct {
+	Time            int64
+	Size            int
+	VSize           int64
+	FeesSat         int64
+	UnknownFeeCount int
+	FeeHistogram    []MempoolFeeRateBucket
+	ProjectedBlocks []MempoolProjectedBlock
+}
+
+type mempoolFeeEntry struct {
+	fee   int64
+	vsize int32
+	rate  float64
+}
+
+func roundFeeRate(r float64) float64 {
+	return math.Round(r*100) / 100
+}
+
+// computeMempoolStats computes the fee rate histogram and projects the mempool transactions into blocks
+// by their fee rate, ignoring dependencies between the transactions
+func computeMempoolStats(entries []mempoolFeeEntry, unknownFeeCount int) *MempoolStats {
+	s := &MempoolStats{
+		Time:            time.Now().Unix(),
+		Size:            len(entries) + unknownFeeCount,
+		UnknownFeeCount: unknownFeeCount,
+		FeeHistogram:    make([]MempoolFeeRateBucket, len(MempoolFeeRateBuckets)),
+	}
+	for i, b := range MempoolFeeRateBuckets {
+		s.FeeHistogram[i].FeePerVByte = b
+	}
+	for i := range entries {
+		e := &entries[i]
+		e.rate = float64(e.fee) / float64(e.vsize)
+		s.VSize += int64(e.vsize)
+		s.FeesSat += e.fee
+		// find the last bucket with the lower bound not greater than the fee rate
+		b := sort.Search(len(MempoolFeeRateBuckets), func(i int) bool { return float64(MempoolFeeRateBuckets[i]) > e.rate }) - 1
+		s.FeeHistogram[b].Count++
+		s.FeeHistogram[b].VSize += int64(e.vsize)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].rate > entries[j].rate })
+	from := 0
+	for from < len(entries) {
+		var vsize int64
+		to := from
+		if len(s.ProjectedBlocks) == mempoolProjectedBlocks-1 {
+			to = len(entries)
+			for i := from; i < to; i++ {
+				vsize += int64(entries[i].vsize)
+			}
+		} else {
+			for ; to < len(entries) && vsize+int64(entries[to].vsize) <= maxBlockVSize; to++ {
+				vsize += int64(entries[to].vsize)
+			}
+			// a transaction bigger than the block size, put it to the block alone
+			if to == from {
+				vsize = int64(entries[to].vsize)
+				to++
+			}
+		}
+		pb := MempoolProjectedBlock{
+			TxCount:           to - from,
+			VSize:             vsize,
+			MaxFeePerVByte:    roundFeeRate(entries[from].rate),
+			MinFeePerVByte:    roundFeeRate(entries[to-1].rate),
+			MedianFeePerVByte: roundFeeRate(entries[from+(to-from)/2].rate),
+		}
+		for i := from; i < to; i++ {
+			pb.FeesSat += entries[i].fee
+		}
+		s.ProjectedBlocks = append(s.ProjectedBlocks, pb)
+		from = to
+	}
+	return s
+}
+
+// updateStats recomputes the mempool statistics from the current mempool entries
+func (m *BaseMempool) updateStats() {
+	m.mux.Lock()
+	entries := make([]mempoolFeeEntry, 0, len(m.txEntries))
+	unknown := 0
+	for _, e := range m.txEntries {
+		if e.fee < 0 || e.vsize <= 0 {
+			unknown++
+			continue
+		}
+		entries = append(entries, mempoolFeeEntry{fee: e.fee, vsize: e.vsize})
+	}
+	m.mux.Unlock()
+	stats := computeMempoolStats(entries, unknown)
+	m.mux.Lock()
+	m.stats = stats
+	m.mux.Unlock()
+}
+
+// GetStats returns the mempool statistics computed in the last resync, nil if not available
+func (m *BaseMempool) GetStats() *MempoolStats {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.stats
+}
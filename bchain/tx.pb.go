@@ -36,6 +36,8 @@ type ProtoTransaction struct {
 	Vin       []*ProtoTransaction_VinType  `protobuf:"bytes,6,rep,name=Vin" json:"Vin,omitempty"`
 	Vout      []*ProtoTransaction_VoutType `protobuf:"bytes,7,rep,name=Vout" json:"Vout,omitempty"`
 	Version   int32                        `protobuf:"varint,8,opt,name=Version" json:"Version,omitempty"`
+	VSize     uint32                       `protobuf:"varint,9,opt,name=VSize,proto3" json:"VSize,omitempty"`
+	Weight    uint32                       `protobuf:"varint,10,opt,name=Weight,proto3" json:"Weight,omitempty"`
 }
 
 func (m *ProtoTransaction) Reset()                    { *m = ProtoTransaction{} }
@@ -99,6 +101,20 @@ func (m *ProtoTransaction) GetVersion() int32 {
 	return 0
 }
 
+func (m *ProtoTransaction) GetVSize() uint32 {
+	if m != nil {
+		return m.VSize
+	}
+	return 0
+}
+
+func (m *ProtoTransaction) GetWeight() uint32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
 type ProtoTransaction_VinType struct {
 	Coinbase     string   `protobuf:"bytes,1,opt,name=Coinbase" json:"Coinbase,omitempty"`
 	Txid         []byte   `protobuf:"bytes,2,opt,name=Txid,proto3" json:"Txid,omitempty"`
@@ -106,6 +122,7 @@ type ProtoTransaction_VinType struct {
 	ScriptSigHex []byte   `protobuf:"bytes,4,opt,name=ScriptSigHex,proto3" json:"ScriptSigHex,omitempty"`
 	Sequence     uint32   `protobuf:"varint,5,opt,name=Sequence" json:"Sequence,omitempty"`
 	Addresses    []string `protobuf:"bytes,6,rep,name=Addresses" json:"Addresses,omitempty"`
+	Witness      [][]byte `protobuf:"bytes,7,rep,name=Witness,proto3" json:"Witness,omitempty"`
 }
 
 func (m *ProtoTransaction_VinType) Reset()                    { *m = ProtoTransaction_VinType{} }
@@ -155,6 +172,13 @@ func (m *ProtoTransaction_VinType) GetAddresses() []string {
 	return nil
 }
 
+func (m *ProtoTransaction_VinType) GetWitness() [][]byte {
+	if m != nil {
+		return m.Witness
+	}
+	return nil
+}
+
 type ProtoTransaction_VoutType struct {
 	ValueSat        []byte   `protobuf:"bytes,1,opt,name=ValueSat,proto3" json:"ValueSat,omitempty"`
 	N               uint32   `protobuf:"varint,2,opt,name=N" json:"N,omitempty"`
@@ -204,27 +228,29 @@ func init() {
 func init() { proto.RegisterFile("tx.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 346 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x41, 0x6e, 0xea, 0x30,
-	0x14, 0x94, 0x89, 0x09, 0xe1, 0x7d, 0xd0, 0x47, 0x5e, 0x54, 0x16, 0xea, 0x22, 0x65, 0x95, 0x55,
-	0x16, 0x54, 0x3d, 0x40, 0xdb, 0x0d, 0x52, 0x2b, 0x84, 0x1c, 0x94, 0x7d, 0x12, 0x2c, 0xb0, 0x4a,
-	0x6d, 0x9a, 0x38, 0x12, 0x48, 0xbd, 0x51, 0x8f, 0xd0, 0xcb, 0x55, 0x7e, 0x84, 0x50, 0x90, 0xba,
-	0xf3, 0x8c, 0xdf, 0x64, 0xe6, 0x4d, 0x0c, 0x81, 0xdd, 0xc7, 0xbb, 0xd2, 0x58, 0xc3, 0xfc, 0xbc,
-	0xd8, 0x64, 0x4a, 0x4f, 0xbe, 0x29, 0x8c, 0x16, 0x8e, 0x59, 0x96, 0x99, 0xae, 0xb2, 0xc2, 0x2a,
-	0xa3, 0x19, 0x03, 0xba, 0xdc, 0xab, 0x15, 0x27, 0x21, 0x89, 0x06, 0x02, 0xcf, 0x6c, 0x04, 0xde,
-	0x4c, 0xee, 0x79, 0x07, 0x29, 0x77, 0x64, 0xb7, 0xd0, 0x7f, 0xda, 0x9a, 0xe2, 0xcd, 0xaa, 0x77,
-	0xc9, 0xbd, 0x90, 0x44, 0x54, 0x9c, 0x09, 0x36, 0x86, 0xe0, 0xf5, 0x74, 0x49, 0x43, 0x12, 0x0d,
-	0x45, 0x8b, 0xd9, 0x0d, 0xf8, 0x33, 0xa9, 0xd6, 0x1b, 0xcb, 0xbb, 0x78, 0xd3, 0x20, 0x36, 0x05,
-	0x2f, 0x55, 0x9a, 0xfb, 0xa1, 0x17, 0xfd, 0x9b, 0x86, 0xf1, 0x31, 0x62, 0x7c, 0x1d, 0x2f, 0x4e,
-	0x95, 0x5e, 0x1e, 0x76, 0x52, 0xb8, 0x61, 0xf6, 0x00, 0x34, 0x35, 0xb5, 0xe5, 0x3d, 0x14, 0xdd,
-	0xfd, 0x2d, 0x32, 0xb5, 0x45, 0x15, 0x8e, 0x33, 0x0e, 0xbd, 0x54, 0x96, 0x95, 0x32, 0x9a, 0x07,
-	0x21, 0x89, 0xba, 0xe2, 0x04, 0xc7, 0x5f, 0x04, 0x7a, 0x8d, 0x83, 0x5b, 0xe2, 0xd9, 0x28, 0x9d,
-	0x67, 0x95, 0xc4, 0x32, 0xfa, 0xa2, 0xc5, 0x6d, 0x49, 0x9d, 0x5f, 0x25, 0xb1, 0x26, 0x8c, 0x87,
-	0x6b, 0x1d, 0x9d, 0x26, 0x30, 0x48, 0x8a, 0x52, 0xed, 0x6c, 0xa2, 0xd6, 0xae, 0x41, 0x8a, 0xf3,
-	0x17, 0x9c, 0xf3, 0x49, 0xe4, 0x47, 0x2d, 0x75, 0x21, 0x9b, 0x4a, 0x5a, 0xec, 0x6a, 0x7e, 0x5c,
-	0xad, 0x4a, 0x59, 0x55, 0xb2, 0xc2, 0x6a, 0xfa, 0xe2, 0x4c, 0x8c, 0x3f, 0x21, 0x38, 0x6d, 0xe6,
-	0xbe, 0x92, 0x66, 0xdb, 0x5a, 0x26, 0x99, 0x6d, 0x7e, 0x5d, 0x8b, 0xd9, 0x00, 0xc8, 0x1c, 0xa3,
-	0x0e, 0x05, 0x99, 0xb3, 0x08, 0xfe, 0x1f, 0xfd, 0x17, 0x75, 0xfe, 0x22, 0x0f, 0x2e, 0x96, 0x87,
-	0x82, 0x6b, 0xfa, 0xd2, 0x9d, 0x5e, 0xb9, 0xe7, 0x3e, 0x3e, 0xa6, 0xfb, 0x9f, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0xa1, 0x51, 0x2e, 0xba, 0x58, 0x02, 0x00, 0x00,
+	// 377 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xcd, 0x8a, 0xdb, 0x30,
+	0x14, 0x85, 0x51, 0xfc, 0x13, 0xfb, 0xd6, 0xa1, 0x41, 0x94, 0x22, 0x42, 0x17, 0x6e, 0x56, 0x5e,
+	0x79, 0x91, 0xd2, 0x07, 0x68, 0xbb, 0x09, 0xb4, 0x84, 0x20, 0x07, 0x67, 0x6d, 0x3b, 0x22, 0x11,
+	0xcd, 0x48, 0x19, 0x4b, 0x86, 0x64, 0x98, 0xdd, 0x3c, 0xdb, 0xbc, 0xd7, 0x20, 0xf9, 0x27, 0x93,
+	0xc0, 0xec, 0x74, 0x8e, 0xee, 0x95, 0xbf, 0x7b, 0x7c, 0x21, 0xd0, 0xe7, 0xf4, 0x54, 0x4b, 0x2d,
+	0xb1, 0x5f, 0x56, 0x87, 0x82, 0x8b, 0xf9, 0x8b, 0x07, 0xd3, 0xb5, 0x71, 0x36, 0x75, 0x21, 0x54,
+	0x51, 0x69, 0x2e, 0x05, 0xc6, 0xe0, 0x6e, 0xce, 0x7c, 0x47, 0x50, 0x8c, 0x92, 0x88, 0xda, 0x33,
+	0x9e, 0x82, 0xb3, 0x64, 0x67, 0x32, 0xb2, 0x96, 0x39, 0xe2, 0x6f, 0x10, 0xfe, 0x3e, 0xca, 0xea,
+	0xbf, 0xe6, 0x0f, 0x8c, 0x38, 0x31, 0x4a, 0x5c, 0x7a, 0x35, 0xf0, 0x0c, 0x82, 0x7f, 0xfd, 0xa5,
+	0x1b, 0xa3, 0x64, 0x42, 0x07, 0x8d, 0xbf, 0x82, 0xbf, 0x64, 0x7c, 0x7f, 0xd0, 0xc4, 0xb3, 0x37,
+	0x9d, 0xc2, 0x0b, 0x70, 0x72, 0x2e, 0x88, 0x1f, 0x3b, 0xc9, 0xa7, 0x45, 0x9c, 0xb6, 0x88, 0xe9,
+	0x3d, 0x5e, 0x9a, 0x73, 0xb1, 0xb9, 0x9c, 0x18, 0x35, 0xc5, 0xf8, 0x27, 0xb8, 0xb9, 0x6c, 0x34,
+	0x19, 0xdb, 0xa6, 0xef, 0x1f, 0x37, 0xc9, 0x46, 0xdb, 0x2e, 0x5b, 0x8e, 0x09, 0x8c, 0x73, 0x56,
+	0x2b, 0x2e, 0x05, 0x09, 0x62, 0x94, 0x78, 0xb4, 0x97, 0xf8, 0x0b, 0x78, 0x79, 0xc6, 0x9f, 0x18,
+	0x09, 0x2d, 0x5b, 0x2b, 0x0c, 0xf2, 0xb6, 0x45, 0x86, 0x16, 0xb9, 0x55, 0xb3, 0x57, 0x04, 0xe3,
+	0x8e, 0xc7, 0x8c, 0xfc, 0x47, 0x72, 0x51, 0x16, 0x8a, 0xd9, 0xe8, 0x42, 0x3a, 0xe8, 0x21, 0xd2,
+	0xd1, 0xbb, 0x48, 0x71, 0x87, 0xee, 0xd8, 0x17, 0x5b, 0xae, 0x39, 0x44, 0x59, 0x55, 0xf3, 0x93,
+	0xce, 0xf8, 0xde, 0xe4, 0xed, 0xda, 0xfa, 0x1b, 0xcf, 0x7c, 0x27, 0x63, 0x8f, 0x0d, 0x13, 0x15,
+	0xeb, 0x02, 0x1c, 0xb4, 0xf9, 0x29, 0xbf, 0x76, 0xbb, 0x9a, 0x29, 0xc5, 0x94, 0x0d, 0x32, 0xa4,
+	0x57, 0xc3, 0x4c, 0xbd, 0xe5, 0x5a, 0x30, 0xa5, 0x6c, 0x5e, 0x11, 0xed, 0xe5, 0xec, 0x19, 0x82,
+	0x3e, 0x21, 0xf3, 0x7e, 0x5e, 0x1c, 0x1b, 0x96, 0x15, 0xba, 0x5b, 0x81, 0x41, 0xe3, 0x08, 0xd0,
+	0xca, 0x0e, 0x31, 0xa1, 0x68, 0x85, 0x13, 0xf8, 0xdc, 0x92, 0xad, 0x9b, 0xf2, 0x2f, 0xbb, 0x18,
+	0x60, 0xc7, 0x36, 0xdc, 0xdb, 0xb7, 0x5c, 0xee, 0x1d, 0x57, 0xe9, 0xdb, 0xa5, 0xfc, 0xf1, 0x36,
+	0x00, 0x79, 0x4f, 0xec, 0x82, 0xa0, 0x02, 0x00, 0x00,
 }
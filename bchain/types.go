@@ -57,6 +57,7 @@ type Vin struct {
 	ScriptSig ScriptSig `json:"scriptSig"`
 	Sequence  uint32    `json:"sequence"`
 	Addresses []string  `json:"addresses"`
+	Witness   []string  `json:"txinwitness,omitempty"`
 }
 
 // ScriptPubKey contains data about output script
@@ -87,6 +88,8 @@ type Tx struct {
 	BlockHeight uint32 `json:"blockHeight,omitempty"`
 	// BlockHash     string `json:"blockhash,omitempty"`
 	Confirmations    uint32      `json:"confirmations,omitempty"`
+	VSize            int64       `json:"vsize,omitempty"`
+	Weight           int64       `json:"weight,omitempty"`
 	Time             int64       `json:"time,omitempty"`
 	Blocktime        int64       `json:"blocktime,omitempty"`
 	CoinSpecificData interface{} `json:"-"`
@@ -141,9 +144,18 @@ type BlockInfo struct {
 	Txids      []string          `json:"tx,omitempty"`
 }
 
+// MempoolEntryFees contains fees of mempool entry as returned by newer backends
+type MempoolEntryFees struct {
+	Base       common.JSONNumber `json:"base"`
+	Modified   common.JSONNumber `json:"modified"`
+	Ancestor   common.JSONNumber `json:"ancestor"`
+	Descendant common.JSONNumber `json:"descendant"`
+}
+
 // MempoolEntry is used to get data about mempool entry
 type MempoolEntry struct {
 	Size            uint32 `json:"size"`
+	VSize           uint32 `json:"vsize"`
 	FeeSat          big.Int
 	Fee             common.JSONNumber `json:"fee"`
 	ModifiedFeeSat  big.Int
@@ -157,6 +169,10 @@ type MempoolEntry struct {
 	AncestorSize    uint32            `json:"ancestorsize"`
 	AncestorFees    uint32            `json:"ancestorfees"`
 	Depends         []string          `json:"depends"`
+	Fees            *MempoolEntryFees `json:"fees,omitempty"`
+	// AncestorFeesSat and DescendantFeesSat are filled from AncestorFees/DescendantFees or from Fees, whichever is returned by backend
+	AncestorFeesSat   big.Int `json:"-"`
+	DescendantFeesSat big.Int `json:"-"`
 }
 
 // ChainInfo is used to get information about blockchain
@@ -218,10 +234,33 @@ type Erc20Transfer struct {
 	Tokens   big.Int
 }
 
-// MempoolTxidEntry contains mempool txid with first seen time
+// TaprootInscription contains information about an inscription envelope found in a taproot leaf script
+type TaprootInscription struct {
+	ContentType string `json:"contentType,omitempty"`
+	ContentSize int    `json:"contentSize"`
+}
+
+// TaprootSpend contains decoded witness of an input spending a P2TR output
+// for the key path spend only the witness size is set, the script path fields are hex encoded
+type TaprootSpend struct {
+	ScriptPath       bool                 `json:"scriptPath"`
+	WitnessSize      int                  `json:"witnessSize"`
+	Annex            string               `json:"annex,omitempty"`
+	ControlBlock     string               `json:"controlBlock,omitempty"`
+	LeafVersion      int                  `json:"leafVersion,omitempty"`
+	InternalKey      string               `json:"internalKey,omitempty"`
+	MerklePathLength int                  `json:"merklePathLength,omitempty"`
+	LeafScript       string               `json:"leafScript,omitempty"`
+	LeafScriptAsm    string               `json:"leafScriptAsm,omitempty"`
+	Inscriptions     []TaprootInscription `json:"inscriptions,omitempty"`
+}
+
+// MempoolTxidEntry contains mempool txid with first seen time, fee (-1 if unknown) and virtual size
 type MempoolTxidEntry struct {
-	Txid string
-	Time uint32
+	Txid   string
+	Time   uint32
+	FeeSat int64
+	VSize  int32
 }
 
 // ScriptType - type of output script parsed from xpub (descriptor)
@@ -234,16 +273,26 @@ const (
 	P2SHWPKH
 	P2WPKH
 	P2TR
+	P2SHMULTISIG
+	P2WSHMULTISIG
+	P2SHWSHMULTISIG
 )
 
 // XpubDescriptor contains parsed data from xpub descriptor
 type XpubDescriptor struct {
 	XpubDescriptor string // The whole descriptor
-	Xpub           string // Xpub part of the descriptor
+	Xpub           string // Xpub part of the descriptor, the first xpub in case of multisig descriptor
 	Type           ScriptType
 	Bip            string
 	ChangeIndexes  []uint32
 	ExtKey         interface{} // extended key parsed from xpub, usually of type *hdkeychain.ExtendedKey
+	// multisig descriptors (multi, sortedmulti) only
+	RequiredSigs int           // number of signatures required by the multisig script
+	SortedKeys   bool          // keys are sorted in the script (sortedmulti)
+	Xpubs        []string      // all xpubs of the descriptor
+	ExtKeys      []interface{} // extended keys parsed from Xpubs
+	OriginPath   string        // key origin derivation path of the first xpub, e.g. /48'/0'/0'/2'
+	NonRanged    bool          // keys are used without derivation, the descriptor describes a single script
 }
 
 // MempoolTxidEntries is array of MempoolTxidEntry
@@ -258,6 +307,35 @@ type OnNewTxAddrFunc func(tx *Tx, desc AddressDescriptor)
 // OnNewTxFunc is used to send notification about a new transaction/address
 type OnNewTxFunc func(tx *MempoolTx)
 
+// ReplacedMempoolTx contains information about a mempool transaction replaced by a conflicting transaction (RBF)
+type ReplacedMempoolTx struct {
+	Txid       string
+	ReplacedBy string
+	AddrDescs  []AddressDescriptor
+}
+
+// OnReplacedTxFunc is used to send notification about a mempool transaction replaced by a conflicting transaction
+type OnReplacedTxFunc func(tx *ReplacedMempoolTx)
+
+// DisconnectedTx contains information about a transaction of a block disconnected by a chain reorganization
+type DisconnectedTx struct {
+	Txid      string
+	AddrDescs []AddressDescriptor
+}
+
+// ChainReorg contains information about a chain reorganization, the blocks above the fork height were disconnected
+type ChainReorg struct {
+	ForkHeight         uint32
+	DisconnectedHashes []string
+	Txs                []DisconnectedTx
+}
+
+// OnReorgFunc is used to send notification about a chain reorganization
+type OnReorgFunc func(reorg *ChainReorg)
+
+// OnNewMempoolStatsFunc is used to send notification about new mempool statistics computed in mempool resync
+type OnNewMempoolStatsFunc func(stats *MempoolStats)
+
 // AddrDescForOutpointFunc returns address descriptor and value for given outpoint or nil if outpoint not found
 type AddrDescForOutpointFunc func(outpoint Outpoint) (AddressDescriptor, *big.Int)
 
@@ -269,7 +347,7 @@ type BlockChain interface {
 	// create mempool but do not initialize it
 	CreateMempool(BlockChain) (Mempool, error)
 	// initialize mempool, create ZeroMQ (or other) subscription
-	InitializeMempool(AddrDescForOutpointFunc, OnNewTxAddrFunc, OnNewTxFunc) error
+	InitializeMempool(AddrDescForOutpointFunc, OnNewTxAddrFunc, OnNewTxFunc, OnReplacedTxFunc) error
 	// shutdown mempool, ZeroMQ and block chain connections
 	Shutdown(ctx context.Context) error
 	// chain info
@@ -339,11 +417,17 @@ type BlockChainParser interface {
 	PackBlockHash(hash string) ([]byte, error)
 	UnpackBlockHash(buf []byte) (string, error)
 	ParseBlock(b []byte) (*Block, error)
+	// GetMessageSignatureMagic returns the prefix prepended to a message before it is hashed and signed,
+	// empty if the signed messages are not supported
+	GetMessageSignatureMagic() string
 	// xpub
 	ParseXpub(xpub string) (*XpubDescriptor, error)
 	DerivationBasePath(descriptor *XpubDescriptor) (string, error)
 	DeriveAddressDescriptors(descriptor *XpubDescriptor, change uint32, indexes []uint32) ([]AddressDescriptor, error)
 	DeriveAddressDescriptorsFromTo(descriptor *XpubDescriptor, change uint32, fromIndex uint32, toIndex uint32) ([]AddressDescriptor, error)
+	// taproot witness decoding, prevAddrDescs are address descriptors of the outputs spent by the inputs of the tx
+	// returns a slice aligned with tx.Vin, nil for inputs which do not spend P2TR outputs
+	DecodeTaprootSpends(tx *Tx, prevAddrDescs []AddressDescriptor) ([]*TaprootSpend, error)
 	// EthereumType specific
 	EthereumTypeGetErc20FromTx(tx *Tx) ([]Erc20Transfer, error)
 }
@@ -355,4 +439,9 @@ type Mempool interface {
 	GetAddrDescTransactions(addrDesc AddressDescriptor) ([]Outpoint, error)
 	GetAllEntries() MempoolTxidEntries
 	GetTransactionTime(txid string) uint32
+	GetTxEntry(txid string) (MempoolTxidEntry, []Outpoint, bool)
+	GetTxReplacement(txid string) (string, []string)
+	GetSpendingTxid(outpoint Outpoint) string
+	GetAddrDescForScriptHash(scriptHash []byte) AddressDescriptor
+	GetStats() *MempoolStats
 }
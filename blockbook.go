@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"math/big"
 	"math/rand"
 	"net/http"
 	_ "net/http/pprof"
@@ -53,7 +55,7 @@ var (
 	rollbackHeight = flag.Int("rollback", -1, "rollback to the given height and quit")
 
 	synchronize = flag.Bool("sync", false, "synchronizes until tip, if together with zeromq, keeps index synchronized")
-	repair      = flag.Bool("repair", false, "repair the database")
+	repair      = flag.Bool("repair", false, "repair the database, with -verify repair the discrepancies which can be derived from other columns of the index")
 	fixUtxo     = flag.Bool("fixutxo", false, "check and fix utxo db and exit")
 	prof        = flag.String("prof", "", "http server binding [address]:port of the interface to profiling data /debug/pprof/ (default no profiling)")
 
@@ -75,9 +77,36 @@ var (
 
 	enableSubNewTx = flag.Bool("enablesubnewtx", false, "enable support for subscribing to all new transactions")
 
-	computeColumnStats  = flag.Bool("computedbstats", false, "compute column stats and exit")
-	computeFeeStatsFlag = flag.Bool("computefeestats", false, "compute fee stats for blocks in blockheight-blockuntil range and exit")
-	dbStatsPeriodHours  = flag.Int("dbstatsperiod", 24, "period of db stats collection in hours, 0 disables stats collection")
+	computeColumnStats     = flag.Bool("computedbstats", false, "compute column stats and exit")
+	computeFeeStatsFlag    = flag.Bool("computefeestats", false, "compute fee stats for blocks in blockheight-blockuntil range, store them to the index and exit")
+	computeSupplyStatsFlag = flag.Bool("computesupplystats", false, "compute supply stats of the blocks indexed without them, store them to the index and exit, interrupted computation is resumed on next run")
+	computeBlockFilters    = flag.Bool("computeblockfilters", false, "compute block filters of the blocks indexed without them, store them to the index and exit, interrupted computation is resumed on next run, requires -blockfilters")
+	dbStatsPeriodHours     = flag.Int("dbstatsperiod", 24, "period of db stats collection in hours, 0 disables stats collection")
+	migrateDB              = flag.Bool("migrate", false, "migrate the index to the current data format version before start, interrupted migration is resumed on next run")
+
+	checkpointDir     = flag.String("checkpointdir", "", "directory for checkpoints of the index created by -createcheckpoint or by the internal server endpoint admin/checkpoint")
+	createCheckpoint  = flag.Bool("createcheckpoint", false, "create checkpoint of the index in the -checkpointdir directory and exit")
+	restoreCheckpoint = flag.String("restorecheckpoint", "", "validate the checkpoint in the given directory against the backend and restore it as the index before start, the index directory must not exist or be empty")
+
+	verify       = flag.Bool("verify", false, "verify consistency of all columns of the index, write the report and exit")
+	verifyReport = flag.String("verifyreport", "", "with -verify, file to which the report is written in JSON format (default standard output)")
+
+	richList      = flag.Bool("richlist", false, "maintain the list of addresses ordered by balance, the list is built from the index when enabled for the first time; for Ethereum type coins maintain the holders of ERC20 tokens, must be used from the initial synchronization")
+	opReturnIndex = flag.Bool("opreturnindex", false, "maintain the index of OP_RETURN payloads (Bitcoin type coins only), the index is built from the stored transactions when enabled for the first time")
+	blockFilters  = flag.Bool("blockfilters", false, "compute and store the BIP158 block filters of the connected blocks (Bitcoin type coins only), the filters of the blocks indexed before are computed by -computeblockfilters")
+
+	scriptHashIndex   = flag.Bool("scripthashindex", false, "maintain the index of script hashes of the addresses required by the electrum server (Bitcoin type coins only), the index is built from the index when enabled for the first time, implied by -electrum")
+	electrumBinding   = flag.String("electrum", "", "electrum protocol server binding [address]:port (default no electrum server)")
+	electrumCertFiles = flag.String("electrumcertfile", "", "to enable SSL in the electrum server specify path to certificate files without extension, expecting <electrumcertfile>.crt and <electrumcertfile>.key (default no SSL)")
+
+	grpcBinding   = flag.String("grpc", "", "grpc server binding [address]:port (default no grpc server)")
+	grpcCertFiles = flag.String("grpccertfile", "", "to enable TLS in the grpc server specify path to certificate files without extension, expecting <grpccertfile>.crt and <grpccertfile>.key (default no TLS)")
+
+	esplora = flag.Bool("esplora", false, "serve the Esplora compatible REST API on the path esplora/ of the public interface (Bitcoin type coins only), the scripthash requests require -scripthashindex")
+
+	readOnly               = flag.Bool("readonly", false, "run as read only API replica of the index in -datadir maintained by another blockbook process, the replica does not synchronize the index")
+	replicaDir             = flag.String("replicadir", "", "directory of the secondary instance of the read only replica (default -datadir with suffix -replica)")
+	replicaCatchUpPeriodMs = flag.Int("replicacatchupperiod", 2000, "period in milliseconds in which the read only replica catches up with the index")
 
 	// resync index at least each resyncIndexPeriodMs (could be more often if invoked by message from ZeroMQ)
 	resyncIndexPeriodMs = flag.Int("resyncindexperiod", 935093, "resync index period in milliseconds")
@@ -99,10 +128,14 @@ var (
 	txCache                       *db.TxCache
 	metrics                       *common.Metrics
 	syncWorker                    *db.SyncWorker
+	replicaWorker                 *db.ReplicaWorker
 	internalState                 *common.InternalState
 	callbacksOnNewBlock           []bchain.OnNewBlockFunc
 	callbacksOnNewTxAddr          []bchain.OnNewTxAddrFunc
 	callbacksOnNewTx              []bchain.OnNewTxFunc
+	callbacksOnReplacedTx         []bchain.OnReplacedTxFunc
+	callbacksOnReorg              []bchain.OnReorgFunc
+	callbacksOnNewMempoolStats    []bchain.OnNewMempoolStatsFunc
 	callbacksOnNewFiatRatesTicker []fiat.OnNewFiatRatesTicker
 	chanOsSignal                  chan os.Signal
 	inShutdown                    int32
@@ -143,7 +176,7 @@ func mainWithExitCode() int {
 		}()
 	}
 
-	if *repair {
+	if *repair && !*verify {
 		if err := db.RepairRocksDB(*dbPath); err != nil {
 			glog.Errorf("RepairRocksDB %s: %v", *dbPath, err)
 			return exitCodeFatal
@@ -156,6 +189,13 @@ func mainWithExitCode() int {
 		return exitCodeFatal
 	}
 
+	if *readOnly && (*synchronize || *fixUtxo || *migrateDB || *computeFeeStatsFlag || *computeSupplyStatsFlag || *computeBlockFilters ||
+		*computeColumnStats || *createCheckpoint || *restoreCheckpoint != "" || *rollbackHeight >= 0 || *blockFrom >= 0 || *verify ||
+		*richList || *opReturnIndex || *blockFilters || *scriptHashIndex) {
+		glog.Error("The -readonly flag cannot be combined with flags modifying the index")
+		return exitCodeFatal
+	}
+
 	coin, coinShortcut, coinLabel, err := coins.GetCoinNameFromConfig(*blockchain)
 	if err != nil {
 		glog.Error("config: ", err)
@@ -175,7 +215,24 @@ func mainWithExitCode() int {
 		return exitCodeFatal
 	}
 
-	index, err = db.NewRocksDB(*dbPath, *dbCache, *dbMaxOpenFiles, chain.GetChainParser(), metrics)
+	if *restoreCheckpoint != "" {
+		info, err := db.RestoreCheckpoint(*restoreCheckpoint, *dbPath, coin, chain)
+		if err != nil {
+			glog.Error("restoreCheckpoint: ", err)
+			return exitCodeFatal
+		}
+		glog.Info("restoreCheckpoint: index restored at height ", info.Height, ", hash ", info.Hash)
+	}
+
+	if *readOnly {
+		secondaryPath := *replicaDir
+		if secondaryPath == "" {
+			secondaryPath = strings.TrimRight(*dbPath, "/") + "-replica"
+		}
+		index, err = db.NewRocksDBReadOnly(*dbPath, secondaryPath, *dbCache, *dbMaxOpenFiles, chain.GetChainParser(), metrics)
+	} else {
+		index, err = db.NewRocksDB(*dbPath, *dbCache, *dbMaxOpenFiles, chain.GetChainParser(), metrics)
+	}
 	if err != nil {
 		glog.Error("rocksDB: ", err)
 		return exitCodeFatal
@@ -187,9 +244,26 @@ func mainWithExitCode() int {
 		glog.Error("internalState: ", err)
 		return exitCodeFatal
 	}
+	index.SetInternalState(internalState)
+
+	if version, needed := index.MigrationNeeded(internalState); needed {
+		if !*migrateDB {
+			glog.Error("internalState: DB version ", version, " must be migrated to the current version, run with the -migrate flag")
+			return exitCodeFatal
+		}
+		err = index.Migrate(internalState, chanOsSignal)
+		if err == db.ErrOperationInterrupted {
+			glog.Info("migrate: interrupted, the migration will be resumed on next run with the -migrate flag")
+			return exitCodeOK
+		}
+		if err != nil {
+			glog.Error("migrate: ", err)
+			return exitCodeFatal
+		}
+	}
 
-	// fix possible inconsistencies in the UTXO index
-	if *fixUtxo || !internalState.UtxoChecked {
+	// fix possible inconsistencies in the UTXO index, the replica relies on the primary
+	if *fixUtxo || (!internalState.UtxoChecked && !*readOnly && !*verify) {
 		err = index.FixUtxos(chanOsSignal)
 		if err != nil {
 			glog.Error("fixUtxos: ", err)
@@ -197,7 +271,6 @@ func mainWithExitCode() int {
 		}
 		internalState.UtxoChecked = true
 	}
-	index.SetInternalState(internalState)
 	if *fixUtxo {
 		err = index.StoreInternalState(internalState)
 		if err != nil {
@@ -207,12 +280,57 @@ func mainWithExitCode() int {
 		return exitCodeOK
 	}
 
+	// verification runs also on the index in inconsistent state
+	if *verify {
+		if err = verifyIndex(); err != nil {
+			glog.Error("verify: ", err)
+			return exitCodeFatal
+		}
+		return exitCodeOK
+	}
+
 	if internalState.DbState != common.DbStateClosed {
 		if internalState.DbState == common.DbStateInconsistent {
 			glog.Error("internalState: database is in inconsistent state and cannot be used")
 			return exitCodeFatal
 		}
-		glog.Warning("internalState: database was left in open state, possibly previous ungraceful shutdown")
+		if !*readOnly {
+			glog.Warning("internalState: database was left in open state, possibly previous ungraceful shutdown")
+		}
+	}
+
+	if !*readOnly {
+		err = index.InitRichList(*richList, chanOsSignal)
+		if err == db.ErrOperationInterrupted {
+			glog.Info("richList: interrupted, the rich list will be built on next run with the -richlist flag")
+			return exitCodeOK
+		}
+		if err != nil {
+			glog.Error("richList: ", err)
+			return exitCodeFatal
+		}
+		err = index.InitOpReturnIndex(*opReturnIndex, chanOsSignal)
+		if err == db.ErrOperationInterrupted {
+			glog.Info("opReturnIndex: interrupted, the OP_RETURN index will be built on next run with the -opreturnindex flag")
+			return exitCodeOK
+		}
+		if err != nil {
+			glog.Error("opReturnIndex: ", err)
+			return exitCodeFatal
+		}
+		if err = index.InitBlockFilters(*blockFilters); err != nil {
+			glog.Error("blockFilters: ", err)
+			return exitCodeFatal
+		}
+		err = index.InitScriptHashIndex(*scriptHashIndex || *electrumBinding != "", chanOsSignal)
+		if err == db.ErrOperationInterrupted {
+			glog.Info("scriptHashIndex: interrupted, the script hash index will be built on next run with the -scripthashindex or -electrum flag")
+			return exitCodeOK
+		}
+		if err != nil {
+			glog.Error("scriptHashIndex: ", err)
+			return exitCodeFatal
+		}
 	}
 
 	if *computeFeeStatsFlag {
@@ -225,6 +343,53 @@ func mainWithExitCode() int {
 		return exitCodeOK
 	}
 
+	if *computeSupplyStatsFlag {
+		internalState.DbState = common.DbStateOpen
+		err = index.ComputeMissingSupplyStats(chain, chanOsSignal)
+		if err == db.ErrOperationInterrupted {
+			glog.Info("computeSupplyStats: interrupted, the computation will be resumed on next run with the -computesupplystats flag")
+			return exitCodeOK
+		}
+		if err != nil {
+			glog.Error("computeSupplyStats: ", err)
+			return exitCodeFatal
+		}
+		return exitCodeOK
+	}
+
+	if *computeBlockFilters {
+		if !*blockFilters {
+			glog.Error("computeBlockFilters: the block filters are not enabled, use the -blockfilters flag")
+			return exitCodeFatal
+		}
+		internalState.DbState = common.DbStateOpen
+		err = index.ComputeMissingBlockFilters(chain, chanOsSignal)
+		if err == db.ErrOperationInterrupted {
+			glog.Info("computeBlockFilters: interrupted, the computation will be resumed on next run with the -computeblockfilters flag")
+			return exitCodeOK
+		}
+		if err != nil {
+			glog.Error("computeBlockFilters: ", err)
+			return exitCodeFatal
+		}
+		return exitCodeOK
+	}
+
+	if *createCheckpoint {
+		if *checkpointDir == "" {
+			glog.Error("createCheckpoint: missing -checkpointdir")
+			return exitCodeFatal
+		}
+		path := db.NewCheckpointPath(*checkpointDir)
+		info, err := index.CreateCheckpoint(path)
+		if err != nil {
+			glog.Error("createCheckpoint: ", err)
+			return exitCodeFatal
+		}
+		glog.Info("createCheckpoint: checkpoint at height ", info.Height, ", hash ", info.Hash, " created in ", path)
+		return exitCodeOK
+	}
+
 	if *computeColumnStats {
 		internalState.DbState = common.DbStateOpen
 		err = index.ComputeInternalStateColumnStats(chanOsSignal)
@@ -236,18 +401,27 @@ func mainWithExitCode() int {
 		return exitCodeOK
 	}
 
-	syncWorker, err = db.NewSyncWorker(index, chain, *syncWorkers, *syncChunk, *blockFrom, *dryRun, chanOsSignal, metrics, internalState)
-	if err != nil {
-		glog.Errorf("NewSyncWorker %v", err)
-		return exitCodeFatal
-	}
+	if *readOnly {
+		// the replica does not synchronize the index, it only catches up with the primary
+		replicaWorker, err = db.NewReplicaWorker(index, internalState)
+		if err != nil {
+			glog.Error("NewReplicaWorker ", err)
+			return exitCodeFatal
+		}
+	} else {
+		syncWorker, err = db.NewSyncWorker(index, chain, *syncWorkers, *syncChunk, *blockFrom, *dryRun, chanOsSignal, metrics, internalState)
+		if err != nil {
+			glog.Errorf("NewSyncWorker %v", err)
+			return exitCodeFatal
+		}
 
-	// set the DbState to open at this moment, after all important workers are initialized
-	internalState.DbState = common.DbStateOpen
-	err = index.StoreInternalState(internalState)
-	if err != nil {
-		glog.Error("internalState: ", err)
-		return exitCodeFatal
+		// set the DbState to open at this moment, after all important workers are initialized
+		internalState.DbState = common.DbStateOpen
+		err = index.StoreInternalState(internalState)
+		if err != nil {
+			glog.Error("internalState: ", err)
+			return exitCodeFatal
+		}
 	}
 
 	if *rollbackHeight >= 0 {
@@ -258,7 +432,8 @@ func mainWithExitCode() int {
 		return exitCodeOK
 	}
 
-	if txCache, err = db.NewTxCache(index, chain, metrics, internalState, !*noTxCache); err != nil {
+	// the replica cannot store the transactions to the txcache
+	if txCache, err = db.NewTxCache(index, chain, metrics, internalState, !*noTxCache && !*readOnly); err != nil {
 		glog.Error("txCache ", err)
 		return exitCodeFatal
 	}
@@ -268,6 +443,11 @@ func mainWithExitCode() int {
 		glog.Error("blockbookAppInfoMetric ", err)
 	}
 
+	if err = initFeeEstimator(*blockchain, index, chain, txCache, internalState, metrics); err != nil {
+		glog.Error("fee estimator: ", err)
+		return exitCodeFatal
+	}
+
 	var internalServer *server.InternalServer
 	if *internalBinding != "" {
 		internalServer, err = startInternalServer()
@@ -289,7 +469,7 @@ func mainWithExitCode() int {
 	if *synchronize {
 		internalState.SyncMode = true
 		internalState.InitialSync = true
-		if err := syncWorker.ResyncIndex(nil, true); err != nil {
+		if err := syncWorker.ResyncIndex(nil, nil, true); err != nil {
 			if err != db.ErrOperationInterrupted {
 				glog.Error("resyncIndex ", err)
 				return exitCodeFatal
@@ -297,34 +477,63 @@ func mainWithExitCode() int {
 			return exitCodeOK
 		}
 		// initialize mempool after the initial sync is complete
-		var addrDescForOutpoint bchain.AddrDescForOutpointFunc
-		if chain.GetChainParser().GetChainType() == bchain.ChainBitcoinType {
-			addrDescForOutpoint = index.AddrDescForOutpoint
+		if err = initMempool(); err != nil {
+			return exitCodeFatal
 		}
-		err = chain.InitializeMempool(addrDescForOutpoint, onNewTxAddr, onNewTx)
-		if err != nil {
-			glog.Error("initializeMempool ", err)
+		go syncIndexLoop()
+		go syncMempoolLoop()
+		internalState.InitialSync = false
+	}
+	if *readOnly {
+		if err = replicaWorker.CatchUp(nil, nil); err != nil {
+			glog.Error("replica catch up ", err)
 			return exitCodeFatal
 		}
-		var mempoolCount int
-		if mempoolCount, err = mempool.Resync(); err != nil {
-			glog.Error("resyncMempool ", err)
+		if err = initMempool(); err != nil {
 			return exitCodeFatal
 		}
-		internalState.FinishedMempoolSync(mempoolCount)
-		go syncIndexLoop()
+		go replicaCatchUpLoop()
 		go syncMempoolLoop()
-		internalState.InitialSync = false
+	} else {
+		go storeInternalStateLoop()
 	}
-	go storeInternalStateLoop()
 
 	if publicServer != nil {
 		// start full public interface
 		callbacksOnNewBlock = append(callbacksOnNewBlock, publicServer.OnNewBlock)
 		callbacksOnNewTxAddr = append(callbacksOnNewTxAddr, publicServer.OnNewTxAddr)
 		callbacksOnNewTx = append(callbacksOnNewTx, publicServer.OnNewTx)
+		callbacksOnReplacedTx = append(callbacksOnReplacedTx, publicServer.OnReplacedTx)
+		callbacksOnReorg = append(callbacksOnReorg, publicServer.OnReorg)
+		callbacksOnNewMempoolStats = append(callbacksOnNewMempoolStats, publicServer.OnNewMempoolStats)
 		callbacksOnNewFiatRatesTicker = append(callbacksOnNewFiatRatesTicker, publicServer.OnNewFiatRatesTicker)
 		publicServer.ConnectFullPublicInterface()
+		if *esplora {
+			publicServer.ConnectEsploraInterface()
+		}
+	}
+
+	var electrumServer *server.ElectrumServer
+	if *electrumBinding != "" {
+		electrumServer, err = startElectrumServer()
+		if err != nil {
+			glog.Error("electrum server: ", err)
+			return exitCodeFatal
+		}
+		callbacksOnNewBlock = append(callbacksOnNewBlock, electrumServer.OnNewBlock)
+		callbacksOnNewTx = append(callbacksOnNewTx, electrumServer.OnNewTx)
+	}
+
+	var grpcServer *server.GrpcServer
+	if *grpcBinding != "" {
+		grpcServer, err = startGrpcServer()
+		if err != nil {
+			glog.Error("grpc server: ", err)
+			return exitCodeFatal
+		}
+		callbacksOnNewBlock = append(callbacksOnNewBlock, grpcServer.OnNewBlock)
+		callbacksOnNewTxAddr = append(callbacksOnNewTxAddr, grpcServer.OnNewTxAddr)
+		callbacksOnNewFiatRatesTicker = append(callbacksOnNewFiatRatesTicker, grpcServer.OnNewFiatRatesTicker)
 	}
 
 	if *blockFrom >= 0 {
@@ -345,10 +554,12 @@ func mainWithExitCode() int {
 		}
 	}
 
-	if internalServer != nil || publicServer != nil || chain != nil {
-		// start fiat rates downloader only if not shutting down immediately
-		initFiatRatesDownloader(index, *blockchain)
-		waitForSignalAndShutdown(internalServer, publicServer, chain, 10*time.Second)
+	if internalServer != nil || publicServer != nil || electrumServer != nil || grpcServer != nil || chain != nil {
+		// start fiat rates downloader only if not shutting down immediately, the replica reads the rates stored by the primary
+		if !*readOnly {
+			initFiatRatesDownloader(index, *blockchain)
+		}
+		waitForSignalAndShutdown(internalServer, publicServer, electrumServer, grpcServer, chain, 10*time.Second)
 	}
 
 	if *synchronize {
@@ -358,6 +569,11 @@ func mainWithExitCode() int {
 		<-chanSyncIndexDone
 		<-chanSyncMempoolDone
 		<-chanStoreInternalStateDone
+	} else if *readOnly {
+		close(chanSyncIndex)
+		close(chanSyncMempool)
+		<-chanSyncIndexDone
+		<-chanSyncMempoolDone
 	}
 	return exitCodeOK
 }
@@ -387,7 +603,7 @@ func getBlockChainWithRetry(coin string, configfile string, pushHandler func(bch
 }
 
 func startInternalServer() (*server.InternalServer, error) {
-	internalServer, err := server.NewInternalServer(*internalBinding, *certFiles, index, chain, mempool, txCache, metrics, internalState)
+	internalServer, err := server.NewInternalServer(*internalBinding, *certFiles, *checkpointDir, index, chain, mempool, txCache, metrics, internalState)
 	if err != nil {
 		return nil, err
 	}
@@ -425,6 +641,40 @@ func startPublicServer() (*server.PublicServer, error) {
 	return publicServer, err
 }
 
+func startElectrumServer() (*server.ElectrumServer, error) {
+	// the read only replica uses the script hash index maintained by the primary process
+	if !index.IsScriptHashIndexEnabled() {
+		return nil, errors.New("the script hash index is not built, run the primary process with the -scripthashindex or -electrum flag")
+	}
+	electrumServer, err := server.NewElectrumServer(*electrumBinding, *electrumCertFiles, index, chain, mempool, txCache, metrics, internalState)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := electrumServer.Run(); err != nil {
+			glog.Error("electrum server: ", err)
+		} else {
+			glog.Info("electrum server: closed")
+		}
+	}()
+	return electrumServer, nil
+}
+
+func startGrpcServer() (*server.GrpcServer, error) {
+	grpcServer, err := server.NewGrpcServer(*grpcBinding, *grpcCertFiles, index, chain, mempool, txCache, metrics, internalState)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := grpcServer.Run(); err != nil {
+			glog.Error("grpc server: ", err)
+		} else {
+			glog.Info("grpc server: closed")
+		}
+	}()
+	return grpcServer, nil
+}
+
 func performRollback() error {
 	bestHeight, bestHash, err := index.GetBestBlock()
 	if err != nil {
@@ -536,11 +786,11 @@ func syncIndexLoop() {
 	glog.Info("syncIndexLoop starting")
 	// resync index about every 15 minutes if there are no chanSyncIndex requests, with debounce 1 second
 	tickAndDebounce(time.Duration(*resyncIndexPeriodMs)*time.Millisecond, debounceResyncIndexMs*time.Millisecond, chanSyncIndex, func() {
-		if err := syncWorker.ResyncIndex(onNewBlockHash, false); err != nil {
+		if err := syncWorker.ResyncIndex(onNewBlockHash, onReorg, false); err != nil {
 			glog.Error("syncIndexLoop ", errors.ErrorStack(err), ", will retry...")
 			// retry once in case of random network error, after a slight delay
 			time.Sleep(time.Millisecond * 2500)
-			if err := syncWorker.ResyncIndex(onNewBlockHash, false); err != nil {
+			if err := syncWorker.ResyncIndex(onNewBlockHash, onReorg, false); err != nil {
 				glog.Error("syncIndexLoop ", errors.ErrorStack(err))
 			}
 		}
@@ -548,6 +798,78 @@ func syncIndexLoop() {
 	glog.Info("syncIndexLoop stopped")
 }
 
+// replicaCatchUpLoop catches up the read only replica with the index maintained by the primary,
+// the requests to resync the index from the backend notifications trigger the catch up as well
+func replicaCatchUpLoop() {
+	defer close(chanSyncIndexDone)
+	glog.Info("replicaCatchUpLoop starting")
+	tickAndDebounce(time.Duration(*replicaCatchUpPeriodMs)*time.Millisecond, debounceResyncIndexMs*time.Millisecond, chanSyncIndex, func() {
+		if err := replicaWorker.CatchUp(onNewBlockHash, onReorg); err != nil {
+			glog.Error("replicaCatchUpLoop ", errors.ErrorStack(err))
+		}
+	})
+	glog.Info("replicaCatchUpLoop stopped")
+}
+
+func initMempool() error {
+	var addrDescForOutpoint bchain.AddrDescForOutpointFunc
+	if chain.GetChainParser().GetChainType() == bchain.ChainBitcoinType {
+		addrDescForOutpoint = func(outpoint bchain.Outpoint) (bchain.AddressDescriptor, *big.Int) {
+			index.BeginRead()
+			defer index.EndRead()
+			return index.AddrDescForOutpoint(outpoint)
+		}
+	}
+	err := chain.InitializeMempool(addrDescForOutpoint, onNewTxAddr, onNewTx, onReplacedTx)
+	if err != nil {
+		glog.Error("initializeMempool ", err)
+		return err
+	}
+	mempoolCount, err := mempool.Resync()
+	if err != nil {
+		glog.Error("resyncMempool ", err)
+		return err
+	}
+	internalState.FinishedMempoolSync(mempoolCount)
+	return nil
+}
+
+// verifyIndex verifies the consistency of the index and writes the report
+// if all found discrepancies were repaired, the index left in inconsistent state can be used again
+func verifyIndex() error {
+	report, err := index.Verify(*repair, chanOsSignal)
+	if report != nil {
+		buf, e := json.MarshalIndent(report, "", "  ")
+		if e != nil {
+			return e
+		}
+		if *verifyReport != "" {
+			if e = ioutil.WriteFile(*verifyReport, buf, 0644); e != nil {
+				return e
+			}
+		} else {
+			fmt.Println(string(buf))
+		}
+	}
+	if err == db.ErrOperationInterrupted {
+		glog.Info("verify: interrupted")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	issues, repaired := report.IssuesCount()
+	glog.Info("verify: found ", issues, " issues, repaired ", repaired)
+	if *repair && report.Consistent() && internalState.DbState == common.DbStateInconsistent {
+		internalState.DbState = common.DbStateClosed
+		if err = index.StoreInternalState(internalState); err != nil {
+			return err
+		}
+		glog.Info("verify: all issues repaired, inconsistent state of the index cleared")
+	}
+	return nil
+}
+
 func onNewBlockHash(hash string, height uint32) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -559,6 +881,28 @@ func onNewBlockHash(hash string, height uint32) {
 	}
 }
 
+func onReorg(reorg *bchain.ChainReorg) {
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("onReorg recovered from panic: ", r)
+		}
+	}()
+	for _, c := range callbacksOnReorg {
+		c(reorg)
+	}
+}
+
+func onNewMempoolStats(stats *bchain.MempoolStats) {
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("onNewMempoolStats recovered from panic: ", r)
+		}
+	}()
+	for _, c := range callbacksOnNewMempoolStats {
+		c(stats)
+	}
+}
+
 func onNewFiatRatesTicker(ticker *db.CurrencyRatesTicker) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -580,7 +924,9 @@ func syncMempoolLoop() {
 			glog.Error("syncMempoolLoop ", errors.ErrorStack(err))
 		} else {
 			internalState.FinishedMempoolSync(count)
-
+			if stats := mempool.GetStats(); stats != nil {
+				onNewMempoolStats(stats)
+			}
 		}
 	})
 	glog.Info("syncMempoolLoop stopped")
@@ -652,6 +998,17 @@ func onNewTx(tx *bchain.MempoolTx) {
 	}
 }
 
+func onReplacedTx(tx *bchain.ReplacedMempoolTx) {
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("onReplacedTx recovered from panic: ", r)
+		}
+	}()
+	for _, c := range callbacksOnReplacedTx {
+		c(tx)
+	}
+}
+
 func pushSynchronizationHandler(nt bchain.NotificationType) {
 	glog.V(1).Info("MQ: notification ", nt)
 	if atomic.LoadInt32(&inShutdown) != 0 {
@@ -666,7 +1023,7 @@ func pushSynchronizationHandler(nt bchain.NotificationType) {
 	}
 }
 
-func waitForSignalAndShutdown(internal *server.InternalServer, public *server.PublicServer, chain bchain.BlockChain, timeout time.Duration) {
+func waitForSignalAndShutdown(internal *server.InternalServer, public *server.PublicServer, electrum *server.ElectrumServer, grpc *server.GrpcServer, chain bchain.BlockChain, timeout time.Duration) {
 	sig := <-chanOsSignal
 	atomic.StoreInt32(&inShutdown, 1)
 	glog.Infof("shutdown: %v", sig)
@@ -686,6 +1043,18 @@ func waitForSignalAndShutdown(internal *server.InternalServer, public *server.Pu
 		}
 	}
 
+	if electrum != nil {
+		if err := electrum.Close(); err != nil {
+			glog.Error("electrum server: shutdown error: ", err)
+		}
+	}
+
+	if grpc != nil {
+		if err := grpc.Close(); err != nil {
+			glog.Error("grpc server: shutdown error: ", err)
+		}
+	}
+
 	if chain != nil {
 		if err := chain.Shutdown(ctx); err != nil {
 			glog.Error("rpc: shutdown error: ", err)
@@ -704,7 +1073,7 @@ func normalizeName(s string) string {
 	return s
 }
 
-// computeFeeStats computes fee distribution in defined blocks
+// computeFeeStats computes and stores fee statistics of the blocks in defined range
 func computeFeeStats(stopCompute chan os.Signal, blockFrom, blockTo int, db *db.RocksDB, chain bchain.BlockChain, txCache *db.TxCache, is *common.InternalState, metrics *common.Metrics) error {
 	start := time.Now()
 	glog.Info("computeFeeStats start")
@@ -717,6 +1086,39 @@ func computeFeeStats(stopCompute chan os.Signal, blockFrom, blockTo int, db *db.
 	return err
 }
 
+// initFeeEstimator creates the fee estimator selected in the blockchain config, which is shared by all api workers
+func initFeeEstimator(configfile string, db *db.RocksDB, chain bchain.BlockChain, txCache *db.TxCache, is *common.InternalState, metrics *common.Metrics) error {
+	data, err := ioutil.ReadFile(configfile)
+	if err != nil {
+		return errors.Annotatef(err, "reading file %v", configfile)
+	}
+
+	var config struct {
+		FeeEstimator       string `json:"fee_estimator"`
+		FeeEstimatorParams string `json:"fee_estimator_params"`
+	}
+
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return errors.Annotatef(err, "parsing config file %v", configfile)
+	}
+
+	if config.FeeEstimator == "" || chain.GetChainParser().GetChainType() != bchain.ChainBitcoinType {
+		return nil
+	}
+	w, err := api.NewWorker(db, chain, mempool, txCache, metrics, is)
+	if err != nil {
+		return err
+	}
+	fe, err := api.NewFeeEstimator(w, config.FeeEstimator, config.FeeEstimatorParams)
+	if err != nil {
+		return err
+	}
+	api.SetFeeEstimator(fe)
+	glog.Infof("Using %v fee estimator", config.FeeEstimator)
+	return nil
+}
+
 func initFiatRatesDownloader(db *db.RocksDB, configfile string) {
 	data, err := ioutil.ReadFile(configfile)
 	if err != nil {
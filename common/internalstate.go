@@ -26,6 +26,15 @@ type InternalStateColumn struct {
 	Updated    time.Time `json:"updated"`
 }
 
+// MigrationState contains the progress of a running migration of the DB data format
+type MigrationState struct {
+	FromVersion uint32    `json:"fromVersion"`
+	Column      string    `json:"column"`
+	LastKey     []byte    `json:"lastKey,omitempty"`
+	Rows        int64     `json:"rows"`
+	Started     time.Time `json:"started"`
+}
+
 // BackendInfo is used to get information about blockchain
 type BackendInfo struct {
 	BackendError    string      `json:"error,omitempty"`
@@ -73,6 +82,20 @@ type InternalState struct {
 
 	UtxoChecked bool `json:"utxoChecked"`
 
+	// true if the rich list is built and maintained in the index
+	RichList bool `json:"richList"`
+
+	// true if the OP_RETURN index is built and maintained in the index
+	OpReturnIndex bool `json:"opReturnIndex"`
+
+	// true if the block filters of the connected blocks are computed and stored in the index
+	BlockFilters bool `json:"blockFilters"`
+
+	// true if the script hashes of the addresses are indexed, used by the Electrum server
+	ScriptHashIndex bool `json:"scriptHashIndex"`
+
+	Migration *MigrationState `json:"migration,omitempty"`
+
 	BackendInfo BackendInfo `json:"-"`
 }
 
@@ -214,6 +237,13 @@ func (is *InternalState) RemoveLastBlockTimes(count int) {
 	is.BlockTimes = is.BlockTimes[:len(is.BlockTimes)-count]
 }
 
+// SetBlockTimes replaces BlockTimes
+func (is *InternalState) SetBlockTimes(times []uint32) {
+	is.mux.Lock()
+	defer is.mux.Unlock()
+	is.BlockTimes = times
+}
+
 // GetBlockHeightOfTime returns block height of the first block with time greater or equal to the given time or MaxUint32 if no such block
 func (is *InternalState) GetBlockHeightOfTime(time uint32) uint32 {
 	is.mux.Lock()
@@ -235,6 +265,30 @@ func (is *InternalState) GetBlockHeightOfTime(time uint32) uint32 {
 	return uint32(height)
 }
 
+// SetReplicaState updates the synchronization state and the column stats of a read only replica
+// from the internal state stored by the primary, the mempool state of the replica is kept
+func (is *InternalState) SetReplicaState(primary *InternalState, bestHeight uint32) {
+	is.mux.Lock()
+	defer is.mux.Unlock()
+	is.BestHeight = bestHeight
+	is.LastSync = primary.LastSync
+	is.IsSynchronized = primary.IsSynchronized
+	is.InitialSync = primary.InitialSync
+	is.UtxoChecked = primary.UtxoChecked
+	is.RichList = primary.RichList
+	is.OpReturnIndex = primary.OpReturnIndex
+	is.BlockFilters = primary.BlockFilters
+	is.ScriptHashIndex = primary.ScriptHashIndex
+	for i := range is.DbColumns {
+		for j := range primary.DbColumns {
+			if is.DbColumns[i].Name == primary.DbColumns[j].Name {
+				is.DbColumns[i] = primary.DbColumns[j]
+				break
+			}
+		}
+	}
+}
+
 // SetBackendInfo sets new BackendInfo
 func (is *InternalState) SetBackendInfo(bi *BackendInfo) {
 	is.mux.Lock()
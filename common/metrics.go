@@ -33,6 +33,12 @@ type Metrics struct {
 	WebsocketPendingRequests *prometheus.GaugeVec
 	SocketIOPendingRequests  *prometheus.GaugeVec
 	XPubCacheSize            prometheus.Gauge
+	ElectrumRequests         *prometheus.CounterVec
+	ElectrumClients          prometheus.Gauge
+	ElectrumReqDuration      *prometheus.HistogramVec
+	GrpcRequests             *prometheus.CounterVec
+	GrpcSubscribes           *prometheus.GaugeVec
+	GrpcReqDuration          *prometheus.HistogramVec
 }
 
 // Labels represents a collection of label name -> value mappings.
@@ -238,6 +244,55 @@ func GetMetrics(coin string) (*Metrics, error) {
 			ConstLabels: Labels{"coin": coin},
 		},
 	)
+	metrics.ElectrumRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_electrum_requests",
+			Help:        "Total number of electrum requests by method and status",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method", "status"},
+	)
+	metrics.ElectrumClients = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_electrum_clients",
+			Help:        "Number of currently connected electrum clients",
+			ConstLabels: Labels{"coin": coin},
+		},
+	)
+	metrics.ElectrumReqDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "blockbook_electrum_req_duration",
+			Help:        "Electrum request duration by method (in microseconds)",
+			Buckets:     []float64{1, 5, 10, 25, 50, 75, 100, 250},
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method"},
+	)
+	metrics.GrpcRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "blockbook_grpc_requests",
+			Help:        "Total number of grpc requests by method and status",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method", "status"},
+	)
+	metrics.GrpcSubscribes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:        "blockbook_grpc_subscribes",
+			Help:        "Number of active grpc streaming subscriptions by method",
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method"},
+	)
+	metrics.GrpcReqDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "blockbook_grpc_req_duration",
+			Help:        "Grpc request duration by method (in microseconds)",
+			Buckets:     []float64{1, 5, 10, 25, 50, 75, 100, 250},
+			ConstLabels: Labels{"coin": coin},
+		},
+		[]string{"method"},
+	)
 
 	v := reflect.ValueOf(metrics)
 	for i := 0; i < v.NumField(); i++ {
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/martinboehm/btcutil/txscript"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// BlockFeeStats contains fee statistics of the transactions of a block, stored in the blockFeeStats column
+// only transactions with known fee and size are included, coinbase is skipped
+type BlockFeeStats struct {
+	TxCount         int
+	TotalFeesSat    big.Int
+	TotalVSize      int64
+	AverageFeePerKb int64
+	MinFeePerKb     int64
+	MaxFeePerKb     int64
+	DecilesFeePerKb [11]int64
+	SegwitTxCount   int
+	TaprootTxCount  int
+}
+
+// isWitnessAddrDesc returns true for native segwit outputs, i.e. witness program of version 0 or 1
+func isWitnessAddrDesc(ad bchain.AddressDescriptor) bool {
+	return (len(ad) == 22 && ad[0] == txscript.OP_0 && ad[1] == txscript.OP_DATA_20) ||
+		(len(ad) == 34 && (ad[0] == txscript.OP_0 || ad[0] == txscript.OP_1) && ad[1] == txscript.OP_DATA_32)
+}
+
+func isTaprootAddrDesc(ad bchain.AddressDescriptor) bool {
+	return len(ad) == 34 && ad[0] == txscript.OP_1 && ad[1] == txscript.OP_DATA_32
+}
+
+// ComputeBlockFeeStats computes the fee statistics of the block from the block transactions and their TxAddresses (in the same order)
+// the fee rate of a transaction is computed from its vsize, transactions spending native segwit or taproot outputs are counted
+func ComputeBlockFeeStats(block *bchain.Block, blockTxAddresses []*TxAddresses) *BlockFeeStats {
+	s := &BlockFeeStats{}
+	feesPerKb := make([]int64, 0, len(block.Txs))
+	sum := int64(0)
+	for i := range block.Txs {
+		tx := &block.Txs[i]
+		ta := blockTxAddresses[i]
+		if ta == nil || tx.VSize <= 0 {
+			continue
+		}
+		var fee big.Int
+		segwit, taproot := false, false
+		resolved := 0
+		for j := range ta.Inputs {
+			// the value of the input is zero if the spent output was not found (or the input is coinbase),
+			// a spent output of zero value cannot be told apart and such tx is skipped as well
+			if ta.Inputs[j].ValueSat.Sign() > 0 {
+				resolved++
+			}
+			fee.Add(&fee, &ta.Inputs[j].ValueSat)
+			if isWitnessAddrDesc(ta.Inputs[j].AddrDesc) {
+				segwit = true
+				if isTaprootAddrDesc(ta.Inputs[j].AddrDesc) {
+					taproot = true
+				}
+			}
+		}
+		// the fee is known only if the values of all inputs are known
+		if len(ta.Inputs) == 0 || resolved != len(ta.Inputs) {
+			continue
+		}
+		for j := range ta.Outputs {
+			fee.Sub(&fee, &ta.Outputs[j].ValueSat)
+		}
+		if fee.Sign() < 0 {
+			continue
+		}
+		s.TotalFeesSat.Add(&s.TotalFeesSat, &fee)
+		s.TotalVSize += tx.VSize
+		if segwit {
+			s.SegwitTxCount++
+		}
+		if taproot {
+			s.TaprootTxCount++
+		}
+		feePerKb := int64(float64(fee.Int64()) / float64(tx.VSize) * 1000)
+		sum += feePerKb
+		feesPerKb = append(feesPerKb, feePerKb)
+	}
+	n := len(feesPerKb)
+	s.TxCount = n
+	if n > 0 {
+		s.AverageFeePerKb = sum / int64(n)
+		sort.Slice(feesPerKb, func(i, j int) bool { return feesPerKb[i] < feesPerKb[j] })
+		s.MinFeePerKb = feesPerKb[0]
+		s.MaxFeePerKb = feesPerKb[n-1]
+		for k := 0; k <= 10; k++ {
+			index := int(math.Floor(0.5+float64(k)*float64(n+1)/10)) - 1
+			if index < 0 {
+				index = 0
+			} else if index >= n {
+				index = n - 1
+			}
+			s.DecilesFeePerKb[k] = feesPerKb[index]
+		}
+	}
+	return s
+}
+
+func packBlockFeeStats(s *BlockFeeStats) []byte {
+	buf := make([]byte, 0, 32+14*maxPackedBigintBytes)
+	varBuf := make([]byte, maxPackedBigintBytes)
+	l := packVaruint(uint(s.TxCount), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packBigint(&s.TotalFeesSat, varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(s.TotalVSize), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	for _, v := range append([]int64{s.AverageFeePerKb, s.MinFeePerKb, s.MaxFeePerKb}, s.DecilesFeePerKb[:]...) {
+		l = packVarint(int(v), varBuf)
+		buf = append(buf, varBuf[:l]...)
+	}
+	l = packVaruint(uint(s.SegwitTxCount), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(s.TaprootTxCount), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	return buf
+}
+
+func unpackBlockFeeStats(buf []byte) (*BlockFeeStats, error) {
+	s := &BlockFeeStats{}
+	txCount, l := unpackVaruint(buf)
+	s.TxCount = int(txCount)
+	var ll int
+	s.TotalFeesSat, ll = unpackBigint(buf[l:])
+	l += ll
+	vsize, ll := unpackVaruint(buf[l:])
+	s.TotalVSize = int64(vsize)
+	l += ll
+	values := make([]int64, 3+len(s.DecilesFeePerKb))
+	for i := range values {
+		if l >= len(buf) {
+			return nil, errors.New("Invalid block fee stats")
+		}
+		v, ll := unpackVarint(buf[l:])
+		values[i] = int64(v)
+		l += ll
+	}
+	s.AverageFeePerKb, s.MinFeePerKb, s.MaxFeePerKb = values[0], values[1], values[2]
+	copy(s.DecilesFeePerKb[:], values[3:])
+	if l >= len(buf) {
+		return nil, errors.New("Invalid block fee stats")
+	}
+	segwit, ll := unpackVaruint(buf[l:])
+	s.SegwitTxCount = int(segwit)
+	l += ll
+	taproot, _ := unpackVaruint(buf[l:])
+	s.TaprootTxCount = int(taproot)
+	return s, nil
+}
+
+func (d *RocksDB) storeBlockFeeStats(wb *grocksdb.WriteBatch, height uint32, s *BlockFeeStats) {
+	wb.PutCF(d.cfh[cfBlockFeeStats], packUint(height), packBlockFeeStats(s))
+}
+
+// StoreBlockFeeStats stores the fee statistics of the block at given height
+func (d *RocksDB) StoreBlockFeeStats(height uint32, s *BlockFeeStats) error {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return errors.New("Block fee stats are supported only for Bitcoin type")
+	}
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	d.storeBlockFeeStats(wb, height, s)
+	return d.db.Write(d.wo, wb)
+}
+
+// GetBlockFeeStats returns the stored fee statistics of the block at given height or nil if they are not stored
+func (d *RocksDB) GetBlockFeeStats(height uint32) (*BlockFeeStats, error) {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return nil, nil
+	}
+	val, err := d.db.GetCF(d.ro, d.cfh[cfBlockFeeStats], packUint(height))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	return unpackBlockFeeStats(buf)
+}
+
+// GetBlockFeeStatsRange calls fn for all stored block fee statistics in the height range from-to (inclusive), in ascending order of height
+func (d *RocksDB) GetBlockFeeStatsRange(from, to uint32, fn func(height uint32, s *BlockFeeStats) error) error {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return nil
+	}
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfBlockFeeStats])
+	defer it.Close()
+	for it.Seek(packUint(from)); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if len(key) != 4 {
+			continue
+		}
+		height := unpackUint(key)
+		if height > to {
+			break
+		}
+		s, err := unpackBlockFeeStats(it.Value().Data())
+		if err != nil {
+			return errors.Annotatef(err, "height %d", height)
+		}
+		if err = fn(height, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,409 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/bits"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/dchest/siphash"
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/martinboehm/btcutil/txscript"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// parameters of the BIP158 basic filter
+const (
+	blockFilterP = 19
+	blockFilterM = 784931
+)
+
+// BlockFilterHeaderLen is the length of the filter header and of the filter hash
+const BlockFilterHeaderLen = 32
+
+// BlockFilter is the BIP158 basic filter of a block with its BIP157 filter header, stored in the blockFilters column
+type BlockFilter struct {
+	// Filter is the serialized filter, the number of elements followed by the Golomb-Rice coded set
+	Filter []byte
+	// Header commits to the filter and to the filters of all previous blocks, nil if the filter header of the previous block is not known
+	Header []byte
+}
+
+func doubleSha256(b []byte) []byte {
+	h := sha256.Sum256(b)
+	h = sha256.Sum256(h[:])
+	return h[:]
+}
+
+// FilterHash returns the double SHA256 hash of the serialized filter
+func (f *BlockFilter) FilterHash() []byte {
+	return doubleSha256(f.Filter)
+}
+
+// bitWriter writes the bits from the most significant bit of each byte
+type bitWriter struct {
+	buf  []byte
+	used uint
+}
+
+func (w *bitWriter) writeBit(bit bool) {
+	if w.used == 0 {
+		w.buf = append(w.buf, 0)
+		w.used = 8
+	}
+	w.used--
+	if bit {
+		w.buf[len(w.buf)-1] |= 1 << w.used
+	}
+}
+
+func (w *bitWriter) writeBits(v uint64, n uint) {
+	for n > 0 {
+		n--
+		w.writeBit(v&(1<<n) != 0)
+	}
+}
+
+func appendCompactSize(buf []byte, n uint64) []byte {
+	switch {
+	case n < 0xfd:
+		return append(buf, byte(n))
+	case n <= 0xffff:
+		buf = append(buf, 0xfd, 0, 0)
+		binary.LittleEndian.PutUint16(buf[len(buf)-2:], uint16(n))
+	case n <= 0xffffffff:
+		buf = append(buf, 0xfe, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(buf[len(buf)-4:], uint32(n))
+	default:
+		buf = append(buf, 0xff, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint64(buf[len(buf)-8:], n)
+	}
+	return buf
+}
+
+// buildGCSFilter builds the Golomb-coded set of the elements, the key of the hash function is the first 16 bytes of the block hash
+func buildGCSFilter(key []byte, elements [][]byte) []byte {
+	n := uint64(len(elements))
+	filter := appendCompactSize(nil, n)
+	if n == 0 {
+		return filter
+	}
+	k0 := binary.LittleEndian.Uint64(key[0:8])
+	k1 := binary.LittleEndian.Uint64(key[8:16])
+	f := n * blockFilterM
+	values := make([]uint64, len(elements))
+	for i, e := range elements {
+		// map the hash uniformly to the range [0, f)
+		values[i], _ = bits.Mul64(siphash.Hash(k0, k1, e), f)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	w := bitWriter{buf: filter}
+	var last uint64
+	for _, v := range values {
+		delta := v - last
+		last = v
+		for q := delta >> blockFilterP; q > 0; q-- {
+			w.writeBit(true)
+		}
+		w.writeBit(false)
+		w.writeBits(delta, blockFilterP)
+	}
+	return w.buf
+}
+
+// blockHashToBytes converts the block hash in the hex format to bytes in the internal (reversed) order
+func blockHashToBytes(hash string) ([]byte, error) {
+	b, err := hex.DecodeString(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 16 {
+		return nil, errors.Errorf("Invalid block hash %s", hash)
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b, nil
+}
+
+// ComputeBlockFilter builds the BIP158 basic filter of the block from the output scripts of the block transactions
+// and the scripts of the outputs spent by them and computes its header from the header of the previous filter
+// (nil if the previous header is not known)
+func ComputeBlockFilter(block *bchain.Block, spentScripts [][]byte, prevHeader []byte) (*BlockFilter, error) {
+	key, err := blockHashToBytes(block.Hash)
+	if err != nil {
+		return nil, err
+	}
+	elements := make([][]byte, 0, 2*len(block.Txs))
+	unique := make(map[string]struct{})
+	add := func(script []byte) {
+		if len(script) == 0 {
+			return
+		}
+		if _, found := unique[string(script)]; !found {
+			unique[string(script)] = struct{}{}
+			elements = append(elements, script)
+		}
+	}
+	for i := range block.Txs {
+		tx := &block.Txs[i]
+		for j := range tx.Vout {
+			// OP_RETURN outputs are not included
+			if s := outputScript(&tx.Vout[j]); len(s) > 0 && s[0] != txscript.OP_RETURN {
+				add(s)
+			}
+		}
+	}
+	for _, s := range spentScripts {
+		add(s)
+	}
+	f := &BlockFilter{Filter: buildGCSFilter(key, elements)}
+	if prevHeader != nil {
+		f.Header = doubleSha256(append(f.FilterHash(), prevHeader...))
+	}
+	return f, nil
+}
+
+// computeBlockFilter computes the filter of the block, the scripts of its outputs which must be stored
+// in the outputScripts column are added to pending, which contains the scripts not written to the db yet
+func (d *RocksDB) computeBlockFilter(block *bchain.Block, blockTxAddresses []*TxAddresses, pending map[string][]byte, prevHeader []byte) (*BlockFilter, error) {
+	if err := d.addOutputScripts(block, blockTxAddresses, pending); err != nil {
+		return nil, err
+	}
+	spentScripts, err := d.getSpentOutputScripts(block, blockTxAddresses, pending)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeBlockFilter(block, spentScripts, prevHeader)
+}
+
+// getPrevBlockFilterHeader returns the filter header of the block preceding the block at given height,
+// the zero hash for the genesis block or nil if it is not known
+func (d *RocksDB) getPrevBlockFilterHeader(height uint32) ([]byte, error) {
+	if height == 0 {
+		return make([]byte, BlockFilterHeaderLen), nil
+	}
+	f, err := d.GetBlockFilter(height - 1)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	return f.Header, nil
+}
+
+func packBlockFilter(f *BlockFilter) []byte {
+	buf := make([]byte, 0, 1+len(f.Header)+len(f.Filter))
+	buf = append(buf, byte(len(f.Header)))
+	buf = append(buf, f.Header...)
+	return append(buf, f.Filter...)
+}
+
+func unpackBlockFilter(buf []byte) (*BlockFilter, error) {
+	if len(buf) == 0 {
+		return nil, errors.New("Invalid block filter")
+	}
+	l := int(buf[0])
+	if l != 0 && l != BlockFilterHeaderLen || len(buf) < 1+l {
+		return nil, errors.New("Invalid block filter")
+	}
+	f := &BlockFilter{
+		Filter: append([]byte(nil), buf[1+l:]...),
+	}
+	if l > 0 {
+		f.Header = append([]byte(nil), buf[1:1+l]...)
+	}
+	return f, nil
+}
+
+func (d *RocksDB) storeBlockFilter(wb *grocksdb.WriteBatch, height uint32, f *BlockFilter) {
+	wb.PutCF(d.cfh[cfBlockFilters], packUint(height), packBlockFilter(f))
+}
+
+// GetBlockFilter returns the stored filter of the block at given height or nil if it is not stored
+func (d *RocksDB) GetBlockFilter(height uint32) (*BlockFilter, error) {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return nil, nil
+	}
+	val, err := d.db.GetCF(d.ro, d.cfh[cfBlockFilters], packUint(height))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	return unpackBlockFilter(buf)
+}
+
+// GetBlockFilterRange calls fn for all stored block filters in the height range from-to (inclusive), in ascending order of height
+func (d *RocksDB) GetBlockFilterRange(from, to uint32, fn func(height uint32, f *BlockFilter) error) error {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return nil
+	}
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfBlockFilters])
+	defer it.Close()
+	for it.Seek(packUint(from)); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if len(key) != 4 {
+			continue
+		}
+		height := unpackUint(key)
+		if height > to {
+			break
+		}
+		f, err := unpackBlockFilter(it.Value().Data())
+		if err != nil {
+			return errors.Annotatef(err, "height %d", height)
+		}
+		if err = fn(height, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsBlockFiltersEnabled returns true if the block filters of the connected blocks are computed and stored
+func (d *RocksDB) IsBlockFiltersEnabled() bool {
+	return d.is != nil && d.is.BlockFilters
+}
+
+// InitBlockFilters switches the computation of the block filters on or off. The filters of the blocks indexed
+// while the filters were switched off are computed by ComputeMissingBlockFilters, which needs the backend.
+// If the filters are switched off, the stored filters are kept and the filters of the blocks connected
+// in the meantime are computed by ComputeMissingBlockFilters when the filters are switched on again.
+func (d *RocksDB) InitBlockFilters(enabled bool) error {
+	if d.is == nil {
+		return errors.New("Internal state not set")
+	}
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		if enabled {
+			return errors.New("Block filters are supported only for Bitcoin type coins")
+		}
+		return nil
+	}
+	d.blockFilters = enabled
+	if d.is.BlockFilters == enabled {
+		return nil
+	}
+	if enabled {
+		glog.Info("block filters: enabled, the filters of the already indexed blocks are computed with the -computeblockfilters flag")
+	} else {
+		glog.Info("block filters: disabled, the missing filters are computed with the -computeblockfilters flag when enabled again")
+	}
+	d.is.BlockFilters = enabled
+	return d.storeState(d.is)
+}
+
+// blockFiltersBatchSize is the number of blocks with computed filters written in one write batch
+const blockFiltersBatchSize = 1000
+
+// firstBlockWithoutFilterHeader returns the height of the first indexed block without the filter or the filter header
+// and false if all indexed blocks have them
+func (d *RocksDB) firstBlockWithoutFilterHeader() (uint32, bool, error) {
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfHeight])
+	defer it.Close()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		height := unpackUint(it.Key().Data())
+		f, err := d.GetBlockFilter(height)
+		if err != nil {
+			return 0, false, err
+		}
+		if f == nil || f.Header == nil {
+			return height, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// ComputeMissingBlockFilters computes the filters and the filter headers of the blocks indexed without them (by older versions
+// of Blockbook) from the block transactions of the backend and stores them together with the output scripts of the blocks.
+// The filter headers form a chain starting at the genesis block, therefore the index must be built from the genesis block.
+// The filters are computed from the first block without the filter header, the computation can be interrupted
+// and is resumed by the next call.
+func (d *RocksDB) ComputeMissingBlockFilters(chain bchain.BlockChain, stop chan os.Signal) error {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return errors.New("Block filters are supported only for Bitcoin type")
+	}
+	if !d.IsBlockFiltersEnabled() {
+		return errors.New("Block filters are not enabled")
+	}
+	if bi, err := d.GetBlockInfo(0); err != nil || bi == nil {
+		if err == nil {
+			err = errors.New("Block filters can be computed only for the index built from the genesis block")
+		}
+		return err
+	}
+	from, found, err := d.firstBlockWithoutFilterHeader()
+	if err != nil || !found {
+		return err
+	}
+	bestHeight, _, err := d.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	header, err := d.getPrevBlockFilterHeader(from)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	glog.Info("block filters: computing blocks ", from, "-", bestHeight)
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	outputScripts := make(map[string][]byte)
+	write := func() error {
+		d.storeOutputScripts(wb, outputScripts)
+		if err := d.db.Write(d.wo, wb); err != nil {
+			return err
+		}
+		wb.Clear()
+		outputScripts = make(map[string][]byte)
+		return nil
+	}
+	for height := from; height <= bestHeight; height++ {
+		select {
+		case <-stop:
+			if err = write(); err != nil {
+				return err
+			}
+			glog.Info("block filters: interrupted at height ", height)
+			return ErrOperationInterrupted
+		default:
+		}
+		hash, err := d.GetBlockHash(height)
+		if err != nil {
+			return err
+		}
+		if hash == "" {
+			return errors.Errorf("Block %v is missing in the index", height)
+		}
+		block, err := chain.GetBlock(hash, height)
+		if err != nil {
+			return errors.Annotatef(err, "GetBlock %v %v", height, hash)
+		}
+		blockTxAddresses := make([]*TxAddresses, len(block.Txs))
+		for i := range block.Txs {
+			if blockTxAddresses[i], err = d.GetTxAddresses(block.Txs[i].Txid); err != nil {
+				return err
+			}
+		}
+		filter, err := d.computeBlockFilter(block, blockTxAddresses, outputScripts, header)
+		if err != nil {
+			return err
+		}
+		d.storeBlockFilter(wb, height, filter)
+		header = filter.Header
+		if (height-from+1)%blockFiltersBatchSize == 0 || height == bestHeight {
+			if err = write(); err != nil {
+				return err
+			}
+			glog.Info("block filters: computed up to block ", height)
+		}
+	}
+	glog.Info("block filters: computed blocks ", from, "-", bestHeight, " in ", time.Since(start))
+	return nil
+}
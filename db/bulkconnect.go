@@ -1,10 +1,11 @@
 package db
 
 import (
+	"math/big"
 	"time"
 
-	"github.com/flier/gorocksdb"
 	"github.com/golang/glog"
+	"github.com/linxGnu/grocksdb"
 	"github.com/trezor/blockbook/bchain"
 )
 
@@ -17,6 +18,9 @@ import (
 type bulkAddresses struct {
 	bi        BlockInfo
 	addresses addressesMap
+	feeStats  *BlockFeeStats
+	filter    *BlockFilter
+	opReturns []opReturnRow
 }
 
 // BulkConnect is used to connect blocks in bulk, faster but if interrupted inconsistent way
@@ -29,6 +33,16 @@ type BulkConnect struct {
 	balances           map[string]*AddrBalance
 	addressContracts   map[string]*AddrContracts
 	height             uint32
+	// supply are the supply statistics of the last connected block, nil if they are not available
+	supply     *SupplyStats
+	supplyInit bool
+	// filterHeader is the filter header of the last connected block, nil if it is not known
+	filterHeader     []byte
+	filterHeaderInit bool
+	// outputScripts are the output scripts of the connected blocks, which are stored together with the blocks
+	outputScripts map[string][]byte
+	// tokenBalanceChanges are the changes of the token balances of the connected blocks which are not stored yet
+	tokenBalanceChanges map[string]*big.Int
 }
 
 const (
@@ -39,16 +53,19 @@ const (
 	partialStoreBalances      = maxBulkBalances / 10
 	maxBulkAddrContracts      = 1200000
 	partialStoreAddrContracts = maxBulkAddrContracts / 10
+	maxBulkTokenBalances      = 1000000
 )
 
 // InitBulkConnect initializes bulk connect and switches DB to inconsistent state
 func (d *RocksDB) InitBulkConnect() (*BulkConnect, error) {
 	b := &BulkConnect{
-		d:                d,
-		chainType:        d.chainParser.GetChainType(),
-		txAddressesMap:   make(map[string]*TxAddresses),
-		balances:         make(map[string]*AddrBalance),
-		addressContracts: make(map[string]*AddrContracts),
+		d:                   d,
+		chainType:           d.chainParser.GetChainType(),
+		txAddressesMap:      make(map[string]*TxAddresses),
+		balances:            make(map[string]*AddrBalance),
+		addressContracts:    make(map[string]*AddrContracts),
+		tokenBalanceChanges: make(map[string]*big.Int),
+		outputScripts:       make(map[string][]byte),
 	}
 	if err := d.SetInconsistentState(true); err != nil {
 		return nil, err
@@ -57,7 +74,7 @@ func (d *RocksDB) InitBulkConnect() (*BulkConnect, error) {
 	return b, nil
 }
 
-func (b *BulkConnect) storeTxAddresses(wb *gorocksdb.WriteBatch, all bool) (int, int, error) {
+func (b *BulkConnect) storeTxAddresses(wb *grocksdb.WriteBatch, all bool) (int, int, error) {
 	var txm map[string]*TxAddresses
 	var sp int
 	if all {
@@ -100,7 +117,7 @@ func (b *BulkConnect) storeTxAddresses(wb *gorocksdb.WriteBatch, all bool) (int,
 func (b *BulkConnect) parallelStoreTxAddresses(c chan error, all bool) {
 	defer close(c)
 	start := time.Now()
-	wb := gorocksdb.NewWriteBatch()
+	wb := grocksdb.NewWriteBatch()
 	defer wb.Destroy()
 	count, sp, err := b.storeTxAddresses(wb, all)
 	if err != nil {
@@ -115,7 +132,7 @@ func (b *BulkConnect) parallelStoreTxAddresses(c chan error, all bool) {
 	c <- nil
 }
 
-func (b *BulkConnect) storeBalances(wb *gorocksdb.WriteBatch, all bool) (int, error) {
+func (b *BulkConnect) storeBalances(wb *grocksdb.WriteBatch, all bool) (int, error) {
 	var bal map[string]*AddrBalance
 	if all {
 		bal = b.balances
@@ -140,7 +157,7 @@ func (b *BulkConnect) storeBalances(wb *gorocksdb.WriteBatch, all bool) (int, er
 func (b *BulkConnect) parallelStoreBalances(c chan error, all bool) {
 	defer close(c)
 	start := time.Now()
-	wb := gorocksdb.NewWriteBatch()
+	wb := grocksdb.NewWriteBatch()
 	defer wb.Destroy()
 	count, err := b.storeBalances(wb, all)
 	if err != nil {
@@ -155,7 +172,7 @@ func (b *BulkConnect) parallelStoreBalances(c chan error, all bool) {
 	c <- nil
 }
 
-func (b *BulkConnect) storeBulkAddresses(wb *gorocksdb.WriteBatch) error {
+func (b *BulkConnect) storeBulkAddresses(wb *grocksdb.WriteBatch) error {
 	for _, ba := range b.bulkAddresses {
 		if err := b.d.storeAddresses(wb, ba.bi.Height, ba.addresses); err != nil {
 			return err
@@ -163,7 +180,16 @@ func (b *BulkConnect) storeBulkAddresses(wb *gorocksdb.WriteBatch) error {
 		if err := b.d.writeHeight(wb, ba.bi.Height, &ba.bi, opInsert); err != nil {
 			return err
 		}
+		if ba.feeStats != nil {
+			b.d.storeBlockFeeStats(wb, ba.bi.Height, ba.feeStats)
+		}
+		if ba.filter != nil {
+			b.d.storeBlockFilter(wb, ba.bi.Height, ba.filter)
+		}
+		b.d.storeOpReturns(wb, ba.opReturns)
 	}
+	b.d.storeOutputScripts(wb, b.outputScripts)
+	b.outputScripts = make(map[string][]byte)
 	b.bulkAddressesCount = 0
 	b.bulkAddresses = b.bulkAddresses[:0]
 	return nil
@@ -171,9 +197,43 @@ func (b *BulkConnect) storeBulkAddresses(wb *gorocksdb.WriteBatch) error {
 
 func (b *BulkConnect) connectBlockBitcoinType(block *bchain.Block, storeBlockTxs bool) error {
 	addresses := make(addressesMap)
-	if err := b.d.processAddressesBitcoinType(block, addresses, b.txAddressesMap, b.balances); err != nil {
+	blockTxAddresses, err := b.d.processAddressesBitcoinType(block, addresses, b.txAddressesMap, b.balances)
+	if err != nil {
 		return err
 	}
+	feeStats := ComputeBlockFeeStats(block, blockTxAddresses)
+	if !b.supplyInit {
+		if b.supply, err = b.d.getPrevSupplyStats(block.Height); err != nil {
+			return err
+		}
+		b.supplyInit = true
+	}
+	if b.supply != nil {
+		b.supply = b.d.ComputeSupplyStats(b.supply, block, blockTxAddresses)
+	}
+	var filter *BlockFilter
+	if b.d.blockFilters {
+		if !b.filterHeaderInit {
+			if b.filterHeader, err = b.d.getPrevBlockFilterHeader(block.Height); err != nil {
+				return err
+			}
+			b.filterHeaderInit = true
+		}
+		if filter, err = b.d.computeBlockFilter(block, blockTxAddresses, b.outputScripts, b.filterHeader); err != nil {
+			return err
+		}
+		b.filterHeader = filter.Header
+	} else if b.d.opReturnIndex {
+		if err = b.d.addOutputScripts(block, blockTxAddresses, b.outputScripts); err != nil {
+			return err
+		}
+	}
+	var opReturns []opReturnRow
+	if b.d.opReturnIndex {
+		if opReturns, err = b.d.getOpReturnRows(block, blockTxAddresses); err != nil {
+			return err
+		}
+	}
 	var storeAddressesChan, storeBalancesChan chan error
 	var sa bool
 	if len(b.txAddressesMap) > maxBulkTxAddresses || len(b.balances) > maxBulkBalances {
@@ -194,14 +254,18 @@ func (b *BulkConnect) connectBlockBitcoinType(block *bchain.Block, storeBlockTxs
 			Txs:    uint32(len(block.Txs)),
 			Size:   uint32(block.Size),
 			Height: block.Height,
+			Supply: b.supply,
 		},
 		addresses: addresses,
+		feeStats:  feeStats,
+		filter:    filter,
+		opReturns: opReturns,
 	})
 	b.bulkAddressesCount += len(addresses)
 	// open WriteBatch only if going to write
 	if sa || b.bulkAddressesCount > maxBulkAddresses || storeBlockTxs {
 		start := time.Now()
-		wb := gorocksdb.NewWriteBatch()
+		wb := grocksdb.NewWriteBatch()
 		defer wb.Destroy()
 		bac := b.bulkAddressesCount
 		if sa || b.bulkAddressesCount > maxBulkAddresses {
@@ -234,7 +298,7 @@ func (b *BulkConnect) connectBlockBitcoinType(block *bchain.Block, storeBlockTxs
 	return nil
 }
 
-func (b *BulkConnect) storeAddressContracts(wb *gorocksdb.WriteBatch, all bool) (int, error) {
+func (b *BulkConnect) storeAddressContracts(wb *grocksdb.WriteBatch, all bool) (int, error) {
 	var ac map[string]*AddrContracts
 	if all {
 		ac = b.addressContracts
@@ -259,7 +323,7 @@ func (b *BulkConnect) storeAddressContracts(wb *gorocksdb.WriteBatch, all bool)
 func (b *BulkConnect) parallelStoreAddressContracts(c chan error, all bool) {
 	defer close(c)
 	start := time.Now()
-	wb := gorocksdb.NewWriteBatch()
+	wb := grocksdb.NewWriteBatch()
 	defer wb.Destroy()
 	count, err := b.storeAddressContracts(wb, all)
 	if err != nil {
@@ -274,12 +338,49 @@ func (b *BulkConnect) parallelStoreAddressContracts(c chan error, all bool) {
 	c <- nil
 }
 
+// storeTokenBalances stores the cached changes of the token balances in a separate write batch
+func (b *BulkConnect) storeTokenBalances() error {
+	if len(b.tokenBalanceChanges) == 0 {
+		return nil
+	}
+	start := time.Now()
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	if err := b.d.storeTokenBalances(wb, b.height, b.tokenBalanceChanges, false); err != nil {
+		return err
+	}
+	if err := b.d.db.Write(b.d.wo, wb); err != nil {
+		return err
+	}
+	glog.Info("rocksdb: height ", b.height, ", stored ", len(b.tokenBalanceChanges), " token balances, done in ", time.Since(start))
+	b.tokenBalanceChanges = make(map[string]*big.Int)
+	return nil
+}
+
 func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTxs bool) error {
 	addresses := make(addressesMap)
 	blockTxs, err := b.d.processAddressesEthereumType(block, addresses, b.addressContracts)
 	if err != nil {
 		return err
 	}
+	// the token balances of the blocks which can be disconnected are stored with the block to be able to undo them
+	var blockTokenBalanceChanges map[string]*big.Int
+	if b.d.richList {
+		if storeBlockTxs {
+			if err = b.storeTokenBalances(); err != nil {
+				return err
+			}
+			blockTokenBalanceChanges = make(map[string]*big.Int)
+			b.d.addTokenTransfers(block, blockTokenBalanceChanges)
+		} else {
+			b.d.addTokenTransfers(block, b.tokenBalanceChanges)
+			if len(b.tokenBalanceChanges) > maxBulkTokenBalances {
+				if err = b.storeTokenBalances(); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	var storeAddrContracts chan error
 	var sa bool
 	if len(b.addressContracts) > maxBulkAddrContracts {
@@ -301,7 +402,7 @@ func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTx
 	// open WriteBatch only if going to write
 	if sa || b.bulkAddressesCount > maxBulkAddresses || storeBlockTxs {
 		start := time.Now()
-		wb := gorocksdb.NewWriteBatch()
+		wb := grocksdb.NewWriteBatch()
 		defer wb.Destroy()
 		bac := b.bulkAddressesCount
 		if sa || b.bulkAddressesCount > maxBulkAddresses {
@@ -313,6 +414,11 @@ func (b *BulkConnect) connectBlockEthereumType(block *bchain.Block, storeBlockTx
 			if err := b.d.storeAndCleanupBlockTxsEthereumType(wb, block, blockTxs); err != nil {
 				return err
 			}
+			if blockTokenBalanceChanges != nil {
+				if err := b.d.storeTokenBalances(wb, block.Height, blockTokenBalanceChanges, true); err != nil {
+					return err
+				}
+			}
 		}
 		if err := b.d.db.Write(b.d.wo, wb); err != nil {
 			return err
@@ -347,6 +453,9 @@ func (b *BulkConnect) Close() error {
 	glog.Info("rocksdb: bulk connect closing")
 	start := time.Now()
 	var storeTxAddressesChan, storeBalancesChan, storeAddressContractsChan chan error
+	if err := b.storeTokenBalances(); err != nil {
+		return err
+	}
 	if b.chainType == bchain.ChainBitcoinType {
 		storeTxAddressesChan = make(chan error)
 		go b.parallelStoreTxAddresses(storeTxAddressesChan, true)
@@ -356,7 +465,7 @@ func (b *BulkConnect) Close() error {
 		storeAddressContractsChan = make(chan error)
 		go b.parallelStoreAddressContracts(storeAddressContractsChan, true)
 	}
-	wb := gorocksdb.NewWriteBatch()
+	wb := grocksdb.NewWriteBatch()
 	defer wb.Destroy()
 	bac := b.bulkAddressesCount
 	if err := b.storeBulkAddresses(wb); err != nil {
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+)
+
+const checkpointInfoKey = "checkpointInfo"
+
+// cache used when a checkpoint is opened only to be finalized or validated
+const checkpointCacheSize = 1 << 24
+
+// CheckpointInfo describes the state of the index captured by a checkpoint
+type CheckpointInfo struct {
+	Coin    string    `json:"coin"`
+	Height  uint32    `json:"height"`
+	Hash    string    `json:"hash"`
+	Created time.Time `json:"created"`
+}
+
+// NewCheckpointPath returns the path of a new checkpoint in the directory baseDir, named by the current time
+func NewCheckpointPath(baseDir string) string {
+	return filepath.Join(baseDir, "checkpoint-"+time.Now().UTC().Format("20060102150405"))
+}
+
+// CreateCheckpoint creates a consistent snapshot of the index in the directory dir, which must not exist
+// The snapshot is a complete database containing the internal state and the checkpoint info
+// with the height and hash of the best block of the snapshot.
+func (d *RocksDB) CreateCheckpoint(dir string) (*CheckpointInfo, error) {
+	if d.is == nil {
+		return nil, errors.New("Internal state not created")
+	}
+	if d.is.InitialSync {
+		// bulk connect keeps part of the data in memory, the index is consistent only after the initial sync
+		return nil, errors.New("Cannot create checkpoint during initial synchronization")
+	}
+	if _, needed := d.MigrationNeeded(d.is); needed {
+		return nil, errors.New("Cannot create checkpoint of DB which must be migrated")
+	}
+	if err := d.storeState(d.is); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	cp, err := d.db.NewCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	defer cp.Destroy()
+	// log size 0 forces flush of the memtables, the checkpoint then does not need the write ahead log
+	if err = cp.CreateCheckpoint(dir, 0); err != nil {
+		return nil, errors.Annotatef(err, "CreateCheckpoint %v", dir)
+	}
+	info, err := d.finalizeCheckpoint(dir)
+	if err != nil {
+		return nil, err
+	}
+	glog.Info("rocksdb: checkpoint at height ", info.Height, " created in ", dir, ", ", time.Since(start))
+	return info, nil
+}
+
+// finalizeCheckpoint marks the internal state of the checkpoint as closed and stores the checkpoint info
+// the best block is read from the checkpoint, the blocks may have been connected after the internal state was stored
+func (d *RocksDB) finalizeCheckpoint(dir string) (*CheckpointInfo, error) {
+	c, err := NewRocksDB(dir, checkpointCacheSize, d.maxOpenFiles, d.chainParser, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	height, hash, err := c.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	is, err := c.getStoredInternalState()
+	if err != nil {
+		return nil, err
+	}
+	is.DbState = common.DbStateClosed
+	is.BestHeight = height
+	info := &CheckpointInfo{
+		Coin:    is.Coin,
+		Height:  height,
+		Hash:    hash,
+		Created: time.Now().UTC(),
+	}
+	bufInfo, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	bufIs, err := is.Pack()
+	if err != nil {
+		return nil, err
+	}
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	wb.PutCF(c.cfh[cfDefault], []byte(internalStateKey), bufIs)
+	wb.PutCF(c.cfh[cfDefault], []byte(checkpointInfoKey), bufInfo)
+	if err = c.db.Write(c.wo, wb); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetCheckpointInfo returns the checkpoint info stored in the db or nil if the db was not created as a checkpoint
+// the index restored from a checkpoint keeps the info of the checkpoint it was restored from
+func (d *RocksDB) GetCheckpointInfo() (*CheckpointInfo, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfDefault], []byte(checkpointInfoKey))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if len(val.Data()) == 0 {
+		return nil, nil
+	}
+	var info CheckpointInfo
+	if err := json.Unmarshal(val.Data(), &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// RestoreCheckpoint validates the checkpoint in the directory dir and restores it as the index in the directory path,
+// which must not exist or must be empty
+// The checkpoint is valid if its best block matches the checkpoint info and the block hash at the checkpoint height
+// reported by the backend. The synchronization then continues from the checkpoint height.
+func RestoreCheckpoint(dir, path string, rpcCoin string, chain bchain.BlockChain) (*CheckpointInfo, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, errors.Annotatef(err, "checkpoint %v", dir)
+	}
+	files, err := ioutil.ReadDir(path)
+	if err == nil {
+		if len(files) > 0 {
+			return nil, errors.Errorf("Index directory %v is not empty", path)
+		}
+		// the checkpoint is created only in a directory which does not exist
+		if err = os.Remove(path); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	c, err := NewRocksDB(dir, checkpointCacheSize, -1, chain.GetChainParser(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	info, err := c.GetCheckpointInfo()
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.Errorf("%v is not a checkpoint", dir)
+	}
+	if info.Coin != rpcCoin {
+		return nil, errors.Errorf("Coins do not match. Checkpoint coin %v, RPC coin %v", info.Coin, rpcCoin)
+	}
+	height, hash, err := c.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if height != info.Height || hash != info.Hash {
+		return nil, errors.Errorf("Checkpoint best block %v %v does not match checkpoint info %v %v", height, hash, info.Height, info.Hash)
+	}
+	backendHash, err := chain.GetBlockHash(info.Height)
+	if err != nil {
+		return nil, errors.Annotatef(err, "GetBlockHash %v", info.Height)
+	}
+	if backendHash != info.Hash {
+		return nil, errors.Errorf("Checkpoint block %v at height %v does not match backend block %v", info.Hash, info.Height, backendHash)
+	}
+	cp, err := c.db.NewCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	defer cp.Destroy()
+	if err = cp.CreateCheckpoint(path, 0); err != nil {
+		return nil, errors.Annotatef(err, "CreateCheckpoint %v", path)
+	}
+	glog.Info("rocksdb: checkpoint ", dir, " at height ", info.Height, " restored to ", path)
+	return info, nil
+}
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"bytes"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+)
+
+// migration converts the data of the DB from the version fromVersion to the version fromVersion+1
+// The columns returned by columns are processed one by one in batches. Each row is passed to migrateRow,
+// which returns the new value of the row or nil if the row is to be deleted, the keys cannot be changed.
+// The progress is stored in the internal state in the same write batch as the migrated rows,
+// therefore the migration can be interrupted at any time and resumed from the last written batch.
+type migration struct {
+	fromVersion uint32
+	description string
+	columns     func(d *RocksDB) []int
+	migrateRow  func(d *RocksDB, col int, key, value []byte) ([]byte, error)
+}
+
+// migrations contains the registered migrations by the version they migrate from
+var migrations = make(map[uint32]*migration)
+
+// migrationBatchSize is the number of rows migrated in one write batch
+var migrationBatchSize = 100000
+
+func registerMigration(m *migration) {
+	if _, found := migrations[m.fromVersion]; found {
+		panic(errors.Errorf("Duplicate migration from version %v", m.fromVersion))
+	}
+	migrations[m.fromVersion] = m
+}
+
+func init() {
+	// version 6 stores witness, virtual size and weight of the transactions, the rows stored without them are deleted,
+	// the txcache is filled again on demand, the block fee statistics can be recomputed using the -computefeestats flag
+	registerMigration(&migration{
+		fromVersion: 5,
+		description: "delete txcache and block fee statistics stored without witness, vsize and weight",
+		columns: func(d *RocksDB) []int {
+			if d.chainParser.GetChainType() == bchain.ChainBitcoinType {
+				return []int{cfTransactions, cfBlockFeeStats}
+			}
+			return []int{cfTransactions}
+		},
+		migrateRow: deleteRow,
+	})
+}
+
+// deleteRow is a migrateRow function deleting all rows of the column
+func deleteRow(d *RocksDB, col int, key, value []byte) ([]byte, error) {
+	return nil, nil
+}
+
+// canMigrate returns true if there is a chain of registered migrations from the version to dbVersion
+func canMigrate(version uint32) bool {
+	if version >= dbVersion {
+		return false
+	}
+	for v := version; v < dbVersion; v++ {
+		if _, found := migrations[v]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MigrationNeeded returns the lowest version of the DB columns and true if the DB must be migrated by Migrate before use
+func (d *RocksDB) MigrationNeeded(is *common.InternalState) (uint32, bool) {
+	version := uint32(dbVersion)
+	for i := range is.DbColumns {
+		if is.DbColumns[i].Version < version {
+			version = is.DbColumns[i].Version
+		}
+	}
+	return version, version < dbVersion
+}
+
+// Migrate runs the registered migrations of the DB data format up to dbVersion
+// if the migration is interrupted by the stop signal, ErrOperationInterrupted is returned and the next call resumes the migration
+func (d *RocksDB) Migrate(is *common.InternalState, stop chan os.Signal) error {
+	for {
+		version, needed := d.MigrationNeeded(is)
+		if !needed {
+			return nil
+		}
+		m, found := migrations[version]
+		if !found {
+			return errors.Errorf("No migration from DB version %v", version)
+		}
+		if err := d.runMigration(is, m, stop); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *RocksDB) runMigration(is *common.InternalState, m *migration, stop chan os.Signal) error {
+	if is.Migration == nil || is.Migration.FromVersion != m.fromVersion {
+		glog.Info("rocksdb: migration from version ", m.fromVersion, " to ", m.fromVersion+1, " started: ", m.description)
+		is.Migration = &common.MigrationState{
+			FromVersion: m.fromVersion,
+			Started:     time.Now(),
+		}
+	} else {
+		glog.Info("rocksdb: migration from version ", m.fromVersion, " to ", m.fromVersion+1, " resumed in column ", is.Migration.Column, " after ", is.Migration.Rows, " rows")
+	}
+	columns := m.columns(d)
+	// skip the columns which were already migrated
+	first := 0
+	for i, col := range columns {
+		if cfNames[col] == is.Migration.Column {
+			first = i
+			break
+		}
+	}
+	for i := first; i < len(columns); i++ {
+		if is.Migration.Column != cfNames[columns[i]] {
+			is.Migration.Column = cfNames[columns[i]]
+			is.Migration.LastKey = nil
+		}
+		if err := d.migrateColumn(is, m, columns[i], stop); err != nil {
+			return err
+		}
+	}
+	for i := range is.DbColumns {
+		if is.DbColumns[i].Version == m.fromVersion {
+			is.DbColumns[i].Version = m.fromVersion + 1
+		}
+	}
+	glog.Info("rocksdb: migration from version ", m.fromVersion, " to ", m.fromVersion+1, " finished, ", is.Migration.Rows, " rows processed in ", time.Since(is.Migration.Started))
+	is.Migration = nil
+	return d.storeState(is)
+}
+
+// migrateColumn migrates the rows of the column following the key is.Migration.LastKey
+func (d *RocksDB) migrateColumn(is *common.InternalState, m *migration, col int, stop chan os.Signal) error {
+	// do not use cache
+	ro := grocksdb.NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetFillCache(false)
+	for {
+		done, err := d.migrateColumnBatch(is, m, col, ro)
+		if err != nil {
+			return errors.Annotatef(err, "column %v", cfNames[col])
+		}
+		if done {
+			return nil
+		}
+		glog.Info("rocksdb: migration of column ", cfNames[col], ", rows ", is.Migration.Rows, ", in progress...")
+		select {
+		case <-stop:
+			return ErrOperationInterrupted
+		default:
+		}
+	}
+}
+
+// migrateColumnBatch migrates at most migrationBatchSize rows and stores them together with the progress
+// returns true if the end of the column was reached
+func (d *RocksDB) migrateColumnBatch(is *common.InternalState, m *migration, col int, ro *grocksdb.ReadOptions) (bool, error) {
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	it := d.db.NewIteratorCF(ro, d.cfh[col])
+	defer it.Close()
+	lastKey := is.Migration.LastKey
+	if lastKey == nil {
+		it.SeekToFirst()
+	} else {
+		// the last migrated row may have been deleted, then the iterator is already at the following row
+		it.Seek(lastKey)
+		if it.Valid() && bytes.Equal(it.Key().Data(), lastKey) {
+			it.Next()
+		}
+	}
+	var rows, rowsDiff, keyBytesDiff, valueBytesDiff int64
+	for ; it.Valid() && rows < int64(migrationBatchSize); it.Next() {
+		key := it.Key().Data()
+		value := it.Value().Data()
+		newValue, err := m.migrateRow(d, col, key, value)
+		if err != nil {
+			return false, errors.Annotatef(err, "key %x", key)
+		}
+		if newValue == nil {
+			wb.DeleteCF(d.cfh[col], key)
+			rowsDiff--
+			keyBytesDiff -= int64(len(key))
+			valueBytesDiff -= int64(len(value))
+		} else if !bytes.Equal(newValue, value) {
+			wb.PutCF(d.cfh[col], key, newValue)
+			valueBytesDiff += int64(len(newValue) - len(value))
+		}
+		lastKey = append([]byte{}, key...)
+		rows++
+	}
+	done := !it.Valid()
+	if rowsDiff != 0 || keyBytesDiff != 0 || valueBytesDiff != 0 {
+		r, k, v := is.GetDBColumnStatValues(col)
+		is.SetDBColumnStats(col, nonNegative(r+rowsDiff), nonNegative(k+keyBytesDiff), nonNegative(v+valueBytesDiff))
+	}
+	is.Migration.LastKey = lastKey
+	is.Migration.Rows += rows
+	buf, err := is.Pack()
+	if err != nil {
+		return false, err
+	}
+	wb.PutCF(d.cfh[cfDefault], []byte(internalStateKey), buf)
+	if err := d.db.Write(d.wo, wb); err != nil {
+		return false, err
+	}
+	return done, nil
+}
+
+func nonNegative(v int64) int64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,298 @@
+package db
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"time"
+
+	vlq "github.com/bsm/go-vlq"
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/martinboehm/btcutil/txscript"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// maxOpReturnPayload is the maximum length of the indexed OP_RETURN payload, longer payloads are truncated
+const maxOpReturnPayload = 256
+
+var opReturnBatchSize = 10000
+
+// OpReturnEntry is an OP_RETURN output found in the OP_RETURN index
+type OpReturnEntry struct {
+	Payload []byte
+	Txid    string
+	Vout    int32
+	Height  uint32
+}
+
+// opReturnRow is a row of the opReturns column
+type opReturnRow struct {
+	key   []byte
+	value []byte
+}
+
+// opReturnPayload returns the data pushed by the OP_RETURN script or nil if the script is not OP_RETURN script
+// if the script contains anything else than data pushes, the whole script after the OP_RETURN opcode is returned
+func opReturnPayload(script []byte) []byte {
+	if len(script) < 2 || script[0] != txscript.OP_RETURN {
+		return nil
+	}
+	var payload []byte
+	for i := 1; i < len(script); {
+		op := script[i]
+		i++
+		var l int
+		switch {
+		case op <= txscript.OP_DATA_75:
+			l = int(op)
+		case op == txscript.OP_PUSHDATA1 && i+1 <= len(script):
+			l = int(script[i])
+			i++
+		case op == txscript.OP_PUSHDATA2 && i+2 <= len(script):
+			l = int(binary.LittleEndian.Uint16(script[i:]))
+			i += 2
+		case op == txscript.OP_PUSHDATA4 && i+4 <= len(script):
+			l = int(binary.LittleEndian.Uint32(script[i:]))
+			i += 4
+		default:
+			return script[1:]
+		}
+		if l < 0 || i+l > len(script) {
+			return script[1:]
+		}
+		payload = append(payload, script[i:i+l]...)
+		i += l
+	}
+	return payload
+}
+
+// packOpReturnKey creates a key composed of the (possibly truncated) payload, txid and output index,
+// the keys of the payloads with the same prefix are stored next to each other
+func packOpReturnKey(payload []byte, btxID []byte, vout int32) []byte {
+	if len(payload) > maxOpReturnPayload {
+		payload = payload[:maxOpReturnPayload]
+	}
+	key := make([]byte, 0, len(payload)+len(btxID)+4)
+	key = append(key, payload...)
+	key = append(key, btxID...)
+	return append(key, packUint(uint32(vout))...)
+}
+
+func (d *RocksDB) unpackOpReturnKey(key []byte) ([]byte, string, int32, error) {
+	l := len(key) - d.chainParser.PackedTxidLen() - 4
+	if l < 0 {
+		return nil, "", 0, errors.New("Invalid OP_RETURN key")
+	}
+	txid, err := d.chainParser.UnpackTxid(key[l : len(key)-4])
+	if err != nil {
+		return nil, "", 0, err
+	}
+	return append([]byte(nil), key[:l]...), txid, int32(unpackUint(key[len(key)-4:])), nil
+}
+
+// appendOpReturnRows appends the rows of the OP_RETURN outputs of the transaction with the output scripts
+// (indexed by the output index) included in the block at the height
+func appendOpReturnRows(rows []opReturnRow, btxID []byte, height uint32, scripts [][]byte) []opReturnRow {
+	for i := range scripts {
+		if payload := opReturnPayload(scripts[i]); len(payload) > 0 {
+			rows = append(rows, opReturnRow{
+				key:   packOpReturnKey(payload, btxID, int32(i)),
+				value: packVaruint32(height),
+			})
+		}
+	}
+	return rows
+}
+
+func packVaruint32(v uint32) []byte {
+	buf := make([]byte, vlq.MaxLen32)
+	l := packVaruint(uint(v), buf)
+	return buf[:l]
+}
+
+// getOpReturnRows returns the rows of the OP_RETURN outputs of the block, the payloads are read from the output scripts
+// of the block transactions, the block transactions and their TxAddresses must be in the same order
+func (d *RocksDB) getOpReturnRows(block *bchain.Block, blockTxAddresses []*TxAddresses) ([]opReturnRow, error) {
+	var rows []opReturnRow
+	for i := range block.Txs {
+		tx := &block.Txs[i]
+		if blockTxAddresses[i] == nil {
+			continue
+		}
+		btxID, err := d.chainParser.PackTxid(tx.Txid)
+		if err != nil {
+			return nil, err
+		}
+		scripts := make([][]byte, len(tx.Vout))
+		for j := range tx.Vout {
+			scripts[j] = outputScript(&tx.Vout[j])
+		}
+		rows = appendOpReturnRows(rows, btxID, block.Height, scripts)
+	}
+	return rows, nil
+}
+
+func (d *RocksDB) storeOpReturns(wb *grocksdb.WriteBatch, rows []opReturnRow) {
+	for i := range rows {
+		wb.PutCF(d.cfh[cfOpReturns], rows[i].key, rows[i].value)
+	}
+}
+
+// deleteOpReturns removes the OP_RETURN outputs of the disconnected transaction from the index
+func (d *RocksDB) deleteOpReturns(wb *grocksdb.WriteBatch, btxID []byte, ta *TxAddresses) error {
+	scripts, err := d.getTxOutputScripts(btxID, ta)
+	if err != nil {
+		return err
+	}
+	for _, row := range appendOpReturnRows(nil, btxID, ta.Height, scripts) {
+		wb.DeleteCF(d.cfh[cfOpReturns], row.key)
+	}
+	return nil
+}
+
+// IsOpReturnIndexEnabled returns true if the OP_RETURN index is built and maintained
+func (d *RocksDB) IsOpReturnIndexEnabled() bool {
+	return d.is != nil && d.is.OpReturnIndex
+}
+
+// GetOpReturns returns at most count OP_RETURN outputs with the payload starting with the prefix, skipping the first offset outputs,
+// and the number of the outputs with the prefix, counted up to maxTotal
+func (d *RocksDB) GetOpReturns(prefix []byte, offset, count, maxTotal int) ([]OpReturnEntry, int, error) {
+	if !d.IsOpReturnIndexEnabled() {
+		return nil, 0, errors.New("OP_RETURN index is not enabled")
+	}
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfOpReturns])
+	defer it.Close()
+	entries := make([]OpReturnEntry, 0, count)
+	total := 0
+	suffixLen := d.chainParser.PackedTxidLen() + 4
+	for it.Seek(prefix); it.Valid() && total < maxTotal; it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+		// skip the payloads shorter than the prefix, which match the prefix only together with the txid
+		if len(key)-suffixLen < len(prefix) {
+			continue
+		}
+		total++
+		if total <= offset || len(entries) >= count {
+			continue
+		}
+		payload, txid, vout, err := d.unpackOpReturnKey(key)
+		if err != nil {
+			return nil, 0, err
+		}
+		height, _ := unpackVaruint(it.Value().Data())
+		entries = append(entries, OpReturnEntry{
+			Payload: payload,
+			Txid:    txid,
+			Vout:    vout,
+			Height:  uint32(height),
+		})
+	}
+	return entries, total, nil
+}
+
+// InitOpReturnIndex switches the OP_RETURN index on or off. If the index is enabled and was not maintained
+// until now, it is built from the txAddresses column. The build can be interrupted by a signal and is restarted on next run.
+// If the index is disabled, its rows are kept but are no longer valid and are rebuilt when the index is enabled again.
+func (d *RocksDB) InitOpReturnIndex(enabled bool, stop chan os.Signal) error {
+	if d.is == nil {
+		return errors.New("Internal state not set")
+	}
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		if enabled {
+			return errors.New("OP_RETURN index is supported only for Bitcoin type coins")
+		}
+		return nil
+	}
+	if !enabled {
+		d.opReturnIndex = false
+		if d.is.OpReturnIndex {
+			glog.Info("OP_RETURN index: disabled, it will be rebuilt when enabled again")
+			d.is.OpReturnIndex = false
+			return d.storeState(d.is)
+		}
+		return nil
+	}
+	if !d.is.OpReturnIndex {
+		if err := d.buildOpReturnIndex(stop); err != nil {
+			return err
+		}
+		d.is.OpReturnIndex = true
+		if err := d.storeState(d.is); err != nil {
+			return err
+		}
+	}
+	d.opReturnIndex = true
+	return nil
+}
+
+// buildOpReturnIndex replaces the OP_RETURN index by the OP_RETURN outputs from the txAddresses column,
+// the scripts longer than maxAddrDescLen are read from the outputScripts column
+func (d *RocksDB) buildOpReturnIndex(stop chan os.Signal) error {
+	start := time.Now()
+	glog.Info("OP_RETURN index: building from the txAddresses column")
+	// do not use cache
+	ro := grocksdb.NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetFillCache(false)
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	write := func() error {
+		if err := d.db.Write(d.wo, wb); err != nil {
+			return err
+		}
+		wb.Clear()
+		select {
+		case <-stop:
+			return ErrOperationInterrupted
+		default:
+		}
+		return nil
+	}
+	// remove the rows left from the previous build
+	it := d.db.NewIteratorCF(ro, d.cfh[cfOpReturns])
+	defer it.Close()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		wb.DeleteCF(d.cfh[cfOpReturns], it.Key().Data())
+		if wb.Count() >= opReturnBatchSize {
+			if err := write(); err != nil {
+				return err
+			}
+		}
+	}
+	var txs, outputs uint
+	itt := d.db.NewIteratorCF(ro, d.cfh[cfTxAddresses])
+	defer itt.Close()
+	for itt.SeekToFirst(); itt.Valid(); itt.Next() {
+		txs++
+		ta, err := unpackTxAddresses(itt.Value().Data())
+		if err != nil {
+			return err
+		}
+		scripts, err := d.getTxOutputScripts(itt.Key().Data(), ta)
+		if err != nil {
+			return err
+		}
+		rows := appendOpReturnRows(nil, itt.Key().Data(), ta.Height, scripts)
+		d.storeOpReturns(wb, rows)
+		outputs += uint(len(rows))
+		if wb.Count() >= opReturnBatchSize {
+			if err := write(); err != nil {
+				return err
+			}
+		}
+		if txs%1000000 == 0 {
+			glog.Info("OP_RETURN index: processed ", txs, " transactions, ", outputs, " OP_RETURN outputs")
+		}
+	}
+	if err := d.db.Write(d.wo, wb); err != nil {
+		return err
+	}
+	glog.Info("OP_RETURN index: built from ", txs, " transactions, ", outputs, " OP_RETURN outputs, done in ", time.Since(start))
+	return nil
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/linxGnu/grocksdb"
+	"github.com/martinboehm/btcutil/txscript"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// The outputScripts column contains the scripts of the outputs which cannot be obtained from the address descriptors
+// stored in the txAddresses column: the scripts longer than maxAddrDescLen, which are stored without the address descriptor,
+// and the pay-to-pubkey scripts, whose address descriptors are converted to pay-to-pubkey-hash. The rows are kept also after
+// the outputs are spent, the block filters of the spending blocks contain the scripts of the spent outputs. The column
+// is maintained only if the block filters or the OP_RETURN index are enabled.
+
+// packOutputScriptKey creates the key composed of the txid and the output index
+func packOutputScriptKey(btxID []byte, vout int32) []byte {
+	key := make([]byte, 0, len(btxID)+4)
+	key = append(key, btxID...)
+	return append(key, packUint(uint32(vout))...)
+}
+
+// outputScript returns the script of the output or nil if the script is not in the hex format
+func outputScript(output *bchain.Vout) []byte {
+	script, err := hex.DecodeString(output.ScriptPubKey.Hex)
+	if err != nil {
+		return nil
+	}
+	return script
+}
+
+// isOutputScriptStored returns true if the script of the output with the address descriptor is stored in the outputScripts column
+func isOutputScriptStored(script []byte, addrDesc bchain.AddressDescriptor) bool {
+	if len(script) == 0 || bytes.Equal(script, addrDesc) {
+		return false
+	}
+	return len(addrDesc) == 0 || txscript.GetScriptClass(script) == txscript.PubKeyTy
+}
+
+// addOutputScripts adds the scripts of the outputs of the block transactions which must be stored in the outputScripts column
+// to the map keyed by the output script key, the block transactions and their TxAddresses must be in the same order
+func (d *RocksDB) addOutputScripts(block *bchain.Block, blockTxAddresses []*TxAddresses, scripts map[string][]byte) error {
+	for i := range block.Txs {
+		tx := &block.Txs[i]
+		ta := blockTxAddresses[i]
+		if ta == nil {
+			continue
+		}
+		var btxID []byte
+		for j := range tx.Vout {
+			if j >= len(ta.Outputs) {
+				break
+			}
+			script := outputScript(&tx.Vout[j])
+			if !isOutputScriptStored(script, ta.Outputs[j].AddrDesc) {
+				continue
+			}
+			if btxID == nil {
+				var err error
+				if btxID, err = d.chainParser.PackTxid(tx.Txid); err != nil {
+					return err
+				}
+			}
+			scripts[string(packOutputScriptKey(btxID, int32(j)))] = script
+		}
+	}
+	return nil
+}
+
+func (d *RocksDB) storeOutputScripts(wb *grocksdb.WriteBatch, scripts map[string][]byte) {
+	for key, script := range scripts {
+		wb.PutCF(d.cfh[cfOutputScripts], []byte(key), script)
+	}
+}
+
+// deleteOutputScripts removes the stored output scripts of the disconnected transaction
+func (d *RocksDB) deleteOutputScripts(wb *grocksdb.WriteBatch, btxID []byte) {
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfOutputScripts])
+	defer it.Close()
+	for it.Seek(btxID); it.Valid(); it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, btxID) {
+			break
+		}
+		wb.DeleteCF(d.cfh[cfOutputScripts], append([]byte(nil), key...))
+	}
+}
+
+// getOutputScript returns the stored script of the output or nil if it is not stored
+func (d *RocksDB) getOutputScript(btxID []byte, vout int32) ([]byte, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfOutputScripts], packOutputScriptKey(btxID, vout))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if len(val.Data()) == 0 {
+		return nil, nil
+	}
+	return append([]byte(nil), val.Data()...), nil
+}
+
+// getTxOutputScripts returns the scripts of the outputs of the transaction, the scripts which cannot be obtained
+// from the address descriptors in TxAddresses are read from the outputScripts column
+func (d *RocksDB) getTxOutputScripts(btxID []byte, ta *TxAddresses) ([][]byte, error) {
+	scripts := make([][]byte, len(ta.Outputs))
+	for i := range ta.Outputs {
+		if scripts[i] = ta.Outputs[i].AddrDesc; len(scripts[i]) > 0 {
+			continue
+		}
+		script, err := d.getOutputScript(btxID, int32(i))
+		if err != nil {
+			return nil, err
+		}
+		scripts[i] = script
+	}
+	return scripts, nil
+}
+
+// getSpentOutputScripts returns the scripts of the outputs spent by the block transactions, the block transactions and their
+// TxAddresses must be in the same order. The scripts of the outputs are looked up in pending (the scripts which are not written
+// to the db yet) and in the outputScripts column, the address descriptors of the spent outputs are used for the other outputs.
+func (d *RocksDB) getSpentOutputScripts(block *bchain.Block, blockTxAddresses []*TxAddresses, pending map[string][]byte) ([][]byte, error) {
+	var scripts [][]byte
+	for i := range block.Txs {
+		tx := &block.Txs[i]
+		ta := blockTxAddresses[i]
+		for j := range tx.Vin {
+			input := &tx.Vin[j]
+			if input.Txid == "" {
+				continue
+			}
+			btxID, err := d.chainParser.PackTxid(input.Txid)
+			if err != nil {
+				continue
+			}
+			script, found := pending[string(packOutputScriptKey(btxID, int32(input.Vout)))]
+			if !found {
+				if script, err = d.getOutputScript(btxID, int32(input.Vout)); err != nil {
+					return nil, err
+				}
+			}
+			if script == nil && ta != nil && j < len(ta.Inputs) {
+				script = ta.Inputs[j].AddrDesc
+			}
+			if len(script) > 0 {
+				scripts = append(scripts, script)
+			}
+		}
+	}
+	return scripts, nil
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+)
+
+// number of the last blocks remembered by the replica to detect the chain reorganizations
+const replicaBlockHashes = 100
+
+// NewRocksDBReadOnly opens the index in the directory path as a read only replica of the index maintained
+// by another blockbook process (the primary). The replica is a RocksDB secondary instance, which keeps its own
+// info logs in secondaryPath. It sees the data written by the primary until the time of opening,
+// ReplicaWorker.CatchUp makes it see the data written since then.
+func NewRocksDBReadOnly(path, secondaryPath string, cacheSize, maxOpenFiles int, parser bchain.BlockChainParser, metrics *common.Metrics) (*RocksDB, error) {
+	if secondaryPath == "" {
+		return nil, errors.New("Missing path of the secondary instance")
+	}
+	return newRocksDB(path, secondaryPath, cacheSize, maxOpenFiles, parser, metrics)
+}
+
+// IsReadOnly returns true if the db is opened as a read only replica
+func (d *RocksDB) IsReadOnly() bool {
+	return d.readOnly
+}
+
+// BeginRead must be called by each request or background task accessing the read only replica
+// it prevents the catching up of the replica until EndRead is called, so that the request sees consistent data,
+// the calls must not be nested
+func (d *RocksDB) BeginRead() {
+	if d.readOnly {
+		d.replicaLock.RLock()
+	}
+}
+
+// EndRead ends the access started by BeginRead
+func (d *RocksDB) EndRead() {
+	if d.readOnly {
+		d.replicaLock.RUnlock()
+	}
+}
+
+// catchUpWithPrimary makes the secondary instance see the data written by the primary, after the running readers finish
+// only the new entries of the write ahead log and the new table files of the primary are read, the db is not reopened
+func (d *RocksDB) catchUpWithPrimary() error {
+	d.replicaLock.Lock()
+	defer d.replicaLock.Unlock()
+	return d.db.TryCatchUpWithPrimary()
+}
+
+// getStoredInternalState returns the internal state stored in the db without any modification
+func (d *RocksDB) getStoredInternalState() (*common.InternalState, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfDefault], []byte(internalStateKey))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if len(val.Data()) == 0 {
+		return nil, errors.New("Internal state not found")
+	}
+	return common.UnpackInternalState(val.Data())
+}
+
+// ReplicaWorker keeps the read only replica and its internal state up to date with the primary
+type ReplicaWorker struct {
+	db         *RocksDB
+	is         *common.InternalState
+	bestHeight uint32
+	// hashes of the last blocks seen by the replica, the last one is at bestHeight
+	hashes []string
+}
+
+// NewReplicaWorker creates a new ReplicaWorker for the read only replica
+func NewReplicaWorker(db *RocksDB, is *common.InternalState) (*ReplicaWorker, error) {
+	if !db.readOnly {
+		return nil, errors.New("DB is not opened as read only replica")
+	}
+	w := &ReplicaWorker{
+		db: db,
+		is: is,
+	}
+	if err := w.loadBlockHashes(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *ReplicaWorker) loadBlockHashes() error {
+	bestHeight, bestHash, err := w.db.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	w.bestHeight = bestHeight
+	w.hashes = w.hashes[:0]
+	if bestHash == "" {
+		return nil
+	}
+	from := uint32(0)
+	if bestHeight >= replicaBlockHashes {
+		from = bestHeight - replicaBlockHashes + 1
+	}
+	for height := from; height <= bestHeight; height++ {
+		hash, err := w.db.GetBlockHash(height)
+		if err != nil {
+			return err
+		}
+		w.hashes = append(w.hashes, hash)
+	}
+	return nil
+}
+
+// forkIndex returns the index in hashes of the highest block seen by the replica which is still in the index or -1
+func (w *ReplicaWorker) forkIndex(bestHeight uint32) (int, error) {
+	first := w.bestHeight + 1 - uint32(len(w.hashes))
+	for i := len(w.hashes) - 1; i >= 0; i-- {
+		height := first + uint32(i)
+		if height > bestHeight {
+			continue
+		}
+		hash, err := w.db.GetBlockHash(height)
+		if err != nil {
+			return 0, err
+		}
+		if hash == w.hashes[i] {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// CatchUp catches up the replica with the primary to see the blocks connected by it and updates the internal state
+// onNewBlock is called for each new block, onReorg if the blocks seen by the replica were disconnected by the primary
+// the reorg notification contains only the disconnected blocks, their transactions are not known to the replica
+func (w *ReplicaWorker) CatchUp(onNewBlock bchain.OnNewBlockFunc, onReorg bchain.OnReorgFunc) error {
+	if err := w.db.catchUpWithPrimary(); err != nil {
+		return errors.Annotatef(err, "TryCatchUpWithPrimary")
+	}
+	bestHeight, _, err := w.db.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	fork, err := w.forkIndex(bestHeight)
+	if err != nil {
+		return err
+	}
+	if fork < 0 {
+		if len(w.hashes) > 0 {
+			glog.Warning("replica: reorg deeper than ", len(w.hashes), " blocks, reloading block times")
+		}
+		times, err := w.db.loadBlockTimes()
+		if err != nil {
+			return err
+		}
+		w.is.SetBlockTimes(times)
+		if err = w.loadBlockHashes(); err != nil {
+			return err
+		}
+	} else {
+		forkHeight := w.bestHeight + 1 - uint32(len(w.hashes)) + uint32(fork)
+		if forkHeight < w.bestHeight {
+			disconnected := make([]string, 0, len(w.hashes)-fork-1)
+			for i := len(w.hashes) - 1; i > fork; i-- {
+				disconnected = append(disconnected, w.hashes[i])
+			}
+			glog.Info("replica: blocks ", forkHeight+1, "-", w.bestHeight, " disconnected by the primary")
+			w.is.RemoveLastBlockTimes(int(w.bestHeight - forkHeight))
+			w.hashes = w.hashes[:fork+1]
+			if onReorg != nil {
+				onReorg(&bchain.ChainReorg{
+					ForkHeight:         forkHeight,
+					DisconnectedHashes: disconnected,
+				})
+			}
+		}
+		for height := forkHeight + 1; height <= bestHeight; height++ {
+			bi, err := w.db.GetBlockInfo(height)
+			if err != nil {
+				return err
+			}
+			if bi == nil {
+				return errors.Errorf("Block %v not found", height)
+			}
+			w.is.AppendBlockTime(uint32(bi.Time))
+			w.hashes = append(w.hashes, bi.Hash)
+			if onNewBlock != nil {
+				onNewBlock(bi.Hash, height)
+			}
+		}
+		if len(w.hashes) > replicaBlockHashes {
+			w.hashes = append(w.hashes[:0:0], w.hashes[len(w.hashes)-replicaBlockHashes:]...)
+		}
+		w.bestHeight = bestHeight
+	}
+	primary, err := w.db.getStoredInternalState()
+	if err != nil {
+		return err
+	}
+	w.is.SetReplicaState(primary, w.bestHeight)
+	return nil
+}
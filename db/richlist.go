@@ -0,0 +1,245 @@
+package db
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// RichListEntry is an address with positive balance in the rich list
+type RichListEntry struct {
+	AddrDesc   bchain.AddressDescriptor
+	BalanceSat big.Int
+}
+
+// the row with the number of addresses and the sum of their balances, sorted after all addresses
+var richListTotalKey = []byte{0xff}
+
+var richListBatchSize = 10000
+
+// packRichListKey creates a key which sorts the addresses in the descending order of the balance,
+// the key is composed of the inverted length and the inverted big endian bytes of the balance followed by the address descriptor
+func packRichListKey(addrDesc bchain.AddressDescriptor, balance *big.Int) []byte {
+	b := balance.Bytes()
+	key := make([]byte, 0, 1+len(b)+len(addrDesc))
+	key = append(key, ^byte(len(b)))
+	for _, v := range b {
+		key = append(key, ^v)
+	}
+	return append(key, addrDesc...)
+}
+
+func unpackRichListKey(key []byte) (bchain.AddressDescriptor, *big.Int, error) {
+	if len(key) == 0 {
+		return nil, nil, errors.New("Invalid rich list key")
+	}
+	l := int(^key[0])
+	if len(key) < 1+l {
+		return nil, nil, errors.New("Invalid rich list key")
+	}
+	b := make([]byte, l)
+	for i := range b {
+		b[i] = ^key[1+i]
+	}
+	return append(bchain.AddressDescriptor(nil), key[1+l:]...), new(big.Int).SetBytes(b), nil
+}
+
+func packRichListTotal(count uint, total *big.Int) []byte {
+	buf := make([]byte, 0, 2*maxPackedBigintBytes)
+	varBuf := make([]byte, maxPackedBigintBytes)
+	l := packVaruint(count, varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packBigint(total, varBuf)
+	return append(buf, varBuf[:l]...)
+}
+
+// IsRichListEnabled returns true if the rich list is built and maintained in the index
+func (d *RocksDB) IsRichListEnabled() bool {
+	return d.is != nil && d.is.RichList
+}
+
+// GetRichListTotal returns the number of addresses with positive balance and the sum of their balances
+func (d *RocksDB) GetRichListTotal() (uint, *big.Int, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfRichList], richListTotalKey)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return 0, new(big.Int), nil
+	}
+	count, l := unpackVaruint(buf)
+	total, _ := unpackBigint(buf[l:])
+	return count, &total, nil
+}
+
+// GetRichList returns at most count addresses with the highest balance, skipping the first offset addresses
+func (d *RocksDB) GetRichList(offset, count int) ([]RichListEntry, error) {
+	if !d.IsRichListEnabled() {
+		return nil, errors.New("Rich list is not enabled")
+	}
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfRichList])
+	defer it.Close()
+	entries := make([]RichListEntry, 0, count)
+	for it.SeekToFirst(); it.Valid() && len(entries) < count; it.Next() {
+		key := it.Key().Data()
+		if bytes.Equal(key, richListTotalKey) {
+			break
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		addrDesc, balance, err := unpackRichListKey(key)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, RichListEntry{AddrDesc: addrDesc, BalanceSat: *balance})
+	}
+	return entries, nil
+}
+
+// storeRichList updates the positions of the addresses in the rich list from their balances stored in the db to the new balances,
+// it must be called before the new balances are written to the db
+func (d *RocksDB) storeRichList(wb *grocksdb.WriteBatch, abm map[string]*AddrBalance) error {
+	count, total, err := d.GetRichListTotal()
+	if err != nil {
+		return err
+	}
+	for addrDesc, ab := range abm {
+		var balance *big.Int
+		if ab != nil && ab.Txs > 0 {
+			balance = &ab.BalanceSat
+		}
+		old, err := d.GetAddrDescBalance(bchain.AddressDescriptor(addrDesc), AddressBalanceDetailNoUTXO)
+		if err != nil {
+			return err
+		}
+		if old != nil && balance != nil && old.BalanceSat.Cmp(balance) == 0 {
+			continue
+		}
+		if old != nil && old.BalanceSat.Sign() > 0 {
+			wb.DeleteCF(d.cfh[cfRichList], packRichListKey(bchain.AddressDescriptor(addrDesc), &old.BalanceSat))
+			total.Sub(total, &old.BalanceSat)
+			count--
+		}
+		if balance != nil && balance.Sign() > 0 {
+			wb.PutCF(d.cfh[cfRichList], packRichListKey(bchain.AddressDescriptor(addrDesc), balance), []byte{})
+			total.Add(total, balance)
+			count++
+		}
+	}
+	wb.PutCF(d.cfh[cfRichList], richListTotalKey, packRichListTotal(count, total))
+	return nil
+}
+
+// InitRichList switches the maintenance of the rich list on or off. If the rich list is enabled and was not maintained
+// until now, it is built from the addressBalance column. The build can be interrupted by a signal and is restarted on next run.
+// If the rich list is disabled, its rows are kept but are no longer valid and are rebuilt when the rich list is enabled again.
+// For Ethereum type coins, the token holders are maintained instead, they can be enabled only before the first block is connected.
+func (d *RocksDB) InitRichList(enabled bool, stop chan os.Signal) error {
+	if d.is == nil {
+		return errors.New("Internal state not set")
+	}
+	if d.chainParser.GetChainType() == bchain.ChainEthereumType {
+		return d.initTokenHolders(enabled)
+	}
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		if enabled {
+			return errors.New("Rich list is supported only for Bitcoin type coins")
+		}
+		return nil
+	}
+	if !enabled {
+		d.richList = false
+		if d.is.RichList {
+			glog.Info("rich list: disabled, it will be rebuilt when enabled again")
+			d.is.RichList = false
+			return d.storeState(d.is)
+		}
+		return nil
+	}
+	if !d.is.RichList {
+		if err := d.buildRichList(stop); err != nil {
+			return err
+		}
+		d.is.RichList = true
+		if err := d.storeState(d.is); err != nil {
+			return err
+		}
+	}
+	d.richList = true
+	return nil
+}
+
+// buildRichList replaces the rich list by the addresses with positive balance from the addressBalance column
+func (d *RocksDB) buildRichList(stop chan os.Signal) error {
+	start := time.Now()
+	glog.Info("rich list: building from the addressBalance column")
+	// do not use cache
+	ro := grocksdb.NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetFillCache(false)
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	write := func() error {
+		if err := d.db.Write(d.wo, wb); err != nil {
+			return err
+		}
+		wb.Clear()
+		select {
+		case <-stop:
+			return ErrOperationInterrupted
+		default:
+		}
+		return nil
+	}
+	// remove the rows left from the previous build
+	it := d.db.NewIteratorCF(ro, d.cfh[cfRichList])
+	defer it.Close()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		wb.DeleteCF(d.cfh[cfRichList], it.Key().Data())
+		if wb.Count() >= richListBatchSize {
+			if err := write(); err != nil {
+				return err
+			}
+		}
+	}
+	var count, rows uint
+	total := new(big.Int)
+	itb := d.db.NewIteratorCF(ro, d.cfh[cfAddressBalance])
+	defer itb.Close()
+	for itb.SeekToFirst(); itb.Valid(); itb.Next() {
+		rows++
+		ab, err := unpackAddrBalance(itb.Value().Data(), d.chainParser.PackedTxidLen(), AddressBalanceDetailNoUTXO)
+		if err != nil {
+			return err
+		}
+		if ab.BalanceSat.Sign() > 0 {
+			wb.PutCF(d.cfh[cfRichList], packRichListKey(itb.Key().Data(), &ab.BalanceSat), []byte{})
+			total.Add(total, &ab.BalanceSat)
+			count++
+		}
+		if wb.Count() >= richListBatchSize {
+			if err := write(); err != nil {
+				return err
+			}
+		}
+		if rows%1000000 == 0 {
+			glog.Info("rich list: processed ", rows, " balances, ", count, " addresses with positive balance")
+		}
+	}
+	wb.PutCF(d.cfh[cfRichList], richListTotalKey, packRichListTotal(count, total))
+	if err := d.db.Write(d.wo, wb); err != nil {
+		return err
+	}
+	glog.Info("rich list: built from ", rows, " balances, ", count, " addresses with positive balance, total ", total.String(), ", done in ", time.Since(start))
+	return nil
+}
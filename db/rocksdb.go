@@ -11,18 +11,21 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 	"unsafe"
 
 	vlq "github.com/bsm/go-vlq"
-	"github.com/flier/gorocksdb"
 	"github.com/golang/glog"
 	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
 	"github.com/trezor/blockbook/bchain"
 	"github.com/trezor/blockbook/common"
 )
 
-const dbVersion = 5
+// version 6 stores witness, virtual size and weight of the transactions in the txcache
+// and computes the block fee statistics from the virtual size of the transactions
+const dbVersion = 6
 
 const packedHeightBytes = 4
 const maxAddrDescLen = 1024
@@ -62,8 +65,8 @@ type ResultTickerListAsString struct {
 // RepairRocksDB calls RocksDb db repair function
 func RepairRocksDB(name string) error {
 	glog.Infof("rocksdb: repair")
-	opts := gorocksdb.NewDefaultOptions()
-	return gorocksdb.RepairDb(name, opts)
+	opts := grocksdb.NewDefaultOptions()
+	return grocksdb.RepairDb(name, opts)
 }
 
 type connectBlockStats struct {
@@ -88,16 +91,29 @@ const (
 // RocksDB handle
 type RocksDB struct {
 	path         string
-	db           *gorocksdb.DB
-	wo           *gorocksdb.WriteOptions
-	ro           *gorocksdb.ReadOptions
-	cfh          []*gorocksdb.ColumnFamilyHandle
+	db           *grocksdb.DB
+	wo           *grocksdb.WriteOptions
+	ro           *grocksdb.ReadOptions
+	cfh          []*grocksdb.ColumnFamilyHandle
 	chainParser  bchain.BlockChainParser
 	is           *common.InternalState
 	metrics      *common.Metrics
-	cache        *gorocksdb.Cache
+	cache        *grocksdb.Cache
 	maxOpenFiles int
 	cbs          connectBlockStats
+	readOnly     bool
+	// secondaryPath is the directory of the secondary instance of the read only replica
+	secondaryPath string
+	// richList is true if the rich list is maintained together with the balances, for Ethereum type coins the token holders
+	richList bool
+	// opReturnIndex is true if the OP_RETURN outputs of the connected blocks are indexed
+	opReturnIndex bool
+	// blockFilters is true if the block filters of the connected blocks are computed and stored
+	blockFilters bool
+	// scriptHashIndex is true if the script hashes of the addresses are indexed together with the balances
+	scriptHashIndex bool
+	// replicaLock guards the catching up of the read only replica against the running readers
+	replicaLock sync.RWMutex
 }
 
 const (
@@ -107,35 +123,58 @@ const (
 	cfBlockTxs
 	cfTransactions
 	cfFiatRates
+	cfWatchGroups
+	cfStaleBlocks
 	// BitcoinType
 	cfAddressBalance
 	cfTxAddresses
+	cfBlockFeeStats
+	cfRichList
+	cfBlockFilters
+	cfOpReturns
+	cfScriptHashes
+	cfOutputScripts
 	// EthereumType
-	cfAddressContracts = cfAddressBalance
+	cfAddressContracts  = cfAddressBalance
+	cfTokenBalances     = cfTxAddresses
+	cfTokenHolders      = cfBlockFeeStats
+	cfTokenBalancesUndo = cfRichList
 )
 
 // common columns
 var cfNames []string
-var cfBaseNames = []string{"default", "height", "addresses", "blockTxs", "transactions", "fiatRates"}
+var cfBaseNames = []string{"default", "height", "addresses", "blockTxs", "transactions", "fiatRates", "watchGroups", "staleBlocks"}
 
 // type specific columns
-var cfNamesBitcoinType = []string{"addressBalance", "txAddresses"}
-var cfNamesEthereumType = []string{"addressContracts"}
+var cfNamesBitcoinType = []string{"addressBalance", "txAddresses", "blockFeeStats", "richList", "blockFilters", "opReturns", "scriptHashes", "outputScripts"}
+var cfNamesEthereumType = []string{"addressContracts", "tokenBalances", "tokenHolders", "tokenBalancesUndo"}
 
-func openDB(path string, c *gorocksdb.Cache, openFiles int) (*gorocksdb.DB, []*gorocksdb.ColumnFamilyHandle, error) {
+// openDB opens the db in path, if secondaryPath is set, the db is opened as a secondary instance of the db maintained by another process
+func openDB(path string, secondaryPath string, c *grocksdb.Cache, openFiles int) (*grocksdb.DB, []*grocksdb.ColumnFamilyHandle, error) {
+	if secondaryPath != "" {
+		// the secondary instance must keep all files open to be able to catch up with the primary
+		openFiles = -1
+	}
 	// opts with bloom filter
 	opts := createAndSetDBOptions(10, c, openFiles)
 	// opts for addresses without bloom filter
 	// from documentation: if most of your queries are executed using iterators, you shouldn't set bloom filter
 	optsAddresses := createAndSetDBOptions(0, c, openFiles)
-	// default, height, addresses, blockTxids, transactions
-	cfOptions := []*gorocksdb.Options{opts, opts, optsAddresses, opts, opts, opts}
+	// default, height, addresses, blockTxids, transactions, fiatRates, watchGroups, staleBlocks
+	cfOptions := []*grocksdb.Options{opts, opts, optsAddresses, opts, opts, opts, opts, opts}
 	// append type specific options
 	count := len(cfNames) - len(cfOptions)
 	for i := 0; i < count; i++ {
 		cfOptions = append(cfOptions, opts)
 	}
-	db, cfh, err := gorocksdb.OpenDbColumnFamilies(opts, path, cfNames, cfOptions)
+	var db *grocksdb.DB
+	var cfh []*grocksdb.ColumnFamilyHandle
+	var err error
+	if secondaryPath != "" {
+		db, cfh, err = grocksdb.OpenDbAsSecondaryColumnFamilies(opts, path, secondaryPath, cfNames, cfOptions)
+	} else {
+		db, cfh, err = grocksdb.OpenDbColumnFamilies(opts, path, cfNames, cfOptions)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -145,7 +184,11 @@ func openDB(path string, c *gorocksdb.Cache, openFiles int) (*gorocksdb.DB, []*g
 // NewRocksDB opens an internal handle to RocksDB environment.  Close
 // needs to be called to release it.
 func NewRocksDB(path string, cacheSize, maxOpenFiles int, parser bchain.BlockChainParser, metrics *common.Metrics) (d *RocksDB, err error) {
-	glog.Infof("rocksdb: opening %s, required data version %v, cache size %v, max open files %v", path, dbVersion, cacheSize, maxOpenFiles)
+	return newRocksDB(path, "", cacheSize, maxOpenFiles, parser, metrics)
+}
+
+func newRocksDB(path string, secondaryPath string, cacheSize, maxOpenFiles int, parser bchain.BlockChainParser, metrics *common.Metrics) (d *RocksDB, err error) {
+	glog.Infof("rocksdb: opening %s, required data version %v, cache size %v, max open files %v, secondary path %q", path, dbVersion, cacheSize, maxOpenFiles, secondaryPath)
 
 	cfNames = append([]string{}, cfBaseNames...)
 	chainType := parser.GetChainType()
@@ -157,14 +200,26 @@ func NewRocksDB(path string, cacheSize, maxOpenFiles int, parser bchain.BlockCha
 		return nil, errors.New("Unknown chain type")
 	}
 
-	c := gorocksdb.NewLRUCache(uint64(cacheSize))
-	db, cfh, err := openDB(path, c, maxOpenFiles)
+	c := grocksdb.NewLRUCache(uint64(cacheSize))
+	db, cfh, err := openDB(path, secondaryPath, c, maxOpenFiles)
 	if err != nil {
 		return nil, err
 	}
-	wo := gorocksdb.NewDefaultWriteOptions()
-	ro := gorocksdb.NewDefaultReadOptions()
-	return &RocksDB{path, db, wo, ro, cfh, parser, nil, metrics, c, maxOpenFiles, connectBlockStats{}}, nil
+	wo := grocksdb.NewDefaultWriteOptions()
+	ro := grocksdb.NewDefaultReadOptions()
+	return &RocksDB{
+		path:          path,
+		db:            db,
+		wo:            wo,
+		ro:            ro,
+		cfh:           cfh,
+		chainParser:   parser,
+		metrics:       metrics,
+		cache:         c,
+		maxOpenFiles:  maxOpenFiles,
+		readOnly:      secondaryPath != "",
+		secondaryPath: secondaryPath,
+	}, nil
 }
 
 func (d *RocksDB) closeDB() error {
@@ -277,8 +332,8 @@ func (d *RocksDB) FiatRatesFindLastTicker() (*CurrencyRatesTicker, error) {
 // Close releases the RocksDB environment opened in NewRocksDB.
 func (d *RocksDB) Close() error {
 	if d.db != nil {
-		// store the internal state of the app
-		if d.is != nil && d.is.DbState == common.DbStateOpen {
+		// store the internal state of the app, the state of the read only replica belongs to the primary
+		if d.is != nil && d.is.DbState == common.DbStateOpen && !d.readOnly {
 			d.is.DbState = common.DbStateClosed
 			if err := d.StoreInternalState(d.is); err != nil {
 				glog.Info("internalState: ", err)
@@ -300,7 +355,7 @@ func (d *RocksDB) Reopen() error {
 		return err
 	}
 	d.db = nil
-	db, cfh, err := openDB(d.path, d.cache, d.maxOpenFiles)
+	db, cfh, err := openDB(d.path, d.secondaryPath, d.cache, d.maxOpenFiles)
 	if err != nil {
 		return err
 	}
@@ -437,7 +492,7 @@ const (
 
 // ConnectBlock indexes addresses in the block and stores them in db
 func (d *RocksDB) ConnectBlock(block *bchain.Block) error {
-	wb := gorocksdb.NewWriteBatch()
+	wb := grocksdb.NewWriteBatch()
 	defer wb.Destroy()
 
 	if glog.V(2) {
@@ -446,16 +501,49 @@ func (d *RocksDB) ConnectBlock(block *bchain.Block) error {
 
 	chainType := d.chainParser.GetChainType()
 
-	if err := d.writeHeightFromBlock(wb, block, opInsert); err != nil {
-		return err
-	}
 	addresses := make(addressesMap)
 	if chainType == bchain.ChainBitcoinType {
 		txAddressesMap := make(map[string]*TxAddresses)
 		balances := make(map[string]*AddrBalance)
-		if err := d.processAddressesBitcoinType(block, addresses, txAddressesMap, balances); err != nil {
+		blockTxAddresses, err := d.processAddressesBitcoinType(block, addresses, txAddressesMap, balances)
+		if err != nil {
+			return err
+		}
+		supply, err := d.getPrevSupplyStats(block.Height)
+		if err != nil {
+			return err
+		}
+		if supply != nil {
+			supply = d.ComputeSupplyStats(supply, block, blockTxAddresses)
+		}
+		if err := d.writeHeightFromBlock(wb, block, supply, opInsert); err != nil {
 			return err
 		}
+		d.storeBlockFeeStats(wb, block.Height, ComputeBlockFeeStats(block, blockTxAddresses))
+		outputScripts := make(map[string][]byte)
+		if d.blockFilters {
+			prevFilterHeader, err := d.getPrevBlockFilterHeader(block.Height)
+			if err != nil {
+				return err
+			}
+			filter, err := d.computeBlockFilter(block, blockTxAddresses, outputScripts, prevFilterHeader)
+			if err != nil {
+				return err
+			}
+			d.storeBlockFilter(wb, block.Height, filter)
+		} else if d.opReturnIndex {
+			if err := d.addOutputScripts(block, blockTxAddresses, outputScripts); err != nil {
+				return err
+			}
+		}
+		d.storeOutputScripts(wb, outputScripts)
+		if d.opReturnIndex {
+			rows, err := d.getOpReturnRows(block, blockTxAddresses)
+			if err != nil {
+				return err
+			}
+			d.storeOpReturns(wb, rows)
+		}
 		if err := d.storeTxAddresses(wb, txAddressesMap); err != nil {
 			return err
 		}
@@ -466,6 +554,9 @@ func (d *RocksDB) ConnectBlock(block *bchain.Block) error {
 			return err
 		}
 	} else if chainType == bchain.ChainEthereumType {
+		if err := d.writeHeightFromBlock(wb, block, nil, opInsert); err != nil {
+			return err
+		}
 		addressContracts := make(map[string]*AddrContracts)
 		blockTxs, err := d.processAddressesEthereumType(block, addresses, addressContracts)
 		if err != nil {
@@ -474,6 +565,13 @@ func (d *RocksDB) ConnectBlock(block *bchain.Block) error {
 		if err := d.storeAddressContracts(wb, addressContracts); err != nil {
 			return err
 		}
+		if d.richList {
+			changes := make(map[string]*big.Int)
+			d.addTokenTransfers(block, changes)
+			if err := d.storeTokenBalances(wb, block.Height, changes, true); err != nil {
+				return err
+			}
+		}
 		if err := d.storeAndCleanupBlockTxsEthereumType(wb, block, blockTxs); err != nil {
 			return err
 		}
@@ -677,7 +775,8 @@ func (d *RocksDB) GetAndResetConnectBlockStats() string {
 	return s
 }
 
-func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses addressesMap, txAddressesMap map[string]*TxAddresses, balances map[string]*AddrBalance) error {
+// processAddressesBitcoinType processes the block transactions, returns TxAddresses of the block transactions in the order of the transactions
+func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses addressesMap, txAddressesMap map[string]*TxAddresses, balances map[string]*AddrBalance) ([]*TxAddresses, error) {
 	blockTxIDs := make([][]byte, len(block.Txs))
 	blockTxAddresses := make([]*TxAddresses, len(block.Txs))
 	// first process all outputs so that inputs can refer to txs in this block
@@ -685,7 +784,7 @@ func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses add
 		tx := &block.Txs[txi]
 		btxID, err := d.chainParser.PackTxid(tx.Txid)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		blockTxIDs[txi] = btxID
 		ta := TxAddresses{Height: block.Height}
@@ -714,7 +813,7 @@ func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses add
 				if !e {
 					balance, err = d.GetAddrDescBalance(addrDesc, addressBalanceDetailUTXOIndexed)
 					if err != nil {
-						return err
+						return nil, err
 					}
 					if balance == nil {
 						balance = &AddrBalance{}
@@ -753,14 +852,14 @@ func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses add
 				if err == bchain.ErrTxidMissing {
 					continue
 				}
-				return err
+				return nil, err
 			}
 			stxID := string(btxID)
 			ita, e := txAddressesMap[stxID]
 			if !e {
 				ita, err = d.getTxAddresses(btxID)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				if ita == nil {
 					// allow parser to process unknown input, some coins may implement special handling, default is to log warning
@@ -797,7 +896,7 @@ func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses add
 				if !e {
 					balance, err = d.GetAddrDescBalance(spentOutput.AddrDesc, addressBalanceDetailUTXOIndexed)
 					if err != nil {
-						return err
+						return nil, err
 					}
 					if balance == nil {
 						balance = &AddrBalance{}
@@ -820,7 +919,7 @@ func (d *RocksDB) processAddressesBitcoinType(block *bchain.Block, addresses add
 			}
 		}
 	}
-	return nil
+	return blockTxAddresses, nil
 }
 
 // addToAddressesMap maintains mapping between addresses and transactions in one block
@@ -846,7 +945,7 @@ func addToAddressesMap(addresses addressesMap, strAddrDesc string, btxID []byte,
 	return false
 }
 
-func (d *RocksDB) storeAddresses(wb *gorocksdb.WriteBatch, height uint32, addresses addressesMap) error {
+func (d *RocksDB) storeAddresses(wb *grocksdb.WriteBatch, height uint32, addresses addressesMap) error {
 	for addrDesc, txi := range addresses {
 		ba := bchain.AddressDescriptor(addrDesc)
 		key := packAddressKey(ba, height)
@@ -856,7 +955,7 @@ func (d *RocksDB) storeAddresses(wb *gorocksdb.WriteBatch, height uint32, addres
 	return nil
 }
 
-func (d *RocksDB) storeTxAddresses(wb *gorocksdb.WriteBatch, am map[string]*TxAddresses) error {
+func (d *RocksDB) storeTxAddresses(wb *grocksdb.WriteBatch, am map[string]*TxAddresses) error {
 	varBuf := make([]byte, maxPackedBigintBytes)
 	buf := make([]byte, 1024)
 	for txID, ta := range am {
@@ -866,7 +965,15 @@ func (d *RocksDB) storeTxAddresses(wb *gorocksdb.WriteBatch, am map[string]*TxAd
 	return nil
 }
 
-func (d *RocksDB) storeBalances(wb *gorocksdb.WriteBatch, abm map[string]*AddrBalance) error {
+func (d *RocksDB) storeBalances(wb *grocksdb.WriteBatch, abm map[string]*AddrBalance) error {
+	if d.richList {
+		if err := d.storeRichList(wb, abm); err != nil {
+			return err
+		}
+	}
+	if d.scriptHashIndex {
+		d.storeScriptHashes(wb, abm)
+	}
 	// allocate buffer initial buffer
 	buf := make([]byte, 1024)
 	varBuf := make([]byte, maxPackedBigintBytes)
@@ -882,7 +989,7 @@ func (d *RocksDB) storeBalances(wb *gorocksdb.WriteBatch, abm map[string]*AddrBa
 	return nil
 }
 
-func (d *RocksDB) cleanupBlockTxs(wb *gorocksdb.WriteBatch, block *bchain.Block) error {
+func (d *RocksDB) cleanupBlockTxs(wb *grocksdb.WriteBatch, block *bchain.Block) error {
 	keep := d.chainParser.KeepBlockAddresses()
 	// cleanup old block address
 	if block.Height > uint32(keep) {
@@ -903,7 +1010,7 @@ func (d *RocksDB) cleanupBlockTxs(wb *gorocksdb.WriteBatch, block *bchain.Block)
 	return nil
 }
 
-func (d *RocksDB) storeAndCleanupBlockTxs(wb *gorocksdb.WriteBatch, block *bchain.Block) error {
+func (d *RocksDB) storeAndCleanupBlockTxs(wb *grocksdb.WriteBatch, block *bchain.Block) error {
 	pl := d.chainParser.PackedTxidLen()
 	buf := make([]byte, 0, pl*len(block.Txs))
 	varBuf := make([]byte, vlq.MaxLen64)
@@ -1235,6 +1342,8 @@ type BlockInfo struct {
 	Txs    uint32
 	Size   uint32
 	Height uint32 // Height is not packed!
+	// Supply are the cumulative supply statistics after the block, nil if not available
+	Supply *SupplyStats
 }
 
 func (d *RocksDB) packBlockInfo(block *BlockInfo) ([]byte, error) {
@@ -1259,6 +1368,9 @@ func (d *RocksDB) packBlockInfo(block *BlockInfo) ([]byte, error) {
 	packed = append(packed, varBuf[:l]...)
 	l = packVaruint(uint(block.Size), varBuf)
 	packed = append(packed, varBuf[:l]...)
+	if block.Supply != nil {
+		packed = packSupplyStats(block.Supply, packed)
+	}
 	return packed, nil
 }
 
@@ -1274,13 +1386,20 @@ func (d *RocksDB) unpackBlockInfo(buf []byte) (*BlockInfo, error) {
 	}
 	t := unpackUint(buf[pl:])
 	txs, l := unpackVaruint(buf[pl+4:])
-	size, _ := unpackVaruint(buf[pl+4+l:])
-	return &BlockInfo{
+	size, ll := unpackVaruint(buf[pl+4+l:])
+	bi := &BlockInfo{
 		Hash: txid,
 		Time: int64(t),
 		Txs:  uint32(txs),
 		Size: uint32(size),
-	}, nil
+	}
+	// the supply statistics follow the block info, they are missing in the blocks indexed without them
+	if l = pl + 4 + l + ll; l < len(buf) {
+		if bi.Supply, err = unpackSupplyStats(buf[l:]); err != nil {
+			return nil, err
+		}
+	}
+	return bi, nil
 }
 
 // GetBestBlock returns the block hash of the block with highest height in the db
@@ -1331,17 +1450,18 @@ func (d *RocksDB) GetBlockInfo(height uint32) (*BlockInfo, error) {
 	return bi, err
 }
 
-func (d *RocksDB) writeHeightFromBlock(wb *gorocksdb.WriteBatch, block *bchain.Block, op int) error {
+func (d *RocksDB) writeHeightFromBlock(wb *grocksdb.WriteBatch, block *bchain.Block, supply *SupplyStats, op int) error {
 	return d.writeHeight(wb, block.Height, &BlockInfo{
 		Hash:   block.Hash,
 		Time:   block.Time,
 		Txs:    uint32(len(block.Txs)),
 		Size:   uint32(block.Size),
 		Height: block.Height,
+		Supply: supply,
 	}, op)
 }
 
-func (d *RocksDB) writeHeight(wb *gorocksdb.WriteBatch, height uint32, bi *BlockInfo, op int) error {
+func (d *RocksDB) writeHeight(wb *grocksdb.WriteBatch, height uint32, bi *BlockInfo, op int) error {
 	key := packUint(height)
 	switch op {
 	case opInsert:
@@ -1360,7 +1480,7 @@ func (d *RocksDB) writeHeight(wb *gorocksdb.WriteBatch, height uint32, bi *Block
 
 // Disconnect blocks
 
-func (d *RocksDB) disconnectTxAddressesInputs(wb *gorocksdb.WriteBatch, btxID []byte, inputs []outpoint, txa *TxAddresses, txAddressesToUpdate map[string]*TxAddresses,
+func (d *RocksDB) disconnectTxAddressesInputs(wb *grocksdb.WriteBatch, btxID []byte, inputs []outpoint, txa *TxAddresses, txAddressesToUpdate map[string]*TxAddresses,
 	getAddressBalance func(addrDesc bchain.AddressDescriptor) (*AddrBalance, error),
 	addressFoundInTx func(addrDesc bchain.AddressDescriptor, btxID []byte) bool) error {
 	var err error
@@ -1416,7 +1536,7 @@ func (d *RocksDB) disconnectTxAddressesInputs(wb *gorocksdb.WriteBatch, btxID []
 	return nil
 }
 
-func (d *RocksDB) disconnectTxAddressesOutputs(wb *gorocksdb.WriteBatch, btxID []byte, txa *TxAddresses,
+func (d *RocksDB) disconnectTxAddressesOutputs(wb *grocksdb.WriteBatch, btxID []byte, txa *TxAddresses,
 	getAddressBalance func(addrDesc bchain.AddressDescriptor) (*AddrBalance, error),
 	addressFoundInTx func(addrDesc bchain.AddressDescriptor, btxID []byte) bool) error {
 	for i, t := range txa.Outputs {
@@ -1448,7 +1568,7 @@ func (d *RocksDB) disconnectTxAddressesOutputs(wb *gorocksdb.WriteBatch, btxID [
 }
 
 func (d *RocksDB) disconnectBlock(height uint32, blockTxs []blockTxs) error {
-	wb := gorocksdb.NewWriteBatch()
+	wb := grocksdb.NewWriteBatch()
 	defer wb.Destroy()
 	txAddressesToUpdate := make(map[string]*TxAddresses)
 	txAddresses := make([]*TxAddresses, len(blockTxs))
@@ -1517,6 +1637,12 @@ func (d *RocksDB) disconnectBlock(height uint32, blockTxs []blockTxs) error {
 		if err := d.disconnectTxAddressesOutputs(wb, btxID, txa, getAddressBalance, addressFoundInTx); err != nil {
 			return err
 		}
+		if d.opReturnIndex {
+			if err := d.deleteOpReturns(wb, btxID, txa); err != nil {
+				return err
+			}
+		}
+		d.deleteOutputScripts(wb, btxID)
 	}
 	for a := range blockAddressesTxs {
 		key := packAddressKey([]byte(a), height)
@@ -1525,6 +1651,8 @@ func (d *RocksDB) disconnectBlock(height uint32, blockTxs []blockTxs) error {
 	key := packUint(height)
 	wb.DeleteCF(d.cfh[cfBlockTxs], key)
 	wb.DeleteCF(d.cfh[cfHeight], key)
+	wb.DeleteCF(d.cfh[cfBlockFeeStats], key)
+	wb.DeleteCF(d.cfh[cfBlockFilters], key)
 	d.storeTxAddresses(wb, txAddressesToUpdate)
 	d.storeBalancesDisconnect(wb, balances)
 	for s := range txsToDelete {
@@ -1560,7 +1688,7 @@ func (d *RocksDB) DisconnectBlockRangeBitcoinType(lower uint32, higher uint32) e
 	return nil
 }
 
-func (d *RocksDB) storeBalancesDisconnect(wb *gorocksdb.WriteBatch, balances map[string]*AddrBalance) {
+func (d *RocksDB) storeBalancesDisconnect(wb *grocksdb.WriteBatch, balances map[string]*AddrBalance) {
 	for _, b := range balances {
 		if b != nil {
 			// remove spent utxos
@@ -1646,14 +1774,14 @@ func (d *RocksDB) DeleteTx(txid string) error {
 		return nil
 	}
 	// use write batch so that this delete matches other deletes
-	wb := gorocksdb.NewWriteBatch()
+	wb := grocksdb.NewWriteBatch()
 	defer wb.Destroy()
 	d.internalDeleteTx(wb, key)
 	return d.db.Write(d.wo, wb)
 }
 
 // internalDeleteTx checks if tx is cached and updates internal state accordingly
-func (d *RocksDB) internalDeleteTx(wb *gorocksdb.WriteBatch, key []byte) {
+func (d *RocksDB) internalDeleteTx(wb *grocksdb.WriteBatch, key []byte) {
 	val, err := d.db.GetCF(d.ro, d.cfh[cfTransactions], key)
 	// ignore error, it is only for statistics
 	if err == nil {
@@ -1729,9 +1857,13 @@ func (d *RocksDB) LoadInternalState(rpcCoin string) (*common.InternalState, erro
 		nc[i].Version = dbVersion
 		for j := 0; j < len(sc); j++ {
 			if sc[j].Name == nc[i].Name {
-				// check the version of the column, if it does not match, the db is not compatible
+				// check the version of the column, if it does not match and cannot be migrated, the db is not compatible
+				// the version of a migratable column is kept, the migration must be run by Migrate before the db is used
 				if sc[j].Version != dbVersion {
-					return nil, errors.Errorf("DB version %v of column '%v' does not match the required version %v. DB is not compatible.", sc[j].Version, sc[j].Name, dbVersion)
+					if !canMigrate(sc[j].Version) {
+						return nil, errors.Errorf("DB version %v of column '%v' does not match the required version %v. DB is not compatible.", sc[j].Version, sc[j].Name, dbVersion)
+					}
+					nc[i].Version = sc[j].Version
 				}
 				nc[i].Rows = sc[j].Rows
 				nc[i].KeyBytes = sc[j].KeyBytes
@@ -1772,6 +1904,11 @@ func (d *RocksDB) SetInconsistentState(inconsistent bool) error {
 // SetInternalState sets the InternalState to be used by db to collect internal state
 func (d *RocksDB) SetInternalState(is *common.InternalState) {
 	d.is = is
+	// keep the built rich list up to date until it is disabled by InitRichList
+	d.richList = is != nil && is.RichList && !d.readOnly
+	d.opReturnIndex = is != nil && is.OpReturnIndex && !d.readOnly
+	d.blockFilters = is != nil && is.BlockFilters && !d.readOnly
+	d.scriptHashIndex = is != nil && is.ScriptHashIndex && !d.readOnly
 }
 
 // StoreInternalState stores the internal state to db
@@ -1798,7 +1935,7 @@ func (d *RocksDB) computeColumnSize(col int, stopCompute chan os.Signal) (int64,
 	var rows, keysSum, valuesSum int64
 	var seekKey []byte
 	// do not use cache
-	ro := gorocksdb.NewDefaultReadOptions()
+	ro := grocksdb.NewDefaultReadOptions()
 	ro.SetFillCache(false)
 	for {
 		var key []byte
@@ -1943,7 +2080,7 @@ func (d *RocksDB) fixUtxo(addrDesc bchain.AddressDescriptor, ba *AddrBalance) (b
 				utxos[i], utxos[opp] = utxos[opp], utxos[i]
 			}
 			ba.Utxos = utxos
-			wb := gorocksdb.NewWriteBatch()
+			wb := grocksdb.NewWriteBatch()
 			err = d.storeBalances(wb, map[string]*AddrBalance{string(addrDesc): ba})
 			if err == nil {
 				err = d.db.Write(d.wo, wb)
@@ -1956,7 +2093,7 @@ func (d *RocksDB) fixUtxo(addrDesc bchain.AddressDescriptor, ba *AddrBalance) (b
 		}
 		return fixed, false, errors.Errorf("balance %s, checksum %s, from txa %s, txs %d", ba.BalanceSat.String(), checksum.String(), checksumFromTxs.String(), ba.Txs)
 	} else if reorder {
-		wb := gorocksdb.NewWriteBatch()
+		wb := grocksdb.NewWriteBatch()
 		err := d.storeBalances(wb, map[string]*AddrBalance{string(addrDesc): ba})
 		if err == nil {
 			err = d.db.Write(d.wo, wb)
@@ -1979,7 +2116,7 @@ func (d *RocksDB) FixUtxos(stop chan os.Signal) error {
 	var row, errorsCount, fixedCount int64
 	var seekKey []byte
 	// do not use cache
-	ro := gorocksdb.NewDefaultReadOptions()
+	ro := grocksdb.NewDefaultReadOptions()
 	ro.SetFillCache(false)
 	for {
 		var addrDesc bchain.AddressDescriptor
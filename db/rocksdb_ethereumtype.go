@@ -5,9 +5,9 @@ import (
 	"encoding/hex"
 
 	vlq "github.com/bsm/go-vlq"
-	"github.com/flier/gorocksdb"
 	"github.com/golang/glog"
 	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
 	"github.com/trezor/blockbook/bchain"
 	"github.com/trezor/blockbook/bchain/coins/eth"
 )
@@ -25,7 +25,7 @@ type AddrContracts struct {
 	Contracts      []AddrContract
 }
 
-func (d *RocksDB) storeAddressContracts(wb *gorocksdb.WriteBatch, acm map[string]*AddrContracts) error {
+func (d *RocksDB) storeAddressContracts(wb *grocksdb.WriteBatch, acm map[string]*AddrContracts) error {
 	buf := make([]byte, 64)
 	varBuf := make([]byte, vlq.MaxLen64)
 	for addrDesc, acs := range acm {
@@ -56,7 +56,10 @@ func (d *RocksDB) GetAddrDescContracts(addrDesc bchain.AddressDescriptor) (*Addr
 		return nil, err
 	}
 	defer val.Free()
-	buf := val.Data()
+	return unpackAddrContracts(val.Data(), addrDesc)
+}
+
+func unpackAddrContracts(buf []byte, addrDesc bchain.AddressDescriptor) (*AddrContracts, error) {
 	if len(buf) == 0 {
 		return nil, nil
 	}
@@ -243,7 +246,7 @@ func (d *RocksDB) processAddressesEthereumType(block *bchain.Block, addresses ad
 	return blockTxs, nil
 }
 
-func (d *RocksDB) storeAndCleanupBlockTxsEthereumType(wb *gorocksdb.WriteBatch, block *bchain.Block, blockTxs []ethBlockTx) error {
+func (d *RocksDB) storeAndCleanupBlockTxsEthereumType(wb *grocksdb.WriteBatch, block *bchain.Block, blockTxs []ethBlockTx) error {
 	pl := d.chainParser.PackedTxidLen()
 	buf := make([]byte, 0, (pl+2*eth.EthereumTypeAddressDescriptorLen)*len(blockTxs))
 	varBuf := make([]byte, vlq.MaxLen64)
@@ -340,7 +343,7 @@ func (d *RocksDB) getBlockTxsEthereumType(height uint32) ([]ethBlockTx, error) {
 	return bt, nil
 }
 
-func (d *RocksDB) disconnectBlockTxsEthereumType(wb *gorocksdb.WriteBatch, height uint32, blockTxs []ethBlockTx, contracts map[string]*AddrContracts) error {
+func (d *RocksDB) disconnectBlockTxsEthereumType(wb *grocksdb.WriteBatch, height uint32, blockTxs []ethBlockTx, contracts map[string]*AddrContracts) error {
 	glog.Info("Disconnecting block ", height, " containing ", len(blockTxs), " transactions")
 	addresses := make(map[string]map[string]struct{})
 	disconnectAddress := func(btxID []byte, addrDesc, contract bchain.AddressDescriptor) error {
@@ -441,7 +444,7 @@ func (d *RocksDB) DisconnectBlockRangeEthereumType(lower uint32, higher uint32)
 		}
 		blocks[height-lower] = blockTxs
 	}
-	wb := gorocksdb.NewWriteBatch()
+	wb := grocksdb.NewWriteBatch()
 	defer wb.Destroy()
 	contracts := make(map[string]*AddrContracts)
 	for height := higher; height >= lower; height-- {
@@ -453,6 +456,11 @@ func (d *RocksDB) DisconnectBlockRangeEthereumType(lower uint32, higher uint32)
 		wb.DeleteCF(d.cfh[cfHeight], key)
 	}
 	d.storeAddressContracts(wb, contracts)
+	if d.richList {
+		if err := d.disconnectTokenBalances(wb, lower, higher); err != nil {
+			return err
+		}
+	}
 	err := d.db.Write(d.wo, wb)
 	if err == nil {
 		d.is.RemoveLastBlockTimes(int(higher-lower) + 1)
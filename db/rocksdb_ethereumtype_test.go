@@ -4,10 +4,12 @@ package db
 
 import (
 	"encoding/hex"
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/juju/errors"
+	"github.com/trezor/blockbook/bchain"
 	"github.com/trezor/blockbook/bchain/coins/eth"
 	"github.com/trezor/blockbook/tests/dbtestdata"
 )
@@ -302,3 +304,182 @@ func TestRocksDB_Index_EthereumType(t *testing.T) {
 	}
 
 }
+
+func TestRocksDB_Verify_EthereumType(t *testing.T) {
+	d := setupRocksDB(t, &testEthereumParser{
+		EthereumParser: ethereumTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	for _, block := range []*bchain.Block{dbtestdata.GetTestEthereumTypeBlock1(d.chainParser), dbtestdata.GetTestEthereumTypeBlock2(d.chainParser)} {
+		if err := d.ConnectBlock(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report, err := d.Verify(false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, _ := report.IssuesCount(); issues != 0 {
+		t.Fatalf("Verify of consistent db: %+v", report.Issues)
+	}
+
+	// wrong counters of transactions and a contract without transactions
+	key, _ := hex.DecodeString(dbtestdata.AddressToPubKeyHex(dbtestdata.EthAddr20, d.chainParser))
+	val, _ := hex.DecodeString("0503" + dbtestdata.AddressToPubKeyHex(dbtestdata.EthAddrContract4a, d.chainParser) + "01" + dbtestdata.AddressToPubKeyHex(dbtestdata.EthAddrContract0d, d.chainParser) + "00")
+	if err = d.db.PutCF(d.wo, d.cfh[cfAddressContracts], key, val); err != nil {
+		t.Fatal(err)
+	}
+	// cached transaction which is not in the index and a valid cached transaction
+	block2 := dbtestdata.GetTestEthereumTypeBlock2(d.chainParser)
+	orphan := block2.Txs[0]
+	orphan.Txid = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	for _, tx := range []*bchain.Tx{&orphan, &block2.Txs[1]} {
+		if err = d.PutTx(tx, block2.Height, tx.Blocktime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report, err = d.Verify(true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, repaired := report.IssuesCount(); issues != 2 || repaired != 2 {
+		t.Errorf("Verify: %+v", report.Issues)
+	}
+	if tx, _, err := d.GetTx(orphan.Txid); err != nil || tx != nil {
+		t.Errorf("GetTx of removed cached transaction: %v, %v", tx, err)
+	}
+	if tx, _, err := d.GetTx(block2.Txs[1].Txid); err != nil || tx == nil {
+		t.Errorf("GetTx of valid cached transaction: %v, %v", tx, err)
+	}
+	if err = d.DeleteTx(block2.Txs[1].Txid); err != nil {
+		t.Fatal(err)
+	}
+	verifyAfterEthereumTypeBlock2(t, d)
+}
+
+func TestRocksDB_TokenHolders(t *testing.T) {
+	d := setupRocksDB(t, &testEthereumParser{
+		EthereumParser: ethereumTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	addrDesc := func(a string) bchain.AddressDescriptor {
+		b, _ := hex.DecodeString(a)
+		return b
+	}
+	holder := func(a, balance string) RichListEntry {
+		var b big.Int
+		b.SetString(balance, 10)
+		return RichListEntry{AddrDesc: addrDesc(a), BalanceSat: b}
+	}
+	checkHolders := func(contract string, want []RichListEntry) {
+		t.Helper()
+		got, err := d.GetTokenHolders(addrDesc(contract), 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(want) == 0 {
+			want = []RichListEntry{}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("GetTokenHolders(%v) = %+v, want %+v", contract, got, want)
+		}
+		var sum big.Int
+		for i := range want {
+			sum.Add(&sum, &want[i].BalanceSat)
+		}
+		count, total, err := d.GetTokenHoldersTotal(addrDesc(contract))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int(count) != len(want) || total.Cmp(&sum) != 0 {
+			t.Fatalf("GetTokenHoldersTotal(%v) = %v, %v, want %v, %v", contract, count, total, len(want), sum.String())
+		}
+	}
+
+	if err := d.InitRichList(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ConnectBlock(dbtestdata.GetTestEthereumTypeBlock1(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	// the minted tokens are not held by the sender, its balance would be negative
+	block1 := []RichListEntry{holder(dbtestdata.EthAddr55, "10000000000000000000000")}
+	checkHolders(dbtestdata.EthAddrContract4a, block1)
+	checkHolders(dbtestdata.EthAddrContract0d, nil)
+
+	if err := d.ConnectBlock(dbtestdata.GetTestEthereumTypeBlock2(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	checkHolders(dbtestdata.EthAddrContract4a, []RichListEntry{
+		holder(dbtestdata.EthAddr55, "10000000854307892726464"),
+		holder(dbtestdata.EthAddr4b, "16872108223720"),
+	})
+	checkHolders(dbtestdata.EthAddrContract0d, []RichListEntry{holder(dbtestdata.EthAddr7b, "7675000000000000000")})
+	if entries, err := d.GetTokenHolders(addrDesc(dbtestdata.EthAddrContract4a), 1, 1); err != nil || len(entries) != 1 || entries[0].BalanceSat.String() != "16872108223720" {
+		t.Fatalf("GetTokenHolders(4a, 1, 1) = %+v, %v", entries, err)
+	}
+	report, err := d.Verify(false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, _ := report.IssuesCount(); issues != 0 || len(report.Checks) != 8 {
+		t.Fatalf("Verify: checks %+v, issues %+v", report.Checks, report.Issues)
+	}
+
+	if err := d.DisconnectBlockRangeEthereumType(4321001, 4321001); err != nil {
+		t.Fatal(err)
+	}
+	checkHolders(dbtestdata.EthAddrContract4a, block1)
+	checkHolders(dbtestdata.EthAddrContract0d, nil)
+	// the parser keeps the data of one block, the undo row of block 1 was removed when block 2 was connected
+	if err := checkColumn(d, cfTokenBalancesUndo, []keyPair{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the token holders cannot be built from the existing index
+	if err := d.InitRichList(false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.InitRichList(true, nil); err == nil {
+		t.Fatal("InitRichList: expected error in an existing index")
+	}
+}
+
+func TestRocksDB_TokenHolders_BulkConnect(t *testing.T) {
+	d := setupRocksDB(t, &testEthereumParser{
+		EthereumParser: ethereumTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	if err := d.InitRichList(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	bc, err := d.InitBulkConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = bc.ConnectBlock(dbtestdata.GetTestEthereumTypeBlock1(d.chainParser), false); err != nil {
+		t.Fatal(err)
+	}
+	if err = bc.ConnectBlock(dbtestdata.GetTestEthereumTypeBlock2(d.chainParser), true); err != nil {
+		t.Fatal(err)
+	}
+	if err = bc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	contract, _ := hex.DecodeString(dbtestdata.EthAddrContract4a)
+	count, total, err := d.GetTokenHoldersTotal(contract)
+	if err != nil || count != 2 || total.String() != "10000000871180000950184" {
+		t.Fatalf("GetTokenHoldersTotal() = %v, %v, %v", count, total, err)
+	}
+	// the block stored with blockTxs can be disconnected
+	if err = d.DisconnectBlockRangeEthereumType(4321001, 4321001); err != nil {
+		t.Fatal(err)
+	}
+	count, total, err = d.GetTokenHoldersTotal(contract)
+	if err != nil || count != 1 || total.String() != "10000000000000000000000" {
+		t.Fatalf("GetTokenHoldersTotal() after disconnect = %v, %v, %v", count, total, err)
+	}
+}
@@ -3,11 +3,14 @@
 package db
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
 	"io/ioutil"
 	"math/big"
+	"math/bits"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
@@ -15,8 +18,11 @@ import (
 	"time"
 
 	vlq "github.com/bsm/go-vlq"
+	"github.com/dchest/siphash"
 	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
 	"github.com/martinboehm/btcutil/chaincfg"
+	"github.com/martinboehm/btcutil/txscript"
 	"github.com/trezor/blockbook/bchain"
 	"github.com/trezor/blockbook/bchain/coins/btc"
 	"github.com/trezor/blockbook/common"
@@ -94,6 +100,29 @@ func varuintToHex(i uint) string {
 	return hex.EncodeToString(b[:l])
 }
 
+func sumSat(values ...*big.Int) *big.Int {
+	s := new(big.Int)
+	for _, v := range values {
+		s.Add(s, v)
+	}
+	return s
+}
+
+func supplyStatsToHex(outputs, spent uint, issued, burned, utxoSet *big.Int) string {
+	return varuintToHex(outputs) + varuintToHex(spent) + bigintToHex(issued) + bigintToHex(burned) + bigintToHex(utxoSet)
+}
+
+func supplyStatsBlock1Hex() string {
+	issued := sumSat(dbtestdata.SatB1T1A1, dbtestdata.SatB1T1A2, dbtestdata.SatB1T1A2, dbtestdata.SatB1T2A3, dbtestdata.SatB1T2A4, dbtestdata.SatB1T2A5)
+	return supplyStatsToHex(6, 0, issued, dbtestdata.SatZero, issued)
+}
+
+func supplyStatsBlock2Hex() string {
+	// the outputs of the block 2 spent in the block 2 cancel out, OP_RETURN output with zero value is burned
+	issued := sumSat(dbtestdata.SatB1T1A1, dbtestdata.SatB1T1A2, dbtestdata.SatB2T1A7, dbtestdata.SatB2T2A8, dbtestdata.SatB2T2A9, dbtestdata.SatB2T3A5, dbtestdata.SatB2T4AA)
+	return supplyStatsToHex(13, 5, issued, dbtestdata.SatZero, issued)
+}
+
 func uintToHex(i uint32) string {
 	buf := make([]byte, 4)
 	binary.BigEndian.PutUint32(buf, i)
@@ -181,7 +210,7 @@ func verifyAfterBitcoinTypeBlock1(t *testing.T, d *RocksDB, afterDisconnect bool
 	if err := checkColumn(d, cfHeight, []keyPair{
 		{
 			"000370d5",
-			"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997" + uintToHex(1521515026) + varuintToHex(2) + varuintToHex(1234567),
+			"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997" + uintToHex(1521515026) + varuintToHex(2) + varuintToHex(1234567) + supplyStatsBlock1Hex(),
 			nil,
 		},
 	}); err != nil {
@@ -283,18 +312,26 @@ func verifyAfterBitcoinTypeBlock1(t *testing.T, d *RocksDB, afterDisconnect bool
 			t.Fatal(err)
 		}
 	}
+	// inputs of the transactions in the 1st block are unknown, fee stats are empty
+	if err := checkColumn(d, cfBlockFeeStats, []keyPair{
+		{"000370d5", hex.EncodeToString(packBlockFeeStats(&BlockFeeStats{})), nil},
+	}); err != nil {
+		{
+			t.Fatal(err)
+		}
+	}
 }
 
 func verifyAfterBitcoinTypeBlock2(t *testing.T, d *RocksDB) {
 	if err := checkColumn(d, cfHeight, []keyPair{
 		{
 			"000370d5",
-			"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997" + uintToHex(1521515026) + varuintToHex(2) + varuintToHex(1234567),
+			"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997" + uintToHex(1521515026) + varuintToHex(2) + varuintToHex(1234567) + supplyStatsBlock1Hex(),
 			nil,
 		},
 		{
 			"000370d6",
-			"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6" + uintToHex(1521595678) + varuintToHex(4) + varuintToHex(2345678),
+			"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6" + uintToHex(1521595678) + varuintToHex(4) + varuintToHex(2345678) + supplyStatsBlock2Hex(),
 			nil,
 		},
 	}); err != nil {
@@ -466,6 +503,26 @@ func verifyAfterBitcoinTypeBlock2(t *testing.T, d *RocksDB) {
 			t.Fatal(err)
 		}
 	}
+	if err := checkColumn(d, cfBlockFeeStats, []keyPair{
+		{"000370d5", hex.EncodeToString(packBlockFeeStats(&BlockFeeStats{})), nil},
+		{
+			"000370d6",
+			hex.EncodeToString(packBlockFeeStats(&BlockFeeStats{
+				TxCount:         3,
+				TotalFeesSat:    *big.NewInt(1284),
+				TotalVSize:      977,
+				AverageFeePerKb: 1398,
+				MinFeePerKb:     155,
+				MaxFeePerKb:     2361,
+				DecilesFeePerKb: [11]int64{155, 155, 155, 155, 1679, 1679, 1679, 2361, 2361, 2361, 2361},
+			})),
+			nil,
+		},
+	}); err != nil {
+		{
+			t.Fatal(err)
+		}
+	}
 }
 
 type txidIndex struct {
@@ -512,8 +569,9 @@ func testTxCache(t *testing.T, d *RocksDB, b *bchain.Block, tx *bchain.Tx) {
 	if b.Height != height {
 		t.Fatalf("GetTx: got height %v, expected %v", height, b.Height)
 	}
-	// Confirmations are not stored in the DB, set them from input tx
+	// Confirmations and VSize are not stored in the DB, set them from input tx
 	gtx.Confirmations = tx.Confirmations
+	gtx.VSize = tx.VSize
 	if !reflect.DeepEqual(gtx, tx) {
 		t.Errorf("GetTx: %v, want %v", gtx, tx)
 	}
@@ -627,11 +685,27 @@ func TestRocksDB_Index_BitcoinType(t *testing.T) {
 		Size:   2345678,
 		Time:   1521595678,
 		Height: 225494,
+		Supply: &SupplyStats{Outputs: 13, SpentOutputs: 5},
 	}
+	issued := sumSat(dbtestdata.SatB1T1A1, dbtestdata.SatB1T1A2, dbtestdata.SatB2T1A7, dbtestdata.SatB2T2A8, dbtestdata.SatB2T2A9, dbtestdata.SatB2T3A5, dbtestdata.SatB2T4AA)
+	iw.Supply.IssuedSat.Set(issued)
+	iw.Supply.UtxoSetSat.Set(issued)
 	if !reflect.DeepEqual(info, iw) {
 		t.Errorf("GetBlockInfo() = %+v, want %+v", info, iw)
 	}
 
+	// GetSupplyStatsRange
+	var heights []uint32
+	if err := d.GetSupplyStatsRange(0, 300000, 1, func(bi *BlockInfo) error {
+		heights = append(heights, bi.Height)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(heights, []uint32{225493, 225494}) {
+		t.Errorf("GetSupplyStatsRange() heights = %v, want [225493 225494]", heights)
+	}
+
 	// Test tx caching functionality, leave one tx in db to test cleanup in DisconnectBlock
 	testTxCache(t, d, block1, &block1.Txs[0])
 	testTxCache(t, d, block2, &block2.Txs[0])
@@ -655,6 +729,23 @@ func TestRocksDB_Index_BitcoinType(t *testing.T) {
 	}
 	verifyAfterBitcoinTypeBlock2(t, d)
 
+	// the transactions of the 2nd block are reported as disconnected together with their addresses
+	dtxs, err := d.GetBlockDisconnectedTxs(225494)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dtxids []string
+	for i := range dtxs {
+		dtxids = append(dtxids, dtxs[i].Txid)
+	}
+	if !reflect.DeepEqual(dtxids, []string{dbtestdata.TxidB2T1, dbtestdata.TxidB2T2, dbtestdata.TxidB2T3, dbtestdata.TxidB2T4}) {
+		t.Errorf("GetBlockDisconnectedTxs() txids = %v", dtxids)
+	}
+	addr5, _ := d.chainParser.GetAddrDescFromAddress(dbtestdata.Addr5)
+	if len(dtxs) != 4 || !reflect.DeepEqual(dtxs[2].AddrDescs[len(dtxs[2].AddrDescs)-1], addr5) {
+		t.Errorf("GetBlockDisconnectedTxs() = %+v", dtxs)
+	}
+
 	// disconnect the 2nd block, verify that the db contains only data from the 1st block with restored unspentTxs
 	// and that the cached tx is removed
 	err = d.DisconnectBlockRangeBitcoinType(225494, 225494)
@@ -704,7 +795,7 @@ func TestRocksDB_Index_BitcoinType(t *testing.T) {
 		t.Errorf("GetAddressBalance() = %+v, want %+v", ab, abw)
 	}
 	rs := ab.ReceivedSat()
-	rsw := dbtestdata.SatB1T2A5.Add(dbtestdata.SatB1T2A5, dbtestdata.SatB2T3A5)
+	rsw := new(big.Int).Add(dbtestdata.SatB1T2A5, dbtestdata.SatB2T3A5)
 	if rs.Cmp(rsw) != 0 {
 		t.Errorf("GetAddressBalance().ReceivedSat() = %v, want %v", rs, rsw)
 	}
@@ -901,6 +992,92 @@ func addressToAddrDesc(addr string, parser bchain.BlockChainParser) []byte {
 	return b
 }
 
+func Test_packBlockFeeStats_unpackBlockFeeStats(t *testing.T) {
+	tests := []BlockFeeStats{
+		{},
+		{
+			TxCount:         2871,
+			TotalFeesSat:    *big.NewInt(12345678901),
+			TotalVSize:      998123,
+			AverageFeePerKb: 21345,
+			MinFeePerKb:     1000,
+			MaxFeePerKb:     2100000,
+			DecilesFeePerKb: [11]int64{1000, 2001, 3512, 5001, 8123, 10120, 12000, 15400, 21000, 45000, 2100000},
+			SegwitTxCount:   2412,
+			TaprootTxCount:  312,
+		},
+	}
+	for i := range tests {
+		got, err := unpackBlockFeeStats(packBlockFeeStats(&tests[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*got, tests[i]) {
+			t.Errorf("unpackBlockFeeStats() = %+v, want %+v", *got, tests[i])
+		}
+	}
+	if _, err := unpackBlockFeeStats([]byte{1, 0}); err == nil {
+		t.Error("unpackBlockFeeStats() of invalid data, expected error")
+	}
+}
+
+func Test_packWatchGroup_unpackWatchGroup(t *testing.T) {
+	tests := []WatchGroup{
+		{ID: "0123456789abcdef0123456789abcdef"},
+		{
+			ID:          "0123456789abcdef0123456789abcdef",
+			Descriptors: []string{dbtestdata.Xpub, dbtestdata.Addr1, ""},
+			Created:     1600000000,
+			LastUsed:    1650000000,
+		},
+	}
+	for i := range tests {
+		got, err := unpackWatchGroup(tests[i].ID, packWatchGroup(&tests[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*got, tests[i]) {
+			t.Errorf("unpackWatchGroup() = %+v, want %+v", *got, tests[i])
+		}
+	}
+	for _, buf := range [][]byte{{}, {1, 2}, {1, 2, 1}, {1, 2, 1, 5, 'a'}} {
+		if _, err := unpackWatchGroup("id", buf); err == nil {
+			t.Errorf("unpackWatchGroup(%v) of invalid data, expected error", buf)
+		}
+	}
+}
+
+func TestComputeBlockFeeStats(t *testing.T) {
+	block := &bchain.Block{Txs: []bchain.Tx{{VSize: 100}, {VSize: 200}, {VSize: 250}}}
+	blockTxAddresses := []*TxAddresses{
+		// coinbase
+		{Inputs: []TxInput{{}}, Outputs: []TxOutput{{ValueSat: *big.NewInt(5000)}}},
+		// all inputs known, fee 2000
+		{
+			Inputs:  []TxInput{{ValueSat: *big.NewInt(10000)}, {ValueSat: *big.NewInt(3000)}},
+			Outputs: []TxOutput{{ValueSat: *big.NewInt(11000)}},
+		},
+		// the second input was not found, the fee cannot be computed
+		{
+			Inputs:  []TxInput{{ValueSat: *big.NewInt(10000)}, {}},
+			Outputs: []TxOutput{{ValueSat: *big.NewInt(9000)}},
+		},
+	}
+	got := ComputeBlockFeeStats(block, blockTxAddresses)
+	want := &BlockFeeStats{
+		TxCount:         1,
+		TotalFeesSat:    *big.NewInt(2000),
+		TotalVSize:      200,
+		AverageFeePerKb: 10000,
+		MinFeePerKb:     10000,
+		MaxFeePerKb:     10000,
+		DecilesFeePerKb: [11]int64{10000, 10000, 10000, 10000, 10000, 10000, 10000, 10000, 10000, 10000, 10000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeBlockFeeStats() = %+v, want %+v", got, want)
+	}
+}
+
 func Test_packTxAddresses_unpackTxAddresses(t *testing.T) {
 	parser := bitcoinTestnetParser()
 	tests := []struct {
@@ -1558,3 +1735,1375 @@ func TestRocksTickers(t *testing.T) {
 		t.Errorf("Ticker found, but the timestamp is older than the last ticker entry.")
 	}
 }
+
+func TestRocksDB_WatchGroups(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	group := &WatchGroup{
+		ID:          "0123456789abcdef0123456789abcdef",
+		Descriptors: []string{dbtestdata.Xpub, dbtestdata.Addr1},
+		Created:     1600000000,
+	}
+	if err := d.StoreWatchGroup(group); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.StoreWatchGroup(&WatchGroup{}); err == nil {
+		t.Error("StoreWatchGroup: expected error for group without id")
+	}
+	got, err := d.GetWatchGroup(group.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, group) {
+		t.Errorf("GetWatchGroup() = %+v, want %+v", got, group)
+	}
+	got, err = d.GetWatchGroup("fedcba9876543210fedcba9876543210")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("GetWatchGroup() = %+v, want nil", got)
+	}
+	if err = d.DeleteWatchGroup(group.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, err = d.GetWatchGroup(group.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("GetWatchGroup() after delete = %+v, want nil", got)
+	}
+
+	// the groups not used since the expiry time are purged
+	groups := []*WatchGroup{
+		{ID: "00", Descriptors: []string{dbtestdata.Addr1}, Created: 1600000000},
+		{ID: "01", Descriptors: []string{dbtestdata.Addr1}, Created: 1600000000, LastUsed: 1700000000},
+		{ID: "02", Descriptors: []string{dbtestdata.Addr1}, Created: 1700000000},
+	}
+	for _, g := range groups {
+		if err = d.StoreWatchGroup(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	remaining, purged, err := d.PurgeWatchGroups(1650000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 2 || purged != 1 {
+		t.Errorf("PurgeWatchGroups() = %d, %d, want 2, 1", remaining, purged)
+	}
+	for _, g := range groups {
+		got, err = d.GetWatchGroup(g.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (got == nil) != (g.ID == "00") {
+			t.Errorf("GetWatchGroup(%v) after purge = %+v", g.ID, got)
+		}
+	}
+}
+
+func TestRocksDB_Migrate(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	// fixture db with two blocks and their transactions in the txcache
+	block1 := dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)
+	for _, block := range []*bchain.Block{block1, block2} {
+		if err := d.ConnectBlock(block); err != nil {
+			t.Fatal(err)
+		}
+		for i := range block.Txs {
+			if err := d.PutTx(&block.Txs[i], block.Height, block.Time); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := d.StoreBlockFeeStats(block1.Height, &BlockFeeStats{TxCount: 1}); err != nil {
+		t.Fatal(err)
+	}
+	txs := len(block1.Txs) + len(block2.Txs)
+	d.is.SetDBColumnStats(cfTransactions, int64(txs), 0, 0)
+	setVersion := func(version uint32) {
+		for i := range d.is.DbColumns {
+			d.is.DbColumns[i].Version = version
+		}
+		if err := d.StoreInternalState(d.is); err != nil {
+			t.Fatal(err)
+		}
+	}
+	countRows := func(col int) int {
+		it := d.db.NewIteratorCF(d.ro, d.cfh[col])
+		defer it.Close()
+		n := 0
+		for it.SeekToFirst(); it.Valid(); it.Next() {
+			n++
+		}
+		return n
+	}
+
+	indexRows := make(map[int]int)
+	for _, col := range []int{cfHeight, cfAddresses, cfTxAddresses, cfAddressBalance, cfBlockTxs} {
+		indexRows[col] = countRows(col)
+	}
+
+	// there is no migration from the version 4
+	setVersion(4)
+	if _, err := d.LoadInternalState("coin-unittest"); err == nil {
+		t.Fatal("LoadInternalState: expected error for incompatible DB version")
+	}
+
+	setVersion(5)
+	is, err := d.LoadInternalState("coin-unittest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, needed := d.MigrationNeeded(is); !needed || version != 5 {
+		t.Fatalf("MigrationNeeded() = %v, %v, want 5, true", version, needed)
+	}
+
+	// interrupt the migration after the first batch
+	defer func(size int) { migrationBatchSize = size }(migrationBatchSize)
+	migrationBatchSize = 1
+	stop := make(chan os.Signal, 1)
+	stop <- os.Interrupt
+	if err = d.Migrate(is, stop); err != ErrOperationInterrupted {
+		t.Fatalf("Migrate() = %v, want ErrOperationInterrupted", err)
+	}
+	is, err = d.LoadInternalState("coin-unittest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is.Migration == nil || is.Migration.FromVersion != 5 || is.Migration.Column != "transactions" || is.Migration.Rows != 1 {
+		t.Fatalf("Migration = %+v", is.Migration)
+	}
+	if got := countRows(cfTransactions); got != txs-1 {
+		t.Errorf("transactions rows %v, want %v", got, txs-1)
+	}
+	if rows, _, _ := is.GetDBColumnStatValues(cfTransactions); rows != int64(txs-1) {
+		t.Errorf("transactions stats rows %v, want %v", rows, txs-1)
+	}
+	if _, needed := d.MigrationNeeded(is); !needed {
+		t.Fatal("MigrationNeeded() = false after interrupted migration")
+	}
+
+	// resume the migration
+	if err = d.Migrate(is, make(chan os.Signal, 1)); err != nil {
+		t.Fatal(err)
+	}
+	is, err = d.LoadInternalState("coin-unittest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, needed := d.MigrationNeeded(is); needed || is.Migration != nil {
+		t.Fatalf("MigrationNeeded() = true or Migration = %+v after finished migration", is.Migration)
+	}
+	for _, c := range is.DbColumns {
+		if c.Version != dbVersion {
+			t.Errorf("column %v version %v, want %v", c.Name, c.Version, dbVersion)
+		}
+	}
+	if got := countRows(cfTransactions); got != 0 {
+		t.Errorf("transactions rows %v, want 0", got)
+	}
+	if got := countRows(cfBlockFeeStats); got != 0 {
+		t.Errorf("blockFeeStats rows %v, want 0", got)
+	}
+	// the index data are not touched by the migration
+	for col, rows := range indexRows {
+		if got := countRows(col); got != rows {
+			t.Errorf("column %v rows %v, want %v", cfNames[col], got, rows)
+		}
+	}
+}
+
+func TestRocksDB_ComputeMissingSupplyStats(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	// simulate the index built by an older version, the block 1 is stored without the statistics
+	// and therefore the block 2 connected after it does not get them either
+	block1 := dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)
+	if err := d.ConnectBlock(block1); err != nil {
+		t.Fatal(err)
+	}
+	bi, err := d.GetBlockInfo(block1.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bi.Supply = nil
+	val, err := d.packBlockInfo(bi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.db.PutCF(d.wo, d.cfh[cfHeight], packUint(block1.Height), val); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	if bi, err = d.GetBlockInfo(225494); err != nil || bi.Supply != nil {
+		t.Fatalf("GetBlockInfo(225494) = %+v, %v, want block without supply stats", bi, err)
+	}
+
+	chain, err := dbtestdata.NewFakeBlockChain(d.chainParser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// interrupted computation does not store anything
+	stop := make(chan os.Signal, 1)
+	stop <- os.Interrupt
+	if err = d.ComputeMissingSupplyStats(chain, stop); err != ErrOperationInterrupted {
+		t.Fatalf("ComputeMissingSupplyStats() = %v, want ErrOperationInterrupted", err)
+	}
+	if height, found, err := d.firstBlockWithoutSupplyStats(); err != nil || !found || height != 225493 {
+		t.Fatalf("firstBlockWithoutSupplyStats() = %v, %v, %v, want 225493, true, nil", height, found, err)
+	}
+
+	if err = d.ComputeMissingSupplyStats(chain, make(chan os.Signal, 1)); err != nil {
+		t.Fatal(err)
+	}
+	verifyAfterBitcoinTypeBlock2(t, d)
+	if _, found, err := d.firstBlockWithoutSupplyStats(); err != nil || found {
+		t.Fatalf("firstBlockWithoutSupplyStats() = %v, %v, want false, nil", found, err)
+	}
+	// nothing to compute
+	if err = d.ComputeMissingSupplyStats(chain, make(chan os.Signal, 1)); err != nil {
+		t.Fatal(err)
+	}
+	verifyAfterBitcoinTypeBlock2(t, d)
+}
+
+func TestRocksDB_StaleBlocks(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	blocks := []StaleBlock{
+		{
+			BlockInfo: BlockInfo{
+				Hash:   "00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6",
+				Height: 225494,
+				Time:   1534859988,
+				Txs:    4,
+				Size:   2345678,
+			},
+			ForkHeight:       225493,
+			DisconnectedTime: 1600000000,
+		},
+		{
+			BlockInfo: BlockInfo{
+				Hash:   "0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997",
+				Height: 225493,
+				Time:   1534858022,
+				Txs:    2,
+				Size:   1234567,
+			},
+			ForkHeight:       225492,
+			DisconnectedTime: 1500000000,
+		},
+	}
+	if got, err := d.GetStaleBlocks(10); err != nil || len(got) != 0 {
+		t.Fatalf("GetStaleBlocks() = %v, %v, want empty", got, err)
+	}
+	// store the blocks in the reverse order to verify that they are returned sorted by height
+	if err := d.StoreStaleBlocks([]StaleBlock{blocks[1], blocks[0]}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.GetStaleBlocks(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, blocks) {
+		t.Errorf("GetStaleBlocks() = %+v, want %+v", got, blocks)
+	}
+	got, err = d.GetStaleBlocks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, blocks[:1]) {
+		t.Errorf("GetStaleBlocks(1) = %+v, want %+v", got, blocks[:1])
+	}
+}
+
+type hashMismatchChain struct {
+	bchain.BlockChain
+}
+
+func (c *hashMismatchChain) GetBlockHash(height uint32) (string, error) {
+	return "0000000000000000000000000000000000000000000000000000000000000000", nil
+}
+
+func TestRocksDB_Checkpoint(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	block1 := dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)
+	for _, block := range []*bchain.Block{block1, block2} {
+		if err := d.ConnectBlock(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.is.DbState = common.DbStateOpen
+	chain, err := dbtestdata.NewFakeBlockChain(d.chainParser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp, err := ioutil.TempDir("", "testcheckpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	d.is.InitialSync = true
+	if _, err = d.CreateCheckpoint(filepath.Join(tmp, "initial")); err == nil {
+		t.Fatal("CreateCheckpoint: expected error during initial sync")
+	}
+	d.is.InitialSync = false
+
+	cpDir := filepath.Join(tmp, "checkpoint")
+	info, err := d.CreateCheckpoint(cpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Coin != "coin-unittest" || info.Height != block2.Height || info.Hash != block2.Hash {
+		t.Fatalf("CreateCheckpoint() = %+v", info)
+	}
+	if _, err = d.CreateCheckpoint(cpDir); err == nil {
+		t.Fatal("CreateCheckpoint: expected error for existing directory")
+	}
+	// the checkpoint is not affected by the changes of the index
+	if err = d.DisconnectBlockRangeBitcoinType(block2.Height, block2.Height); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = RestoreCheckpoint(cpDir, filepath.Join(tmp, "index-coin"), "other-coin", chain); err == nil {
+		t.Error("RestoreCheckpoint: expected error for coin mismatch")
+	}
+	if _, err = RestoreCheckpoint(cpDir, filepath.Join(tmp, "index-hash"), "coin-unittest", &hashMismatchChain{chain}); err == nil {
+		t.Error("RestoreCheckpoint: expected error for block hash mismatch")
+	}
+	if _, err = RestoreCheckpoint(d.path, filepath.Join(tmp, "index-nocp"), "coin-unittest", chain); err == nil {
+		t.Error("RestoreCheckpoint: expected error for db which is not a checkpoint")
+	}
+	if _, err = RestoreCheckpoint(cpDir, tmp, "coin-unittest", chain); err == nil {
+		t.Error("RestoreCheckpoint: expected error for not empty index directory")
+	}
+
+	indexDir := filepath.Join(tmp, "index")
+	got, err := RestoreCheckpoint(cpDir, indexDir, "coin-unittest", chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, info) {
+		t.Errorf("RestoreCheckpoint() = %+v, want %+v", got, info)
+	}
+	r, err := NewRocksDB(indexDir, 100000, -1, d.chainParser, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	is, err := r.LoadInternalState("coin-unittest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is.DbState != common.DbStateClosed || is.BestHeight != block2.Height {
+		t.Errorf("restored internal state DbState %v, BestHeight %v", is.DbState, is.BestHeight)
+	}
+	r.SetInternalState(is)
+	verifyAfterBitcoinTypeBlock2(t, r)
+}
+
+func TestRocksDB_Replica(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	block1 := dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)
+	if err := d.ConnectBlock(block1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.StoreInternalState(d.is); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewRocksDBReadOnly(d.path, "", 100000, -1, d.chainParser, nil); err == nil {
+		t.Error("NewRocksDBReadOnly without secondary path: expected error")
+	}
+	r, err := NewRocksDBReadOnly(d.path, d.path+"-replica", 100000, -1, d.chainParser, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(d.path + "-replica")
+	defer r.Close()
+	is, err := r.LoadInternalState("coin-unittest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetInternalState(is)
+	w, err := NewReplicaWorker(r, is)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewReplicaWorker(d, d.is); err == nil {
+		t.Error("NewReplicaWorker of the primary db: expected error")
+	}
+	if err = r.StoreInternalState(is); err == nil {
+		t.Error("StoreInternalState of the replica: expected error")
+	}
+
+	var newBlocks []uint32
+	var reorgs []*bchain.ChainReorg
+	onNewBlock := func(hash string, height uint32) {
+		newBlocks = append(newBlocks, height)
+	}
+	onReorg := func(reorg *bchain.ChainReorg) {
+		reorgs = append(reorgs, reorg)
+	}
+
+	// new block connected by the primary
+	if err = d.ConnectBlock(block2); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.StoreInternalState(d.is); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.CatchUp(onNewBlock, onReorg); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(newBlocks, []uint32{225494}) || len(reorgs) != 0 {
+		t.Fatalf("CatchUp: new blocks %v, reorgs %v", newBlocks, reorgs)
+	}
+	if is.BestHeight != 225494 || is.GetBlockTime(225494) != uint32(block2.Time) {
+		t.Errorf("CatchUp: best height %v, block time %v", is.BestHeight, is.GetBlockTime(225494))
+	}
+
+	// block disconnected by the primary
+	newBlocks = nil
+	if err = d.DisconnectBlockRangeBitcoinType(225494, 225494); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.CatchUp(onNewBlock, onReorg); err != nil {
+		t.Fatal(err)
+	}
+	if len(newBlocks) != 0 || len(reorgs) != 1 {
+		t.Fatalf("CatchUp: new blocks %v, reorgs %v", newBlocks, reorgs)
+	}
+	want := &bchain.ChainReorg{ForkHeight: 225493, DisconnectedHashes: []string{block2.Hash}}
+	if !reflect.DeepEqual(reorgs[0], want) {
+		t.Errorf("CatchUp: reorg %+v, want %+v", reorgs[0], want)
+	}
+	if is.BestHeight != 225493 || is.GetBlockTime(225494) != 0 {
+		t.Errorf("CatchUp: best height %v, block time %v", is.BestHeight, is.GetBlockTime(225494))
+	}
+}
+
+func TestRocksDB_Verify(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	if err := d.InitBlockFilters(true); err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range []*bchain.Block{dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser), dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)} {
+		if err := d.ConnectBlock(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stop := make(chan os.Signal, 1)
+	report, err := d.Verify(false, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, _ := report.IssuesCount(); issues != 0 || !report.Consistent() {
+		t.Fatalf("Verify of consistent db: %+v", report.Issues)
+	}
+
+	// corrupt the index
+	put := func(col int, keyHex, valHex string) {
+		key, _ := hex.DecodeString(keyHex)
+		val, _ := hex.DecodeString(valHex)
+		if err := d.db.PutCF(d.wo, d.cfh[col], key, val); err != nil {
+			t.Fatal(err)
+		}
+	}
+	del := func(col int, keyHex string) {
+		key, _ := hex.DecodeString(keyHex)
+		if err := d.db.DeleteCF(d.wo, d.cfh[col], key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// blockTxs of a block which is not in the index
+	put(cfBlockTxs, "000370d7", dbtestdata.TxidB2T1+"00")
+	// output spent in block 2 not marked as spent
+	ta, err := d.GetTxAddresses(dbtestdata.TxidB1T2)
+	if err != nil || ta == nil {
+		t.Fatal(err)
+	}
+	ta.Outputs[0].Spent = false
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	btxID, _ := d.chainParser.PackTxid(dbtestdata.TxidB1T2)
+	if err = d.storeTxAddresses(wb, map[string]*TxAddresses{string(btxID): ta}); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.db.Write(d.wo, wb); err != nil {
+		t.Fatal(err)
+	}
+	// missing entry in addresses
+	del(cfAddresses, addressKeyHex(dbtestdata.Addr8, 225494, d))
+	// wrong balance
+	put(cfAddressBalance, dbtestdata.AddressToPubKeyHex(dbtestdata.Addr9, d.chainParser), "05"+bigintToHex(dbtestdata.SatZero)+bigintToHex(dbtestdata.SatB2T2A9))
+	// missing balance
+	del(cfAddressBalance, dbtestdata.AddressToPubKeyHex(dbtestdata.Addr7, d.chainParser))
+
+	checkReport := func(report *VerifyReport, repair bool) {
+		want := map[string]int64{
+			"height":      0,
+			"blockTxs":    2,
+			"txAddresses": 1,
+			"addresses":   0,
+			// without repair, the balances of the addresses affected by the spent flag and the missing entry differ as well
+			"addressBalance": 4,
+			"transactions":   0,
+			"blockFeeStats":  0,
+			"blockFilters":   0,
+			"outputScripts":  0,
+		}
+		if repair {
+			want["addressBalance"] = 2
+		}
+		if len(report.Checks) != len(want) {
+			t.Fatalf("Verify: checks %+v", report.Checks)
+		}
+		for _, c := range report.Checks {
+			repaired := int64(0)
+			if repair {
+				repaired = want[c.Name]
+			}
+			if c.Issues != want[c.Name] || c.Repaired != repaired {
+				t.Errorf("Verify: check %v, issues %d, repaired %d, want %d", c.Name, c.Issues, c.Repaired, want[c.Name])
+			}
+		}
+		if report.Consistent() != repair {
+			t.Errorf("Verify: Consistent() %v, issues %+v", report.Consistent(), report.Issues)
+		}
+	}
+	report, err = d.Verify(false, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReport(report, false)
+	report, err = d.Verify(true, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReport(report, true)
+	verifyAfterBitcoinTypeBlock2(t, d)
+	report, err = d.Verify(false, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, _ := report.IssuesCount(); issues != 0 {
+		t.Errorf("Verify of repaired db: %+v", report.Issues)
+	}
+
+	// interrupted verification
+	stop <- os.Interrupt
+	if report, err = d.Verify(false, stop); err != ErrOperationInterrupted || !report.Interrupted {
+		t.Errorf("Verify: expected interruption, got %v", err)
+	}
+}
+
+func TestRocksDB_VerifyDerivedColumns(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	if err := d.InitBlockFilters(true); err != nil {
+		t.Fatal(err)
+	}
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)
+	for _, block := range []*bchain.Block{dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser), block2} {
+		if err := d.ConnectBlock(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.InitRichList(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.InitOpReturnIndex(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.InitScriptHashIndex(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.PutTx(&block2.Txs[0], block2.Height, block2.Txs[0].Blocktime); err != nil {
+		t.Fatal(err)
+	}
+	stop := make(chan os.Signal, 1)
+	report, err := d.Verify(false, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, _ := report.IssuesCount(); issues != 0 || len(report.Checks) != 12 {
+		t.Fatalf("Verify of consistent db: checks %+v, issues %+v", report.Checks, report.Issues)
+	}
+
+	put := func(col int, key, val []byte) {
+		if err := d.db.PutCF(d.wo, d.cfh[col], key, val); err != nil {
+			t.Fatal(err)
+		}
+	}
+	firstKey := func(col int) []byte {
+		it := d.db.NewIteratorCF(d.ro, d.cfh[col])
+		defer it.Close()
+		it.SeekToFirst()
+		if !it.Valid() {
+			t.Fatalf("column %v is empty", cfNames[col])
+		}
+		return append([]byte(nil), it.Key().Data()...)
+	}
+	// transaction cached at a height lower than the height of its block
+	if err = d.PutTx(&block2.Txs[1], block2.Height-1, block2.Txs[1].Blocktime); err != nil {
+		t.Fatal(err)
+	}
+	// confirmed transaction cached from a disconnected block
+	orphan := block2.Txs[2]
+	orphan.Txid = "0000000000000000000000000000000000000000000000000000000000000001"
+	if err = d.PutTx(&orphan, block2.Height, orphan.Blocktime); err != nil {
+		t.Fatal(err)
+	}
+	// fee stats and filter of a block which is not in the index
+	put(cfBlockFeeStats, packUint(block2.Height+1), []byte{0})
+	put(cfBlockFilters, packUint(block2.Height+1), []byte{0})
+	// output script which is stored in txAddresses
+	btxID, _ := d.chainParser.PackTxid(block2.Txs[0].Txid)
+	put(cfOutputScripts, packOutputScriptKey(btxID, 0), outputScript(&block2.Txs[0].Vout[0]))
+	// missing entry of the rich list, the totals do not match as well
+	if err = d.db.DeleteCF(d.wo, d.cfh[cfRichList], firstKey(cfRichList)); err != nil {
+		t.Fatal(err)
+	}
+	// missing OP_RETURN output
+	if err = d.db.DeleteCF(d.wo, d.cfh[cfOpReturns], firstKey(cfOpReturns)); err != nil {
+		t.Fatal(err)
+	}
+	// script hash which does not match the address
+	addrDesc, _ := hex.DecodeString(dbtestdata.AddressToPubKeyHex(dbtestdata.Addr1, d.chainParser))
+	put(cfScriptHashes, make([]byte, ScriptHashLen), addrDesc)
+
+	want := map[string]int64{
+		"height":         0,
+		"blockTxs":       0,
+		"txAddresses":    0,
+		"addresses":      0,
+		"addressBalance": 0,
+		"transactions":   2,
+		"blockFeeStats":  1,
+		"blockFilters":   1,
+		"outputScripts":  1,
+		"richList":       2,
+		"opReturns":      1,
+		"scriptHashes":   1,
+	}
+	for _, repair := range []bool{false, true} {
+		report, err = d.Verify(repair, stop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Checks) != len(want) {
+			t.Fatalf("Verify: checks %+v", report.Checks)
+		}
+		for _, c := range report.Checks {
+			repaired := int64(0)
+			if repair {
+				repaired = want[c.Name]
+			}
+			if c.Issues != want[c.Name] || c.Repaired != repaired {
+				t.Errorf("Verify(%v): check %v, issues %d, repaired %d, want %d", repair, c.Name, c.Issues, c.Repaired, want[c.Name])
+			}
+		}
+		if report.Consistent() != repair {
+			t.Errorf("Verify(%v): Consistent() %v, issues %+v", repair, report.Consistent(), report.Issues)
+		}
+	}
+	report, err = d.Verify(false, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, _ := report.IssuesCount(); issues != 0 {
+		t.Errorf("Verify of repaired db: %+v", report.Issues)
+	}
+	if tx, _, err := d.GetTx(block2.Txs[0].Txid); err != nil || tx == nil {
+		t.Errorf("GetTx of valid cached transaction: %v, %v", tx, err)
+	}
+}
+
+func TestRocksDB_RichList(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	// richList returns the rich list and checks it against the balances in the addressBalance column
+	richList := func() []RichListEntry {
+		t.Helper()
+		entries, err := d.GetRichList(0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want []RichListEntry
+		it := d.db.NewIteratorCF(d.ro, d.cfh[cfAddressBalance])
+		defer it.Close()
+		for it.SeekToFirst(); it.Valid(); it.Next() {
+			ab, err := unpackAddrBalance(it.Value().Data(), d.chainParser.PackedTxidLen(), AddressBalanceDetailNoUTXO)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ab.BalanceSat.Sign() > 0 {
+				want = append(want, RichListEntry{AddrDesc: append(bchain.AddressDescriptor(nil), it.Key().Data()...), BalanceSat: ab.BalanceSat})
+			}
+		}
+		sort.Slice(want, func(i, j int) bool {
+			if c := want[i].BalanceSat.Cmp(&want[j].BalanceSat); c != 0 {
+				return c > 0
+			}
+			return bytes.Compare(want[i].AddrDesc, want[j].AddrDesc) < 0
+		})
+		if !reflect.DeepEqual(entries, want) {
+			t.Fatalf("GetRichList() = %+v, want %+v", entries, want)
+		}
+		count, total, err := d.GetRichListTotal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sum big.Int
+		for i := range want {
+			sum.Add(&sum, &want[i].BalanceSat)
+		}
+		if int(count) != len(want) || total.Cmp(&sum) != 0 {
+			t.Fatalf("GetRichListTotal() = %v, %v, want %v, %v", count, total, len(want), sum.String())
+		}
+		return entries
+	}
+
+	if err := d.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.GetRichList(0, 100); err == nil {
+		t.Fatal("GetRichList: expected error when the rich list is not enabled")
+	}
+	// build the rich list from the balances
+	if err := d.InitRichList(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	block1 := richList()
+	if len(block1) != 5 {
+		t.Fatalf("rich list after block 1 has %d addresses, want 5", len(block1))
+	}
+
+	// maintain the rich list with the balances
+	if err := d.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	block2 := richList()
+	if block2[0].BalanceSat.String() != dbtestdata.SatB2T1A7.String() {
+		t.Fatalf("top address balance %v, want %v", block2[0].BalanceSat.String(), dbtestdata.SatB2T1A7.String())
+	}
+	if entries, err := d.GetRichList(1, 2); err != nil || !reflect.DeepEqual(entries, block2[1:3]) {
+		t.Fatalf("GetRichList(1, 2) = %+v, %v, want %+v", entries, err, block2[1:3])
+	}
+
+	if err := d.DisconnectBlockRangeBitcoinType(225494, 225494); err != nil {
+		t.Fatal(err)
+	}
+	if entries := richList(); !reflect.DeepEqual(entries, block1) {
+		t.Fatalf("rich list after disconnect = %+v, want %+v", entries, block1)
+	}
+
+	// disabled rich list is not maintained and is rebuilt when enabled again
+	if err := d.InitRichList(false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.InitRichList(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if entries := richList(); !reflect.DeepEqual(entries, block2) {
+		t.Fatalf("rebuilt rich list = %+v, want %+v", entries, block2)
+	}
+}
+
+func Test_ComputeBlockFilter(t *testing.T) {
+	// test vectors from BIP158, testnet genesis block
+	script, _ := hex.DecodeString("4104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac")
+	// the filter contains the pay-to-pubkey script, not its address descriptor
+	block := &bchain.Block{
+		BlockHeader: bchain.BlockHeader{Hash: "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943"},
+		Txs: []bchain.Tx{{
+			Vin:  []bchain.Vin{{Coinbase: "04ffff001d0104"}},
+			Vout: []bchain.Vout{{ValueSat: *big.NewInt(5000000000), ScriptPubKey: bchain.ScriptPubKey{Hex: hex.EncodeToString(script)}}},
+		}},
+	}
+	f, err := ComputeBlockFilter(block, nil, make([]byte, BlockFilterHeaderLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(f.Filter); got != "019dfca8" {
+		t.Errorf("ComputeBlockFilter() filter = %v, want 019dfca8", got)
+	}
+	header, _ := blockHashToBytes("21584579b7eb08997773e5aeff3a7f932700042d0ed2a6129012b7d7ae81b750")
+	if !bytes.Equal(f.Header, header) {
+		t.Errorf("ComputeBlockFilter() header = %x, want %x", f.Header, header)
+	}
+	// unknown previous header
+	f, err = ComputeBlockFilter(block, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Header != nil {
+		t.Errorf("ComputeBlockFilter() header = %x, want nil", f.Header)
+	}
+	// empty filter
+	block.Txs = nil
+	f, err = ComputeBlockFilter(block, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(f.Filter); got != "00" {
+		t.Errorf("ComputeBlockFilter() filter = %v, want 00", got)
+	}
+}
+
+// blockFilterMatch decodes the Golomb-coded set of the filter and checks if it contains the element
+func blockFilterMatch(t *testing.T, blockHash string, filter []byte, element []byte) bool {
+	t.Helper()
+	key, err := blockHashToBytes(blockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := uint64(filter[0])
+	if n >= 0xfd {
+		t.Fatal("blockFilterMatch supports only filters with less than 253 elements")
+	}
+	value, _ := bits.Mul64(siphash.Hash(binary.LittleEndian.Uint64(key[0:8]), binary.LittleEndian.Uint64(key[8:16]), element), n*blockFilterM)
+	pos := uint(8)
+	bit := func() uint64 {
+		b := uint64(filter[pos/8]>>(7-pos%8)) & 1
+		pos++
+		return b
+	}
+	var last uint64
+	for i := uint64(0); i < n; i++ {
+		var q uint64
+		for bit() == 1 {
+			q++
+		}
+		r := uint64(0)
+		for j := 0; j < blockFilterP; j++ {
+			r = r<<1 | bit()
+		}
+		last += q<<blockFilterP | r
+		if last == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRocksDB_BlockFilters(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	if err := d.InitBlockFilters(true); err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsBlockFiltersEnabled() {
+		t.Fatal("IsBlockFiltersEnabled() = false, want true")
+	}
+	// the filter of the block preceding the first test block, to start the chain of the filter headers
+	prevHeader, _ := hex.DecodeString("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20")
+	wb := grocksdb.NewWriteBatch()
+	d.storeBlockFilter(wb, 225492, &BlockFilter{Filter: []byte{0}, Header: prevHeader})
+	if err := d.db.Write(d.wo, wb); err != nil {
+		t.Fatal(err)
+	}
+	wb.Destroy()
+
+	block1 := dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)
+	if err := d.ConnectBlock(block1); err != nil {
+		t.Fatal(err)
+	}
+	block2 := dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)
+	if err := d.ConnectBlock(block2); err != nil {
+		t.Fatal(err)
+	}
+
+	addrDesc := func(addr string) []byte {
+		b, _ := hex.DecodeString(dbtestdata.AddressToPubKeyHex(addr, d.chainParser))
+		return b
+	}
+	tests := []struct {
+		block      *bchain.Block
+		elements   int
+		contains   []string
+		notContain []string
+	}{
+		{
+			block:      block1,
+			elements:   5,
+			contains:   []string{dbtestdata.Addr1, dbtestdata.Addr2, dbtestdata.Addr3, dbtestdata.Addr4, dbtestdata.Addr5},
+			notContain: []string{dbtestdata.Addr6, dbtestdata.AddrA},
+		},
+		{
+			// the outputs and the spent outputs, OP_RETURN and empty output scripts are not included
+			block:      block2,
+			elements:   9,
+			contains:   []string{dbtestdata.Addr2, dbtestdata.Addr3, dbtestdata.Addr4, dbtestdata.Addr5, dbtestdata.Addr6, dbtestdata.Addr7, dbtestdata.Addr8, dbtestdata.Addr9, dbtestdata.AddrA},
+			notContain: []string{dbtestdata.Addr1},
+		},
+	}
+	for _, tt := range tests {
+		f, err := d.GetBlockFilter(tt.block.Height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f == nil {
+			t.Fatalf("GetBlockFilter(%d) returned nil", tt.block.Height)
+		}
+		if int(f.Filter[0]) != tt.elements {
+			t.Errorf("GetBlockFilter(%d) elements = %d, want %d", tt.block.Height, f.Filter[0], tt.elements)
+		}
+		for _, a := range tt.contains {
+			if !blockFilterMatch(t, tt.block.Hash, f.Filter, addrDesc(a)) {
+				t.Errorf("GetBlockFilter(%d) does not match %s", tt.block.Height, a)
+			}
+		}
+		for _, a := range tt.notContain {
+			if blockFilterMatch(t, tt.block.Hash, f.Filter, addrDesc(a)) {
+				t.Errorf("GetBlockFilter(%d) matches %s", tt.block.Height, a)
+			}
+		}
+		wantHeader := doubleSha256(append(f.FilterHash(), prevHeader...))
+		if !bytes.Equal(f.Header, wantHeader) {
+			t.Errorf("GetBlockFilter(%d) header = %x, want %x", tt.block.Height, f.Header, wantHeader)
+		}
+		prevHeader = f.Header
+	}
+
+	var heights []uint32
+	if err := d.GetBlockFilterRange(225493, 225494, func(height uint32, f *BlockFilter) error {
+		heights = append(heights, height)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(heights, []uint32{225493, 225494}) {
+		t.Errorf("GetBlockFilterRange() heights = %v, want [225493 225494]", heights)
+	}
+
+	// the filter is removed with the disconnected block
+	if err := d.DisconnectBlockRangeBitcoinType(225494, 225494); err != nil {
+		t.Fatal(err)
+	}
+	f, err := d.GetBlockFilter(225494)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != nil {
+		t.Errorf("GetBlockFilter(225494) = %+v, want nil", f)
+	}
+
+	// the filters are not computed if they are not enabled
+	if err := d.InitBlockFilters(false); err != nil {
+		t.Fatal(err)
+	}
+	if d.IsBlockFiltersEnabled() {
+		t.Error("IsBlockFiltersEnabled() = true, want false")
+	}
+	if err := d.ConnectBlock(block2); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = d.GetBlockFilter(225494); err != nil || f != nil {
+		t.Errorf("GetBlockFilter(225494) = %+v, %v, want nil", f, err)
+	}
+	if err := d.ComputeMissingBlockFilters(&blocksChain{}, make(chan os.Signal, 1)); err == nil {
+		t.Error("ComputeMissingBlockFilters() expected error, the block filters are not enabled")
+	}
+}
+
+// blocksChain returns the blocks from the map
+type blocksChain struct {
+	bchain.BlockChain
+	blocks map[uint32]*bchain.Block
+}
+
+func (c *blocksChain) GetBlock(hash string, height uint32) (*bchain.Block, error) {
+	if b, found := c.blocks[height]; found && b.Hash == hash {
+		return b, nil
+	}
+	return nil, bchain.ErrBlockNotFound
+}
+
+func TestRocksDB_OutputScripts(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	if err := d.InitBlockFilters(true); err != nil {
+		t.Fatal(err)
+	}
+	p2pk, _ := hex.DecodeString("4104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac")
+	long := bytes.Repeat([]byte{txscript.OP_TRUE}, maxAddrDescLen+1)
+	script := func(addr string) []byte {
+		b, _ := hex.DecodeString(dbtestdata.AddressToPubKeyHex(addr, d.chainParser))
+		return b
+	}
+	vout := func(n uint32, script []byte) bchain.Vout {
+		return bchain.Vout{N: n, ValueSat: *big.NewInt(1000), ScriptPubKey: bchain.ScriptPubKey{Hex: hex.EncodeToString(script)}}
+	}
+	const (
+		txidA = "00000000000000000000000000000000000000000000000000000000000000a0"
+		txidB = "00000000000000000000000000000000000000000000000000000000000000b0"
+		txidC = "00000000000000000000000000000000000000000000000000000000000000c0"
+		txidD = "00000000000000000000000000000000000000000000000000000000000000d0"
+	)
+	// the pay-to-pubkey output of the block 0 is spent in the same block, the long script output in the block 1
+	block0 := &bchain.Block{
+		BlockHeader: bchain.BlockHeader{Hash: "0000000000000000000000000000000000000000000000000000000000000f00", Height: 0, Time: 1521515026},
+		Txs: []bchain.Tx{
+			{
+				Txid: txidA,
+				Vin:  []bchain.Vin{{Coinbase: "01"}},
+				Vout: []bchain.Vout{vout(0, p2pk), vout(1, long), vout(2, script(dbtestdata.Addr1))},
+			},
+			{
+				Txid: txidB,
+				Vin:  []bchain.Vin{{Txid: txidA, Vout: 0}},
+				Vout: []bchain.Vout{vout(0, script(dbtestdata.Addr2))},
+			},
+		},
+	}
+	block1 := &bchain.Block{
+		BlockHeader: bchain.BlockHeader{Hash: "0000000000000000000000000000000000000000000000000000000000000f01", Height: 1, Time: 1521515027},
+		Txs: []bchain.Tx{
+			{
+				Txid: txidC,
+				Vin:  []bchain.Vin{{Coinbase: "02"}},
+				Vout: []bchain.Vout{vout(0, p2pk)},
+			},
+			{
+				Txid: txidD,
+				Vin:  []bchain.Vin{{Txid: txidA, Vout: 1}},
+				Vout: []bchain.Vout{vout(0, script(dbtestdata.Addr4))},
+			},
+		},
+	}
+	for _, block := range []*bchain.Block{block0, block1} {
+		if err := d.ConnectBlock(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outputScriptKey := func(txid string, vout int32) string {
+		btxID, _ := d.chainParser.PackTxid(txid)
+		return hex.EncodeToString(packOutputScriptKey(btxID, vout))
+	}
+	if err := checkColumn(d, cfOutputScripts, []keyPair{
+		{outputScriptKey(txidA, 0), hex.EncodeToString(p2pk), nil},
+		{outputScriptKey(txidA, 1), hex.EncodeToString(long), nil},
+		{outputScriptKey(txidC, 0), hex.EncodeToString(p2pk), nil},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	p2pkAddrDesc, _ := d.chainParser.GetAddrDescFromVout(&block0.Txs[0].Vout[0])
+	tests := []struct {
+		block      *bchain.Block
+		elements   int
+		contains   [][]byte
+		notContain [][]byte
+	}{
+		{
+			block:      block0,
+			elements:   4,
+			contains:   [][]byte{p2pk, long, script(dbtestdata.Addr1), script(dbtestdata.Addr2)},
+			notContain: [][]byte{p2pkAddrDesc},
+		},
+		{
+			block:      block1,
+			elements:   3,
+			contains:   [][]byte{p2pk, long, script(dbtestdata.Addr4)},
+			notContain: [][]byte{script(dbtestdata.Addr1)},
+		},
+	}
+	filters := make([]*BlockFilter, len(tests))
+	prevHeader := make([]byte, BlockFilterHeaderLen)
+	for i, tt := range tests {
+		f, err := d.GetBlockFilter(tt.block.Height)
+		if err != nil || f == nil {
+			t.Fatalf("GetBlockFilter(%d) = %v, %v", tt.block.Height, f, err)
+		}
+		if int(f.Filter[0]) != tt.elements {
+			t.Errorf("GetBlockFilter(%d) elements = %d, want %d", tt.block.Height, f.Filter[0], tt.elements)
+		}
+		for _, e := range tt.contains {
+			if !blockFilterMatch(t, tt.block.Hash, f.Filter, e) {
+				t.Errorf("GetBlockFilter(%d) does not match %x", tt.block.Height, e)
+			}
+		}
+		for _, e := range tt.notContain {
+			if blockFilterMatch(t, tt.block.Hash, f.Filter, e) {
+				t.Errorf("GetBlockFilter(%d) matches %x", tt.block.Height, e)
+			}
+		}
+		if wantHeader := doubleSha256(append(f.FilterHash(), prevHeader...)); !bytes.Equal(f.Header, wantHeader) {
+			t.Errorf("GetBlockFilter(%d) header = %x, want %x", tt.block.Height, f.Header, wantHeader)
+		}
+		prevHeader = f.Header
+		filters[i] = f
+	}
+
+	// the index built without the filters and the output scripts
+	for _, col := range []int{cfBlockFilters, cfOutputScripts} {
+		it := d.db.NewIteratorCF(d.ro, d.cfh[col])
+		for it.SeekToFirst(); it.Valid(); it.Next() {
+			if err := d.db.DeleteCF(d.wo, d.cfh[col], it.Key().Data()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		it.Close()
+	}
+	chain := &blocksChain{blocks: map[uint32]*bchain.Block{0: block0, 1: block1}}
+	stop := make(chan os.Signal, 1)
+	stop <- os.Interrupt
+	if err := d.ComputeMissingBlockFilters(chain, stop); err != ErrOperationInterrupted {
+		t.Fatalf("ComputeMissingBlockFilters() = %v, want ErrOperationInterrupted", err)
+	}
+	if height, found, err := d.firstBlockWithoutFilterHeader(); err != nil || !found || height != 0 {
+		t.Fatalf("firstBlockWithoutFilterHeader() = %v, %v, %v, want 0, true, nil", height, found, err)
+	}
+	if err := d.ComputeMissingBlockFilters(chain, make(chan os.Signal, 1)); err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range tests {
+		f, err := d.GetBlockFilter(tt.block.Height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(f, filters[i]) {
+			t.Errorf("ComputeMissingBlockFilters() filter %d = %+v, want %+v", tt.block.Height, f, filters[i])
+		}
+	}
+
+	// the output scripts of the disconnected block are removed
+	if err := d.DisconnectBlockRangeBitcoinType(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkColumn(d, cfOutputScripts, []keyPair{
+		{outputScriptKey(txidA, 0), hex.EncodeToString(p2pk), nil},
+		{outputScriptKey(txidA, 1), hex.EncodeToString(long), nil},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_opReturnPayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{name: "not OP_RETURN", script: "76a914010d39800f86122416e28f485029acf77507169288ac", want: ""},
+		{name: "empty OP_RETURN", script: "6a", want: ""},
+		{name: "push", script: "6a072020f1686f6a20", want: "2020f1686f6a20"},
+		{name: "OP_PUSHDATA1", script: "6a4c03010203", want: "010203"},
+		{name: "OP_PUSHDATA2", script: "6a4d0300010203", want: "010203"},
+		{name: "multiple pushes", script: "6a0201020103", want: "010203"},
+		{name: "invalid push length", script: "6a05010203", want: "05010203"},
+		{name: "not push opcode", script: "6a0102ac", want: "0102ac"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, _ := hex.DecodeString(tt.script)
+			if got := hex.EncodeToString(opReturnPayload(script)); got != tt.want {
+				t.Errorf("opReturnPayload() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRocksDB_OpReturnIndex(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	if err := d.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.GetOpReturns([]byte{0x20}, 0, 10, 100); err == nil {
+		t.Fatal("GetOpReturns() expected error, the index is not enabled")
+	}
+	if err := d.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	want := []OpReturnEntry{{
+		Payload: []byte{0x20, 0x20, 0xf1, 0x68, 0x6f, 0x6a, 0x20},
+		Txid:    dbtestdata.TxidB2T1,
+		Vout:    2,
+		Height:  225494,
+	}}
+	check := func(prefix []byte, offset int, want []OpReturnEntry, wantTotal int) {
+		t.Helper()
+		got, total, err := d.GetOpReturns(prefix, offset, 10, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) || total != wantTotal {
+			t.Errorf("GetOpReturns(%x, %d) = %+v, %d, want %+v, %d", prefix, offset, got, total, want, wantTotal)
+		}
+	}
+
+	// the index is built from the already connected blocks
+	if err := d.InitOpReturnIndex(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	check([]byte{0x20, 0x20, 0xf1}, 0, want, 1)
+	check(want[0].Payload, 0, want, 1)
+	check([]byte{0x20, 0x20, 0xf1}, 1, []OpReturnEntry{}, 1)
+	check([]byte{0x21}, 0, []OpReturnEntry{}, 0)
+	// the prefix longer than the payload must not match the txid
+	btxID, _ := d.chainParser.PackTxid(dbtestdata.TxidB2T1)
+	check(append(append([]byte{}, want[0].Payload...), btxID[:2]...), 0, []OpReturnEntry{}, 0)
+
+	// the index is updated when the block is disconnected and connected again
+	if err := d.DisconnectBlockRangeBitcoinType(225494, 225494); err != nil {
+		t.Fatal(err)
+	}
+	check([]byte{0x20}, 0, []OpReturnEntry{}, 0)
+	if err := d.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	check([]byte{0x20}, 0, want, 1)
+
+	// the OP_RETURN script longer than maxAddrDescLen is stored in txAddresses without the address descriptor
+	payload := bytes.Repeat([]byte{0x30}, maxAddrDescLen+100)
+	script := append([]byte{txscript.OP_RETURN, txscript.OP_PUSHDATA2, 0, 0}, payload...)
+	binary.LittleEndian.PutUint16(script[2:], uint16(len(payload)))
+	block3 := &bchain.Block{
+		BlockHeader: bchain.BlockHeader{Hash: "00000000000000000000000000000000000000000000000000000000000370d7", Height: 225495, Time: 1521595679},
+		Txs: []bchain.Tx{{
+			Txid: "00000000000000000000000000000000000000000000000000000000000000a0",
+			Vin:  []bchain.Vin{{Coinbase: "01"}},
+			Vout: []bchain.Vout{{ScriptPubKey: bchain.ScriptPubKey{Hex: hex.EncodeToString(script)}}},
+		}},
+	}
+	if err := d.ConnectBlock(block3); err != nil {
+		t.Fatal(err)
+	}
+	wantLong := []OpReturnEntry{{
+		Payload: payload[:maxOpReturnPayload],
+		Txid:    block3.Txs[0].Txid,
+		Vout:    0,
+		Height:  225495,
+	}}
+	check([]byte{0x30}, 0, wantLong, 1)
+	// the rebuilt index contains the long script as well
+	if err := d.InitOpReturnIndex(false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.InitOpReturnIndex(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	check([]byte{0x30}, 0, wantLong, 1)
+	check([]byte{0x20}, 0, want, 1)
+	report, err := d.Verify(false, make(chan os.Signal, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, _ := report.IssuesCount(); issues != 0 {
+		t.Errorf("Verify() issues %+v", report.Issues)
+	}
+	if err := d.DisconnectBlockRangeBitcoinType(225495, 225495); err != nil {
+		t.Fatal(err)
+	}
+	check([]byte{0x30}, 0, []OpReturnEntry{}, 0)
+
+	if err := d.InitOpReturnIndex(false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if d.IsOpReturnIndexEnabled() {
+		t.Error("IsOpReturnIndexEnabled() = true, want false")
+	}
+}
+
+func TestRocksDB_ScriptHashIndex(t *testing.T) {
+	d := setupRocksDB(t, &testBitcoinParser{
+		BitcoinParser: bitcoinTestnetParser(),
+	})
+	defer closeAndDestroyRocksDB(t, d)
+
+	if err := d.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock1(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	addr1 := addressToAddrDesc(dbtestdata.Addr1, d.chainParser)
+	addr6 := addressToAddrDesc(dbtestdata.Addr6, d.chainParser)
+	if _, err := d.GetAddrDescForScriptHash(ScriptHash(addr1)); err == nil {
+		t.Fatal("GetAddrDescForScriptHash() expected error, the index is not enabled")
+	}
+	check := func(addrDesc bchain.AddressDescriptor, want bchain.AddressDescriptor) {
+		t.Helper()
+		got, err := d.GetAddrDescForScriptHash(ScriptHash(addrDesc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("GetAddrDescForScriptHash(%s) = %s, want %s", addrDesc, got, want)
+		}
+	}
+
+	// the index is built from the balances of the first block and updated by the second block
+	if err := d.InitScriptHashIndex(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	check(addr1, addr1)
+	check(addr6, nil)
+	if err := d.ConnectBlock(dbtestdata.GetTestBitcoinTypeBlock2(d.chainParser)); err != nil {
+		t.Fatal(err)
+	}
+	check(addr1, addr1)
+	check(addr6, addr6)
+	if _, err := d.GetAddrDescForScriptHash([]byte{1, 2, 3}); err == nil {
+		t.Error("GetAddrDescForScriptHash() expected error for invalid script hash")
+	}
+
+	if err := d.InitScriptHashIndex(false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if d.IsScriptHashIndexEnabled() {
+		t.Error("IsScriptHashIndexEnabled() = true, want false")
+	}
+}
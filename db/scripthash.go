@@ -0,0 +1,151 @@
+package db
+
+import (
+	"crypto/sha256"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// ScriptHashLen is the length of the script hash, the SHA256 hash of the address descriptor
+const ScriptHashLen = sha256.Size
+
+var scriptHashBatchSize = 10000
+
+// ScriptHash returns the SHA256 hash of the address descriptor (output script), the key of the scriptHashes column
+func ScriptHash(addrDesc bchain.AddressDescriptor) []byte {
+	h := sha256.Sum256(addrDesc)
+	return h[:]
+}
+
+// IsScriptHashIndexEnabled returns true if the index of script hashes is built and maintained
+func (d *RocksDB) IsScriptHashIndexEnabled() bool {
+	return d.is != nil && d.is.ScriptHashIndex
+}
+
+// GetAddrDescForScriptHash returns the address descriptor with given script hash or nil if it is not in the index
+func (d *RocksDB) GetAddrDescForScriptHash(scriptHash []byte) (bchain.AddressDescriptor, error) {
+	if !d.IsScriptHashIndexEnabled() {
+		return nil, errors.New("Script hash index is not enabled")
+	}
+	if len(scriptHash) != ScriptHashLen {
+		return nil, errors.New("Invalid script hash")
+	}
+	val, err := d.db.GetCF(d.ro, d.cfh[cfScriptHashes], scriptHash)
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	return append(bchain.AddressDescriptor(nil), buf...), nil
+}
+
+// storeScriptHashes stores the script hashes of the addresses with changed balance,
+// the rows of the addresses removed on disconnect are kept, they point to an address without transactions
+func (d *RocksDB) storeScriptHashes(wb *grocksdb.WriteBatch, abm map[string]*AddrBalance) {
+	for addrDesc, ab := range abm {
+		if ab != nil && ab.Txs > 0 && len(addrDesc) > 0 {
+			wb.PutCF(d.cfh[cfScriptHashes], ScriptHash(bchain.AddressDescriptor(addrDesc)), []byte(addrDesc))
+		}
+	}
+}
+
+// InitScriptHashIndex switches the index of script hashes on or off. If the index is enabled and was not maintained
+// until now, it is built from the addressBalance column. The build can be interrupted by a signal and is restarted on next run.
+// If the index is disabled, its rows are kept but are no longer valid and are rebuilt when the index is enabled again.
+func (d *RocksDB) InitScriptHashIndex(enabled bool, stop chan os.Signal) error {
+	if d.is == nil {
+		return errors.New("Internal state not set")
+	}
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		if enabled {
+			return errors.New("Script hash index is supported only for Bitcoin type coins")
+		}
+		return nil
+	}
+	if !enabled {
+		d.scriptHashIndex = false
+		if d.is.ScriptHashIndex {
+			glog.Info("script hash index: disabled, it will be rebuilt when enabled again")
+			d.is.ScriptHashIndex = false
+			return d.storeState(d.is)
+		}
+		return nil
+	}
+	if !d.is.ScriptHashIndex {
+		if err := d.buildScriptHashIndex(stop); err != nil {
+			return err
+		}
+		d.is.ScriptHashIndex = true
+		if err := d.storeState(d.is); err != nil {
+			return err
+		}
+	}
+	d.scriptHashIndex = true
+	return nil
+}
+
+// buildScriptHashIndex replaces the index of script hashes by the addresses from the addressBalance column
+func (d *RocksDB) buildScriptHashIndex(stop chan os.Signal) error {
+	start := time.Now()
+	glog.Info("script hash index: building from the addressBalance column")
+	// do not use cache
+	ro := grocksdb.NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetFillCache(false)
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	write := func() error {
+		if err := d.db.Write(d.wo, wb); err != nil {
+			return err
+		}
+		wb.Clear()
+		select {
+		case <-stop:
+			return ErrOperationInterrupted
+		default:
+		}
+		return nil
+	}
+	// remove the rows left from the previous build
+	it := d.db.NewIteratorCF(ro, d.cfh[cfScriptHashes])
+	defer it.Close()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		wb.DeleteCF(d.cfh[cfScriptHashes], it.Key().Data())
+		if wb.Count() >= scriptHashBatchSize {
+			if err := write(); err != nil {
+				return err
+			}
+		}
+	}
+	var rows uint
+	itb := d.db.NewIteratorCF(ro, d.cfh[cfAddressBalance])
+	defer itb.Close()
+	for itb.SeekToFirst(); itb.Valid(); itb.Next() {
+		rows++
+		addrDesc := itb.Key().Data()
+		if len(addrDesc) > 0 {
+			wb.PutCF(d.cfh[cfScriptHashes], ScriptHash(addrDesc), addrDesc)
+		}
+		if wb.Count() >= scriptHashBatchSize {
+			if err := write(); err != nil {
+				return err
+			}
+		}
+		if rows%1000000 == 0 {
+			glog.Info("script hash index: processed ", rows, " addresses")
+		}
+	}
+	if err := d.db.Write(d.wo, wb); err != nil {
+		return err
+	}
+	glog.Info("script hash index: built from ", rows, " addresses, done in ", time.Since(start))
+	return nil
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	vlq "github.com/bsm/go-vlq"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// StaleBlock is a block disconnected from the index by a chain reorganization, stored in the staleBlocks column
+type StaleBlock struct {
+	BlockInfo
+	ForkHeight       uint32
+	DisconnectedTime int64
+}
+
+func (d *RocksDB) packStaleBlockKey(height uint32, hash string) ([]byte, error) {
+	bhash, err := d.chainParser.PackBlockHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return append(packUint(height), bhash...), nil
+}
+
+func packStaleBlock(b *StaleBlock) []byte {
+	buf := make([]byte, 0, 5*vlq.MaxLen64)
+	varBuf := make([]byte, vlq.MaxLen64)
+	l := packVarint(int(b.Time), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(b.Txs), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(b.Size), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(b.ForkHeight), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVarint(int(b.DisconnectedTime), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	return buf
+}
+
+func (d *RocksDB) unpackStaleBlock(key, buf []byte) (*StaleBlock, error) {
+	if len(key) <= packedHeightBytes || len(buf) == 0 {
+		return nil, errors.New("Invalid stale block")
+	}
+	hash, err := d.chainParser.UnpackBlockHash(key[packedHeightBytes:])
+	if err != nil {
+		return nil, err
+	}
+	b := &StaleBlock{}
+	b.Height = unpackUint(key)
+	b.Hash = hash
+	t, l := unpackVarint(buf)
+	b.Time = int64(t)
+	values := make([]uint, 3)
+	for i := range values {
+		if l >= len(buf) {
+			return nil, errors.New("Invalid stale block")
+		}
+		v, ll := unpackVaruint(buf[l:])
+		values[i] = v
+		l += ll
+	}
+	b.Txs, b.Size, b.ForkHeight = uint32(values[0]), uint32(values[1]), uint32(values[2])
+	if l >= len(buf) {
+		return nil, errors.New("Invalid stale block")
+	}
+	t, _ = unpackVarint(buf[l:])
+	b.DisconnectedTime = int64(t)
+	return b, nil
+}
+
+// StoreStaleBlocks stores the blocks disconnected by a chain reorganization
+func (d *RocksDB) StoreStaleBlocks(blocks []StaleBlock) error {
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	for i := range blocks {
+		key, err := d.packStaleBlockKey(blocks[i].Height, blocks[i].Hash)
+		if err != nil {
+			return errors.Annotatef(err, "block %v", blocks[i].Hash)
+		}
+		wb.PutCF(d.cfh[cfStaleBlocks], key, packStaleBlock(&blocks[i]))
+	}
+	return d.db.Write(d.wo, wb)
+}
+
+// GetStaleBlocks returns at most limit stored stale blocks, in descending order of height
+func (d *RocksDB) GetStaleBlocks(limit int) ([]StaleBlock, error) {
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfStaleBlocks])
+	defer it.Close()
+	blocks := make([]StaleBlock, 0)
+	for it.SeekToLast(); it.Valid() && len(blocks) < limit; it.Prev() {
+		b, err := d.unpackStaleBlock(it.Key().Data(), it.Value().Data())
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, *b)
+	}
+	return blocks, nil
+}
+
+// GetBlockDisconnectedTxs returns the transactions of the block at given height together with the address descriptors
+// of their inputs and outputs, the data are available only for the blocks which can be disconnected
+func (d *RocksDB) GetBlockDisconnectedTxs(height uint32) ([]bchain.DisconnectedTx, error) {
+	var r []bchain.DisconnectedTx
+	addAddrDesc := func(dtx *bchain.DisconnectedTx, seen map[string]struct{}, addrDesc bchain.AddressDescriptor) {
+		if len(addrDesc) == 0 {
+			return
+		}
+		if _, found := seen[string(addrDesc)]; !found {
+			seen[string(addrDesc)] = struct{}{}
+			dtx.AddrDescs = append(dtx.AddrDescs, addrDesc)
+		}
+	}
+	if d.chainParser.GetChainType() == bchain.ChainBitcoinType {
+		blockTxs, err := d.getBlockTxs(height)
+		if err != nil {
+			return nil, err
+		}
+		r = make([]bchain.DisconnectedTx, len(blockTxs))
+		for i := range blockTxs {
+			dtx := &r[i]
+			if dtx.Txid, err = d.chainParser.UnpackTxid(blockTxs[i].btxID); err != nil {
+				return nil, err
+			}
+			ta, err := d.getTxAddresses(blockTxs[i].btxID)
+			if err != nil {
+				return nil, err
+			}
+			if ta == nil {
+				continue
+			}
+			seen := make(map[string]struct{})
+			for j := range ta.Inputs {
+				addAddrDesc(dtx, seen, ta.Inputs[j].AddrDesc)
+			}
+			for j := range ta.Outputs {
+				addAddrDesc(dtx, seen, ta.Outputs[j].AddrDesc)
+			}
+		}
+	} else if d.chainParser.GetChainType() == bchain.ChainEthereumType {
+		blockTxs, err := d.getBlockTxsEthereumType(height)
+		if err != nil {
+			return nil, err
+		}
+		r = make([]bchain.DisconnectedTx, len(blockTxs))
+		for i := range blockTxs {
+			dtx := &r[i]
+			if dtx.Txid, err = d.chainParser.UnpackTxid(blockTxs[i].btxID); err != nil {
+				return nil, err
+			}
+			seen := make(map[string]struct{})
+			addAddrDesc(dtx, seen, blockTxs[i].from)
+			addAddrDesc(dtx, seen, blockTxs[i].to)
+			for j := range blockTxs[i].contracts {
+				addAddrDesc(dtx, seen, blockTxs[i].contracts[j].addr)
+			}
+		}
+	}
+	return r, nil
+}
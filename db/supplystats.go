@@ -0,0 +1,254 @@
+package db
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+)
+
+// SupplyStats contains the cumulative statistics of the coin supply and of the UTXO set after a block,
+// they are stored together with BlockInfo in the height column (Bitcoin type only)
+// the statistics are accumulated from the first block of the index, they are exact only for the index built from the genesis block
+type SupplyStats struct {
+	// IssuedSat is the value of the outputs of all transactions minus the value of their inputs, i.e. the coinbase outputs minus the fees
+	IssuedSat big.Int
+	// BurnedSat is the value of the unspendable (OP_RETURN) outputs
+	BurnedSat big.Int
+	// UtxoSetSat is the value of the unspent outputs
+	UtxoSetSat big.Int
+	// Outputs is the number of created spendable outputs
+	Outputs uint64
+	// SpentOutputs is the number of spent outputs
+	SpentOutputs uint64
+}
+
+// Utxos returns the number of unspent outputs
+func (s *SupplyStats) Utxos() uint64 {
+	return s.Outputs - s.SpentOutputs
+}
+
+// ComputeSupplyStats adds the issuance, burned value and created and spent outputs of the block to the statistics
+// of the previous block, the block transactions and their TxAddresses must be in the same order
+func (d *RocksDB) ComputeSupplyStats(prev *SupplyStats, block *bchain.Block, blockTxAddresses []*TxAddresses) *SupplyStats {
+	s := &SupplyStats{
+		Outputs:      prev.Outputs,
+		SpentOutputs: prev.SpentOutputs,
+	}
+	s.IssuedSat.Set(&prev.IssuedSat)
+	s.BurnedSat.Set(&prev.BurnedSat)
+	s.UtxoSetSat.Set(&prev.UtxoSetSat)
+	for i := range block.Txs {
+		tx := &block.Txs[i]
+		ta := blockTxAddresses[i]
+		if ta == nil {
+			continue
+		}
+		for j := range ta.Outputs {
+			tao := &ta.Outputs[j]
+			s.IssuedSat.Add(&s.IssuedSat, &tao.ValueSat)
+			if len(tao.AddrDesc) > 0 && !d.chainParser.IsAddrDescIndexable(tao.AddrDesc) {
+				s.BurnedSat.Add(&s.BurnedSat, &tao.ValueSat)
+			} else {
+				s.UtxoSetSat.Add(&s.UtxoSetSat, &tao.ValueSat)
+				s.Outputs++
+			}
+		}
+		for j := range ta.Inputs {
+			// coinbase input does not spend any output
+			if j < len(tx.Vin) && tx.Vin[j].Txid == "" {
+				continue
+			}
+			tai := &ta.Inputs[j]
+			s.IssuedSat.Sub(&s.IssuedSat, &tai.ValueSat)
+			s.UtxoSetSat.Sub(&s.UtxoSetSat, &tai.ValueSat)
+			s.SpentOutputs++
+		}
+	}
+	return s
+}
+
+// getPrevSupplyStats returns the supply statistics of the block preceding the block at given height,
+// empty statistics if the index does not contain the preceding block or nil if the preceding block has no statistics
+func (d *RocksDB) getPrevSupplyStats(height uint32) (*SupplyStats, error) {
+	if height == 0 {
+		return &SupplyStats{}, nil
+	}
+	bi, err := d.GetBlockInfo(height - 1)
+	if err != nil {
+		return nil, err
+	}
+	if bi == nil {
+		return &SupplyStats{}, nil
+	}
+	return bi.Supply, nil
+}
+
+// supplyStatsBatchSize is the number of blocks with computed supply statistics written in one write batch
+const supplyStatsBatchSize = 1000
+
+// firstBlockWithoutSupplyStats returns the height of the first indexed block without supply statistics
+// and false if all indexed blocks have them
+func (d *RocksDB) firstBlockWithoutSupplyStats() (uint32, bool, error) {
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfHeight])
+	defer it.Close()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		bi, err := d.unpackBlockInfo(it.Value().Data())
+		if err != nil {
+			return 0, false, err
+		}
+		if bi != nil && bi.Supply == nil {
+			return unpackUint(it.Key().Data()), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// ComputeMissingSupplyStats computes the supply statistics of the blocks indexed without them (by older versions of Blockbook)
+// from the block transactions of the backend and their TxAddresses and stores them to the height column.
+// The statistics are accumulated from the first block without them, therefore the computation can be interrupted
+// and is resumed by the next call. The blocks connected after the last block with the statistics do not get them
+// until the computation reaches the best block.
+func (d *RocksDB) ComputeMissingSupplyStats(chain bchain.BlockChain, stop chan os.Signal) error {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return errors.New("Supply stats are supported only for Bitcoin type")
+	}
+	from, found, err := d.firstBlockWithoutSupplyStats()
+	if err != nil || !found {
+		return err
+	}
+	bestHeight, _, err := d.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	supply, err := d.getPrevSupplyStats(from)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	glog.Info("supply stats: computing blocks ", from, "-", bestHeight)
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	for height := from; height <= bestHeight; height++ {
+		select {
+		case <-stop:
+			if err = d.db.Write(d.wo, wb); err != nil {
+				return err
+			}
+			glog.Info("supply stats: interrupted at height ", height)
+			return ErrOperationInterrupted
+		default:
+		}
+		bi, err := d.GetBlockInfo(height)
+		if err != nil {
+			return err
+		}
+		if bi == nil {
+			return errors.Errorf("Block %v is missing in the index", height)
+		}
+		block, err := chain.GetBlock(bi.Hash, height)
+		if err != nil {
+			return errors.Annotatef(err, "GetBlock %v %v", height, bi.Hash)
+		}
+		blockTxAddresses := make([]*TxAddresses, len(block.Txs))
+		for i := range block.Txs {
+			if blockTxAddresses[i], err = d.GetTxAddresses(block.Txs[i].Txid); err != nil {
+				return err
+			}
+		}
+		supply = d.ComputeSupplyStats(supply, block, blockTxAddresses)
+		bi.Supply = supply
+		val, err := d.packBlockInfo(bi)
+		if err != nil {
+			return err
+		}
+		wb.PutCF(d.cfh[cfHeight], packUint(height), val)
+		if wb.Count() >= supplyStatsBatchSize || height == bestHeight {
+			if err = d.db.Write(d.wo, wb); err != nil {
+				return err
+			}
+			wb.Clear()
+			glog.Info("supply stats: computed up to block ", height)
+		}
+	}
+	glog.Info("supply stats: computed blocks ", from, "-", bestHeight, " in ", time.Since(start))
+	return nil
+}
+
+func packSupplyStats(s *SupplyStats, buf []byte) []byte {
+	varBuf := make([]byte, maxPackedBigintBytes)
+	l := packVaruint(uint(s.Outputs), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(s.SpentOutputs), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	for _, v := range []*big.Int{&s.IssuedSat, &s.BurnedSat, &s.UtxoSetSat} {
+		l = packBigint(v, varBuf)
+		buf = append(buf, varBuf[:l]...)
+	}
+	return buf
+}
+
+func unpackSupplyStats(buf []byte) (*SupplyStats, error) {
+	s := &SupplyStats{}
+	outputs, l := unpackVaruint(buf)
+	s.Outputs = uint64(outputs)
+	if l >= len(buf) {
+		return nil, errors.New("Invalid supply stats")
+	}
+	spent, ll := unpackVaruint(buf[l:])
+	s.SpentOutputs = uint64(spent)
+	l += ll
+	for _, v := range []*big.Int{&s.IssuedSat, &s.BurnedSat, &s.UtxoSetSat} {
+		if l >= len(buf) {
+			return nil, errors.New("Invalid supply stats")
+		}
+		var bi big.Int
+		bi, ll = unpackBigint(buf[l:])
+		v.Set(&bi)
+		l += ll
+	}
+	return s, nil
+}
+
+// GetSupplyStatsRange calls fn for all blocks with supply statistics in the height range from-to (inclusive)
+// with the given step, in ascending order of height
+func (d *RocksDB) GetSupplyStatsRange(from, to, step uint32, fn func(bi *BlockInfo) error) error {
+	if d.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return nil
+	}
+	if step == 0 {
+		step = 1
+	}
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfHeight])
+	defer it.Close()
+	for height := from; height <= to; {
+		it.Seek(packUint(height))
+		if !it.Valid() {
+			break
+		}
+		height = unpackUint(it.Key().Data())
+		if height > to {
+			break
+		}
+		bi, err := d.unpackBlockInfo(it.Value().Data())
+		if err != nil {
+			return errors.Annotatef(err, "height %d", height)
+		}
+		if bi != nil && bi.Supply != nil {
+			bi.Height = height
+			if err = fn(bi); err != nil {
+				return err
+			}
+		}
+		// do not overflow at the maximum height
+		if height+step < height {
+			break
+		}
+		height += step
+	}
+	return nil
+}
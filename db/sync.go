@@ -76,11 +76,12 @@ func (w *SyncWorker) updateBackendInfo() {
 
 // ResyncIndex synchronizes index to the top of the blockchain
 // onNewBlock is called when new block is connected, but not in initial parallel sync
-func (w *SyncWorker) ResyncIndex(onNewBlock bchain.OnNewBlockFunc, initialSync bool) error {
+// onReorg is called when blocks are disconnected because of a chain reorganization
+func (w *SyncWorker) ResyncIndex(onNewBlock bchain.OnNewBlockFunc, onReorg bchain.OnReorgFunc, initialSync bool) error {
 	start := time.Now()
 	w.is.StartedSync()
 
-	err := w.resyncIndex(onNewBlock, initialSync)
+	err := w.resyncIndex(onNewBlock, onReorg, initialSync)
 
 	// update backend info after each resync
 	w.updateBackendInfo()
@@ -114,7 +115,7 @@ func (w *SyncWorker) ResyncIndex(onNewBlock bchain.OnNewBlockFunc, initialSync b
 	return err
 }
 
-func (w *SyncWorker) resyncIndex(onNewBlock bchain.OnNewBlockFunc, initialSync bool) error {
+func (w *SyncWorker) resyncIndex(onNewBlock bchain.OnNewBlockFunc, onReorg bchain.OnReorgFunc, initialSync bool) error {
 	remoteBestHash, err := w.chain.GetBestBlockHash()
 	if err != nil {
 		return err
@@ -137,7 +138,7 @@ func (w *SyncWorker) resyncIndex(onNewBlock bchain.OnNewBlockFunc, initialSync b
 		if remoteHash != localBestHash {
 			// forked - the remote hash differs from the local hash at the same height
 			glog.Info("resync: local is forked at height ", localBestHeight, ", local hash ", localBestHash, ", remote hash ", remoteHash)
-			return w.handleFork(localBestHeight, localBestHash, onNewBlock, initialSync)
+			return w.handleFork(localBestHeight, localBestHash, onNewBlock, onReorg, initialSync)
 		}
 		glog.Info("resync: local at ", localBestHeight, " is behind")
 		w.startHeight = localBestHeight + 1
@@ -169,17 +170,17 @@ func (w *SyncWorker) resyncIndex(onNewBlock bchain.OnNewBlockFunc, initialSync b
 			}
 			// after parallel load finish the sync using standard way,
 			// new blocks may have been created in the meantime
-			return w.resyncIndex(onNewBlock, initialSync)
+			return w.resyncIndex(onNewBlock, onReorg, initialSync)
 		}
 	}
 	err = w.connectBlocks(onNewBlock, initialSync)
 	if err == errFork {
-		return w.resyncIndex(onNewBlock, initialSync)
+		return w.resyncIndex(onNewBlock, onReorg, initialSync)
 	}
 	return err
 }
 
-func (w *SyncWorker) handleFork(localBestHeight uint32, localBestHash string, onNewBlock bchain.OnNewBlockFunc, initialSync bool) error {
+func (w *SyncWorker) handleFork(localBestHeight uint32, localBestHash string, onNewBlock bchain.OnNewBlockFunc, onReorg bchain.OnReorgFunc, initialSync bool) error {
 	// find forked blocks, disconnect them and then synchronize again
 	var height uint32
 	hashes := []string{localBestHash}
@@ -201,10 +202,50 @@ func (w *SyncWorker) handleFork(localBestHeight uint32, localBestHash string, on
 		}
 		hashes = append(hashes, local)
 	}
+	// the data about the disconnected blocks must be read before they are removed from the index
+	reorg, staleBlocks, err := w.getReorgData(height, localBestHeight, hashes)
+	if err != nil {
+		return err
+	}
 	if err := w.DisconnectBlocks(height+1, localBestHeight, hashes); err != nil {
 		return err
 	}
-	return w.resyncIndex(onNewBlock, initialSync)
+	if err := w.db.StoreStaleBlocks(staleBlocks); err != nil {
+		glog.Error("sync: StoreStaleBlocks error ", err)
+	}
+	if onReorg != nil {
+		onReorg(reorg)
+	}
+	return w.resyncIndex(onNewBlock, onReorg, initialSync)
+}
+
+// getReorgData returns the reorg notification and the stale blocks for the blocks above forkHeight up to bestHeight
+func (w *SyncWorker) getReorgData(forkHeight, bestHeight uint32, hashes []string) (*bchain.ChainReorg, []StaleBlock, error) {
+	now := time.Now().Unix()
+	reorg := &bchain.ChainReorg{
+		ForkHeight:         forkHeight,
+		DisconnectedHashes: hashes,
+	}
+	staleBlocks := make([]StaleBlock, 0, len(hashes))
+	for height := bestHeight; height > forkHeight; height-- {
+		bi, err := w.db.GetBlockInfo(height)
+		if err != nil {
+			return nil, nil, err
+		}
+		if bi != nil {
+			staleBlocks = append(staleBlocks, StaleBlock{
+				BlockInfo:        *bi,
+				ForkHeight:       forkHeight,
+				DisconnectedTime: now,
+			})
+		}
+		txs, err := w.db.GetBlockDisconnectedTxs(height)
+		if err != nil {
+			return nil, nil, err
+		}
+		reorg.Txs = append(reorg.Txs, txs...)
+	}
+	return reorg, staleBlocks, nil
 }
 
 func (w *SyncWorker) connectBlocks(onNewBlock bchain.OnNewBlockFunc, initialSync bool) error {
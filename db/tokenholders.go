@@ -0,0 +1,302 @@
+package db
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/bchain/coins/eth"
+)
+
+// The token holders of Ethereum type coins are maintained together with the rich list. The balances of the holders
+// are computed from the ERC20 transfers of the connected blocks, the index cannot be built from the other columns.
+// The tokenBalances column contains the balances keyed by contract+holder and the rows with the number of holders
+// and the sum of their balances keyed by the contract. The tokenHolders column contains the holders of each contract
+// ordered by the balance, keyed by contract+rich list key. The tokenBalancesUndo column contains the previous
+// balances changed by the block, keyed by the height, for the blocks which can be disconnected.
+
+const tokenBalanceKeyLen = 2 * eth.EthereumTypeAddressDescriptorLen
+
+type tokenHoldersTotal struct {
+	count uint
+	total *big.Int
+}
+
+func packTokenHolderKey(contract, holder bchain.AddressDescriptor, balance *big.Int) []byte {
+	return append(append([]byte(nil), contract...), packRichListKey(holder, balance)...)
+}
+
+// GetTokenHoldersTotal returns the number of the holders of the contract and the sum of their balances
+func (d *RocksDB) GetTokenHoldersTotal(contract bchain.AddressDescriptor) (uint, *big.Int, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfTokenBalances], contract)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return 0, new(big.Int), nil
+	}
+	count, l := unpackVaruint(buf)
+	total, _ := unpackBigint(buf[l:])
+	return count, &total, nil
+}
+
+// GetTokenHolders returns at most count holders of the contract with the highest balance, skipping the first offset holders
+func (d *RocksDB) GetTokenHolders(contract bchain.AddressDescriptor, offset, count int) ([]RichListEntry, error) {
+	if !d.IsTokenHoldersEnabled() {
+		return nil, errors.New("Token holders are not enabled")
+	}
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfTokenHolders])
+	defer it.Close()
+	entries := make([]RichListEntry, 0, count)
+	for it.Seek(contract); it.Valid() && len(entries) < count; it.Next() {
+		key := it.Key().Data()
+		if !bytes.HasPrefix(key, contract) {
+			break
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		addrDesc, balance, err := unpackRichListKey(key[len(contract):])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, RichListEntry{AddrDesc: addrDesc, BalanceSat: *balance})
+	}
+	return entries, nil
+}
+
+// GetTokenBalance returns the balance of the token holder computed from the transfers of the contract
+func (d *RocksDB) GetTokenBalance(contract, holder bchain.AddressDescriptor) (*big.Int, error) {
+	return d.getTokenBalance(append(append([]byte(nil), contract...), holder...))
+}
+
+func (d *RocksDB) getTokenBalance(key []byte) (*big.Int, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfTokenBalances], key)
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	if len(val.Data()) == 0 {
+		return new(big.Int), nil
+	}
+	balance, _ := unpackBigint(val.Data())
+	return &balance, nil
+}
+
+// IsTokenHoldersEnabled returns true if the token holders are maintained in the index
+func (d *RocksDB) IsTokenHoldersEnabled() bool {
+	return d.chainParser.GetChainType() == bchain.ChainEthereumType && d.is != nil && d.is.RichList
+}
+
+// addTokenBalanceChange adds the amount to the change of the balance of the holder, the zero address,
+// which sends the minted and receives the burned tokens, is not a holder
+func addTokenBalanceChange(changes map[string]*big.Int, contract, holder bchain.AddressDescriptor, amount *big.Int, add bool) {
+	if len(contract) != eth.EthereumTypeAddressDescriptorLen || len(holder) != eth.EthereumTypeAddressDescriptorLen || isZeroAddress(holder) {
+		return
+	}
+	key := string(contract) + string(holder)
+	c, found := changes[key]
+	if !found {
+		c = new(big.Int)
+		changes[key] = c
+	}
+	if add {
+		c.Add(c, amount)
+	} else {
+		c.Sub(c, amount)
+	}
+}
+
+// addTokenTransfers adds the ERC20 transfers of the block to the changes of the token balances
+func (d *RocksDB) addTokenTransfers(block *bchain.Block, changes map[string]*big.Int) {
+	for i := range block.Txs {
+		// the errors are logged by processAddressesEthereumType
+		erc20, err := d.chainParser.EthereumTypeGetErc20FromTx(&block.Txs[i])
+		if err != nil {
+			continue
+		}
+		for j := range erc20 {
+			t := &erc20[j]
+			contract, err := d.chainParser.GetAddrDescFromAddress(t.Contract)
+			if err != nil {
+				continue
+			}
+			from, err := d.chainParser.GetAddrDescFromAddress(t.From)
+			if err != nil {
+				continue
+			}
+			to, err := d.chainParser.GetAddrDescFromAddress(t.To)
+			if err != nil {
+				continue
+			}
+			addTokenBalanceChange(changes, contract, from, &t.Tokens, false)
+			addTokenBalanceChange(changes, contract, to, &t.Tokens, true)
+		}
+	}
+}
+
+// replaceTokenBalance replaces the balance of the holder in the balances, holders and totals of the contract
+func (d *RocksDB) replaceTokenBalance(wb *grocksdb.WriteBatch, key string, old, balance *big.Int, totals map[string]*tokenHoldersTotal) error {
+	contract := bchain.AddressDescriptor(key[:eth.EthereumTypeAddressDescriptorLen])
+	holder := bchain.AddressDescriptor(key[eth.EthereumTypeAddressDescriptorLen:])
+	t, found := totals[string(contract)]
+	if !found {
+		count, total, err := d.GetTokenHoldersTotal(contract)
+		if err != nil {
+			return err
+		}
+		t = &tokenHoldersTotal{count: count, total: total}
+		totals[string(contract)] = t
+	}
+	if old.Sign() > 0 {
+		wb.DeleteCF(d.cfh[cfTokenHolders], packTokenHolderKey(contract, holder, old))
+		t.count--
+		t.total.Sub(t.total, old)
+	}
+	if balance.Sign() > 0 {
+		buf := make([]byte, maxPackedBigintBytes)
+		l := packBigint(balance, buf)
+		wb.PutCF(d.cfh[cfTokenBalances], []byte(key), buf[:l])
+		wb.PutCF(d.cfh[cfTokenHolders], packTokenHolderKey(contract, holder, balance), []byte{})
+		t.count++
+		t.total.Add(t.total, balance)
+	} else {
+		wb.DeleteCF(d.cfh[cfTokenBalances], []byte(key))
+	}
+	return nil
+}
+
+func (d *RocksDB) storeTokenHoldersTotals(wb *grocksdb.WriteBatch, totals map[string]*tokenHoldersTotal) {
+	for contract, t := range totals {
+		if t.count == 0 {
+			wb.DeleteCF(d.cfh[cfTokenBalances], []byte(contract))
+		} else {
+			wb.PutCF(d.cfh[cfTokenBalances], []byte(contract), packRichListTotal(t.count, t.total))
+		}
+	}
+}
+
+// storeTokenBalances applies the changes of the token balances made by the blocks up to the height,
+// if undo is set, the changes are made by the block at the height and the previous balances are stored
+// to be restored when the block is disconnected
+func (d *RocksDB) storeTokenBalances(wb *grocksdb.WriteBatch, height uint32, changes map[string]*big.Int, undo bool) error {
+	totals := make(map[string]*tokenHoldersTotal)
+	undoBuf := []byte{}
+	varBuf := make([]byte, maxPackedBigintBytes)
+	for key, change := range changes {
+		if change.Sign() == 0 {
+			continue
+		}
+		old, err := d.getTokenBalance([]byte(key))
+		if err != nil {
+			return err
+		}
+		balance := new(big.Int).Add(old, change)
+		if balance.Sign() < 0 {
+			// the tokens which change the balances without the transfer events cannot be tracked
+			if glog.V(1) {
+				glog.Info("rocksdb: token balance ", balance.String(), " of ", bchain.AddressDescriptor(key).String(), " at height ", height, " is negative")
+			}
+			balance.SetInt64(0)
+		}
+		if undo {
+			undoBuf = append(undoBuf, key...)
+			l := packBigint(old, varBuf)
+			undoBuf = append(undoBuf, varBuf[:l]...)
+		}
+		if err = d.replaceTokenBalance(wb, key, old, balance, totals); err != nil {
+			return err
+		}
+	}
+	d.storeTokenHoldersTotals(wb, totals)
+	if undo {
+		wb.PutCF(d.cfh[cfTokenBalancesUndo], packUint(height), undoBuf)
+		if keep := uint32(d.chainParser.KeepBlockAddresses()); height > keep {
+			wb.DeleteCF(d.cfh[cfTokenBalancesUndo], packUint(height-keep))
+		}
+	}
+	return nil
+}
+
+// disconnectTokenBalances restores the token balances changed by the blocks in the range lower-higher
+func (d *RocksDB) disconnectTokenBalances(wb *grocksdb.WriteBatch, lower, higher uint32) error {
+	undo := make([][]byte, higher-lower+1)
+	for height := lower; height <= higher; height++ {
+		val, err := d.db.GetCF(d.ro, d.cfh[cfTokenBalancesUndo], packUint(height))
+		if err != nil {
+			return err
+		}
+		// nil data means the undo row was not stored, an empty row is stored for the blocks without transfers
+		if val.Data() == nil {
+			val.Free()
+			return errors.Errorf("Cannot disconnect token balances of block %v. It is necessary to rebuild index.", height)
+		}
+		undo[height-lower] = append([]byte{}, val.Data()...)
+		val.Free()
+	}
+	totals := make(map[string]*tokenHoldersTotal)
+	balances := make(map[string]*big.Int)
+	for height := higher; height >= lower; height-- {
+		buf := undo[height-lower]
+		for len(buf) > 0 {
+			if len(buf) <= tokenBalanceKeyLen {
+				return errors.Errorf("Invalid token balances undo of block %v", height)
+			}
+			key := string(buf[:tokenBalanceKeyLen])
+			old, l := unpackBigint(buf[tokenBalanceKeyLen:])
+			buf = buf[tokenBalanceKeyLen+l:]
+			current, found := balances[key]
+			if !found {
+				var err error
+				if current, err = d.getTokenBalance([]byte(key)); err != nil {
+					return err
+				}
+			}
+			if err := d.replaceTokenBalance(wb, key, current, &old, totals); err != nil {
+				return err
+			}
+			balances[key] = &old
+		}
+		wb.DeleteCF(d.cfh[cfTokenBalancesUndo], packUint(height))
+		if height == 0 {
+			break
+		}
+	}
+	d.storeTokenHoldersTotals(wb, totals)
+	return nil
+}
+
+// initTokenHolders switches the maintenance of the token holders on or off, the balances of the holders cannot be
+// computed from the other columns, therefore the token holders can be enabled only in an empty index
+func (d *RocksDB) initTokenHolders(enabled bool) error {
+	if !enabled {
+		d.richList = false
+		if d.is.RichList {
+			glog.Info("token holders: disabled, they can be enabled again only in a new index")
+			d.is.RichList = false
+			return d.storeState(d.is)
+		}
+		return nil
+	}
+	if !d.is.RichList {
+		_, hash, err := d.GetBestBlock()
+		if err != nil {
+			return err
+		}
+		if hash != "" {
+			return errors.New("Token holders can be enabled only before the initial synchronization, it is necessary to rebuild index")
+		}
+		d.is.RichList = true
+		if err := d.storeState(d.is); err != nil {
+			return err
+		}
+	}
+	d.richList = true
+	return nil
+}
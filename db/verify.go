@@ -0,0 +1,1458 @@
+package db
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/bchain/coins/eth"
+)
+
+// maximum number of issues listed in the report, all issues are counted
+const maxVerifyIssues = 1000
+
+// verifyBatchSize is the number of repaired rows written to the db in one write batch
+var verifyBatchSize = 10000
+
+// VerifyIssue is a discrepancy in the index found by Verify
+type VerifyIssue struct {
+	Check    string `json:"check"`
+	Key      string `json:"key"`
+	Message  string `json:"message"`
+	Repaired bool   `json:"repaired"`
+}
+
+// VerifyCheck summarizes one check of the index
+type VerifyCheck struct {
+	Name     string `json:"name"`
+	Rows     int64  `json:"rows"`
+	Issues   int64  `json:"issues"`
+	Repaired int64  `json:"repaired"`
+}
+
+// VerifyReport is the result of the consistency check of the index
+type VerifyReport struct {
+	Coin            string        `json:"coin"`
+	BestHeight      uint32        `json:"bestHeight"`
+	Repair          bool          `json:"repair"`
+	Started         time.Time     `json:"started"`
+	Finished        time.Time     `json:"finished"`
+	Interrupted     bool          `json:"interrupted,omitempty"`
+	Checks          []VerifyCheck `json:"checks"`
+	Issues          []VerifyIssue `json:"issues"`
+	IssuesTruncated bool          `json:"issuesTruncated,omitempty"`
+}
+
+// IssuesCount returns the number of found and repaired issues
+func (r *VerifyReport) IssuesCount() (int64, int64) {
+	var issues, repaired int64
+	for i := range r.Checks {
+		issues += r.Checks[i].Issues
+		repaired += r.Checks[i].Repaired
+	}
+	return issues, repaired
+}
+
+// Consistent returns true if the verification finished and all found issues were repaired
+func (r *VerifyReport) Consistent() bool {
+	issues, repaired := r.IssuesCount()
+	return !r.Interrupted && issues == repaired
+}
+
+type verifier struct {
+	d          *RocksDB
+	repair     bool
+	stop       chan os.Signal
+	bestHeight uint32
+	report     *VerifyReport
+	check      *VerifyCheck
+	wb         *grocksdb.WriteBatch
+	pending    int
+	// rows of the addresses, txAddresses and addressBalance columns modified by the repairs in the pending write batch
+	addressRows map[string][]txIndexes
+	txAddresses map[string]*TxAddresses
+	balances    map[string]*AddrBalance
+	// addresses with transactions but without balance (or contracts) found by the addresses check
+	missingSummaries []bchain.AddressDescriptor
+}
+
+// Verify cross-checks the columns of the index and returns the report of the found discrepancies
+// If repair is set, the discrepancies which can be derived from other columns are repaired in batches.
+// The columns are checked in the order in which they depend on each other, the repairs made by a check
+// are written before the next check starts. The verification can be interrupted by the stop signal,
+// then the partial report is returned together with ErrOperationInterrupted.
+func (d *RocksDB) Verify(repair bool, stop chan os.Signal) (*VerifyReport, error) {
+	if d.is == nil {
+		return nil, errors.New("Internal state not created")
+	}
+	bestHeight, _, err := d.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	v := &verifier{
+		d:          d,
+		repair:     repair,
+		stop:       stop,
+		bestHeight: bestHeight,
+		report: &VerifyReport{
+			Coin:       d.is.Coin,
+			BestHeight: bestHeight,
+			Repair:     repair,
+			Started:    time.Now().UTC(),
+			Checks:     []VerifyCheck{},
+			Issues:     []VerifyIssue{},
+		},
+		wb:          grocksdb.NewWriteBatch(),
+		addressRows: make(map[string][]txIndexes),
+		txAddresses: make(map[string]*TxAddresses),
+		balances:    make(map[string]*AddrBalance),
+	}
+	defer v.wb.Destroy()
+	type check struct {
+		name string
+		fn   func() error
+	}
+	var checks []check
+	switch d.chainParser.GetChainType() {
+	case bchain.ChainBitcoinType:
+		checks = []check{
+			{cfNames[cfHeight], v.verifyHeight},
+			{cfNames[cfBlockTxs], v.verifyBlockTxs},
+			{cfNames[cfTxAddresses], v.verifyTxAddresses},
+			{cfNames[cfAddresses], v.verifyAddresses},
+			{cfNames[cfAddressBalance], v.verifyAddressBalance},
+			{cfNames[cfTransactions], v.verifyTransactionsBitcoinType},
+			{cfNames[cfBlockFeeStats], v.verifyBlockFeeStats},
+		}
+		// the optional indexes are checked only if they are maintained, otherwise they are rebuilt when enabled
+		if d.is.BlockFilters {
+			checks = append(checks, check{cfNames[cfBlockFilters], v.verifyBlockFilters})
+		}
+		// the output scripts are stored for the block filters and for the OP_RETURN index
+		if d.is.BlockFilters || d.is.OpReturnIndex {
+			checks = append(checks, check{cfNames[cfOutputScripts], v.verifyOutputScripts})
+		}
+		if d.is.RichList {
+			checks = append(checks, check{cfNames[cfRichList], v.verifyRichList})
+		}
+		if d.is.OpReturnIndex {
+			checks = append(checks, check{cfNames[cfOpReturns], v.verifyOpReturns})
+		}
+		if d.is.ScriptHashIndex {
+			checks = append(checks, check{cfNames[cfScriptHashes], v.verifyScriptHashes})
+		}
+	case bchain.ChainEthereumType:
+		checks = []check{
+			{cfNames[cfHeight], v.verifyHeight},
+			{cfNames[cfBlockTxs], v.verifyBlockTxsEthereumType},
+			{cfNames[cfAddresses], v.verifyAddresses},
+			{cfNames[cfAddressContracts], v.verifyAddressContracts},
+			{cfNames[cfTransactions], v.verifyTransactionsEthereumType},
+		}
+		if d.is.RichList {
+			checks = append(checks,
+				check{cfNames[cfTokenBalances], v.verifyTokenBalances},
+				check{cfNames[cfTokenHolders], v.verifyTokenHolders},
+				check{cfNames[cfTokenBalancesUndo], v.verifyTokenBalancesUndo},
+			)
+		}
+	}
+	glog.Info("verify: started, repair ", repair)
+	for _, c := range checks {
+		v.report.Checks = append(v.report.Checks, VerifyCheck{Name: c.name})
+		v.check = &v.report.Checks[len(v.report.Checks)-1]
+		err := c.fn()
+		if err == nil {
+			err = v.flush()
+		}
+		if err != nil {
+			v.report.Finished = time.Now().UTC()
+			if err == ErrOperationInterrupted {
+				v.report.Interrupted = true
+				return v.report, err
+			}
+			return v.report, errors.Annotatef(err, "check %v", c.name)
+		}
+		glog.Info("verify: ", c.name, " checked, rows ", v.check.Rows, ", issues ", v.check.Issues, ", repaired ", v.check.Repaired)
+	}
+	v.report.Finished = time.Now().UTC()
+	issues, repaired := v.report.IssuesCount()
+	glog.Info("verify: finished in ", v.report.Finished.Sub(v.report.Started), ", issues ", issues, ", repaired ", repaired)
+	return v.report, nil
+}
+
+// issue records a discrepancy found by the current check
+func (v *verifier) issue(key string, repaired bool, format string, a ...interface{}) {
+	v.check.Issues++
+	if repaired {
+		v.check.Repaired++
+	}
+	msg := fmt.Sprintf(format, a...)
+	if glog.V(1) {
+		glog.Info("verify: ", v.check.Name, " ", key, ": ", msg)
+	}
+	if len(v.report.Issues) < maxVerifyIssues {
+		v.report.Issues = append(v.report.Issues, VerifyIssue{
+			Check:    v.check.Name,
+			Key:      key,
+			Message:  msg,
+			Repaired: repaired,
+		})
+	} else {
+		v.report.IssuesTruncated = true
+	}
+}
+
+func (v *verifier) addrDescKey(addrDesc bchain.AddressDescriptor) string {
+	addrs, _, err := v.d.chainParser.GetAddressesFromAddrDesc(addrDesc)
+	if err == nil && len(addrs) == 1 {
+		return addrs[0]
+	}
+	return hex.EncodeToString(addrDesc)
+}
+
+func (v *verifier) txidKey(btxID []byte) string {
+	txid, err := v.d.chainParser.UnpackTxid(btxID)
+	if err != nil {
+		return hex.EncodeToString(btxID)
+	}
+	return txid
+}
+
+func heightKey(height uint32) string {
+	return strconv.FormatUint(uint64(height), 10)
+}
+
+// iterate calls fn for all rows of the column, the iterator is refreshed every refreshIterator rows
+// a panic caused by invalid data in a row is reported as an issue of the row
+func (v *verifier) iterate(col int, fn func(key, val []byte) error) error {
+	// do not use cache
+	ro := grocksdb.NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetFillCache(false)
+	call := func(key, val []byte) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				v.issue(hex.EncodeToString(key), false, "invalid data: %v", r)
+			}
+		}()
+		return fn(key, val)
+	}
+	var seekKey []byte
+	for {
+		it := v.d.db.NewIteratorCF(ro, v.d.cfh[col])
+		if seekKey == nil {
+			it.SeekToFirst()
+		} else {
+			glog.Info("verify: ", v.check.Name, ", rows ", v.check.Rows, ", issues ", v.check.Issues, ", in progress...")
+			it.Seek(seekKey)
+			if it.Valid() && bytes.Equal(it.Key().Data(), seekKey) {
+				it.Next()
+			}
+		}
+		for count := 0; it.Valid() && count < refreshIterator; it.Next() {
+			select {
+			case <-v.stop:
+				it.Close()
+				return ErrOperationInterrupted
+			default:
+			}
+			key := append([]byte(nil), it.Key().Data()...)
+			if err := call(key, it.Value().Data()); err != nil {
+				it.Close()
+				return err
+			}
+			count++
+			v.check.Rows++
+			seekKey = key
+		}
+		valid := it.Valid()
+		it.Close()
+		if !valid {
+			return nil
+		}
+		if err := v.flushIfFull(); err != nil {
+			return err
+		}
+	}
+}
+
+// written marks a repair added to the pending write batch
+func (v *verifier) written() error {
+	v.pending++
+	return v.flushIfFull()
+}
+
+func (v *verifier) flushIfFull() error {
+	if v.pending+len(v.addressRows)+len(v.txAddresses)+len(v.balances) >= verifyBatchSize {
+		return v.flush()
+	}
+	return nil
+}
+
+// flush writes the pending repairs to the db
+func (v *verifier) flush() error {
+	if v.pending == 0 && len(v.addressRows) == 0 && len(v.txAddresses) == 0 && len(v.balances) == 0 {
+		return nil
+	}
+	for key, txi := range v.addressRows {
+		if len(txi) == 0 {
+			v.wb.DeleteCF(v.d.cfh[cfAddresses], []byte(key))
+		} else {
+			v.wb.PutCF(v.d.cfh[cfAddresses], []byte(key), v.d.packTxIndexes(txi))
+		}
+	}
+	if err := v.d.storeTxAddresses(v.wb, v.txAddresses); err != nil {
+		return err
+	}
+	// the balances are stored at once, the rich list is updated from the balances stored in the db
+	if err := v.d.storeBalances(v.wb, v.balances); err != nil {
+		return err
+	}
+	if err := v.d.db.Write(v.d.wo, v.wb); err != nil {
+		return err
+	}
+	v.wb.Clear()
+	v.pending = 0
+	v.addressRows = make(map[string][]txIndexes)
+	v.txAddresses = make(map[string]*TxAddresses)
+	v.balances = make(map[string]*AddrBalance)
+	return nil
+}
+
+// getTxAddresses returns txAddresses of the transaction including the pending repairs
+func (v *verifier) getTxAddresses(btxID []byte) (*TxAddresses, error) {
+	if ta, found := v.txAddresses[string(btxID)]; found {
+		return ta, nil
+	}
+	return v.d.getTxAddresses(btxID)
+}
+
+// getAddressRow returns the transactions of the row of the addresses column including the pending repairs
+func (v *verifier) getAddressRow(key []byte) ([]txIndexes, error) {
+	if txi, found := v.addressRows[string(key)]; found {
+		return txi, nil
+	}
+	val, err := v.d.db.GetCF(v.d.ro, v.d.cfh[cfAddresses], key)
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	return v.d.unpackTxIndexes(val.Data())
+}
+
+// unpackTxIndexes unpacks the row of the addresses column, the transactions are returned in the order
+// in which they were added to the row, i.e. the order expected by packTxIndexes
+func (d *RocksDB) unpackTxIndexes(buf []byte) ([]txIndexes, error) {
+	txidUnpackedLen := d.chainParser.PackedTxidLen()
+	var r []txIndexes
+	for len(buf) > 0 {
+		if len(buf) <= txidUnpackedLen {
+			return nil, errors.New("Invalid data in addresses")
+		}
+		t := txIndexes{btxID: append([]byte(nil), buf[:txidUnpackedLen]...)}
+		buf = buf[txidUnpackedLen:]
+		for {
+			if len(buf) == 0 {
+				return nil, errors.New("Invalid data in addresses")
+			}
+			index, l := unpackVarint32(buf)
+			t.indexes = append(t.indexes, index>>1)
+			buf = buf[l:]
+			if index&1 == 1 {
+				break
+			}
+		}
+		r = append(r, t)
+	}
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return r, nil
+}
+
+// verifyHeight checks that the blocks in the height column are contiguous and can be unpacked
+func (v *verifier) verifyHeight() error {
+	var prev uint32
+	first := true
+	return v.iterate(cfHeight, func(key, val []byte) error {
+		if len(key) != packedHeightBytes {
+			v.issue(hex.EncodeToString(key), false, "invalid key")
+			return nil
+		}
+		height := unpackUint(key)
+		if !first && height != prev+1 {
+			v.issue(heightKey(height), false, "missing blocks %d-%d", prev+1, height-1)
+		}
+		first = false
+		prev = height
+		if bi, err := v.d.unpackBlockInfo(val); err != nil || bi == nil {
+			v.issue(heightKey(height), false, "invalid block info %v", err)
+		}
+		return nil
+	})
+}
+
+// verifyBlockKey checks that the row of the blockTxs column belongs to a block in the height column
+// the row of a block which is not in the index is deleted on repair
+func (v *verifier) verifyBlockKey(key []byte) (*BlockInfo, error) {
+	if len(key) != packedHeightBytes {
+		v.issue(hex.EncodeToString(key), false, "invalid key")
+		return nil, nil
+	}
+	height := unpackUint(key)
+	bi, err := v.d.GetBlockInfo(height)
+	if err != nil {
+		return nil, err
+	}
+	if bi == nil {
+		if v.repair {
+			v.wb.DeleteCF(v.d.cfh[cfBlockTxs], key)
+			if err = v.written(); err != nil {
+				return nil, err
+			}
+		}
+		v.issue(heightKey(height), v.repair, "block not found in height column")
+		return nil, nil
+	}
+	return bi, nil
+}
+
+// verifyBlockTxs checks the transactions of the blocks in the blockTxs column against txAddresses
+// and that the outputs spent by the transactions are marked as spent, which is repaired
+func (v *verifier) verifyBlockTxs() error {
+	zeroTx := make([]byte, v.d.chainParser.PackedTxidLen())
+	return v.iterate(cfBlockTxs, func(key, val []byte) error {
+		bi, err := v.verifyBlockKey(key)
+		if err != nil || bi == nil {
+			return err
+		}
+		blockTxs, err := v.d.getBlockTxs(bi.Height)
+		if err != nil {
+			v.issue(heightKey(bi.Height), false, "%v", err)
+			return nil
+		}
+		if len(blockTxs) != int(bi.Txs) {
+			v.issue(heightKey(bi.Height), false, "block has %d transactions, blockTxs contain %d", bi.Txs, len(blockTxs))
+		}
+		for i := range blockTxs {
+			bt := &blockTxs[i]
+			ta, err := v.getTxAddresses(bt.btxID)
+			if err != nil {
+				return err
+			}
+			if ta == nil {
+				v.issue(heightKey(bi.Height), false, "tx %s not found in txAddresses", v.txidKey(bt.btxID))
+			} else if ta.Height != bi.Height {
+				v.issue(heightKey(bi.Height), false, "tx %s has height %d in txAddresses", v.txidKey(bt.btxID), ta.Height)
+			}
+			for j := range bt.inputs {
+				in := &bt.inputs[j]
+				if bytes.Equal(in.btxID, zeroTx) {
+					continue
+				}
+				ita, err := v.getTxAddresses(in.btxID)
+				if err != nil {
+					return err
+				}
+				// inputs spending unknown transactions are allowed
+				if ita == nil {
+					continue
+				}
+				if int(in.index) >= len(ita.Outputs) || in.index < 0 {
+					v.issue(heightKey(bi.Height), false, "tx %s input %d spends nonexistent output %s:%d", v.txidKey(bt.btxID), j, v.txidKey(in.btxID), in.index)
+				} else if !ita.Outputs[in.index].Spent {
+					if v.repair {
+						ita.Outputs[in.index].Spent = true
+						v.txAddresses[string(in.btxID)] = ita
+						if err = v.flushIfFull(); err != nil {
+							return err
+						}
+					}
+					v.issue(heightKey(bi.Height), v.repair, "output %s:%d spent by tx %s is not marked as spent", v.txidKey(in.btxID), in.index, v.txidKey(bt.btxID))
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// verifyBlockTxsEthereumType checks the transactions of the blocks in the blockTxs column against the addresses column
+func (v *verifier) verifyBlockTxsEthereumType() error {
+	return v.iterate(cfBlockTxs, func(key, val []byte) error {
+		bi, err := v.verifyBlockKey(key)
+		if err != nil || bi == nil {
+			return err
+		}
+		blockTxs, err := v.d.getBlockTxsEthereumType(bi.Height)
+		if err != nil {
+			v.issue(heightKey(bi.Height), false, "%v", err)
+			return nil
+		}
+		if len(blockTxs) != int(bi.Txs) {
+			v.issue(heightKey(bi.Height), false, "block has %d transactions, blockTxs contain %d", bi.Txs, len(blockTxs))
+		}
+		checkAddress := func(btxID []byte, addrDesc bchain.AddressDescriptor) error {
+			if len(addrDesc) == 0 {
+				return nil
+			}
+			txi, err := v.getAddressRow(packAddressKey(addrDesc, bi.Height))
+			if err != nil {
+				v.issue(heightKey(bi.Height), false, "address %s: %v", v.addrDescKey(addrDesc), err)
+				return nil
+			}
+			for i := range txi {
+				if bytes.Equal(txi[i].btxID, btxID) {
+					return nil
+				}
+			}
+			v.issue(heightKey(bi.Height), false, "tx %s not found in addresses of %s", v.txidKey(btxID), v.addrDescKey(addrDesc))
+			return nil
+		}
+		for i := range blockTxs {
+			bt := &blockTxs[i]
+			if err = checkAddress(bt.btxID, bt.from); err != nil {
+				return err
+			}
+			if err = checkAddress(bt.btxID, bt.to); err != nil {
+				return err
+			}
+			for j := range bt.contracts {
+				if err = checkAddress(bt.btxID, bt.contracts[j].addr); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// verifyTxAddresses checks that the inputs and outputs of the transactions in txAddresses are in the addresses column
+// the missing entries are added on repair
+func (v *verifier) verifyTxAddresses() error {
+	return v.iterate(cfTxAddresses, func(key, val []byte) error {
+		ta, err := unpackTxAddresses(val)
+		if err != nil {
+			v.issue(v.txidKey(key), false, "%v", err)
+			return nil
+		}
+		if ta.Height > v.bestHeight {
+			v.issue(v.txidKey(key), false, "tx height %d is above the best block", ta.Height)
+			return nil
+		}
+		for i := range ta.Outputs {
+			if err = v.verifyAddressEntry(ta.Outputs[i].AddrDesc, ta.Height, key, int32(i)); err != nil {
+				return err
+			}
+		}
+		for i := range ta.Inputs {
+			// inputs spending unknown transactions are not indexed, they are stored without value
+			if ta.Inputs[i].ValueSat.Sign() == 0 {
+				continue
+			}
+			if err = v.verifyAddressEntry(ta.Inputs[i].AddrDesc, ta.Height, key, ^int32(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (v *verifier) verifyAddressEntry(addrDesc bchain.AddressDescriptor, height uint32, btxID []byte, index int32) error {
+	if len(addrDesc) == 0 || !v.d.chainParser.IsAddrDescIndexable(addrDesc) {
+		return nil
+	}
+	key := packAddressKey(addrDesc, height)
+	txi, err := v.getAddressRow(key)
+	if err != nil {
+		v.issue(v.addrDescKey(addrDesc), false, "height %d: %v", height, err)
+		return nil
+	}
+	t := -1
+	for i := range txi {
+		if bytes.Equal(txi[i].btxID, btxID) {
+			t = i
+			for _, ix := range txi[i].indexes {
+				if ix == index {
+					return nil
+				}
+			}
+			break
+		}
+	}
+	if v.repair {
+		if t < 0 {
+			txi = append(txi, txIndexes{btxID: btxID, indexes: []int32{index}})
+		} else {
+			txi[t].indexes = append(txi[t].indexes, index)
+		}
+		v.addressRows[string(key)] = txi
+		if err = v.flushIfFull(); err != nil {
+			return err
+		}
+	}
+	v.issue(v.addrDescKey(addrDesc), v.repair, "tx %s index %d at height %d not found in addresses", v.txidKey(btxID), index, height)
+	return nil
+}
+
+// verifyAddresses checks the rows of the addresses column, for bitcoin type coins the entries must match txAddresses
+// the invalid entries are removed on repair
+func (v *verifier) verifyAddresses() error {
+	bitcoinType := v.d.chainParser.GetChainType() == bchain.ChainBitcoinType
+	var lastAddrDesc bchain.AddressDescriptor
+	return v.iterate(cfAddresses, func(key, val []byte) error {
+		addrDesc, height, err := unpackAddressKey(key)
+		if err != nil {
+			v.issue(hex.EncodeToString(key), false, "%v", err)
+			return nil
+		}
+		if !bytes.Equal(addrDesc, lastAddrDesc) {
+			lastAddrDesc = append(lastAddrDesc[:0], addrDesc...)
+			if err = v.verifyAddressSummaryExists(addrDesc); err != nil {
+				return err
+			}
+		}
+		txi, err := v.d.unpackTxIndexes(val)
+		if err != nil {
+			v.issue(v.addrDescKey(addrDesc), false, "height %d: %v", height, err)
+			return nil
+		}
+		if height > v.bestHeight {
+			if v.repair {
+				v.addressRows[string(key)] = nil
+				if err = v.flushIfFull(); err != nil {
+					return err
+				}
+			}
+			v.issue(v.addrDescKey(addrDesc), v.repair, "height %d is above the best block", height)
+			return nil
+		}
+		if !bitcoinType {
+			return nil
+		}
+		modified := false
+		valid := txi[:0]
+		for i := range txi {
+			t := &txi[i]
+			ta, err := v.getTxAddresses(t.btxID)
+			if err != nil {
+				return err
+			}
+			if ta == nil || ta.Height != height {
+				if ta == nil {
+					v.issue(v.addrDescKey(addrDesc), v.repair, "tx %s at height %d not found in txAddresses", v.txidKey(t.btxID), height)
+				} else {
+					v.issue(v.addrDescKey(addrDesc), v.repair, "tx %s at height %d has height %d in txAddresses", v.txidKey(t.btxID), height, ta.Height)
+				}
+				modified = true
+				continue
+			}
+			indexes := t.indexes[:0]
+			for _, index := range t.indexes {
+				var ad bchain.AddressDescriptor
+				if index >= 0 {
+					if int(index) < len(ta.Outputs) {
+						ad = ta.Outputs[index].AddrDesc
+					}
+				} else if int(^index) < len(ta.Inputs) {
+					ad = ta.Inputs[^index].AddrDesc
+				}
+				if !bytes.Equal(ad, addrDesc) {
+					v.issue(v.addrDescKey(addrDesc), v.repair, "tx %s index %d at height %d does not match txAddresses", v.txidKey(t.btxID), index, height)
+					modified = true
+					continue
+				}
+				indexes = append(indexes, index)
+			}
+			if len(indexes) > 0 {
+				t.indexes = indexes
+				valid = append(valid, *t)
+			}
+		}
+		if modified && v.repair {
+			v.addressRows[string(key)] = valid
+			if err = v.flushIfFull(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// verifyAddressSummaryExists checks that the address has a row in the addressBalance or addressContracts column
+// the missing rows are reported and repaired by the following check
+func (v *verifier) verifyAddressSummaryExists(addrDesc bchain.AddressDescriptor) error {
+	col := cfAddressBalance
+	if v.d.chainParser.GetChainType() == bchain.ChainEthereumType {
+		col = cfAddressContracts
+	}
+	val, err := v.d.db.GetCF(v.d.ro, v.d.cfh[col], addrDesc)
+	if err != nil {
+		return err
+	}
+	defer val.Free()
+	if len(val.Data()) == 0 {
+		v.missingSummaries = append(v.missingSummaries, append(bchain.AddressDescriptor(nil), addrDesc...))
+	}
+	return nil
+}
+
+// computeAddrBalance computes the balance of the address from its transactions in the addresses and txAddresses columns
+// returns also the sum of the unspent outputs, which equals to the balance if the spent flags are consistent
+func (d *RocksDB) computeAddrBalance(addrDesc bchain.AddressDescriptor) (*AddrBalance, *big.Int, error) {
+	ab := &AddrBalance{}
+	var received, unspent big.Int
+	err := d.GetAddrDescTransactions(addrDesc, 0, ^uint32(0), func(txid string, height uint32, indexes []int32) error {
+		btxID, err := d.chainParser.PackTxid(txid)
+		if err != nil {
+			return err
+		}
+		ta, err := d.getTxAddresses(btxID)
+		if err != nil {
+			return err
+		}
+		// missing transactions are reported by the check of the addresses column
+		if ta == nil {
+			return nil
+		}
+		ab.Txs++
+		// sort the indexes so that the utxos are appended in the reverse order
+		sort.Slice(indexes, func(i, j int) bool {
+			return indexes[i] > indexes[j]
+		})
+		for _, index := range indexes {
+			if index >= 0 {
+				if int(index) < len(ta.Outputs) {
+					o := &ta.Outputs[index]
+					received.Add(&received, &o.ValueSat)
+					if !o.Spent {
+						unspent.Add(&unspent, &o.ValueSat)
+						ab.Utxos = append(ab.Utxos, Utxo{BtxID: btxID, Vout: index, Height: height, ValueSat: o.ValueSat})
+					}
+				}
+			} else if int(^index) < len(ta.Inputs) {
+				ab.SentSat.Add(&ab.SentSat, &ta.Inputs[^index].ValueSat)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	ab.BalanceSat.Sub(&received, &ab.SentSat)
+	// the transactions are iterated from the newest, the utxos are stored from the oldest
+	for i, j := 0, len(ab.Utxos)-1; i < j; i, j = i+1, j-1 {
+		ab.Utxos[i], ab.Utxos[j] = ab.Utxos[j], ab.Utxos[i]
+	}
+	return ab, &unspent, nil
+}
+
+func utxosEqual(a, b []Utxo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	m := make(map[string]*Utxo, len(a))
+	for i := range a {
+		m[string(a[i].BtxID)+strconv.Itoa(int(a[i].Vout))] = &a[i]
+	}
+	for i := range b {
+		u, found := m[string(b[i].BtxID)+strconv.Itoa(int(b[i].Vout))]
+		if !found || u.Height != b[i].Height || u.ValueSat.Cmp(&b[i].ValueSat) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyAddressBalance recomputes the balances of the addresses from their transactions and compares them
+// with the stored balances, the balances are repaired if the spent flags of the outputs are consistent with the history
+func (v *verifier) verifyAddressBalance() error {
+	for _, addrDesc := range v.missingSummaries {
+		if err := v.verifyBalance(addrDesc, nil); err != nil {
+			return err
+		}
+	}
+	v.missingSummaries = nil
+	return v.iterate(cfAddressBalance, func(key, val []byte) error {
+		ab, err := unpackAddrBalance(val, v.d.chainParser.PackedTxidLen(), AddressBalanceDetailUTXO)
+		if err != nil {
+			v.issue(v.addrDescKey(key), false, "%v", err)
+			return nil
+		}
+		return v.verifyBalance(key, ab)
+	})
+}
+
+func (v *verifier) verifyBalance(addrDesc bchain.AddressDescriptor, ab *AddrBalance) error {
+	computed, unspent, err := v.d.computeAddrBalance(addrDesc)
+	if err != nil {
+		return err
+	}
+	var diffs []string
+	if ab == nil {
+		if computed.Txs == 0 {
+			return nil
+		}
+		diffs = append(diffs, "missing balance")
+	} else {
+		if ab.Txs != computed.Txs {
+			diffs = append(diffs, fmt.Sprintf("txs %d, computed %d", ab.Txs, computed.Txs))
+		}
+		if ab.SentSat.Cmp(&computed.SentSat) != 0 {
+			diffs = append(diffs, fmt.Sprintf("sent %s, computed %s", ab.SentSat.String(), computed.SentSat.String()))
+		}
+		if ab.BalanceSat.Cmp(&computed.BalanceSat) != 0 {
+			diffs = append(diffs, fmt.Sprintf("balance %s, computed %s", ab.BalanceSat.String(), computed.BalanceSat.String()))
+		}
+		if !utxosEqual(ab.Utxos, computed.Utxos) {
+			diffs = append(diffs, fmt.Sprintf("%d utxos, computed %d", len(ab.Utxos), len(computed.Utxos)))
+		}
+	}
+	consistent := computed.BalanceSat.Cmp(unspent) == 0
+	if !consistent {
+		// the outputs are marked as spent by inputs which are not in the history of the address or vice versa
+		diffs = append(diffs, fmt.Sprintf("unspent outputs %s do not match balance %s computed from transactions", unspent.String(), computed.BalanceSat.String()))
+	}
+	if len(diffs) > 0 {
+		repaired := v.repair && consistent
+		if repaired {
+			v.balances[string(addrDesc)] = computed
+			if err = v.flushIfFull(); err != nil {
+				return err
+			}
+		}
+		v.issue(v.addrDescKey(addrDesc), repaired, "%s", strings.Join(diffs, ", "))
+	}
+	return nil
+}
+
+// computeAddrContracts computes the number of transactions and of transactions without contract of the address
+// from the addresses column, the counters of the contracts cannot be computed as the contract indexes
+// in the addresses column shift when a contract is removed from the address on disconnect
+func (d *RocksDB) computeAddrContracts(addrDesc bchain.AddressDescriptor) (uint, uint, bool, error) {
+	var totalTxs, nonContractTxs uint
+	withContracts := false
+	err := d.GetAddrDescTransactions(addrDesc, 0, ^uint32(0), func(txid string, height uint32, indexes []int32) error {
+		totalTxs++
+		nonContract := false
+		for _, index := range indexes {
+			// index 0 is for ETH transfers, contract indexes start with 1
+			if index == 0 || index == ^int32(0) {
+				nonContract = true
+			} else {
+				withContracts = true
+			}
+		}
+		if nonContract {
+			nonContractTxs++
+		}
+		return nil
+	})
+	return totalTxs, nonContractTxs, withContracts, err
+}
+
+// verifyAddressContracts compares the transaction counters in the addressContracts column with the addresses column
+// the counters and the invalid contracts are repaired
+func (v *verifier) verifyAddressContracts() error {
+	for _, addrDesc := range v.missingSummaries {
+		totalTxs, nonContractTxs, withContracts, err := v.d.computeAddrContracts(addrDesc)
+		if err != nil {
+			return err
+		}
+		if totalTxs == 0 {
+			continue
+		}
+		// the contracts of the address are not known, the row can be repaired only if there are no token transfers
+		repaired := v.repair && !withContracts && !isZeroAddress(addrDesc)
+		if repaired {
+			ac := &AddrContracts{TotalTxs: totalTxs, NonContractTxs: nonContractTxs}
+			if err = v.d.storeAddressContracts(v.wb, map[string]*AddrContracts{string(addrDesc): ac}); err != nil {
+				return err
+			}
+			if err = v.written(); err != nil {
+				return err
+			}
+		}
+		v.issue(v.addrDescKey(addrDesc), repaired, "missing contracts, %d txs", totalTxs)
+	}
+	v.missingSummaries = nil
+	return v.iterate(cfAddressContracts, func(key, val []byte) error {
+		ac, err := unpackAddrContracts(val, key)
+		if err != nil {
+			v.issue(v.addrDescKey(key), false, "%v", err)
+			return nil
+		}
+		if ac == nil {
+			return nil
+		}
+		totalTxs, nonContractTxs, _, err := v.d.computeAddrContracts(key)
+		if err != nil {
+			return err
+		}
+		var diffs []string
+		if ac.TotalTxs != totalTxs {
+			diffs = append(diffs, fmt.Sprintf("txs %d, computed %d", ac.TotalTxs, totalTxs))
+			ac.TotalTxs = totalTxs
+		}
+		// the indexes of the zero address are the indexes of the transfers, not of the contracts
+		if !isZeroAddress(key) && ac.NonContractTxs != nonContractTxs {
+			diffs = append(diffs, fmt.Sprintf("non contract txs %d, computed %d", ac.NonContractTxs, nonContractTxs))
+			ac.NonContractTxs = nonContractTxs
+		}
+		contracts := ac.Contracts[:0]
+		for i := range ac.Contracts {
+			c := &ac.Contracts[i]
+			if c.Txs == 0 {
+				diffs = append(diffs, fmt.Sprintf("contract %s without txs", v.addrDescKey(c.Contract)))
+				continue
+			}
+			if j, found := findContractInAddressContracts(c.Contract, contracts); found {
+				diffs = append(diffs, fmt.Sprintf("duplicate contract %s", v.addrDescKey(c.Contract)))
+				contracts[j].Txs += c.Txs
+				continue
+			}
+			contracts = append(contracts, *c)
+		}
+		ac.Contracts = contracts
+		if len(diffs) > 0 {
+			if v.repair {
+				if totalTxs == 0 {
+					ac = nil
+				}
+				if err = v.d.storeAddressContracts(v.wb, map[string]*AddrContracts{string(key): ac}); err != nil {
+					return err
+				}
+				if err = v.written(); err != nil {
+					return err
+				}
+			}
+			v.issue(v.addrDescKey(key), v.repair, "%s", strings.Join(diffs, ", "))
+		}
+		return nil
+	})
+}
+
+// verifyTransactions checks the transactions cache, a transaction cached as confirmed up to the best block must be indexed
+// at the cached height, otherwise it was cached from a block which was disconnected. The invalid rows are removed
+// on repair, the cache is refilled on demand.
+func (v *verifier) verifyTransactions(check func(btxID []byte, tx *bchain.Tx, height uint32) (string, error)) error {
+	return v.iterate(cfTransactions, func(key, val []byte) error {
+		var msg string
+		tx, height, err := v.d.chainParser.UnpackTx(val)
+		if err != nil {
+			msg = fmt.Sprintf("invalid transaction %v", err)
+		} else if msg, err = check(key, tx, height); err != nil {
+			return err
+		}
+		if msg == "" {
+			return nil
+		}
+		if v.repair {
+			v.d.internalDeleteTx(v.wb, key)
+			if err = v.written(); err != nil {
+				return err
+			}
+		}
+		v.issue(v.txidKey(key), v.repair, "%s", msg)
+		return nil
+	})
+}
+
+// verifyTransactionsBitcoinType checks the cached transactions against txAddresses
+func (v *verifier) verifyTransactionsBitcoinType() error {
+	return v.verifyTransactions(func(btxID []byte, tx *bchain.Tx, height uint32) (string, error) {
+		ta, err := v.getTxAddresses(btxID)
+		if err != nil {
+			return "", err
+		}
+		// the height of a transaction cached before it was indexed is the best height of the backend,
+		// it can be greater than the height of the block of the transaction
+		if ta == nil {
+			if height <= v.bestHeight {
+				return fmt.Sprintf("cached at height %d, not found in txAddresses", height), nil
+			}
+		} else if ta.Height > height {
+			return fmt.Sprintf("cached at height %d, indexed at height %d", height, ta.Height), nil
+		}
+		return "", nil
+	})
+}
+
+// verifyTransactionsEthereumType checks that the cached transactions are in the addresses column of their sender
+func (v *verifier) verifyTransactionsEthereumType() error {
+	return v.verifyTransactions(func(btxID []byte, tx *bchain.Tx, height uint32) (string, error) {
+		if height > v.bestHeight {
+			return "", nil
+		}
+		if len(tx.Vin) == 0 || len(tx.Vin[0].Addresses) == 0 {
+			return "missing sender", nil
+		}
+		addrDesc, err := v.d.chainParser.GetAddrDescFromAddress(tx.Vin[0].Addresses[0])
+		if err != nil {
+			return fmt.Sprintf("invalid sender %v", err), nil
+		}
+		txi, err := v.getAddressRow(packAddressKey(addrDesc, height))
+		if err != nil {
+			return "", err
+		}
+		for i := range txi {
+			if bytes.Equal(txi[i].btxID, btxID) {
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("cached at height %d, not found in addresses", height), nil
+	})
+}
+
+// verifyBlockRows checks that the rows of the column keyed by height belong to the blocks in the height column
+// and that their values are valid, the invalid rows are removed on repair
+func (v *verifier) verifyBlockRows(col int, check func(height uint32, val []byte) error) error {
+	return v.iterate(col, func(key, val []byte) error {
+		var msg string
+		if len(key) != packedHeightBytes {
+			msg = "invalid key"
+		} else {
+			bi, err := v.d.GetBlockInfo(unpackUint(key))
+			if err != nil {
+				return err
+			}
+			if bi == nil {
+				msg = "block not found in height column"
+			} else if err = check(unpackUint(key), val); err != nil {
+				msg = err.Error()
+			}
+		}
+		if msg == "" {
+			return nil
+		}
+		if v.repair {
+			v.wb.DeleteCF(v.d.cfh[col], key)
+			if err := v.written(); err != nil {
+				return err
+			}
+		}
+		if len(key) == packedHeightBytes {
+			v.issue(heightKey(unpackUint(key)), v.repair, "%s", msg)
+		} else {
+			v.issue(hex.EncodeToString(key), v.repair, "%s", msg)
+		}
+		return nil
+	})
+}
+
+// verifyBlockFeeStats checks the rows of the blockFeeStats column
+func (v *verifier) verifyBlockFeeStats() error {
+	return v.verifyBlockRows(cfBlockFeeStats, func(height uint32, val []byte) error {
+		_, err := unpackBlockFeeStats(val)
+		return err
+	})
+}
+
+// verifyBlockFilters checks the rows of the blockFilters column and the chain of the filter headers,
+// a header which does not commit to the filter and to the header of the previous block is recomputed on repair
+func (v *verifier) verifyBlockFilters() error {
+	var prevHeight uint32
+	var prevHeader []byte
+	return v.verifyBlockRows(cfBlockFilters, func(height uint32, val []byte) error {
+		f, err := unpackBlockFilter(val)
+		if err != nil {
+			return err
+		}
+		var expectedPrev []byte
+		if height == 0 {
+			expectedPrev = make([]byte, BlockFilterHeaderLen)
+		} else if prevHeight == height-1 {
+			expectedPrev = prevHeader
+		}
+		prevHeight, prevHeader = height, f.Header
+		if f.Header == nil || expectedPrev == nil {
+			return nil
+		}
+		header := doubleSha256(append(f.FilterHash(), expectedPrev...))
+		if bytes.Equal(header, f.Header) {
+			return nil
+		}
+		prevHeader = header
+		if v.repair {
+			f.Header = header
+			v.d.storeBlockFilter(v.wb, height, f)
+			if err = v.written(); err != nil {
+				return err
+			}
+		}
+		v.issue(heightKey(height), v.repair, "filter header %s does not match the previous header", hex.EncodeToString(f.Header))
+		return nil
+	})
+}
+
+// verifyOutputScripts checks that the rows of the outputScripts column refer to the outputs in txAddresses
+// whose scripts cannot be obtained from their address descriptors, the other rows are removed on repair
+func (v *verifier) verifyOutputScripts() error {
+	pl := v.d.chainParser.PackedTxidLen()
+	return v.iterate(cfOutputScripts, func(key, val []byte) error {
+		var msg string
+		if len(key) != pl+4 {
+			msg = "invalid key"
+		} else {
+			btxID := key[:pl]
+			vout := int32(unpackUint(key[pl:]))
+			ta, err := v.getTxAddresses(btxID)
+			if err != nil {
+				return err
+			}
+			switch {
+			case ta == nil || vout < 0 || int(vout) >= len(ta.Outputs):
+				msg = fmt.Sprintf("output %s:%d not found in txAddresses", v.txidKey(btxID), vout)
+			case !isOutputScriptStored(val, ta.Outputs[vout].AddrDesc):
+				msg = fmt.Sprintf("output %s:%d script is stored in txAddresses", v.txidKey(btxID), vout)
+			}
+		}
+		if msg == "" {
+			return nil
+		}
+		if v.repair {
+			v.wb.DeleteCF(v.d.cfh[cfOutputScripts], key)
+			if err := v.written(); err != nil {
+				return err
+			}
+		}
+		v.issue(hex.EncodeToString(key), v.repair, "%s", msg)
+		return nil
+	})
+}
+
+// verifyRichList checks that the rich list contains exactly the addresses with positive balance in the addressBalance
+// column with their balances and that the row with the totals matches the entries, the rich list is rebuilt on repair
+func (v *verifier) verifyRichList() error {
+	var count uint
+	total := new(big.Int)
+	err := v.iterate(cfRichList, func(key, val []byte) error {
+		if bytes.Equal(key, richListTotalKey) {
+			return nil
+		}
+		addrDesc, balance, err := unpackRichListKey(key)
+		if err != nil {
+			v.issue(hex.EncodeToString(key), v.repair, "%v", err)
+			return nil
+		}
+		count++
+		total.Add(total, balance)
+		ab, err := v.d.GetAddrDescBalance(addrDesc, AddressBalanceDetailNoUTXO)
+		if err != nil {
+			return err
+		}
+		if ab == nil || ab.BalanceSat.Cmp(balance) != 0 {
+			v.issue(v.addrDescKey(addrDesc), v.repair, "balance %s does not match addressBalance", balance.String())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	storedCount, storedTotal, err := v.d.GetRichListTotal()
+	if err != nil {
+		return err
+	}
+	if storedCount != count || storedTotal.Cmp(total) != 0 {
+		v.issue("total", v.repair, "%d addresses with total %s, entries %d with total %s", storedCount, storedTotal.String(), count, total.String())
+	}
+	err = v.iterate(cfAddressBalance, func(key, val []byte) error {
+		ab, err := unpackAddrBalance(val, v.d.chainParser.PackedTxidLen(), AddressBalanceDetailNoUTXO)
+		if err != nil || ab.BalanceSat.Sign() <= 0 {
+			// invalid balances are reported by the addressBalance check
+			return nil
+		}
+		e, err := v.d.db.GetCF(v.d.ro, v.d.cfh[cfRichList], packRichListKey(key, &ab.BalanceSat))
+		if err != nil {
+			return err
+		}
+		defer e.Free()
+		if !e.Exists() {
+			v.issue(v.addrDescKey(key), v.repair, "balance %s missing in rich list", ab.BalanceSat.String())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if v.repair && v.check.Issues > 0 {
+		return v.d.buildRichList(v.stop)
+	}
+	return nil
+}
+
+// verifyOpReturns checks that the rows of the opReturns column refer to the OP_RETURN outputs in txAddresses
+// and that all OP_RETURN outputs in txAddresses are in the index, the rows are removed or added on repair
+func (v *verifier) verifyOpReturns() error {
+	err := v.iterate(cfOpReturns, func(key, val []byte) error {
+		var msg string
+		payload, txid, vout, err := v.d.unpackOpReturnKey(key)
+		if err != nil {
+			msg = err.Error()
+		} else {
+			height, _ := unpackVaruint(val)
+			btxID := key[len(payload) : len(key)-4]
+			ta, err := v.getTxAddresses(btxID)
+			if err != nil {
+				return err
+			}
+			switch {
+			case ta == nil:
+				msg = fmt.Sprintf("output %s:%d not found in txAddresses", txid, vout)
+			case ta.Height != uint32(height):
+				msg = fmt.Sprintf("output %s:%d at height %d, indexed at height %d", txid, vout, height, ta.Height)
+			case vout < 0 || int(vout) >= len(ta.Outputs):
+				msg = fmt.Sprintf("output %s:%d not found in txAddresses", txid, vout)
+			default:
+				// the scripts longer than maxAddrDescLen are stored without the address descriptor
+				script := []byte(ta.Outputs[vout].AddrDesc)
+				if len(script) == 0 {
+					if script, err = v.d.getOutputScript(btxID, vout); err != nil {
+						return err
+					}
+				}
+				if !bytes.Equal(packOpReturnKey(opReturnPayload(script), btxID, vout), key) {
+					msg = fmt.Sprintf("output %s:%d payload does not match", txid, vout)
+				}
+			}
+		}
+		if msg == "" {
+			return nil
+		}
+		if v.repair {
+			v.wb.DeleteCF(v.d.cfh[cfOpReturns], key)
+			if err = v.written(); err != nil {
+				return err
+			}
+		}
+		v.issue(hex.EncodeToString(key), v.repair, "%s", msg)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return v.iterate(cfTxAddresses, func(key, val []byte) error {
+		ta, err := unpackTxAddresses(val)
+		if err != nil {
+			// invalid txAddresses are reported by the txAddresses check
+			return nil
+		}
+		scripts, err := v.d.getTxOutputScripts(key, ta)
+		if err != nil {
+			return err
+		}
+		for _, row := range appendOpReturnRows(nil, key, ta.Height, scripts) {
+			e, err := v.d.db.GetCF(v.d.ro, v.d.cfh[cfOpReturns], row.key)
+			if err != nil {
+				return err
+			}
+			exists := e.Exists()
+			e.Free()
+			if exists {
+				continue
+			}
+			if v.repair {
+				v.d.storeOpReturns(v.wb, []opReturnRow{row})
+				if err = v.written(); err != nil {
+					return err
+				}
+			}
+			v.issue(v.txidKey(key), v.repair, "OP_RETURN output missing in index")
+		}
+		return nil
+	})
+}
+
+// verifyScriptHashes checks that the keys of the scriptHashes column are the hashes of the stored address descriptors
+// and that all addresses in the addressBalance column are in the index, the rows are removed or added on repair
+func (v *verifier) verifyScriptHashes() error {
+	err := v.iterate(cfScriptHashes, func(key, val []byte) error {
+		if bytes.Equal(key, ScriptHash(val)) {
+			return nil
+		}
+		if v.repair {
+			v.wb.DeleteCF(v.d.cfh[cfScriptHashes], key)
+			if err := v.written(); err != nil {
+				return err
+			}
+		}
+		v.issue(hex.EncodeToString(key), v.repair, "script hash of %s does not match", v.addrDescKey(val))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return v.iterate(cfAddressBalance, func(key, val []byte) error {
+		if len(key) == 0 {
+			return nil
+		}
+		e, err := v.d.db.GetCF(v.d.ro, v.d.cfh[cfScriptHashes], ScriptHash(key))
+		if err != nil {
+			return err
+		}
+		defer e.Free()
+		if bytes.Equal(e.Data(), key) {
+			return nil
+		}
+		if v.repair {
+			v.wb.PutCF(v.d.cfh[cfScriptHashes], ScriptHash(key), key)
+			if err = v.written(); err != nil {
+				return err
+			}
+		}
+		v.issue(v.addrDescKey(key), v.repair, "address missing in index")
+		return nil
+	})
+}
+
+// verifyTokenBalances checks that every positive token balance has its row in the tokenHolders column
+// and that the numbers of the holders and the sums of their balances match the balances
+func (v *verifier) verifyTokenBalances() error {
+	computed := make(map[string]*tokenHoldersTotal)
+	stored := make(map[string]*tokenHoldersTotal)
+	err := v.iterate(cfTokenBalances, func(key, val []byte) error {
+		switch len(key) {
+		case eth.EthereumTypeAddressDescriptorLen:
+			count, l := unpackVaruint(val)
+			total, _ := unpackBigint(val[l:])
+			stored[string(key)] = &tokenHoldersTotal{count: count, total: &total}
+			return nil
+		case tokenBalanceKeyLen:
+			contract, holder := key[:eth.EthereumTypeAddressDescriptorLen], key[eth.EthereumTypeAddressDescriptorLen:]
+			balance, _ := unpackBigint(val)
+			if balance.Sign() <= 0 {
+				if v.repair {
+					v.wb.DeleteCF(v.d.cfh[cfTokenBalances], key)
+					if err := v.written(); err != nil {
+						return err
+					}
+				}
+				v.issue(v.addrDescKey(holder), v.repair, "zero balance of contract %s", v.addrDescKey(contract))
+				return nil
+			}
+			t, found := computed[string(contract)]
+			if !found {
+				t = &tokenHoldersTotal{total: new(big.Int)}
+				computed[string(contract)] = t
+			}
+			t.count++
+			t.total.Add(t.total, &balance)
+			holderKey := packTokenHolderKey(contract, holder, &balance)
+			e, err := v.d.db.GetCF(v.d.ro, v.d.cfh[cfTokenHolders], holderKey)
+			if err != nil {
+				return err
+			}
+			exists := e.Exists()
+			e.Free()
+			if exists {
+				return nil
+			}
+			if v.repair {
+				v.wb.PutCF(v.d.cfh[cfTokenHolders], holderKey, []byte{})
+				if err = v.written(); err != nil {
+					return err
+				}
+			}
+			v.issue(v.addrDescKey(holder), v.repair, "balance %s of contract %s missing in token holders", balance.String(), v.addrDescKey(contract))
+			return nil
+		}
+		if v.repair {
+			v.wb.DeleteCF(v.d.cfh[cfTokenBalances], key)
+			if err := v.written(); err != nil {
+				return err
+			}
+		}
+		v.issue(hex.EncodeToString(key), v.repair, "invalid key")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for contract, s := range stored {
+		if _, found := computed[contract]; !found {
+			computed[contract] = &tokenHoldersTotal{total: new(big.Int)}
+		}
+		c := computed[contract]
+		if s.count != c.count || s.total.Cmp(c.total) != 0 {
+			v.issue(v.addrDescKey(bchain.AddressDescriptor(contract)), v.repair, "%d holders with total %s, computed %d with total %s", s.count, s.total.String(), c.count, c.total.String())
+			if v.repair {
+				v.d.storeTokenHoldersTotals(v.wb, map[string]*tokenHoldersTotal{contract: c})
+				if err = v.written(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for contract, c := range computed {
+		if _, found := stored[contract]; !found {
+			v.issue(v.addrDescKey(bchain.AddressDescriptor(contract)), v.repair, "missing total, computed %d holders with total %s", c.count, c.total.String())
+			if v.repair {
+				v.d.storeTokenHoldersTotals(v.wb, map[string]*tokenHoldersTotal{contract: c})
+				if err = v.written(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// verifyTokenHolders checks that the rows of the tokenHolders column match the balances in the tokenBalances column,
+// the rows which do not match are removed on repair
+func (v *verifier) verifyTokenHolders() error {
+	return v.iterate(cfTokenHolders, func(key, val []byte) error {
+		var msg string
+		if len(key) <= eth.EthereumTypeAddressDescriptorLen {
+			msg = "invalid key"
+		} else {
+			contract := key[:eth.EthereumTypeAddressDescriptorLen]
+			holder, balance, err := unpackRichListKey(key[eth.EthereumTypeAddressDescriptorLen:])
+			if err != nil {
+				msg = err.Error()
+			} else {
+				stored, err := v.d.GetTokenBalance(contract, holder)
+				if err != nil {
+					return err
+				}
+				if stored.Cmp(balance) != 0 {
+					msg = fmt.Sprintf("holder %s of contract %s with balance %s, stored balance %s", v.addrDescKey(holder), v.addrDescKey(contract), balance.String(), stored.String())
+				}
+			}
+		}
+		if msg == "" {
+			return nil
+		}
+		if v.repair {
+			v.wb.DeleteCF(v.d.cfh[cfTokenHolders], key)
+			if err := v.written(); err != nil {
+				return err
+			}
+		}
+		v.issue(hex.EncodeToString(key), v.repair, "%s", msg)
+		return nil
+	})
+}
+
+// verifyTokenBalancesUndo checks that the undo rows of the token balances belong to the blocks in the index
+func (v *verifier) verifyTokenBalancesUndo() error {
+	return v.verifyBlockRows(cfTokenBalancesUndo, func(height uint32, val []byte) error {
+		for len(val) > 0 {
+			if len(val) <= tokenBalanceKeyLen {
+				return errors.New("invalid undo data")
+			}
+			_, l := unpackBigint(val[tokenBalanceKeyLen:])
+			val = val[tokenBalanceKeyLen+l:]
+		}
+		return nil
+	})
+}
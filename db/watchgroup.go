@@ -0,0 +1,147 @@
+package db
+
+import (
+	vlq "github.com/bsm/go-vlq"
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/linxGnu/grocksdb"
+)
+
+// WatchGroup is a persisted set of addresses and xpubs (descriptors) which are queried together
+type WatchGroup struct {
+	ID          string   `json:"id"`
+	Descriptors []string `json:"descriptors"`
+	Created     int64    `json:"created"`
+	LastUsed    int64    `json:"lastUsed,omitempty"`
+}
+
+// LastUsedTime returns the unix time of the last use of the group, the groups stored
+// before the time of the last use was tracked return the time of creation
+func (g *WatchGroup) LastUsedTime() int64 {
+	if g.LastUsed > g.Created {
+		return g.LastUsed
+	}
+	return g.Created
+}
+
+// packWatchGroup packs the watch group without its ID, which is the key of the row
+func packWatchGroup(group *WatchGroup) []byte {
+	buf := make([]byte, 0, 3*vlq.MaxLen64)
+	varBuf := make([]byte, vlq.MaxLen64)
+	l := packVarint(int(group.Created), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVarint(int(group.LastUsed), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	l = packVaruint(uint(len(group.Descriptors)), varBuf)
+	buf = append(buf, varBuf[:l]...)
+	for _, descriptor := range group.Descriptors {
+		l = packVaruint(uint(len(descriptor)), varBuf)
+		buf = append(buf, varBuf[:l]...)
+		buf = append(buf, descriptor...)
+	}
+	return buf
+}
+
+func unpackWatchGroup(id string, buf []byte) (*WatchGroup, error) {
+	group := &WatchGroup{ID: id}
+	values := make([]int, 2)
+	l := 0
+	for i := range values {
+		if l >= len(buf) {
+			return nil, errors.New("Invalid watch group")
+		}
+		v, ll := unpackVarint(buf[l:])
+		values[i] = v
+		l += ll
+	}
+	group.Created, group.LastUsed = int64(values[0]), int64(values[1])
+	if l >= len(buf) {
+		return nil, errors.New("Invalid watch group")
+	}
+	count, ll := unpackVaruint(buf[l:])
+	l += ll
+	if count > uint(len(buf)-l) {
+		return nil, errors.New("Invalid watch group")
+	}
+	if count > 0 {
+		group.Descriptors = make([]string, count)
+	}
+	for i := range group.Descriptors {
+		if l >= len(buf) {
+			return nil, errors.New("Invalid watch group")
+		}
+		dl, ll := unpackVaruint(buf[l:])
+		l += ll
+		if dl > uint(len(buf)-l) {
+			return nil, errors.New("Invalid watch group")
+		}
+		group.Descriptors[i] = string(buf[l : l+int(dl)])
+		l += int(dl)
+	}
+	return group, nil
+}
+
+// StoreWatchGroup stores (or overwrites) the watch group under its ID
+func (d *RocksDB) StoreWatchGroup(group *WatchGroup) error {
+	if len(group.ID) == 0 {
+		return errors.New("Error storing watch group: empty id")
+	}
+	if err := d.db.PutCF(d.wo, d.cfh[cfWatchGroups], []byte(group.ID), packWatchGroup(group)); err != nil {
+		glog.Error("Error storing watch group: ", err)
+		return err
+	}
+	return nil
+}
+
+// GetWatchGroup returns the watch group with given ID or nil if the group does not exist
+func (d *RocksDB) GetWatchGroup(id string) (*WatchGroup, error) {
+	val, err := d.db.GetCF(d.ro, d.cfh[cfWatchGroups], []byte(id))
+	if err != nil {
+		return nil, err
+	}
+	defer val.Free()
+	buf := val.Data()
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	group, err := unpackWatchGroup(id, buf)
+	if err != nil {
+		return nil, errors.Annotatef(err, "watch group %v", id)
+	}
+	return group, nil
+}
+
+// DeleteWatchGroup removes the watch group with given ID
+func (d *RocksDB) DeleteWatchGroup(id string) error {
+	return d.db.DeleteCF(d.wo, d.cfh[cfWatchGroups], []byte(id))
+}
+
+// PurgeWatchGroups deletes the watch groups not used since expiredBefore (unix time),
+// returns the number of the remaining and of the deleted groups
+func (d *RocksDB) PurgeWatchGroups(expiredBefore int64) (int, int, error) {
+	it := d.db.NewIteratorCF(d.ro, d.cfh[cfWatchGroups])
+	defer it.Close()
+	wb := grocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	remaining, purged := 0, 0
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		group, err := unpackWatchGroup(string(it.Key().Data()), it.Value().Data())
+		if err != nil {
+			glog.Warning("PurgeWatchGroups: invalid watch group ", string(it.Key().Data()), ": ", err)
+			remaining++
+			continue
+		}
+		if group.LastUsedTime() < expiredBefore {
+			wb.DeleteCF(d.cfh[cfWatchGroups], it.Key().Data())
+			purged++
+		} else {
+			remaining++
+		}
+	}
+	if purged > 0 {
+		if err := d.db.Write(d.wo, wb); err != nil {
+			return 0, 0, err
+		}
+	}
+	return remaining, purged, nil
+}
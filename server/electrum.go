@@ -0,0 +1,1356 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+)
+
+const (
+	electrumProtocolVersion = "1.4"
+	// maximum length of a request line, the broadcasted transactions must fit into it
+	electrumMaxLineLength = 4 * 1024 * 1024
+	// clients are expected to send server.ping at least every few minutes
+	electrumIdleTimeout = 10 * time.Minute
+	electrumHeaderLen   = 80
+	electrumMaxHeaders  = 2016
+	// the block headers are immutable for given block hash, the cache is cleared when it is full
+	electrumMaxCachedHeaders = 100000
+	electrumMaxSubscriptions = 50000
+	// default minimum relay fee of Bitcoin Core, in satoshi per kB
+	electrumRelayFeeSat = 1000
+)
+
+// electrumMaxHistory is the maximum number of transactions returned in the history of a script hash, the same as the limit
+// of ElectrumX with the default max_send; the clients are expected to use another server for the addresses with a larger history
+var electrumMaxHistory = 10000
+
+var errElectrumHistoryTooLarge = &electrumError{Code: electrumErrBadRequest, Message: "history too large"}
+
+// error codes of JSON-RPC and of ElectrumX
+const (
+	electrumErrParse          = -32700
+	electrumErrInvalidRequest = -32600
+	electrumErrMethodNotFound = -32601
+	electrumErrInvalidParams  = -32602
+	electrumErrBadRequest     = 1
+	electrumErrDaemon         = 2
+)
+
+type electrumRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type electrumResult struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+}
+
+type electrumErrorResult struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   *electrumError  `json:"error"`
+}
+
+type electrumNotification struct {
+	JSONRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type electrumError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *electrumError) Error() string {
+	return e.Message
+}
+
+func newElectrumParamsError(format string, a ...interface{}) *electrumError {
+	return &electrumError{Code: electrumErrInvalidParams, Message: fmt.Sprintf(format, a...)}
+}
+
+type electrumClient struct {
+	id        uint64
+	ip        string
+	conn      net.Conn
+	out       chan []byte
+	alive     bool
+	aliveLock sync.Mutex
+	// headers and scriptHashes are guarded by ElectrumServer.subscriptionsLock
+	headers      bool
+	scriptHashes map[string]struct{}
+}
+
+// electrumSubscription is a subscribed script hash with the last status sent to the clients,
+// addrDesc is nil if the script hash is not yet in the index nor in mempool
+type electrumSubscription struct {
+	addrDesc bchain.AddressDescriptor
+	status   string
+	clients  map[*electrumClient]struct{}
+}
+
+type electrumHistoryItem struct {
+	TxHash string   `json:"tx_hash"`
+	Height int      `json:"height"`
+	Fee    *big.Int `json:"fee,omitempty"`
+}
+
+type electrumUnspent struct {
+	TxHash string   `json:"tx_hash"`
+	TxPos  int32    `json:"tx_pos"`
+	Height int      `json:"height"`
+	Value  *big.Int `json:"value"`
+}
+
+type electrumHeader struct {
+	Height uint32 `json:"height"`
+	Hex    string `json:"hex"`
+}
+
+// ElectrumServer is a TCP/TLS server implementing the Electrum protocol on top of the Blockbook index
+type ElectrumServer struct {
+	binding                 string
+	certFiles               string
+	listener                net.Listener
+	listenerLock            sync.Mutex
+	closed                  int32
+	lastClientID            uint64
+	db                      *db.RocksDB
+	txCache                 *db.TxCache
+	chain                   bchain.BlockChain
+	chainParser             bchain.BlockChainParser
+	mempool                 bchain.Mempool
+	metrics                 *common.Metrics
+	is                      *common.InternalState
+	api                     *api.Worker
+	block0hash              string
+	clients                 map[*electrumClient]struct{}
+	clientsLock             sync.Mutex
+	scriptHashSubscriptions map[string]*electrumSubscription
+	subscriptionsLock       sync.Mutex
+	headerCache             map[string][]byte
+	headerCacheLock         sync.Mutex
+}
+
+// NewElectrumServer creates new Electrum protocol server, the index must contain the script hashes of the addresses
+func NewElectrumServer(binding, certFiles string, db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, metrics *common.Metrics, is *common.InternalState) (*ElectrumServer, error) {
+	if chain.GetChainParser().GetChainType() != bchain.ChainBitcoinType {
+		return nil, errors.New("Electrum server is supported only for Bitcoin type coins")
+	}
+	if !db.IsScriptHashIndexEnabled() {
+		return nil, errors.New("Electrum server requires the script hash index")
+	}
+	api, err := api.NewWorker(db, chain, mempool, txCache, metrics, is)
+	if err != nil {
+		return nil, err
+	}
+	b0, err := db.GetBlockHash(0)
+	if err != nil {
+		return nil, err
+	}
+	s := &ElectrumServer{
+		binding:                 binding,
+		certFiles:               certFiles,
+		db:                      db,
+		txCache:                 txCache,
+		chain:                   chain,
+		chainParser:             chain.GetChainParser(),
+		mempool:                 mempool,
+		metrics:                 metrics,
+		is:                      is,
+		api:                     api,
+		block0hash:              b0,
+		clients:                 make(map[*electrumClient]struct{}),
+		scriptHashSubscriptions: make(map[string]*electrumSubscription),
+		headerCache:             make(map[string][]byte),
+	}
+	return s, nil
+}
+
+// Run starts the server and accepts the connections until the server is closed
+func (s *ElectrumServer) Run() error {
+	var l net.Listener
+	var err error
+	if s.certFiles == "" {
+		glog.Info("electrum server: starting to listen on tcp://", s.binding)
+		l, err = net.Listen("tcp", s.binding)
+	} else {
+		var cert tls.Certificate
+		cert, err = tls.LoadX509KeyPair(fmt.Sprint(s.certFiles, ".crt"), fmt.Sprint(s.certFiles, ".key"))
+		if err != nil {
+			return err
+		}
+		glog.Info("electrum server: starting to listen on ssl://", s.binding)
+		l, err = tls.Listen("tcp", s.binding, &tls.Config{Certificates: []tls.Certificate{cert}})
+	}
+	if err != nil {
+		return err
+	}
+	s.listenerLock.Lock()
+	s.listener = l
+	s.listenerLock.Unlock()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if atomic.LoadInt32(&s.closed) != 0 {
+				return nil
+			}
+			glog.Error("electrum server: accept error ", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops accepting new connections and disconnects all clients
+func (s *ElectrumServer) Close() error {
+	glog.Infof("electrum server: closing")
+	atomic.StoreInt32(&s.closed, 1)
+	s.listenerLock.Lock()
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	s.listenerLock.Unlock()
+	s.clientsLock.Lock()
+	clients := make([]*electrumClient, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.clientsLock.Unlock()
+	for _, c := range clients {
+		s.closeClient(c)
+	}
+	return err
+}
+
+func (s *ElectrumServer) serveConn(conn net.Conn) {
+	c := &electrumClient{
+		id:           atomic.AddUint64(&s.lastClientID, 1),
+		ip:           conn.RemoteAddr().String(),
+		conn:         conn,
+		out:          make(chan []byte, outChannelSize),
+		alive:        true,
+		scriptHashes: make(map[string]struct{}),
+	}
+	s.onConnect(c)
+	go s.outputLoop(c)
+	s.inputLoop(c)
+}
+
+func (c *electrumClient) closeOut() bool {
+	c.aliveLock.Lock()
+	defer c.aliveLock.Unlock()
+	if c.alive {
+		c.alive = false
+		close(c.out)
+		for len(c.out) > 0 {
+			<-c.out
+		}
+		return true
+	}
+	return false
+}
+
+func (c *electrumClient) dataOut(data []byte) {
+	c.aliveLock.Lock()
+	defer c.aliveLock.Unlock()
+	if c.alive {
+		if len(c.out) < outChannelSize-1 {
+			c.out <- data
+		} else {
+			glog.Warning("electrum client ", c.id, " overflow, closing")
+			// close the connection, the inputLoop then closes the client
+			c.conn.Close()
+		}
+	}
+}
+
+func (c *electrumClient) notify(method string, params ...interface{}) {
+	data, err := json.Marshal(&electrumNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		glog.Error("electrum client ", c.id, " notify ", method, ": ", err)
+		return
+	}
+	c.dataOut(data)
+}
+
+func (s *ElectrumServer) closeClient(c *electrumClient) {
+	if c.closeOut() {
+		c.conn.Close()
+		s.onDisconnect(c)
+	}
+}
+
+func (s *ElectrumServer) onConnect(c *electrumClient) {
+	s.clientsLock.Lock()
+	s.clients[c] = struct{}{}
+	s.clientsLock.Unlock()
+	glog.Info("electrum client connected ", c.id, ", ", c.ip)
+	s.metrics.ElectrumClients.Inc()
+}
+
+func (s *ElectrumServer) onDisconnect(c *electrumClient) {
+	s.clientsLock.Lock()
+	delete(s.clients, c)
+	s.clientsLock.Unlock()
+	s.subscriptionsLock.Lock()
+	for scriptHash := range c.scriptHashes {
+		s.doUnsubscribeScriptHash(c, scriptHash)
+	}
+	c.headers = false
+	s.subscriptionsLock.Unlock()
+	glog.Info("electrum client disconnected ", c.id, ", ", c.ip)
+	s.metrics.ElectrumClients.Dec()
+}
+
+func (s *ElectrumServer) inputLoop(c *electrumClient) {
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("recovered from panic: ", r, ", ", c.id)
+			debug.PrintStack()
+		}
+		s.closeClient(c)
+	}()
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), electrumMaxLineLength)
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(electrumIdleTimeout))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				glog.V(1).Info("electrum client ", c.id, " read error ", err)
+			}
+			return
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if res := s.onMessage(c, line); res != nil {
+			c.dataOut(res)
+		}
+	}
+}
+
+func (s *ElectrumServer) outputLoop(c *electrumClient) {
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("recovered from panic: ", r, ", ", c.id)
+			s.closeClient(c)
+		}
+	}()
+	for m := range c.out {
+		c.conn.SetWriteDeadline(time.Now().Add(defaultTimeout))
+		if _, err := c.conn.Write(append(m, '\n')); err != nil {
+			glog.V(1).Info("electrum client ", c.id, " write error ", err)
+			s.closeClient(c)
+			return
+		}
+	}
+}
+
+// onMessage processes a single request or a batch of requests and returns the serialized response, nil means no response
+func (s *ElectrumServer) onMessage(c *electrumClient, line []byte) []byte {
+	if line[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(line, &batch); err != nil || len(batch) == 0 {
+			return marshalElectrumResponse(nil, nil, &electrumError{Code: electrumErrInvalidRequest, Message: "Invalid request"})
+		}
+		results := make([]json.RawMessage, 0, len(batch))
+		for _, r := range batch {
+			if res := s.onRequest(c, r); res != nil {
+				results = append(results, res)
+			}
+		}
+		if len(results) == 0 {
+			return nil
+		}
+		data, err := json.Marshal(results)
+		if err != nil {
+			glog.Error("electrum client ", c.id, " batch: ", err)
+			return nil
+		}
+		return data
+	}
+	return s.onRequest(c, line)
+}
+
+func marshalElectrumResponse(id json.RawMessage, result interface{}, e *electrumError) []byte {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	var data []byte
+	var err error
+	if e != nil {
+		data, err = json.Marshal(&electrumErrorResult{JSONRPC: "2.0", ID: id, Error: e})
+	} else {
+		data, err = json.Marshal(&electrumResult{JSONRPC: "2.0", ID: id, Result: result})
+	}
+	if err != nil {
+		glog.Error("electrum response: ", err)
+		data, _ = json.Marshal(&electrumErrorResult{JSONRPC: "2.0", ID: id, Error: &electrumError{Code: electrumErrDaemon, Message: "Internal error"}})
+	}
+	return data
+}
+
+func (s *ElectrumServer) onRequest(c *electrumClient, raw []byte) (res []byte) {
+	var req electrumRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return marshalElectrumResponse(nil, nil, &electrumError{Code: electrumErrParse, Message: "Parse error"})
+	}
+	// requests without id are notifications and are not answered
+	notification := len(req.ID) == 0
+	var result interface{}
+	var e *electrumError
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("electrum client ", c.id, ", onRequest ", req.Method, " recovered from panic: ", r)
+			debug.PrintStack()
+			e = &electrumError{Code: electrumErrDaemon, Message: "Internal error"}
+		}
+		if notification {
+			res = nil
+		} else {
+			res = marshalElectrumResponse(req.ID, result, e)
+		}
+	}()
+	f, ok := electrumHandlers[req.Method]
+	if !ok {
+		glog.V(1).Info("electrum client ", c.id, " onRequest ", req.Method, ": unknown method")
+		e = &electrumError{Code: electrumErrMethodNotFound, Message: fmt.Sprintf("unknown method %q", req.Method)}
+		return
+	}
+	var params []json.RawMessage
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			e = newElectrumParamsError("params must be an array")
+			return
+		}
+	}
+	s.db.BeginRead()
+	defer s.db.EndRead()
+	t := time.Now()
+	defer func() {
+		s.metrics.ElectrumReqDuration.With(common.Labels{"method": req.Method}).Observe(float64(time.Since(t)) / 1e3) // in microseconds
+	}()
+	var err error
+	result, err = f(s, c, params)
+	if err == nil {
+		glog.V(1).Info("electrum client ", c.id, " onRequest ", req.Method, " success")
+		s.metrics.ElectrumRequests.With(common.Labels{"method": req.Method, "status": "success"}).Inc()
+		return
+	}
+	s.metrics.ElectrumRequests.With(common.Labels{"method": req.Method, "status": "failure"}).Inc()
+	switch err := err.(type) {
+	case *electrumError:
+		e = err
+	case *api.APIError:
+		if !err.Public {
+			glog.Error("electrum client ", c.id, " onRequest ", req.Method, ": ", err, ", params ", string(req.Params))
+		}
+		e = &electrumError{Code: electrumErrBadRequest, Message: err.Error()}
+	default:
+		glog.Error("electrum client ", c.id, " onRequest ", req.Method, ": ", errors.ErrorStack(err), ", params ", string(req.Params))
+		e = &electrumError{Code: electrumErrDaemon, Message: err.Error()}
+	}
+	return
+}
+
+var electrumHandlers = map[string]func(*ElectrumServer, *electrumClient, []json.RawMessage) (interface{}, error){
+	"server.version": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		return []string{"Blockbook " + common.GetVersionInfo().Version, electrumProtocolVersion}, nil
+	},
+	"server.banner": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		return fmt.Sprint("Blockbook ", common.GetVersionInfo().Version, ", ", s.is.Coin), nil
+	},
+	"server.donation_address": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		return "", nil
+	},
+	"server.ping": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		return nil, nil
+	},
+	"server.peers.subscribe": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		return []interface{}{}, nil
+	},
+	"server.features": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		return s.features(), nil
+	},
+	"blockchain.headers.subscribe": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		return s.subscribeHeaders(c)
+	},
+	"blockchain.block.header": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		height, err := electrumParamInt(params, 0, -1)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkElectrumCheckpoint(params, 1); err != nil {
+			return nil, err
+		}
+		return s.getBlockHeader(height)
+	},
+	"blockchain.block.headers": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		start, err := electrumParamInt(params, 0, -1)
+		if err != nil {
+			return nil, err
+		}
+		count, err := electrumParamInt(params, 1, -1)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkElectrumCheckpoint(params, 2); err != nil {
+			return nil, err
+		}
+		return s.getBlockHeaders(start, count)
+	},
+	"blockchain.estimatefee": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		blocks, err := electrumParamInt(params, 0, -1)
+		if err != nil {
+			return nil, err
+		}
+		var mode string
+		if _, err = electrumParam(params, 1, &mode); err != nil {
+			return nil, err
+		}
+		return s.estimateFee(blocks, !strings.EqualFold(mode, "ECONOMICAL"))
+	},
+	"blockchain.relayfee": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		return json.Number(s.chainParser.AmountToDecimalString(big.NewInt(electrumRelayFeeSat))), nil
+	},
+	"mempool.get_fee_histogram": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		return s.getFeeHistogram(), nil
+	},
+	"blockchain.scripthash.get_balance": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		addrDesc, _, err := s.scriptHashParam(params)
+		if err != nil {
+			return nil, err
+		}
+		return s.getBalance(addrDesc)
+	},
+	"blockchain.scripthash.get_history": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		addrDesc, _, err := s.scriptHashParam(params)
+		if err != nil {
+			return nil, err
+		}
+		return s.getHistory(addrDesc, true, true)
+	},
+	"blockchain.scripthash.get_mempool": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		addrDesc, _, err := s.scriptHashParam(params)
+		if err != nil {
+			return nil, err
+		}
+		return s.getHistory(addrDesc, false, true)
+	},
+	"blockchain.scripthash.listunspent": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		addrDesc, _, err := s.scriptHashParam(params)
+		if err != nil {
+			return nil, err
+		}
+		return s.listUnspent(addrDesc)
+	},
+	"blockchain.scripthash.subscribe": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		addrDesc, scriptHash, err := s.scriptHashParam(params)
+		if err != nil {
+			return nil, err
+		}
+		return s.subscribeScriptHash(c, scriptHash, addrDesc)
+	},
+	"blockchain.scripthash.unsubscribe": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		scriptHash, err := electrumParamScriptHash(params, 0)
+		if err != nil {
+			return nil, err
+		}
+		return s.unsubscribeScriptHash(c, scriptHash), nil
+	},
+	"blockchain.transaction.get": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		txid, err := electrumParamString(params, 0, "")
+		if err != nil {
+			return nil, err
+		}
+		verbose, err := electrumParamBool(params, 1, false)
+		if err != nil {
+			return nil, err
+		}
+		return s.getTransaction(txid, verbose)
+	},
+	"blockchain.transaction.get_merkle": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		txid, err := electrumParamString(params, 0, "")
+		if err != nil {
+			return nil, err
+		}
+		height, err := electrumParamInt(params, 1, -1)
+		if err != nil {
+			return nil, err
+		}
+		return s.getMerkle(txid, height)
+	},
+	"blockchain.transaction.broadcast": func(s *ElectrumServer, c *electrumClient, params []json.RawMessage) (interface{}, error) {
+		tx, err := electrumParamString(params, 0, "")
+		if err != nil {
+			return nil, err
+		}
+		txid, err := s.chain.SendRawTransaction(tx)
+		if err != nil {
+			return nil, &electrumError{Code: electrumErrBadRequest, Message: err.Error()}
+		}
+		return txid, nil
+	},
+}
+
+func electrumParam(params []json.RawMessage, i int, v interface{}) (bool, error) {
+	if i >= len(params) || string(params[i]) == "null" {
+		return false, nil
+	}
+	if err := json.Unmarshal(params[i], v); err != nil {
+		return false, newElectrumParamsError("invalid parameter %d", i)
+	}
+	return true, nil
+}
+
+// electrumParamString returns string parameter at index i, if def is empty, the parameter is required
+func electrumParamString(params []json.RawMessage, i int, def string) (string, error) {
+	var v string
+	found, err := electrumParam(params, i, &v)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		if def == "" {
+			return "", newElectrumParamsError("missing parameter %d", i)
+		}
+		return def, nil
+	}
+	return v, nil
+}
+
+// electrumParamInt returns non negative integer parameter at index i, if def is negative, the parameter is required
+func electrumParamInt(params []json.RawMessage, i int, def int) (int, error) {
+	var v int
+	found, err := electrumParam(params, i, &v)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		if def < 0 {
+			return 0, newElectrumParamsError("missing parameter %d", i)
+		}
+		return def, nil
+	}
+	if v < 0 {
+		return 0, newElectrumParamsError("invalid parameter %d", i)
+	}
+	return v, nil
+}
+
+func electrumParamBool(params []json.RawMessage, i int, def bool) (bool, error) {
+	var v bool
+	found, err := electrumParam(params, i, &v)
+	if err != nil || !found {
+		return def, err
+	}
+	return v, nil
+}
+
+// electrumParamScriptHash returns the script hash parameter normalized to lower case hex
+func electrumParamScriptHash(params []json.RawMessage, i int) (string, error) {
+	v, err := electrumParamString(params, i, "")
+	if err != nil {
+		return "", err
+	}
+	v = strings.ToLower(v)
+	if b, err := hex.DecodeString(v); err != nil || len(b) != db.ScriptHashLen {
+		return "", newElectrumParamsError("invalid script hash %s", v)
+	}
+	return v, nil
+}
+
+func checkElectrumCheckpoint(params []json.RawMessage, i int) error {
+	cp, err := electrumParamInt(params, i, 0)
+	if err != nil {
+		return err
+	}
+	if cp != 0 {
+		return &electrumError{Code: electrumErrBadRequest, Message: "checkpoint proofs are not supported"}
+	}
+	return nil
+}
+
+// ElectrumScriptHash returns the script hash of the address descriptor as used by the Electrum protocol,
+// the hex of the reversed SHA256 hash of the output script
+func ElectrumScriptHash(addrDesc bchain.AddressDescriptor) string {
+	return reverseHex(db.ScriptHash(addrDesc))
+}
+
+func reverseHex(b []byte) string {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return hex.EncodeToString(r)
+}
+
+// scriptHashParam returns the address descriptor and the script hash from the first parameter,
+// the address descriptor is nil if the script hash was never seen
+func (s *ElectrumServer) scriptHashParam(params []json.RawMessage) (bchain.AddressDescriptor, string, error) {
+	scriptHash, err := electrumParamScriptHash(params, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	addrDesc, err := s.getScriptHashAddrDesc(scriptHash)
+	return addrDesc, scriptHash, err
+}
+
+func (s *ElectrumServer) getScriptHashAddrDesc(scriptHash string) (bchain.AddressDescriptor, error) {
+	b, err := hex.DecodeString(scriptHash)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	addrDesc, err := s.db.GetAddrDescForScriptHash(b)
+	if err != nil || addrDesc != nil {
+		return addrDesc, err
+	}
+	// the address without confirmed transactions may have transactions in mempool
+	if addrDesc = s.mempool.GetAddrDescForScriptHash(b); addrDesc != nil {
+		return addrDesc, nil
+	}
+	// the address may be known only from the mempool transactions of a subscribed script hash
+	s.subscriptionsLock.Lock()
+	defer s.subscriptionsLock.Unlock()
+	if sub, found := s.scriptHashSubscriptions[scriptHash]; found {
+		return sub.addrDesc, nil
+	}
+	return nil, nil
+}
+
+func (s *ElectrumServer) features() interface{} {
+	return map[string]interface{}{
+		"genesis_hash":   s.block0hash,
+		"hosts":          map[string]interface{}{},
+		"protocol_min":   electrumProtocolVersion,
+		"protocol_max":   electrumProtocolVersion,
+		"pruning":        nil,
+		"server_version": "Blockbook " + common.GetVersionInfo().Version,
+		"hash_function":  "sha256",
+	}
+}
+
+// serializeBlockHeader serializes the block header in the Bitcoin format from the block info returned by the backend
+func serializeBlockHeader(bi *bchain.BlockInfo) ([]byte, error) {
+	header := make([]byte, 0, electrumHeaderLen)
+	appendUint32 := func(v uint32) {
+		header = append(header, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+	}
+	appendHash := func(h string) error {
+		b := make([]byte, 32)
+		if h != "" {
+			d, err := hex.DecodeString(h)
+			if err != nil || len(d) != 32 {
+				return errors.Errorf("Invalid hash %s", h)
+			}
+			for i := range d {
+				b[31-i] = d[i]
+			}
+		}
+		header = append(header, b...)
+		return nil
+	}
+	var version, nonce int64
+	var err error
+	if bi.Version != "" {
+		if version, err = bi.Version.Int64(); err != nil {
+			return nil, errors.Annotatef(err, "version")
+		}
+	}
+	if bi.Nonce != "" {
+		if nonce, err = bi.Nonce.Int64(); err != nil {
+			return nil, errors.Annotatef(err, "nonce")
+		}
+	}
+	var bits uint64
+	if bi.Bits != "" {
+		if _, err = fmt.Sscanf(bi.Bits, "%x", &bits); err != nil {
+			return nil, errors.Annotatef(err, "bits")
+		}
+	}
+	appendUint32(uint32(version))
+	if err = appendHash(bi.Prev); err != nil {
+		return nil, err
+	}
+	if err = appendHash(bi.MerkleRoot); err != nil {
+		return nil, err
+	}
+	appendUint32(uint32(bi.Time))
+	appendUint32(uint32(bits))
+	appendUint32(uint32(nonce))
+	return header, nil
+}
+
+// getHeaderByHash returns the serialized header of the block, the headers are cached
+func (s *ElectrumServer) getHeaderByHash(hash string) ([]byte, error) {
+	s.headerCacheLock.Lock()
+	header, found := s.headerCache[hash]
+	s.headerCacheLock.Unlock()
+	if found {
+		return header, nil
+	}
+	bi, err := s.chain.GetBlockInfo(hash)
+	if err != nil {
+		return nil, err
+	}
+	header, err = serializeBlockHeader(bi)
+	if err != nil {
+		return nil, errors.Annotatef(err, "block %s", hash)
+	}
+	s.headerCacheLock.Lock()
+	if len(s.headerCache) >= electrumMaxCachedHeaders {
+		s.headerCache = make(map[string][]byte)
+	}
+	s.headerCache[hash] = header
+	s.headerCacheLock.Unlock()
+	return header, nil
+}
+
+func (s *ElectrumServer) getHeaderByHeight(height uint32) ([]byte, error) {
+	hash, err := s.db.GetBlockHash(height)
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" {
+		return nil, &electrumError{Code: electrumErrBadRequest, Message: fmt.Sprintf("height %d out of range", height)}
+	}
+	return s.getHeaderByHash(hash)
+}
+
+func (s *ElectrumServer) getBlockHeader(height int) (interface{}, error) {
+	header, err := s.getHeaderByHeight(uint32(height))
+	if err != nil {
+		return nil, err
+	}
+	return hex.EncodeToString(header), nil
+}
+
+func (s *ElectrumServer) getBlockHeaders(start, count int) (interface{}, error) {
+	bestHeight, _, err := s.db.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if count > electrumMaxHeaders {
+		count = electrumMaxHeaders
+	}
+	if start+count > int(bestHeight)+1 {
+		count = int(bestHeight) + 1 - start
+	}
+	if count < 0 {
+		count = 0
+	}
+	headers := make([]byte, 0, count*electrumHeaderLen)
+	for h := start; h < start+count; h++ {
+		header, err := s.getHeaderByHeight(uint32(h))
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, header...)
+	}
+	return struct {
+		Count int    `json:"count"`
+		Hex   string `json:"hex"`
+		Max   int    `json:"max"`
+	}{
+		Count: count,
+		Hex:   hex.EncodeToString(headers),
+		Max:   electrumMaxHeaders,
+	}, nil
+}
+
+func (s *ElectrumServer) getBestHeader() (*electrumHeader, error) {
+	height, hash, err := s.db.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	header, err := s.getHeaderByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	return &electrumHeader{Height: height, Hex: hex.EncodeToString(header)}, nil
+}
+
+func (s *ElectrumServer) subscribeHeaders(c *electrumClient) (interface{}, error) {
+	header, err := s.getBestHeader()
+	if err != nil {
+		return nil, err
+	}
+	s.subscriptionsLock.Lock()
+	c.headers = true
+	s.subscriptionsLock.Unlock()
+	return header, nil
+}
+
+func (s *ElectrumServer) estimateFee(blocks int, conservative bool) (interface{}, error) {
+	if blocks == 0 {
+		blocks = 1
+	}
+	fee, err := s.api.BitcoinTypeEstimateFee(blocks, conservative)
+	if err != nil {
+		return nil, err
+	}
+	// -1 means that the fee could not be estimated
+	if fee.Sign() <= 0 {
+		return -1, nil
+	}
+	return json.Number(s.chainParser.AmountToDecimalString(&fee)), nil
+}
+
+// getFeeHistogram returns the pairs of fee rate and virtual size of the mempool transactions with at least this fee rate,
+// in the descending order of the fee rate
+func (s *ElectrumServer) getFeeHistogram() interface{} {
+	stats := s.mempool.GetStats()
+	if stats == nil {
+		return make([][2]int64, 0)
+	}
+	return api.FeeHistogram(stats)
+}
+
+func (s *ElectrumServer) getBalance(addrDesc bchain.AddressDescriptor) (interface{}, error) {
+	confirmed := new(big.Int)
+	unconfirmed := new(big.Int)
+	if addrDesc != nil {
+		utxos, err := s.api.GetAddrDescUtxo(addrDesc, false)
+		if err != nil {
+			return nil, err
+		}
+		for i := range utxos {
+			u := &utxos[i]
+			v := (*big.Int)(u.AmountSat)
+			if u.Confirmations > 0 {
+				confirmed.Add(confirmed, v)
+			} else {
+				unconfirmed.Add(unconfirmed, v)
+			}
+			// the outputs spent in mempool decrease the unconfirmed balance
+			if u.SpentTxID != "" {
+				unconfirmed.Sub(unconfirmed, v)
+			}
+		}
+	}
+	return struct {
+		Confirmed   *big.Int `json:"confirmed"`
+		Unconfirmed *big.Int `json:"unconfirmed"`
+	}{
+		Confirmed:   confirmed,
+		Unconfirmed: unconfirmed,
+	}, nil
+}
+
+func (s *ElectrumServer) listUnspent(addrDesc bchain.AddressDescriptor) (interface{}, error) {
+	unspent := make([]electrumUnspent, 0)
+	if addrDesc == nil {
+		return unspent, nil
+	}
+	utxos, err := s.api.GetAddrDescUtxo(addrDesc, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range utxos {
+		u := &utxos[i]
+		if u.SpentTxID != "" {
+			continue
+		}
+		unspent = append(unspent, electrumUnspent{
+			TxHash: u.Txid,
+			TxPos:  u.Vout,
+			Height: u.Height,
+			Value:  (*big.Int)(u.AmountSat),
+		})
+	}
+	sort.SliceStable(unspent, func(i, j int) bool {
+		hi, hj := unspent[i].Height, unspent[j].Height
+		// the mempool outputs are at the end
+		if hi == 0 || hj == 0 {
+			return hi != 0 && hj == 0
+		}
+		return hi < hj
+	})
+	return unspent, nil
+}
+
+// getHistory returns the confirmed transactions of the address from the oldest, followed by the mempool transactions sorted by txid,
+// the error errElectrumHistoryTooLarge is returned if the history has more than electrumMaxHistory transactions
+func (s *ElectrumServer) getHistory(addrDesc bchain.AddressDescriptor, confirmed, mempool bool) ([]electrumHistoryItem, error) {
+	history := make([]electrumHistoryItem, 0)
+	if addrDesc == nil {
+		return history, nil
+	}
+	inHistory := make(map[string]struct{})
+	if confirmed {
+		tooLarge := false
+		err := s.db.GetAddrDescTransactions(addrDesc, 0, ^uint32(0), func(txid string, height uint32, indexes []int32) error {
+			if len(history) >= electrumMaxHistory {
+				tooLarge = true
+				return &db.StopIteration{}
+			}
+			history = append(history, electrumHistoryItem{TxHash: txid, Height: int(height)})
+			inHistory[txid] = struct{}{}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if tooLarge {
+			return nil, errElectrumHistoryTooLarge
+		}
+		// the index returns the newest transactions first
+		for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+			history[i], history[j] = history[j], history[i]
+		}
+	}
+	if mempool {
+		outpoints, err := s.mempool.GetAddrDescTransactions(addrDesc)
+		if err != nil {
+			return nil, err
+		}
+		var unconfirmed []electrumHistoryItem
+		for _, o := range outpoints {
+			if _, found := inHistory[o.Txid]; found {
+				continue
+			}
+			if len(history)+len(unconfirmed) >= electrumMaxHistory {
+				return nil, errElectrumHistoryTooLarge
+			}
+			inHistory[o.Txid] = struct{}{}
+			unconfirmed = append(unconfirmed, s.getMempoolHistoryItem(o.Txid))
+		}
+		sort.Slice(unconfirmed, func(i, j int) bool { return unconfirmed[i].TxHash < unconfirmed[j].TxHash })
+		history = append(history, unconfirmed...)
+	}
+	return history, nil
+}
+
+// getMempoolHistoryItem returns the mempool transaction with its fee, the height is -1 if the transaction spends unconfirmed outputs;
+// the fee and the spent outputs are taken from the mempool entry, the transaction is not fetched from the backend
+func (s *ElectrumServer) getMempoolHistoryItem(txid string) electrumHistoryItem {
+	item := electrumHistoryItem{TxHash: txid}
+	entry, inputs, found := s.mempool.GetTxEntry(txid)
+	if !found {
+		// the transaction left the mempool in the meantime
+		return item
+	}
+	if entry.FeeSat >= 0 {
+		item.Fee = big.NewInt(entry.FeeSat)
+	}
+	for _, o := range inputs {
+		if s.mempool.GetTransactionTime(o.Txid) != 0 {
+			item.Height = -1
+			break
+		}
+	}
+	return item
+}
+
+// electrumStatus returns the status of the script hash computed from its history, empty string for no history
+func electrumStatus(history []electrumHistoryItem) string {
+	if len(history) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for i := range history {
+		fmt.Fprintf(h, "%s:%d:", history[i].TxHash, history[i].Height)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func electrumStatusValue(status string) interface{} {
+	if status == "" {
+		return nil
+	}
+	return status
+}
+
+func (s *ElectrumServer) subscribeScriptHash(c *electrumClient, scriptHash string, addrDesc bchain.AddressDescriptor) (interface{}, error) {
+	history, err := s.getHistory(addrDesc, true, true)
+	if err != nil {
+		return nil, err
+	}
+	status := electrumStatus(history)
+	s.subscriptionsLock.Lock()
+	defer s.subscriptionsLock.Unlock()
+	if _, found := c.scriptHashes[scriptHash]; !found && len(c.scriptHashes) >= electrumMaxSubscriptions {
+		return nil, &electrumError{Code: electrumErrBadRequest, Message: "too many subscriptions"}
+	}
+	sub, found := s.scriptHashSubscriptions[scriptHash]
+	if !found {
+		sub = &electrumSubscription{
+			addrDesc: addrDesc,
+			clients:  make(map[*electrumClient]struct{}),
+		}
+		s.scriptHashSubscriptions[scriptHash] = sub
+	}
+	sub.status = status
+	sub.clients[c] = struct{}{}
+	c.scriptHashes[scriptHash] = struct{}{}
+	return electrumStatusValue(status), nil
+}
+
+// doUnsubscribeScriptHash removes the subscription of the client, s.subscriptionsLock must be held
+func (s *ElectrumServer) doUnsubscribeScriptHash(c *electrumClient, scriptHash string) bool {
+	if _, found := c.scriptHashes[scriptHash]; !found {
+		return false
+	}
+	delete(c.scriptHashes, scriptHash)
+	if sub, found := s.scriptHashSubscriptions[scriptHash]; found {
+		delete(sub.clients, c)
+		if len(sub.clients) == 0 {
+			delete(s.scriptHashSubscriptions, scriptHash)
+		}
+	}
+	return true
+}
+
+func (s *ElectrumServer) unsubscribeScriptHash(c *electrumClient, scriptHash string) bool {
+	s.subscriptionsLock.Lock()
+	defer s.subscriptionsLock.Unlock()
+	return s.doUnsubscribeScriptHash(c, scriptHash)
+}
+
+func (s *ElectrumServer) getTransaction(txid string, verbose bool) (interface{}, error) {
+	if _, err := hex.DecodeString(txid); err != nil || len(txid) != 64 {
+		return nil, newElectrumParamsError("invalid txid %s", txid)
+	}
+	data, err := s.chain.GetTransactionSpecific(&bchain.Tx{Txid: txid})
+	if err != nil {
+		if err == bchain.ErrTxNotFound {
+			return nil, &electrumError{Code: electrumErrBadRequest, Message: fmt.Sprintf("transaction %s not found", txid)}
+		}
+		return nil, err
+	}
+	if verbose {
+		return data, nil
+	}
+	var tx struct {
+		Hex string `json:"hex"`
+	}
+	if err = json.Unmarshal(data, &tx); err != nil {
+		return nil, err
+	}
+	if tx.Hex == "" {
+		return nil, errors.Errorf("Transaction %s hex not available", txid)
+	}
+	return tx.Hex, nil
+}
+
+func (s *ElectrumServer) getMerkle(txid string, height int) (interface{}, error) {
+	hash, err := s.db.GetBlockHash(uint32(height))
+	if err != nil {
+		return nil, err
+	}
+	if hash == "" {
+		return nil, &electrumError{Code: electrumErrBadRequest, Message: fmt.Sprintf("height %d out of range", height)}
+	}
+	bi, err := s.chain.GetBlockInfo(hash)
+	if err != nil {
+		return nil, err
+	}
+	pos := -1
+	for i := range bi.Txids {
+		if bi.Txids[i] == txid {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return nil, &electrumError{Code: electrumErrBadRequest, Message: fmt.Sprintf("transaction %s not in block at height %d", txid, height)}
+	}
+	merkle, err := merkleBranch(bi.Txids, pos)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		BlockHeight int      `json:"block_height"`
+		Merkle      []string `json:"merkle"`
+		Pos         int      `json:"pos"`
+	}{
+		BlockHeight: height,
+		Merkle:      merkle,
+		Pos:         pos,
+	}, nil
+}
+
+// merkleBranch returns the hashes needed to compute the merkle root from the transaction at position pos
+func merkleBranch(txids []string, pos int) ([]string, error) {
+	hashes := make([][]byte, len(txids))
+	for i, txid := range txids {
+		b, err := hex.DecodeString(txid)
+		if err != nil || len(b) != 32 {
+			return nil, errors.Errorf("Invalid txid %s", txid)
+		}
+		for l, r := 0, len(b)-1; l < r; l, r = l+1, r-1 {
+			b[l], b[r] = b[r], b[l]
+		}
+		hashes[i] = b
+	}
+	branch := make([]string, 0)
+	for len(hashes) > 1 {
+		if len(hashes)%2 == 1 {
+			hashes = append(hashes, hashes[len(hashes)-1])
+		}
+		branch = append(branch, reverseHex(hashes[pos^1]))
+		next := make([][]byte, len(hashes)/2)
+		for i := range next {
+			h := sha256.Sum256(append(append(make([]byte, 0, 64), hashes[2*i]...), hashes[2*i+1]...))
+			h = sha256.Sum256(h[:])
+			next[i] = h[:]
+		}
+		hashes = next
+		pos >>= 1
+	}
+	return branch, nil
+}
+
+// updateScriptHashes recomputes the status of the subscribed script hashes and notifies the clients about the changes
+func (s *ElectrumServer) updateScriptHashes(scriptHashes map[string]bchain.AddressDescriptor) {
+	s.db.BeginRead()
+	defer s.db.EndRead()
+	for scriptHash, addrDesc := range scriptHashes {
+		history, err := s.getHistory(addrDesc, true, true)
+		if err != nil {
+			glog.Error("electrum getHistory ", scriptHash, ": ", err)
+			continue
+		}
+		status := electrumStatus(history)
+		s.subscriptionsLock.Lock()
+		sub, found := s.scriptHashSubscriptions[scriptHash]
+		if found && sub.status != status {
+			sub.status = status
+			for c := range sub.clients {
+				c.notify("blockchain.scripthash.subscribe", scriptHash, electrumStatusValue(status))
+			}
+			glog.V(1).Info("electrum broadcasting status of ", scriptHash, " to ", len(sub.clients), " clients")
+		}
+		s.subscriptionsLock.Unlock()
+	}
+}
+
+// getSubscribedScriptHashes returns the subscribed script hashes of the address descriptors,
+// the address descriptors of the subscriptions not yet found in the index are set
+func (s *ElectrumServer) getSubscribedScriptHashes(addrDescs []bchain.AddressDescriptor) map[string]bchain.AddressDescriptor {
+	subscribed := make(map[string]bchain.AddressDescriptor)
+	s.subscriptionsLock.Lock()
+	defer s.subscriptionsLock.Unlock()
+	if len(s.scriptHashSubscriptions) == 0 {
+		return subscribed
+	}
+	for _, addrDesc := range addrDescs {
+		if len(addrDesc) == 0 {
+			continue
+		}
+		scriptHash := ElectrumScriptHash(addrDesc)
+		if sub, found := s.scriptHashSubscriptions[scriptHash]; found {
+			if sub.addrDesc == nil {
+				sub.addrDesc = addrDesc
+			}
+			subscribed[scriptHash] = addrDesc
+		}
+	}
+	return subscribed
+}
+
+func (s *ElectrumServer) onNewBlockAsync(hash string, height uint32) {
+	if header, err := s.getHeaderByHash(hash); err != nil {
+		glog.Error("electrum header of block ", height, " ", hash, ": ", err)
+	} else {
+		h := &electrumHeader{Height: height, Hex: hex.EncodeToString(header)}
+		s.subscriptionsLock.Lock()
+		s.clientsLock.Lock()
+		var count int
+		for c := range s.clients {
+			if c.headers {
+				c.notify("blockchain.headers.subscribe", h)
+				count++
+			}
+		}
+		s.clientsLock.Unlock()
+		s.subscriptionsLock.Unlock()
+		glog.Info("electrum broadcasting new block ", height, " ", hash, " to ", count, " clients")
+	}
+	s.subscriptionsLock.Lock()
+	subscriptions := len(s.scriptHashSubscriptions)
+	s.subscriptionsLock.Unlock()
+	if subscriptions == 0 {
+		return
+	}
+	// update the subscriptions of the addresses in the transactions of the block
+	bi, err := s.chain.GetBlockInfo(hash)
+	if err != nil {
+		glog.Error("electrum GetBlockInfo ", hash, ": ", err)
+		return
+	}
+	var addrDescs []bchain.AddressDescriptor
+	s.db.BeginRead()
+	for _, txid := range bi.Txids {
+		ta, err := s.db.GetTxAddresses(txid)
+		if err != nil || ta == nil {
+			continue
+		}
+		for i := range ta.Inputs {
+			addrDescs = append(addrDescs, ta.Inputs[i].AddrDesc)
+		}
+		for i := range ta.Outputs {
+			addrDescs = append(addrDescs, ta.Outputs[i].AddrDesc)
+		}
+	}
+	s.db.EndRead()
+	if subscribed := s.getSubscribedScriptHashes(addrDescs); len(subscribed) > 0 {
+		s.updateScriptHashes(subscribed)
+	}
+}
+
+// OnNewBlock is a callback that notifies the subscribed clients about the new block and about the changed statuses of the addresses in it
+func (s *ElectrumServer) OnNewBlock(hash string, height uint32) {
+	go s.onNewBlockAsync(hash, height)
+}
+
+// OnNewTx is a callback that notifies the subscribed clients about the changed statuses of the addresses in a mempool transaction
+func (s *ElectrumServer) OnNewTx(tx *bchain.MempoolTx) {
+	addrDescs := make([]bchain.AddressDescriptor, 0, len(tx.Vin)+len(tx.Vout))
+	for i := range tx.Vin {
+		addrDescs = append(addrDescs, tx.Vin[i].AddrDesc)
+	}
+	for i := range tx.Vout {
+		if addrDesc, err := s.chainParser.GetAddrDescFromVout(&tx.Vout[i]); err == nil {
+			addrDescs = append(addrDescs, addrDesc)
+		}
+	}
+	if subscribed := s.getSubscribedScriptHashes(addrDescs); len(subscribed) > 0 {
+		go s.updateScriptHashes(subscribed)
+	}
+}
@@ -0,0 +1,391 @@
+package server
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+)
+
+// number of blocks returned by the blocks request
+const esploraBlocks = 10
+
+// errEsploraRouteNotFound is returned for the paths which are not part of the Esplora API
+var errEsploraRouteNotFound = &api.EsploraNotFoundError{Text: "Not found"}
+
+// esploraRaw is binary data returned by the Esplora API as application/octet-stream
+type esploraRaw []byte
+
+// ConnectEsploraInterface maps the Esplora compatible REST API to the path esplora/ of the public interface,
+// it is supported only for Bitcoin type coins
+func (s *PublicServer) ConnectEsploraInterface() {
+	serveMux := s.https.Handler.(*http.ServeMux)
+	_, path := splitBinding(s.binding)
+	serveMux.HandleFunc(path+"esplora/", s.esploraHandler(path+"esplora/"))
+}
+
+// esploraHandler serves the Esplora requests, the results are returned as JSON, plain text or binary data
+// and the errors as plain text with the HTTP status code, as in Esplora
+func (s *PublicServer) esploraHandler(prefix string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var data interface{}
+		var err error
+		s.db.BeginRead()
+		defer s.db.EndRead()
+		defer func() {
+			if e := recover(); e != nil {
+				glog.Error("esplora recovered from panic: ", e)
+				debug.PrintStack()
+				data, err = nil, fmt.Errorf("recovered from panic %v", e)
+			}
+			s.writeEsploraResponse(w, r, data, err)
+			s.metrics.ExplorerPendingRequests.With((common.Labels{"method": "esplora"})).Dec()
+		}()
+		s.metrics.ExplorerPendingRequests.With((common.Labels{"method": "esplora"})).Inc()
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-esplora"}).Inc()
+		p := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		data, err = s.esploraRoute(r, strings.Split(p, "/"))
+	}
+}
+
+func (s *PublicServer) writeEsploraResponse(w http.ResponseWriter, r *http.Request, data interface{}, err error) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if err != nil {
+		status := http.StatusInternalServerError
+		text := "Internal server error"
+		switch e := err.(type) {
+		case *api.EsploraNotFoundError:
+			status, text = http.StatusNotFound, e.Text
+		case *api.APIError:
+			if e.Public {
+				status, text = http.StatusBadRequest, e.Text
+			}
+		}
+		if status == http.StatusInternalServerError {
+			glog.Error("esplora ", r.URL.Path, " error: ", err)
+			if s.debug {
+				text = fmt.Sprint("Internal server error: ", err)
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(status)
+		w.Write([]byte(text))
+		return
+	}
+	switch d := data.(type) {
+	case string:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(d))
+	case esploraRaw:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(d)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		e := json.NewEncoder(w)
+		// Esplora does not escape the characters <, > and & in the script asm
+		e.SetEscapeHTML(false)
+		if err := e.Encode(data); err != nil {
+			glog.Warning("json encode ", err)
+		}
+	}
+}
+
+// esploraRoute dispatches the request according to the path split to segments
+func (s *PublicServer) esploraRoute(r *http.Request, p []string) (interface{}, error) {
+	if s.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return nil, api.NewAPIError("Not supported", true)
+	}
+	if r.Method == http.MethodPost {
+		if len(p) == 1 && p[0] == "tx" {
+			return s.esploraSendTx(r)
+		}
+		return nil, errEsploraRouteNotFound
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return nil, errEsploraRouteNotFound
+	}
+	switch p[0] {
+	case "tx":
+		if len(p) >= 2 {
+			return s.esploraTx(p[1], p[2:])
+		}
+	case "address", "scripthash":
+		if len(p) >= 2 {
+			return s.esploraAddress(p[0] == "scripthash", p[1], p[2:])
+		}
+	case "block":
+		if len(p) >= 2 {
+			return s.esploraBlock(p[1], p[2:])
+		}
+	case "block-height":
+		if len(p) == 2 {
+			height, err := strconv.ParseUint(p[1], 10, 32)
+			if err != nil {
+				return nil, api.NewAPIError("Invalid height", true)
+			}
+			return s.api.EsploraGetBlockHash(uint32(height))
+		}
+	case "blocks":
+		return s.esploraBlocks(p[1:])
+	case "mempool":
+		if len(p) == 1 {
+			return s.api.EsploraGetMempool()
+		}
+		if len(p) == 2 && p[1] == "txids" {
+			return s.api.EsploraGetMempoolTxids()
+		}
+	case "fee-estimates":
+		if len(p) == 1 {
+			return s.api.EsploraGetFeeEstimates()
+		}
+	}
+	return nil, errEsploraRouteNotFound
+}
+
+func checkEsploraHash(hash string) error {
+	if b, err := hex.DecodeString(hash); err != nil || len(b) != 32 {
+		return api.NewAPIError("Invalid hex string", true)
+	}
+	return nil
+}
+
+func (s *PublicServer) esploraTx(txid string, p []string) (interface{}, error) {
+	if err := checkEsploraHash(txid); err != nil {
+		return nil, err
+	}
+	if len(p) == 0 {
+		return s.api.EsploraGetTransaction(txid)
+	}
+	switch {
+	case len(p) == 1 && p[0] == "status":
+		return s.api.EsploraGetTransactionStatus(txid)
+	case len(p) == 1 && (p[0] == "hex" || p[0] == "raw"):
+		h, err := s.api.EsploraGetTransactionHex(txid)
+		if err != nil || p[0] == "hex" {
+			return h, err
+		}
+		b, err := hex.DecodeString(h)
+		return esploraRaw(b), err
+	case len(p) == 1 && p[0] == "merkle-proof":
+		return s.esploraMerkleProof(txid)
+	case len(p) == 1 && p[0] == "outspends":
+		return s.api.EsploraGetOutspends(txid)
+	case len(p) == 2 && p[0] == "outspend":
+		vout, err := strconv.Atoi(p[1])
+		if err != nil || vout < 0 {
+			return nil, api.NewAPIError("Invalid output index", true)
+		}
+		outspends, err := s.api.EsploraGetOutspends(txid)
+		if err != nil {
+			return nil, err
+		}
+		if vout >= len(outspends) {
+			return nil, &api.EsploraNotFoundError{Text: "Output not found"}
+		}
+		return outspends[vout], nil
+	}
+	return nil, errEsploraRouteNotFound
+}
+
+// esploraMerkleProof returns the merkle proof of the confirmed transaction in the same format as the Electrum protocol
+func (s *PublicServer) esploraMerkleProof(txid string) (interface{}, error) {
+	status, err := s.api.EsploraGetTransactionStatus(txid)
+	if err != nil {
+		return nil, err
+	}
+	if !status.Confirmed {
+		return nil, &api.EsploraNotFoundError{Text: "Transaction not confirmed"}
+	}
+	txids, err := s.api.EsploraGetBlockTxids(status.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	pos := -1
+	for i := range txids {
+		if txids[i] == txid {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return nil, &api.EsploraNotFoundError{Text: "Transaction not found in block"}
+	}
+	merkle, err := merkleBranch(txids, pos)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		BlockHeight uint32   `json:"block_height"`
+		Merkle      []string `json:"merkle"`
+		Pos         int      `json:"pos"`
+	}{
+		BlockHeight: *status.BlockHeight,
+		Merkle:      merkle,
+		Pos:         pos,
+	}, nil
+}
+
+func (s *PublicServer) esploraSendTx(r *http.Request) (interface{}, error) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(data) == 0 {
+		return nil, api.NewAPIError("Missing tx blob", true)
+	}
+	txid, err := s.chain.SendRawTransaction(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, api.NewAPIError(err.Error(), true)
+	}
+	return txid, nil
+}
+
+// esploraAddress serves the requests for an address or for a script hash
+func (s *PublicServer) esploraAddress(scriptHash bool, param string, p []string) (interface{}, error) {
+	var addrDesc bchain.AddressDescriptor
+	var err error
+	if scriptHash {
+		param = strings.ToLower(param)
+		addrDesc, err = s.api.EsploraGetAddrDescForScriptHash(param)
+	} else {
+		addrDesc, err = s.api.EsploraGetAddrDescForAddress(param)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(p) == 0:
+		a, err := s.api.EsploraGetAddress(addrDesc)
+		if err != nil {
+			return nil, err
+		}
+		if scriptHash {
+			a.ScriptHash = param
+		} else {
+			a.Address = param
+		}
+		return a, nil
+	case len(p) == 1 && p[0] == "txs":
+		return s.api.EsploraGetAddressTxs(addrDesc, true, true, "")
+	case len(p) == 2 && p[0] == "txs" && p[1] == "mempool":
+		return s.api.EsploraGetAddressTxs(addrDesc, true, false, "")
+	case (len(p) == 2 || len(p) == 3) && p[0] == "txs" && p[1] == "chain":
+		var lastSeenTxid string
+		if len(p) == 3 {
+			lastSeenTxid = p[2]
+			if err := checkEsploraHash(lastSeenTxid); err != nil {
+				return nil, err
+			}
+		}
+		return s.api.EsploraGetAddressTxs(addrDesc, false, true, lastSeenTxid)
+	case len(p) == 1 && p[0] == "utxo":
+		return s.api.EsploraGetAddressUtxo(addrDesc)
+	}
+	return nil, errEsploraRouteNotFound
+}
+
+func (s *PublicServer) esploraBlock(hash string, p []string) (interface{}, error) {
+	if err := checkEsploraHash(hash); err != nil {
+		return nil, err
+	}
+	if len(p) == 0 {
+		return s.api.EsploraGetBlock(hash)
+	}
+	switch {
+	case len(p) == 1 && p[0] == "status":
+		return s.api.EsploraGetBlockStatus(hash)
+	case len(p) == 1 && p[0] == "txids":
+		return s.api.EsploraGetBlockTxids(hash)
+	case (len(p) == 1 || len(p) == 2) && p[0] == "txs":
+		var start int
+		if len(p) == 2 {
+			var err error
+			if start, err = strconv.Atoi(p[1]); err != nil {
+				return nil, api.NewAPIError("Invalid start index", true)
+			}
+		}
+		return s.api.EsploraGetBlockTxs(hash, start)
+	case len(p) == 2 && p[0] == "txid":
+		index, err := strconv.Atoi(p[1])
+		if err != nil {
+			return nil, api.NewAPIError("Invalid transaction index", true)
+		}
+		return s.api.EsploraGetBlockTxid(hash, index)
+	case len(p) == 1 && p[0] == "header":
+		bi, err := s.chain.GetBlockInfo(hash)
+		if err != nil {
+			if err == bchain.ErrBlockNotFound {
+				return nil, &api.EsploraNotFoundError{Text: "Block not found"}
+			}
+			return nil, err
+		}
+		header, err := serializeBlockHeader(bi)
+		if err != nil {
+			return nil, err
+		}
+		return hex.EncodeToString(header), nil
+	case len(p) == 1 && p[0] == "raw":
+		h, err := s.chain.GetBlockRaw(hash)
+		if err != nil {
+			if err == bchain.ErrBlockNotFound {
+				return nil, &api.EsploraNotFoundError{Text: "Block not found"}
+			}
+			return nil, err
+		}
+		b, err := hex.DecodeString(h)
+		return esploraRaw(b), err
+	}
+	return nil, errEsploraRouteNotFound
+}
+
+// esploraBlocks returns the tip height or hash or esploraBlocks blocks down from the given height or from the tip
+func (s *PublicServer) esploraBlocks(p []string) (interface{}, error) {
+	bestHeight, bestHash, err := s.db.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(p) == 2 && p[0] == "tip" {
+		switch p[1] {
+		case "height":
+			return strconv.FormatUint(uint64(bestHeight), 10), nil
+		case "hash":
+			return bestHash, nil
+		}
+		return nil, errEsploraRouteNotFound
+	}
+	if len(p) > 1 {
+		return nil, errEsploraRouteNotFound
+	}
+	start := bestHeight
+	if len(p) == 1 && p[0] != "" {
+		h, err := strconv.ParseUint(p[0], 10, 32)
+		if err != nil {
+			return nil, api.NewAPIError("Invalid height", true)
+		}
+		if uint32(h) < start {
+			start = uint32(h)
+		}
+	}
+	blocks := make([]*api.EsploraBlock, 0, esploraBlocks)
+	for h := int64(start); h >= 0 && h > int64(start)-esploraBlocks; h-- {
+		hash, err := s.db.GetBlockHash(uint32(h))
+		if err != nil {
+			return nil, err
+		}
+		if hash == "" {
+			break
+		}
+		b, err := s.api.EsploraGetBlock(hash)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
@@ -0,0 +1,649 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/trezor/blockbook/api"
+)
+
+const (
+	// graphqlMaxDepth is the maximum depth of the nested fields of a query
+	graphqlMaxDepth = 8
+	// graphqlMaxParallelism is the maximum number of resolvers of one query running in parallel
+	graphqlMaxParallelism = 8
+	// graphqlMaxCost is the maximum cost of a query, each call of the worker costs 1 and each loaded transaction costs 1
+	graphqlMaxCost = 1000
+	// graphqlMaxPageSize is the maximum number of transactions on a page of address or block
+	graphqlMaxPageSize = 100
+	// graphqlMaxRequestSize is the maximum size of the body of the POST request
+	graphqlMaxRequestSize = 1 << 16
+)
+
+const graphqlSchema = `
+schema {
+	query: Query
+}
+
+type Query {
+	# address or xpub, tokens is derived, used or nonzero, the same as in the websocket interface
+	address(descriptor: String!, page: Int = 1, pageSize: Int = 25, tokens: String = "", fromHeight: Int = 0, toHeight: Int = 0, contract: String = "", gap: Int = 0): Address
+	transaction(txid: String!): Tx
+	# block height or block hash
+	block(id: String!, page: Int = 1, pageSize: Int = 25): Block
+	# address or xpub
+	utxos(descriptor: String!, confirmed: Boolean = false, gap: Int = 0): [Utxo!]!
+}
+
+type Address {
+	page: Int!
+	totalPages: Int!
+	itemsOnPage: Int!
+	address: String!
+	balance: String!
+	totalReceived: String
+	totalSent: String
+	unconfirmedBalance: String!
+	unconfirmedTxs: Int!
+	txs: Int!
+	nonTokenTxs: Int!
+	txids: [String!]!
+	transactions: [Tx!]!
+	nonce: String
+	usedTokens: Int!
+	tokens: [Token!]!
+	utxos(confirmed: Boolean = false): [Utxo!]!
+}
+
+type Token {
+	type: String!
+	name: String!
+	path: String
+	contract: String
+	transfers: Int!
+	symbol: String
+	decimals: Int!
+	balance: String
+	totalReceived: String
+	totalSent: String
+}
+
+type TokenTransfer {
+	type: String!
+	from: String!
+	to: String!
+	token: String!
+	name: String!
+	symbol: String!
+	decimals: Int!
+	value: String
+}
+
+type Vin {
+	txid: String
+	vout: Int!
+	# Float because the sequence does not fit into the 32 bit Int
+	sequence: Float!
+	n: Int!
+	addresses: [String!]!
+	isAddress: Boolean!
+	value: String
+	hex: String
+	asm: String
+	coinbase: String
+	# the transaction spent by this input
+	prevTx: Tx
+}
+
+type Vout {
+	value: String
+	n: Int!
+	spent: Boolean!
+	spentTxId: String
+	spentIndex: Int!
+	spentHeight: Int!
+	hex: String
+	asm: String
+	addresses: [String!]!
+	isAddress: Boolean!
+	type: String
+}
+
+type Tx {
+	txid: String!
+	version: Int!
+	lockTime: Float!
+	vin: [Vin!]!
+	vout: [Vout!]!
+	blockHash: String
+	blockHeight: Int!
+	confirmations: Int!
+	blockTime: Float!
+	size: Int!
+	vsize: Int!
+	weight: Int!
+	value: String
+	valueIn: String
+	fees: String
+	hex: String
+	rbf: Boolean!
+	tokenTransfers: [TokenTransfer!]!
+}
+
+type Utxo {
+	txid: String!
+	vout: Int!
+	value: String
+	height: Int!
+	confirmations: Int!
+	address: String
+	path: String
+	lockTime: Float!
+	coinbase: Boolean!
+	spentTxId: String
+	tx: Tx
+}
+
+type Block {
+	page: Int!
+	totalPages: Int!
+	itemsOnPage: Int!
+	hash: String!
+	previousBlockHash: String
+	nextBlockHash: String
+	height: Int!
+	confirmations: Int!
+	size: Int!
+	time: Float!
+	version: String!
+	merkleRoot: String!
+	nonce: String!
+	bits: String!
+	difficulty: String!
+	txCount: Int!
+	transactions: [Tx!]!
+}
+`
+
+type graphqlRequestKey struct{}
+
+type graphqlTxEntry struct {
+	done chan struct{}
+	tx   *api.Tx
+	err  error
+}
+
+// graphqlRequest holds the state of one query, the transactions loaded by the query
+// and the cost of the query spent so far
+type graphqlRequest struct {
+	s    *PublicServer
+	cost int32
+	mux  sync.Mutex
+	txs  map[string]*graphqlTxEntry
+}
+
+func (r *graphqlRequest) charge(cost int32) error {
+	if atomic.AddInt32(&r.cost, cost) > graphqlMaxCost {
+		return api.NewAPIError("Query complexity limit exceeded", true)
+	}
+	return nil
+}
+
+// getTx returns the transaction loaded through the tx cache, each transaction is loaded only once per query,
+// the concurrent resolvers requesting the same transaction wait for the first lookup
+func (r *graphqlRequest) getTx(txid string) (*api.Tx, error) {
+	r.mux.Lock()
+	e, found := r.txs[txid]
+	if found {
+		r.mux.Unlock()
+		<-e.done
+		return e.tx, e.err
+	}
+	e = &graphqlTxEntry{done: make(chan struct{})}
+	r.txs[txid] = e
+	r.mux.Unlock()
+	if e.err = r.charge(1); e.err == nil {
+		e.tx, e.err = r.s.api.GetTransaction(txid, false, false)
+	}
+	close(e.done)
+	return e.tx, e.err
+}
+
+// addTxs stores the transactions returned by the worker, so that they are not loaded again
+func (r *graphqlRequest) addTxs(txs []*api.Tx) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for _, tx := range txs {
+		if _, found := r.txs[tx.Txid]; !found {
+			e := &graphqlTxEntry{done: make(chan struct{}), tx: tx}
+			close(e.done)
+			r.txs[tx.Txid] = e
+		}
+	}
+}
+
+func (r *graphqlRequest) txResolvers(txs []*api.Tx) []*graphqlTx {
+	rv := make([]*graphqlTx, len(txs))
+	for i := range txs {
+		rv[i] = &graphqlTx{req: r, tx: txs[i]}
+	}
+	return rv
+}
+
+func (r *graphqlRequest) getUtxos(descriptor string, confirmed bool, gap int) ([]*graphqlUtxo, error) {
+	if err := r.charge(1); err != nil {
+		return nil, err
+	}
+	utxos, err := r.s.api.GetXpubUtxo(descriptor, confirmed, true, gap)
+	if err != nil {
+		utxos, err = r.s.api.GetAddressUtxo(descriptor, confirmed, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rv := make([]*graphqlUtxo, len(utxos))
+	for i := range utxos {
+		rv[i] = &graphqlUtxo{req: r, u: &utxos[i]}
+	}
+	return rv, nil
+}
+
+func graphqlAmount(a *api.Amount) *string {
+	if a == nil {
+		return nil
+	}
+	s := a.String()
+	return &s
+}
+
+func graphqlString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func graphqlPageSize(pageSize int32) int {
+	if pageSize <= 0 {
+		return txsOnPage
+	}
+	if pageSize > graphqlMaxPageSize {
+		return graphqlMaxPageSize
+	}
+	return int(pageSize)
+}
+
+type graphqlLogger struct{}
+
+func (graphqlLogger) LogPanic(ctx context.Context, value interface{}) {
+	glog.Error("graphql recovered from panic: ", value)
+}
+
+func newGraphqlSchema(s *PublicServer) (*graphql.Schema, error) {
+	return graphql.ParseSchema(graphqlSchema, &graphqlResolver{s: s},
+		graphql.MaxDepth(graphqlMaxDepth),
+		graphql.MaxParallelism(graphqlMaxParallelism),
+		graphql.Logger(graphqlLogger{}),
+	)
+}
+
+// apiGraphQL executes the GraphQL query passed either in the JSON body of the POST request
+// or in the parameters of the GET request
+func (s *PublicServer) apiGraphQL(r *http.Request, apiVersion int) (interface{}, error) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if r.Method == http.MethodPost {
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, graphqlMaxRequestSize+1))
+		if err != nil {
+			return nil, api.NewAPIError("Invalid request: "+err.Error(), true)
+		}
+		if len(body) > graphqlMaxRequestSize {
+			return nil, api.NewAPIError("Request too large", true)
+		}
+		if err = json.Unmarshal(body, &params); err != nil {
+			return nil, api.NewAPIError("Invalid request: "+err.Error(), true)
+		}
+	} else {
+		q := r.URL.Query()
+		params.Query = q.Get("query")
+		params.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &params.Variables); err != nil {
+				return nil, api.NewAPIError("Invalid variables: "+err.Error(), true)
+			}
+		}
+	}
+	if params.Query == "" {
+		return nil, api.NewAPIError("Missing query", true)
+	}
+	req := &graphqlRequest{s: s, txs: make(map[string]*graphqlTxEntry)}
+	ctx := context.WithValue(r.Context(), graphqlRequestKey{}, req)
+	return s.graphqlSchema.Exec(ctx, params.Query, params.OperationName, params.Variables), nil
+}
+
+type graphqlResolver struct {
+	s *PublicServer
+}
+
+func requestFromContext(ctx context.Context) *graphqlRequest {
+	return ctx.Value(graphqlRequestKey{}).(*graphqlRequest)
+}
+
+// Address resolves the address or xpub, the transactions are returned only as txids
+// and are loaded only if the query asks for them
+func (gr *graphqlResolver) Address(ctx context.Context, args struct {
+	Descriptor string
+	Page       int32
+	PageSize   int32
+	Tokens     string
+	FromHeight int32
+	ToHeight   int32
+	Contract   string
+	Gap        int32
+}) (*graphqlAddress, error) {
+	r := requestFromContext(ctx)
+	if err := r.charge(1); err != nil {
+		return nil, err
+	}
+	req := &accountInfoReq{
+		Descriptor:     args.Descriptor,
+		Details:        "txids",
+		Tokens:         args.Tokens,
+		Page:           int(args.Page),
+		PageSize:       graphqlPageSize(args.PageSize),
+		FromHeight:     int(args.FromHeight),
+		ToHeight:       int(args.ToHeight),
+		ContractFilter: args.Contract,
+		Gap:            int(args.Gap),
+	}
+	opt, filter := accountInfoReqOptions(req)
+	a, err := gr.s.api.GetXpubAddress(req.Descriptor, req.Page, req.PageSize, opt, filter, req.Gap)
+	if err != nil {
+		a, err = gr.s.api.GetAddress(req.Descriptor, req.Page, req.PageSize, opt, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &graphqlAddress{req: r, a: a, descriptor: args.Descriptor, gap: req.Gap}, nil
+}
+
+// Transaction resolves the transaction
+func (gr *graphqlResolver) Transaction(ctx context.Context, args struct{ Txid string }) (*graphqlTx, error) {
+	r := requestFromContext(ctx)
+	tx, err := r.getTx(args.Txid)
+	if err != nil {
+		return nil, err
+	}
+	return &graphqlTx{req: r, tx: tx}, nil
+}
+
+// Block resolves the block with a page of its transactions
+func (gr *graphqlResolver) Block(ctx context.Context, args struct {
+	ID       string
+	Page     int32
+	PageSize int32
+}) (*graphqlBlock, error) {
+	r := requestFromContext(ctx)
+	pageSize := graphqlPageSize(args.PageSize)
+	if err := r.charge(1 + int32(pageSize)); err != nil {
+		return nil, err
+	}
+	b, err := gr.s.api.GetBlock(args.ID, int(args.Page), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	r.addTxs(b.Transactions)
+	return &graphqlBlock{req: r, b: b}, nil
+}
+
+// Utxos resolves the unspent outputs of the address or xpub
+func (gr *graphqlResolver) Utxos(ctx context.Context, args struct {
+	Descriptor string
+	Confirmed  bool
+	Gap        int32
+}) ([]*graphqlUtxo, error) {
+	return requestFromContext(ctx).getUtxos(args.Descriptor, args.Confirmed, int(args.Gap))
+}
+
+type graphqlAddress struct {
+	req        *graphqlRequest
+	a          *api.Address
+	descriptor string
+	gap        int
+}
+
+func (r *graphqlAddress) Page() int32                { return int32(r.a.Page) }
+func (r *graphqlAddress) TotalPages() int32          { return int32(r.a.TotalPages) }
+func (r *graphqlAddress) ItemsOnPage() int32         { return int32(r.a.ItemsOnPage) }
+func (r *graphqlAddress) Address() string            { return r.a.AddrStr }
+func (r *graphqlAddress) Balance() string            { return r.a.BalanceSat.String() }
+func (r *graphqlAddress) TotalReceived() *string     { return graphqlAmount(r.a.TotalReceivedSat) }
+func (r *graphqlAddress) TotalSent() *string         { return graphqlAmount(r.a.TotalSentSat) }
+func (r *graphqlAddress) UnconfirmedBalance() string { return r.a.UnconfirmedBalanceSat.String() }
+func (r *graphqlAddress) UnconfirmedTxs() int32      { return int32(r.a.UnconfirmedTxs) }
+func (r *graphqlAddress) Txs() int32                 { return int32(r.a.Txs) }
+func (r *graphqlAddress) NonTokenTxs() int32         { return int32(r.a.NonTokenTxs) }
+func (r *graphqlAddress) Nonce() *string             { return graphqlString(r.a.Nonce) }
+func (r *graphqlAddress) UsedTokens() int32          { return int32(r.a.UsedTokens) }
+
+func (r *graphqlAddress) Txids() []string {
+	if r.a.Txids == nil {
+		return []string{}
+	}
+	return r.a.Txids
+}
+
+// Transactions loads the transactions of the page of the address
+func (r *graphqlAddress) Transactions() ([]*graphqlTx, error) {
+	txs := make([]*api.Tx, len(r.a.Txids))
+	for i, txid := range r.a.Txids {
+		tx, err := r.req.getTx(txid)
+		if err != nil {
+			return nil, err
+		}
+		txs[i] = tx
+	}
+	return r.req.txResolvers(txs), nil
+}
+
+func (r *graphqlAddress) Tokens() []*graphqlToken {
+	rv := make([]*graphqlToken, len(r.a.Tokens))
+	for i := range r.a.Tokens {
+		rv[i] = &graphqlToken{t: &r.a.Tokens[i]}
+	}
+	return rv
+}
+
+func (r *graphqlAddress) Utxos(args struct{ Confirmed bool }) ([]*graphqlUtxo, error) {
+	return r.req.getUtxos(r.descriptor, args.Confirmed, r.gap)
+}
+
+type graphqlToken struct {
+	t *api.Token
+}
+
+func (r *graphqlToken) Type() string           { return string(r.t.Type) }
+func (r *graphqlToken) Name() string           { return r.t.Name }
+func (r *graphqlToken) Path() *string          { return graphqlString(r.t.Path) }
+func (r *graphqlToken) Contract() *string      { return graphqlString(r.t.Contract) }
+func (r *graphqlToken) Transfers() int32       { return int32(r.t.Transfers) }
+func (r *graphqlToken) Symbol() *string        { return graphqlString(r.t.Symbol) }
+func (r *graphqlToken) Decimals() int32        { return int32(r.t.Decimals) }
+func (r *graphqlToken) Balance() *string       { return graphqlAmount(r.t.BalanceSat) }
+func (r *graphqlToken) TotalReceived() *string { return graphqlAmount(r.t.TotalReceivedSat) }
+func (r *graphqlToken) TotalSent() *string     { return graphqlAmount(r.t.TotalSentSat) }
+
+type graphqlTokenTransfer struct {
+	t *api.TokenTransfer
+}
+
+func (r *graphqlTokenTransfer) Type() string    { return string(r.t.Type) }
+func (r *graphqlTokenTransfer) From() string    { return r.t.From }
+func (r *graphqlTokenTransfer) To() string      { return r.t.To }
+func (r *graphqlTokenTransfer) Token() string   { return r.t.Token }
+func (r *graphqlTokenTransfer) Name() string    { return r.t.Name }
+func (r *graphqlTokenTransfer) Symbol() string  { return r.t.Symbol }
+func (r *graphqlTokenTransfer) Decimals() int32 { return int32(r.t.Decimals) }
+func (r *graphqlTokenTransfer) Value() *string  { return graphqlAmount(r.t.Value) }
+
+type graphqlTx struct {
+	req *graphqlRequest
+	tx  *api.Tx
+}
+
+func (r *graphqlTx) Txid() string         { return r.tx.Txid }
+func (r *graphqlTx) Version() int32       { return r.tx.Version }
+func (r *graphqlTx) LockTime() float64    { return float64(r.tx.Locktime) }
+func (r *graphqlTx) BlockHash() *string   { return graphqlString(r.tx.Blockhash) }
+func (r *graphqlTx) BlockHeight() int32   { return int32(r.tx.Blockheight) }
+func (r *graphqlTx) Confirmations() int32 { return int32(r.tx.Confirmations) }
+func (r *graphqlTx) BlockTime() float64   { return float64(r.tx.Blocktime) }
+func (r *graphqlTx) Size() int32          { return int32(r.tx.Size) }
+func (r *graphqlTx) Vsize() int32         { return int32(r.tx.VSize) }
+func (r *graphqlTx) Weight() int32        { return int32(r.tx.Weight) }
+func (r *graphqlTx) Value() *string       { return graphqlAmount(r.tx.ValueOutSat) }
+func (r *graphqlTx) ValueIn() *string     { return graphqlAmount(r.tx.ValueInSat) }
+func (r *graphqlTx) Fees() *string        { return graphqlAmount(r.tx.FeesSat) }
+func (r *graphqlTx) Hex() *string         { return graphqlString(r.tx.Hex) }
+func (r *graphqlTx) Rbf() bool            { return r.tx.Rbf }
+
+func (r *graphqlTx) Vin() []*graphqlVin {
+	rv := make([]*graphqlVin, len(r.tx.Vin))
+	for i := range r.tx.Vin {
+		rv[i] = &graphqlVin{req: r.req, v: &r.tx.Vin[i]}
+	}
+	return rv
+}
+
+func (r *graphqlTx) Vout() []*graphqlVout {
+	rv := make([]*graphqlVout, len(r.tx.Vout))
+	for i := range r.tx.Vout {
+		rv[i] = &graphqlVout{v: &r.tx.Vout[i]}
+	}
+	return rv
+}
+
+func (r *graphqlTx) TokenTransfers() []*graphqlTokenTransfer {
+	rv := make([]*graphqlTokenTransfer, len(r.tx.TokenTransfers))
+	for i := range r.tx.TokenTransfers {
+		rv[i] = &graphqlTokenTransfer{t: &r.tx.TokenTransfers[i]}
+	}
+	return rv
+}
+
+type graphqlVin struct {
+	req *graphqlRequest
+	v   *api.Vin
+}
+
+func (r *graphqlVin) Txid() *string     { return graphqlString(r.v.Txid) }
+func (r *graphqlVin) Vout() int32       { return int32(r.v.Vout) }
+func (r *graphqlVin) Sequence() float64 { return float64(r.v.Sequence) }
+func (r *graphqlVin) N() int32          { return int32(r.v.N) }
+func (r *graphqlVin) IsAddress() bool   { return r.v.IsAddress }
+func (r *graphqlVin) Value() *string    { return graphqlAmount(r.v.ValueSat) }
+func (r *graphqlVin) Hex() *string      { return graphqlString(r.v.Hex) }
+func (r *graphqlVin) Asm() *string      { return graphqlString(r.v.Asm) }
+func (r *graphqlVin) Coinbase() *string { return graphqlString(r.v.Coinbase) }
+
+func (r *graphqlVin) Addresses() []string {
+	if r.v.Addresses == nil {
+		return []string{}
+	}
+	return r.v.Addresses
+}
+
+// PrevTx loads the transaction spent by the input, nil for coinbase inputs
+func (r *graphqlVin) PrevTx() (*graphqlTx, error) {
+	if r.v.Txid == "" {
+		return nil, nil
+	}
+	tx, err := r.req.getTx(r.v.Txid)
+	if err != nil {
+		return nil, err
+	}
+	return &graphqlTx{req: r.req, tx: tx}, nil
+}
+
+type graphqlVout struct {
+	v *api.Vout
+}
+
+func (r *graphqlVout) Value() *string     { return graphqlAmount(r.v.ValueSat) }
+func (r *graphqlVout) N() int32           { return int32(r.v.N) }
+func (r *graphqlVout) Spent() bool        { return r.v.Spent }
+func (r *graphqlVout) SpentTxID() *string { return graphqlString(r.v.SpentTxID) }
+func (r *graphqlVout) SpentIndex() int32  { return int32(r.v.SpentIndex) }
+func (r *graphqlVout) SpentHeight() int32 { return int32(r.v.SpentHeight) }
+func (r *graphqlVout) Hex() *string       { return graphqlString(r.v.Hex) }
+func (r *graphqlVout) Asm() *string       { return graphqlString(r.v.Asm) }
+func (r *graphqlVout) IsAddress() bool    { return r.v.IsAddress }
+func (r *graphqlVout) Type() *string      { return graphqlString(r.v.Type) }
+
+func (r *graphqlVout) Addresses() []string {
+	if r.v.Addresses == nil {
+		return []string{}
+	}
+	return r.v.Addresses
+}
+
+type graphqlUtxo struct {
+	req *graphqlRequest
+	u   *api.Utxo
+}
+
+func (r *graphqlUtxo) Txid() string         { return r.u.Txid }
+func (r *graphqlUtxo) Vout() int32          { return r.u.Vout }
+func (r *graphqlUtxo) Value() *string       { return graphqlAmount(r.u.AmountSat) }
+func (r *graphqlUtxo) Height() int32        { return int32(r.u.Height) }
+func (r *graphqlUtxo) Confirmations() int32 { return int32(r.u.Confirmations) }
+func (r *graphqlUtxo) Address() *string     { return graphqlString(r.u.Address) }
+func (r *graphqlUtxo) Path() *string        { return graphqlString(r.u.Path) }
+func (r *graphqlUtxo) LockTime() float64    { return float64(r.u.Locktime) }
+func (r *graphqlUtxo) Coinbase() bool       { return r.u.Coinbase }
+func (r *graphqlUtxo) SpentTxID() *string   { return graphqlString(r.u.SpentTxID) }
+
+// Tx loads the transaction of the unspent output
+func (r *graphqlUtxo) Tx() (*graphqlTx, error) {
+	tx, err := r.req.getTx(r.u.Txid)
+	if err != nil {
+		return nil, err
+	}
+	return &graphqlTx{req: r.req, tx: tx}, nil
+}
+
+type graphqlBlock struct {
+	req *graphqlRequest
+	b   *api.Block
+}
+
+func (r *graphqlBlock) Page() int32                { return int32(r.b.Page) }
+func (r *graphqlBlock) TotalPages() int32          { return int32(r.b.TotalPages) }
+func (r *graphqlBlock) ItemsOnPage() int32         { return int32(r.b.ItemsOnPage) }
+func (r *graphqlBlock) Hash() string               { return r.b.Hash }
+func (r *graphqlBlock) PreviousBlockHash() *string { return graphqlString(r.b.Prev) }
+func (r *graphqlBlock) NextBlockHash() *string     { return graphqlString(r.b.Next) }
+func (r *graphqlBlock) Height() int32              { return int32(r.b.Height) }
+func (r *graphqlBlock) Confirmations() int32       { return int32(r.b.Confirmations) }
+func (r *graphqlBlock) Size() int32                { return int32(r.b.Size) }
+func (r *graphqlBlock) Time() float64              { return float64(r.b.Time) }
+func (r *graphqlBlock) Version() string            { return string(r.b.Version) }
+func (r *graphqlBlock) MerkleRoot() string         { return r.b.MerkleRoot }
+func (r *graphqlBlock) Nonce() string              { return r.b.Nonce }
+func (r *graphqlBlock) Bits() string               { return r.b.Bits }
+func (r *graphqlBlock) Difficulty() string         { return r.b.Difficulty }
+func (r *graphqlBlock) TxCount() int32             { return int32(r.b.TxCount) }
+
+func (r *graphqlBlock) Transactions() []*graphqlTx {
+	return r.req.txResolvers(r.b.Transactions)
+}
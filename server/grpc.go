@@ -0,0 +1,640 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/juju/errors"
+	"github.com/trezor/blockbook/api"
+	"github.com/trezor/blockbook/bchain"
+	"github.com/trezor/blockbook/common"
+	"github.com/trezor/blockbook/db"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// grpcOutChannelSize is the number of messages buffered for a streaming subscription,
+// the subscription of a client which does not read the messages fast enough is closed
+const grpcOutChannelSize = 500
+
+// grpcSubscription is one server-streaming subscription of a client
+type grpcSubscription struct {
+	out      chan interface{}
+	overflow chan struct{}
+	once     sync.Once
+}
+
+func newGrpcSubscription() *grpcSubscription {
+	return &grpcSubscription{
+		out:      make(chan interface{}, grpcOutChannelSize),
+		overflow: make(chan struct{}),
+	}
+}
+
+// send passes the message to the stream without blocking, on overflow the subscription is terminated
+func (c *grpcSubscription) send(m interface{}) {
+	select {
+	case c.out <- m:
+	default:
+		c.once.Do(func() { close(c.overflow) })
+	}
+}
+
+// GrpcServer is a gRPC server exposing the api.Worker operations and streaming subscriptions
+type GrpcServer struct {
+	bchain.UnimplementedBlockbookServer
+	binding                    string
+	certFiles                  string
+	server                     *grpc.Server
+	db                         *db.RocksDB
+	txCache                    *db.TxCache
+	chain                      bchain.BlockChain
+	chainParser                bchain.BlockChainParser
+	mempool                    bchain.Mempool
+	metrics                    *common.Metrics
+	is                         *common.InternalState
+	api                        *api.Worker
+	newBlockSubscriptions      map[*grpcSubscription]struct{}
+	newBlockSubscriptionsLock  sync.Mutex
+	addressSubscriptions       map[string]map[*grpcSubscription]struct{}
+	addressSubscriptionsLock   sync.Mutex
+	fiatRatesSubscriptions     map[string]map[*grpcSubscription]struct{}
+	fiatRatesSubscriptionsLock sync.Mutex
+}
+
+// NewGrpcServer creates new gRPC server, the server is started by Run
+func NewGrpcServer(binding, certFiles string, db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, metrics *common.Metrics, is *common.InternalState) (*GrpcServer, error) {
+	api, err := api.NewWorker(db, chain, mempool, txCache, metrics, is)
+	if err != nil {
+		return nil, err
+	}
+	s := &GrpcServer{
+		binding:                binding,
+		certFiles:              certFiles,
+		db:                     db,
+		txCache:                txCache,
+		chain:                  chain,
+		chainParser:            chain.GetChainParser(),
+		mempool:                mempool,
+		metrics:                metrics,
+		is:                     is,
+		api:                    api,
+		newBlockSubscriptions:  make(map[*grpcSubscription]struct{}),
+		addressSubscriptions:   make(map[string]map[*grpcSubscription]struct{}),
+		fiatRatesSubscriptions: make(map[string]map[*grpcSubscription]struct{}),
+	}
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.streamInterceptor),
+	}
+	if certFiles != "" {
+		creds, err := credentials.NewServerTLSFromFile(fmt.Sprint(certFiles, ".crt"), fmt.Sprint(certFiles, ".key"))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	s.server = grpc.NewServer(opts...)
+	bchain.RegisterBlockbookServer(s.server, s)
+	// the reflection allows generic clients like grpcurl to discover the service
+	reflection.Register(s.server)
+	return s, nil
+}
+
+// Run starts the server and serves the requests until the server is closed
+func (s *GrpcServer) Run() error {
+	l, err := net.Listen("tcp", s.binding)
+	if err != nil {
+		return err
+	}
+	if s.certFiles == "" {
+		glog.Info("grpc server: starting to listen on ", s.binding)
+	} else {
+		glog.Info("grpc server: starting to listen on ", s.binding, " with TLS")
+	}
+	return s.server.Serve(l)
+}
+
+// Close stops the server, the active subscriptions are terminated
+func (s *GrpcServer) Close() error {
+	glog.Infof("grpc server: closing")
+	s.server.Stop()
+	return nil
+}
+
+func methodName(fullMethod string) string {
+	if i := strings.LastIndexByte(fullMethod, '/'); i >= 0 {
+		return fullMethod[i+1:]
+	}
+	return fullMethod
+}
+
+// grpcError converts the error returned by a handler to the gRPC status error,
+// the errors which are not public api errors are logged
+func grpcError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if apiErr, ok := err.(*api.APIError); ok && apiErr.Public {
+		return status.Error(codes.InvalidArgument, apiErr.Text)
+	}
+	glog.Error("grpc server: ", method, ": ", errors.ErrorStack(err))
+	return status.Error(codes.Internal, err.Error())
+}
+
+func (s *GrpcServer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	method := methodName(info.FullMethod)
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("grpc server: ", method, " recovered from panic: ", r)
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+		if err == nil {
+			s.metrics.GrpcRequests.With(common.Labels{"method": method, "status": "success"}).Inc()
+		} else {
+			s.metrics.GrpcRequests.With(common.Labels{"method": method, "status": "failure"}).Inc()
+		}
+	}()
+	s.db.BeginRead()
+	defer s.db.EndRead()
+	t := time.Now()
+	defer func() {
+		s.metrics.GrpcReqDuration.With(common.Labels{"method": method}).Observe(float64(time.Since(t)) / 1e3) // in microseconds
+	}()
+	resp, err = handler(ctx, req)
+	return resp, grpcError(method, err)
+}
+
+func (s *GrpcServer) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	method := methodName(info.FullMethod)
+	s.metrics.GrpcRequests.With(common.Labels{"method": method, "status": "success"}).Inc()
+	s.metrics.GrpcSubscribes.With(common.Labels{"method": method}).Inc()
+	defer s.metrics.GrpcSubscribes.With(common.Labels{"method": method}).Dec()
+	defer func() {
+		if r := recover(); r != nil {
+			glog.Error("grpc server: ", method, " recovered from panic: ", r)
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+	return grpcError(method, handler(srv, ss))
+}
+
+// serveSubscription sends the messages of the subscription to the stream until the client disconnects or the server stops
+func serveSubscription(ctx context.Context, c *grpcSubscription, send func(interface{}) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.overflow:
+			return status.Error(codes.ResourceExhausted, "The client does not read the messages fast enough")
+		case m := <-c.out:
+			if err := send(m); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func amountToString(a *api.Amount) string {
+	if a == nil {
+		return ""
+	}
+	return a.String()
+}
+
+func txToGrpc(tx *api.Tx) *bchain.Transaction {
+	if tx == nil {
+		return nil
+	}
+	t := &bchain.Transaction{
+		Txid:          tx.Txid,
+		Version:       tx.Version,
+		LockTime:      tx.Locktime,
+		Vin:           make([]*bchain.TransactionVin, len(tx.Vin)),
+		Vout:          make([]*bchain.TransactionVout, len(tx.Vout)),
+		BlockHash:     tx.Blockhash,
+		BlockHeight:   int32(tx.Blockheight),
+		Confirmations: tx.Confirmations,
+		BlockTime:     tx.Blocktime,
+		Size:          int32(tx.Size),
+		VSize:         tx.VSize,
+		Weight:        tx.Weight,
+		Value:         amountToString(tx.ValueOutSat),
+		ValueIn:       amountToString(tx.ValueInSat),
+		Fees:          amountToString(tx.FeesSat),
+		Hex:           tx.Hex,
+		Rbf:           tx.Rbf,
+	}
+	for i := range tx.Vin {
+		vin := &tx.Vin[i]
+		t.Vin[i] = &bchain.TransactionVin{
+			Txid:      vin.Txid,
+			Vout:      vin.Vout,
+			Sequence:  vin.Sequence,
+			N:         int32(vin.N),
+			Addresses: vin.Addresses,
+			IsAddress: vin.IsAddress,
+			Value:     amountToString(vin.ValueSat),
+			Hex:       vin.Hex,
+			Coinbase:  vin.Coinbase,
+		}
+	}
+	for i := range tx.Vout {
+		vout := &tx.Vout[i]
+		t.Vout[i] = &bchain.TransactionVout{
+			Value:       amountToString(vout.ValueSat),
+			N:           int32(vout.N),
+			Spent:       vout.Spent,
+			SpentTxid:   vout.SpentTxID,
+			SpentIndex:  int32(vout.SpentIndex),
+			SpentHeight: int32(vout.SpentHeight),
+			Hex:         vout.Hex,
+			Addresses:   vout.Addresses,
+			IsAddress:   vout.IsAddress,
+		}
+	}
+	return t
+}
+
+func txsToGrpc(txs []*api.Tx) []*bchain.Transaction {
+	if len(txs) == 0 {
+		return nil
+	}
+	r := make([]*bchain.Transaction, len(txs))
+	for i := range txs {
+		r[i] = txToGrpc(txs[i])
+	}
+	return r
+}
+
+func addressToGrpc(a *api.Address) *bchain.AccountInfo {
+	r := &bchain.AccountInfo{
+		Page:               int32(a.Page),
+		TotalPages:         int32(a.TotalPages),
+		ItemsOnPage:        int32(a.ItemsOnPage),
+		Address:            a.AddrStr,
+		Balance:            amountToString(a.BalanceSat),
+		TotalReceived:      amountToString(a.TotalReceivedSat),
+		TotalSent:          amountToString(a.TotalSentSat),
+		UnconfirmedBalance: amountToString(a.UnconfirmedBalanceSat),
+		UnconfirmedTxs:     int32(a.UnconfirmedTxs),
+		Txs:                int32(a.Txs),
+		NonTokenTxs:        int32(a.NonTokenTxs),
+		Transactions:       txsToGrpc(a.Transactions),
+		Txids:              a.Txids,
+		Nonce:              a.Nonce,
+		UsedTokens:         int32(a.UsedTokens),
+	}
+	for i := range a.Tokens {
+		t := &a.Tokens[i]
+		r.Tokens = append(r.Tokens, &bchain.Token{
+			Type:          string(t.Type),
+			Name:          t.Name,
+			Path:          t.Path,
+			Contract:      t.Contract,
+			Transfers:     int32(t.Transfers),
+			Symbol:        t.Symbol,
+			Decimals:      int32(t.Decimals),
+			Balance:       amountToString(t.BalanceSat),
+			TotalReceived: amountToString(t.TotalReceivedSat),
+			TotalSent:     amountToString(t.TotalSentSat),
+		})
+	}
+	return r
+}
+
+// GetAccountInfo returns the balances and transactions of an address or xpub
+func (s *GrpcServer) GetAccountInfo(ctx context.Context, req *bchain.AccountInfoRequest) (*bchain.AccountInfo, error) {
+	r := &accountInfoReq{
+		Descriptor:     req.Descriptor_,
+		Details:        req.Details,
+		Tokens:         req.Tokens,
+		PageSize:       int(req.PageSize),
+		Page:           int(req.Page),
+		FromHeight:     int(req.FromHeight),
+		ToHeight:       int(req.ToHeight),
+		ContractFilter: req.ContractFilter,
+		Gap:            int(req.Gap),
+	}
+	opt, filter := accountInfoReqOptions(r)
+	a, err := s.api.GetXpubAddress(r.Descriptor, r.Page, r.PageSize, opt, filter, r.Gap)
+	if err != nil {
+		a, err = s.api.GetAddress(r.Descriptor, r.Page, r.PageSize, opt, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return addressToGrpc(a), nil
+}
+
+// GetAccountUtxo returns the unspent outputs of an address or xpub
+func (s *GrpcServer) GetAccountUtxo(ctx context.Context, req *bchain.AccountUtxoRequest) (*bchain.AccountUtxo, error) {
+	utxos, err := s.api.GetXpubUtxo(req.Descriptor_, req.Confirmed, true, int(req.Gap))
+	if err != nil {
+		utxos, err = s.api.GetAddressUtxo(req.Descriptor_, req.Confirmed, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	r := &bchain.AccountUtxo{Utxos: make([]*bchain.Utxo, len(utxos))}
+	for i := range utxos {
+		u := &utxos[i]
+		r.Utxos[i] = &bchain.Utxo{
+			Txid:          u.Txid,
+			Vout:          u.Vout,
+			Value:         amountToString(u.AmountSat),
+			Height:        int32(u.Height),
+			Confirmations: int32(u.Confirmations),
+			Address:       u.Address,
+			Path:          u.Path,
+			LockTime:      u.Locktime,
+			Coinbase:      u.Coinbase,
+			SpentTxid:     u.SpentTxID,
+		}
+	}
+	return r, nil
+}
+
+// GetTransaction returns the transaction
+func (s *GrpcServer) GetTransaction(ctx context.Context, req *bchain.TransactionRequest) (*bchain.Transaction, error) {
+	tx, err := s.api.GetTransaction(req.Txid, req.Spending, false)
+	if err != nil {
+		return nil, err
+	}
+	return txToGrpc(tx), nil
+}
+
+// GetBlock returns the block with a page of its transactions
+func (s *GrpcServer) GetBlock(ctx context.Context, req *bchain.BlockRequest) (*bchain.BlockDetail, error) {
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = txsOnPage
+	}
+	b, err := s.api.GetBlock(req.Id, int(req.Page), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &bchain.BlockDetail{
+		Page:              int32(b.Page),
+		TotalPages:        int32(b.TotalPages),
+		ItemsOnPage:       int32(b.ItemsOnPage),
+		Hash:              b.Hash,
+		PreviousBlockHash: b.Prev,
+		NextBlockHash:     b.Next,
+		Height:            b.Height,
+		Confirmations:     int32(b.Confirmations),
+		Size:              int32(b.Size),
+		Time:              b.Time,
+		Version:           string(b.Version),
+		MerkleRoot:        b.MerkleRoot,
+		Nonce:             b.Nonce,
+		Bits:              b.Bits,
+		Difficulty:        b.Difficulty,
+		TxCount:           int32(b.TxCount),
+		Transactions:      txsToGrpc(b.Transactions),
+	}, nil
+}
+
+// GetBalanceHistory returns the balance history of an address or xpub
+func (s *GrpcServer) GetBalanceHistory(ctx context.Context, req *bchain.BalanceHistoryRequest) (*bchain.BalanceHistory, error) {
+	from, to, groupBy := req.From, req.To, req.GroupBy
+	if from <= 0 {
+		from = 0
+	}
+	if to <= 0 {
+		to = 0
+	}
+	if groupBy <= 0 {
+		groupBy = 3600
+	}
+	bh, err := s.api.GetXpubBalanceHistory(req.Descriptor_, from, to, req.Currencies, int(req.Gap), groupBy)
+	if err != nil {
+		bh, err = s.api.GetBalanceHistory(req.Descriptor_, from, to, req.Currencies, groupBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	r := &bchain.BalanceHistory{Items: make([]*bchain.BalanceHistoryItem, len(bh))}
+	for i := range bh {
+		h := &bh[i]
+		r.Items[i] = &bchain.BalanceHistoryItem{
+			Time:       h.Time,
+			Txs:        h.Txs,
+			Received:   amountToString(h.ReceivedSat),
+			Sent:       amountToString(h.SentSat),
+			SentToSelf: amountToString(h.SentToSelfSat),
+			Rates:      h.FiatRates,
+		}
+	}
+	return r, nil
+}
+
+// EstimateFee returns the fee estimates for the requested numbers of blocks
+func (s *GrpcServer) EstimateFee(ctx context.Context, req *bchain.EstimateFeeRequest) (*bchain.EstimateFeeResponse, error) {
+	r := &bchain.EstimateFeeResponse{Estimates: make([]*bchain.FeeEstimate, len(req.Blocks))}
+	for i, b := range req.Blocks {
+		var fee big.Int
+		var err error
+		if s.chainParser.GetChainType() == bchain.ChainEthereumType {
+			fee, err = s.chain.EstimateSmartFee(int(b), true)
+		} else {
+			fee, err = s.api.BitcoinTypeEstimateFee(int(b), req.Conservative)
+		}
+		if err != nil {
+			return nil, err
+		}
+		e := &bchain.FeeEstimate{FeePerUnit: fee.String()}
+		if req.TxSize > 0 && s.chainParser.GetChainType() != bchain.ChainEthereumType {
+			// the fee is per 1000 bytes, round to the nearest unit
+			fee.Mul(&fee, big.NewInt(int64(req.TxSize)))
+			fee.Add(&fee, big.NewInt(500))
+			fee.Div(&fee, big.NewInt(1000))
+			e.FeePerTx = fee.String()
+		}
+		r.Estimates[i] = e
+	}
+	return r, nil
+}
+
+// SendTransaction broadcasts the transaction to the network
+func (s *GrpcServer) SendTransaction(ctx context.Context, req *bchain.SendTransactionRequest) (*bchain.SendTransactionResponse, error) {
+	if req.Hex == "" {
+		return nil, api.NewAPIError("Missing tx blob", true)
+	}
+	txid, err := s.chain.SendRawTransaction(req.Hex)
+	if err != nil {
+		return nil, api.NewAPIError(err.Error(), true)
+	}
+	return &bchain.SendTransactionResponse{Txid: txid}, nil
+}
+
+// SubscribeNewBlock streams the new blocks connected to the index
+func (s *GrpcServer) SubscribeNewBlock(req *bchain.SubscribeNewBlockRequest, stream bchain.Blockbook_SubscribeNewBlockServer) error {
+	c := newGrpcSubscription()
+	s.newBlockSubscriptionsLock.Lock()
+	s.newBlockSubscriptions[c] = struct{}{}
+	s.newBlockSubscriptionsLock.Unlock()
+	defer func() {
+		s.newBlockSubscriptionsLock.Lock()
+		delete(s.newBlockSubscriptions, c)
+		s.newBlockSubscriptionsLock.Unlock()
+	}()
+	return serveSubscription(stream.Context(), c, func(m interface{}) error {
+		return stream.Send(m.(*bchain.NewBlock))
+	})
+}
+
+// SubscribeAddresses streams the new mempool transactions of the addresses
+func (s *GrpcServer) SubscribeAddresses(req *bchain.SubscribeAddressesRequest, stream bchain.Blockbook_SubscribeAddressesServer) error {
+	if len(req.Addresses) == 0 {
+		return status.Error(codes.InvalidArgument, "Missing addresses")
+	}
+	descs := make([]string, len(req.Addresses))
+	for i, a := range req.Addresses {
+		addrDesc, err := s.chainParser.GetAddrDescFromAddress(a)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, fmt.Sprintf("Invalid address '%v', %v", a, err))
+		}
+		descs[i] = string(addrDesc)
+	}
+	c := newGrpcSubscription()
+	s.addressSubscriptionsLock.Lock()
+	for _, ad := range descs {
+		as, ok := s.addressSubscriptions[ad]
+		if !ok {
+			as = make(map[*grpcSubscription]struct{})
+			s.addressSubscriptions[ad] = as
+		}
+		as[c] = struct{}{}
+	}
+	s.addressSubscriptionsLock.Unlock()
+	defer func() {
+		s.addressSubscriptionsLock.Lock()
+		for _, ad := range descs {
+			if as, ok := s.addressSubscriptions[ad]; ok {
+				delete(as, c)
+				if len(as) == 0 {
+					delete(s.addressSubscriptions, ad)
+				}
+			}
+		}
+		s.addressSubscriptionsLock.Unlock()
+	}()
+	return serveSubscription(stream.Context(), c, func(m interface{}) error {
+		return stream.Send(m.(*bchain.AddressTransaction))
+	})
+}
+
+// SubscribeFiatRates streams the new fiat rates of the currency or of all currencies
+func (s *GrpcServer) SubscribeFiatRates(req *bchain.SubscribeFiatRatesRequest, stream bchain.Blockbook_SubscribeFiatRatesServer) error {
+	currency := strings.ToLower(req.Currency)
+	if currency == "" {
+		currency = allFiatRates
+	}
+	c := newGrpcSubscription()
+	s.fiatRatesSubscriptionsLock.Lock()
+	as, ok := s.fiatRatesSubscriptions[currency]
+	if !ok {
+		as = make(map[*grpcSubscription]struct{})
+		s.fiatRatesSubscriptions[currency] = as
+	}
+	as[c] = struct{}{}
+	s.fiatRatesSubscriptionsLock.Unlock()
+	defer func() {
+		s.fiatRatesSubscriptionsLock.Lock()
+		delete(as, c)
+		if len(as) == 0 {
+			delete(s.fiatRatesSubscriptions, currency)
+		}
+		s.fiatRatesSubscriptionsLock.Unlock()
+	}()
+	return serveSubscription(stream.Context(), c, func(m interface{}) error {
+		return stream.Send(m.(*bchain.FiatRates))
+	})
+}
+
+// OnNewBlock is a callback that sends the new block to the subscribed streams
+func (s *GrpcServer) OnNewBlock(hash string, height uint32) {
+	s.newBlockSubscriptionsLock.Lock()
+	defer s.newBlockSubscriptionsLock.Unlock()
+	if len(s.newBlockSubscriptions) == 0 {
+		return
+	}
+	m := &bchain.NewBlock{Height: height, Hash: hash}
+	for c := range s.newBlockSubscriptions {
+		c.send(m)
+	}
+	glog.Info("grpc server: broadcasting new block ", height, " ", hash, " to ", len(s.newBlockSubscriptions), " streams")
+}
+
+func (s *GrpcServer) onNewTxAddrAsync(tx *bchain.Tx, desc bchain.AddressDescriptor, address string) {
+	s.db.BeginRead()
+	defer s.db.EndRead()
+	atx, err := s.api.GetTransactionFromBchainTx(tx, 0, false, false)
+	if err != nil {
+		glog.Error("grpc server: GetTransactionFromBchainTx error ", err, " for ", tx.Txid)
+		return
+	}
+	m := &bchain.AddressTransaction{Address: address, Tx: txToGrpc(atx)}
+	s.addressSubscriptionsLock.Lock()
+	defer s.addressSubscriptionsLock.Unlock()
+	as := s.addressSubscriptions[string(desc)]
+	for c := range as {
+		c.send(m)
+	}
+	glog.Info("grpc server: broadcasting new tx ", tx.Txid, ", addr ", address, " to ", len(as), " streams")
+}
+
+// OnNewTxAddr is a callback that sends the new mempool transaction to the streams subscribed to the address
+func (s *GrpcServer) OnNewTxAddr(tx *bchain.Tx, desc bchain.AddressDescriptor) {
+	s.addressSubscriptionsLock.Lock()
+	subscribed := len(s.addressSubscriptions[string(desc)]) > 0
+	s.addressSubscriptionsLock.Unlock()
+	if !subscribed {
+		return
+	}
+	addr, _, err := s.chainParser.GetAddressesFromAddrDesc(desc)
+	if err != nil {
+		glog.Error("grpc server: GetAddressesFromAddrDesc error ", err, " for ", desc)
+		return
+	}
+	if len(addr) == 1 {
+		go s.onNewTxAddrAsync(tx, desc, addr[0])
+	}
+}
+
+func (s *GrpcServer) broadcastTicker(currency string, m *bchain.FiatRates) {
+	as := s.fiatRatesSubscriptions[currency]
+	if len(as) > 0 {
+		for c := range as {
+			c.send(m)
+		}
+		glog.Info("grpc server: broadcasting new rates for currency ", currency, " to ", len(as), " streams")
+	}
+}
+
+// OnNewFiatRatesTicker is a callback that sends the new fiat rates to the subscribed streams
+func (s *GrpcServer) OnNewFiatRatesTicker(ticker *db.CurrencyRatesTicker) {
+	var timestamp int64
+	if ticker.Timestamp != nil {
+		timestamp = ticker.Timestamp.Unix()
+	}
+	s.fiatRatesSubscriptionsLock.Lock()
+	defer s.fiatRatesSubscriptionsLock.Unlock()
+	for currency, rate := range ticker.Rates {
+		s.broadcastTicker(currency, &bchain.FiatRates{Timestamp: timestamp, Rates: map[string]float64{currency: rate}})
+	}
+	s.broadcastTicker(allFiatRates, &bchain.FiatRates{Timestamp: timestamp, Rates: ticker.Rates})
+}
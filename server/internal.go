@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/golang/glog"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -25,10 +26,13 @@ type InternalServer struct {
 	mempool     bchain.Mempool
 	is          *common.InternalState
 	api         *api.Worker
+	// checkpointDir is the directory for checkpoints created by the admin/checkpoint endpoint, empty disables the endpoint
+	checkpointDir  string
+	checkpointLock sync.Mutex
 }
 
 // NewInternalServer creates new internal http interface to blockbook and returns its handle
-func NewInternalServer(binding, certFiles string, db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, metrics *common.Metrics, is *common.InternalState) (*InternalServer, error) {
+func NewInternalServer(binding, certFiles, checkpointDir string, db *db.RocksDB, chain bchain.BlockChain, mempool bchain.Mempool, txCache *db.TxCache, metrics *common.Metrics, is *common.InternalState) (*InternalServer, error) {
 	api, err := api.NewWorker(db, chain, mempool, txCache, metrics, is)
 	if err != nil {
 		return nil, err
@@ -41,19 +45,21 @@ func NewInternalServer(binding, certFiles string, db *db.RocksDB, chain bchain.B
 		Handler: serveMux,
 	}
 	s := &InternalServer{
-		https:       https,
-		certFiles:   certFiles,
-		db:          db,
-		txCache:     txCache,
-		chain:       chain,
-		chainParser: chain.GetChainParser(),
-		mempool:     mempool,
-		is:          is,
-		api:         api,
+		https:         https,
+		certFiles:     certFiles,
+		db:            db,
+		txCache:       txCache,
+		chain:         chain,
+		chainParser:   chain.GetChainParser(),
+		mempool:       mempool,
+		is:            is,
+		api:           api,
+		checkpointDir: checkpointDir,
 	}
 
 	serveMux.Handle(path+"favicon.ico", http.FileServer(http.Dir("./static/")))
 	serveMux.HandleFunc(path+"metrics", promhttp.Handler().ServeHTTP)
+	serveMux.HandleFunc(path+"admin/checkpoint", s.checkpoint)
 	serveMux.HandleFunc(path, s.index)
 
 	return s, nil
@@ -82,6 +88,8 @@ func (s *InternalServer) Shutdown(ctx context.Context) error {
 }
 
 func (s *InternalServer) index(w http.ResponseWriter, r *http.Request) {
+	s.db.BeginRead()
+	defer s.db.EndRead()
 	si, err := s.api.GetSystemInfo(true)
 	if err != nil {
 		glog.Error(err)
@@ -97,3 +105,37 @@ func (s *InternalServer) index(w http.ResponseWriter, r *http.Request) {
 
 	w.Write(buf)
 }
+
+// checkpoint creates a checkpoint of the index in the checkpoint directory, only one checkpoint is created at a time
+func (s *InternalServer) checkpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.checkpointDir == "" {
+		http.Error(w, "Checkpoints are not enabled, run with the -checkpointdir flag", http.StatusNotFound)
+		return
+	}
+	s.checkpointLock.Lock()
+	defer s.checkpointLock.Unlock()
+	path := db.NewCheckpointPath(s.checkpointDir)
+	info, err := s.db.CreateCheckpoint(path)
+	if err != nil {
+		glog.Error("checkpoint: ", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	buf, err := json.MarshalIndent(struct {
+		Path string `json:"path"`
+		*db.CheckpointInfo
+	}{
+		Path:           path,
+		CheckpointInfo: info,
+	}, "", "    ")
+	if err != nil {
+		glog.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Write(buf)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"html/template"
@@ -19,6 +20,7 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/trezor/blockbook/api"
 	"github.com/trezor/blockbook/bchain"
 	"github.com/trezor/blockbook/common"
@@ -28,6 +30,10 @@ import (
 const txsOnPage = 25
 const blocksOnPage = 50
 const mempoolTxsOnPage = 50
+const richListOnPage = 50
+const richListInAPI = 1000
+const opReturnsOnPage = 50
+const opReturnsInAPI = 1000
 const txsInAPI = 1000
 
 const (
@@ -54,6 +60,7 @@ type PublicServer struct {
 	metrics          *common.Metrics
 	is               *common.InternalState
 	templates        []*template.Template
+	graphqlSchema    *graphql.Schema
 	debug            bool
 }
 
@@ -102,6 +109,10 @@ func NewPublicServer(binding string, certFiles string, db *db.RocksDB, chain bch
 		debug:            debugMode,
 	}
 	s.templates = s.parseTemplates()
+	s.graphqlSchema, err = newGraphqlSchema(s)
+	if err != nil {
+		return nil, err
+	}
 
 	// map only basic functions, the rest is enabled by method MapFullPublicInterface
 	serveMux.Handle(path+"favicon.ico", http.FileServer(http.Dir("./static/")))
@@ -142,6 +153,9 @@ func (s *PublicServer) ConnectFullPublicInterface() {
 		serveMux.HandleFunc(path+"spending/", s.htmlTemplateHandler(s.explorerSpendingTx))
 		serveMux.HandleFunc(path+"sendtx", s.htmlTemplateHandler(s.explorerSendTx))
 		serveMux.HandleFunc(path+"mempool", s.htmlTemplateHandler(s.explorerMempool))
+		serveMux.HandleFunc(path+"richlist", s.htmlTemplateHandler(s.explorerRichList))
+		serveMux.HandleFunc(path+"tokenholders/", s.htmlTemplateHandler(s.explorerTokenHolders))
+		serveMux.HandleFunc(path+"opreturn/", s.htmlTemplateHandler(s.explorerOpReturns))
 	} else {
 		// redirect to wallet requests for tx and address, possibly to external site
 		serveMux.HandleFunc(path+"tx/", s.txRedirect)
@@ -166,6 +180,12 @@ func (s *PublicServer) ConnectFullPublicInterface() {
 		serveMux.HandleFunc(path+"api/v1/block/", s.jsonHandler(s.apiBlock, apiV1))
 		serveMux.HandleFunc(path+"api/v1/sendtx/", s.jsonHandler(s.apiSendTx, apiV1))
 		serveMux.HandleFunc(path+"api/v1/estimatefee/", s.jsonHandler(s.apiEstimateFee, apiV1))
+		serveMux.HandleFunc(path+"api/v1/addrs/", s.jsonHandler(s.apiAddressesV1, apiV1))
+		serveMux.HandleFunc(path+"api/v1/txs", s.jsonHandler(s.apiTxsV1, apiV1))
+		serveMux.HandleFunc(path+"api/v1/status", s.jsonHandler(s.apiStatusV1, apiV1))
+		serveMux.HandleFunc(path+"api/v1/sync", s.jsonHandler(s.apiSyncV1, apiV1))
+		serveMux.HandleFunc(path+"api/v1/rawtx/", s.jsonHandler(s.apiRawTxV1, apiV1))
+		serveMux.HandleFunc(path+"api/v1/messages/verify", s.jsonHandler(s.apiMessageVerifyV1, apiV1))
 	}
 	serveMux.HandleFunc(path+"api/block-index/", s.jsonHandler(s.apiBlockIndex, apiDefault))
 	serveMux.HandleFunc(path+"api/tx-specific/", s.jsonHandler(s.apiTxSpecific, apiDefault))
@@ -190,10 +210,22 @@ func (s *PublicServer) ConnectFullPublicInterface() {
 	serveMux.HandleFunc(path+"api/v2/sendtx/", s.jsonHandler(s.apiSendTx, apiV2))
 	serveMux.HandleFunc(path+"api/v2/estimatefee/", s.jsonHandler(s.apiEstimateFee, apiV2))
 	serveMux.HandleFunc(path+"api/v2/feestats/", s.jsonHandler(s.apiFeeStats, apiV2))
+	serveMux.HandleFunc(path+"api/v2/supply/", s.jsonHandler(s.apiSupplyStats, apiV2))
+	serveMux.HandleFunc(path+"api/v2/blockfilter/", s.jsonHandler(s.apiBlockFilter, apiV2))
+	serveMux.HandleFunc(path+"api/v2/blockfilterheaders/", s.jsonHandler(s.apiBlockFilterHeaders, apiV2))
+	serveMux.HandleFunc(path+"api/v2/mempool/", s.jsonHandler(s.apiMempool, apiV2))
+	serveMux.HandleFunc(path+"api/v2/mempoolstats/", s.jsonHandler(s.apiMempoolStats, apiV2))
+	serveMux.HandleFunc(path+"api/v2/chaintips/", s.jsonHandler(s.apiChainTips, apiV2))
+	serveMux.HandleFunc(path+"api/v2/richlist/", s.jsonHandler(s.apiRichList, apiV2))
+	serveMux.HandleFunc(path+"api/v2/tokenholders/", s.jsonHandler(s.apiTokenHolders, apiV2))
+	serveMux.HandleFunc(path+"api/v2/opreturn/", s.jsonHandler(s.apiOpReturns, apiV2))
 	serveMux.HandleFunc(path+"api/v2/balancehistory/", s.jsonHandler(s.apiBalanceHistory, apiDefault))
+	serveMux.HandleFunc(path+"api/v2/balance-at/", s.jsonHandler(s.apiBalanceAt, apiV2))
 	serveMux.HandleFunc(path+"api/v2/tickers/", s.jsonHandler(s.apiTickers, apiV2))
 	serveMux.HandleFunc(path+"api/v2/multi-tickers/", s.jsonHandler(s.apiMultiTickers, apiV2))
 	serveMux.HandleFunc(path+"api/v2/tickers-list/", s.jsonHandler(s.apiTickersList, apiV2))
+	serveMux.HandleFunc(path+"api/v2/group/", s.jsonHandler(s.apiWatchGroup, apiV2))
+	serveMux.HandleFunc(path+"api/graphql", s.jsonHandler(s.apiGraphQL, apiV2))
 	// socket.io interface
 	serveMux.Handle(path+"socket.io/", s.socketio.GetHandler())
 	// websocket interface
@@ -233,6 +265,21 @@ func (s *PublicServer) OnNewTx(tx *bchain.MempoolTx) {
 	s.websocket.OnNewTx(tx)
 }
 
+// OnNewMempoolStats notifies users subscribed to mempool statistics
+func (s *PublicServer) OnNewMempoolStats(stats *bchain.MempoolStats) {
+	s.websocket.OnNewMempoolStats(stats)
+}
+
+// OnReplacedTx notifies users subscribed to addresses of a mempool tx that the tx was replaced
+func (s *PublicServer) OnReplacedTx(tx *bchain.ReplacedMempoolTx) {
+	s.websocket.OnReplacedTx(tx)
+}
+
+// OnReorg notifies users subscribed to new blocks and to the affected addresses about a chain reorganization
+func (s *PublicServer) OnReorg(reorg *bchain.ChainReorg) {
+	s.websocket.OnReorg(reorg)
+}
+
 func (s *PublicServer) txRedirect(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, joinURL(s.explorerURL, r.URL.Path), 302)
 	s.metrics.ExplorerViews.With(common.Labels{"action": "tx-redirect"}).Inc()
@@ -280,6 +327,8 @@ func (s *PublicServer) jsonHandler(handler func(r *http.Request, apiVersion int)
 	return func(w http.ResponseWriter, r *http.Request) {
 		var data interface{}
 		var err error
+		s.db.BeginRead()
+		defer s.db.EndRead()
 		defer func() {
 			if e := recover(); e != nil {
 				glog.Error(handlerName, " recovered from panic: ", e)
@@ -350,6 +399,8 @@ func (s *PublicServer) htmlTemplateHandler(handler func(w http.ResponseWriter, r
 		var t tpl
 		var data *TemplateData
 		var err error
+		s.db.BeginRead()
+		defer s.db.EndRead()
 		defer func() {
 			if e := recover(); e != nil {
 				glog.Error(handlerName, " recovered from panic: ", e)
@@ -417,6 +468,9 @@ const (
 	blockTpl
 	sendTransactionTpl
 	mempoolTpl
+	richListTpl
+	opReturnsTpl
+	tokenHoldersTpl
 
 	tplCount
 )
@@ -436,6 +490,9 @@ type TemplateData struct {
 	Block                *api.Block
 	Info                 *api.SystemInfo
 	MempoolTxids         *api.MempoolTxids
+	RichList             *api.RichList
+	TokenHolders         *api.TokenHolders
+	OpReturns            *api.OpReturns
 	Page                 int
 	PrevPage             int
 	NextPage             int
@@ -512,6 +569,9 @@ func (s *PublicServer) parseTemplates() []*template.Template {
 	}
 	t[xpubTpl] = createTemplate("./static/templates/xpub.html", "./static/templates/txdetail.html", "./static/templates/paging.html", "./static/templates/base.html")
 	t[mempoolTpl] = createTemplate("./static/templates/mempool.html", "./static/templates/paging.html", "./static/templates/base.html")
+	t[richListTpl] = createTemplate("./static/templates/richlist.html", "./static/templates/paging.html", "./static/templates/base.html")
+	t[tokenHoldersTpl] = createTemplate("./static/templates/tokenholders.html", "./static/templates/paging.html", "./static/templates/base.html")
+	t[opReturnsTpl] = createTemplate("./static/templates/opreturn.html", "./static/templates/paging.html", "./static/templates/base.html")
 	return t
 }
 
@@ -787,6 +847,14 @@ func (s *PublicServer) explorerSearch(w http.ResponseWriter, r *http.Request) (t
 	var err error
 	s.metrics.ExplorerViews.With(common.Labels{"action": "search"}).Inc()
 	if len(q) > 0 {
+		// search of OP_RETURN payloads by the hex prefix in the form "OP_RETURN <hex prefix>"
+		if len(q) > 9 && strings.EqualFold(q[:9], "OP_RETURN") {
+			prefix := strings.TrimSpace(q[9:])
+			if _, err = hex.DecodeString(prefix); err == nil && len(prefix) > 0 {
+				http.Redirect(w, r, joinURL("/opreturn/", prefix), 302)
+				return noTpl, nil, nil
+			}
+		}
 		address, err = s.api.GetXpubAddress(q, 0, 1, api.AccountDetailsBasic, &api.AddressFilter{Vout: api.AddressFilterVoutOff}, 0)
 		if err == nil {
 			http.Redirect(w, r, joinURL("/xpub/", url.QueryEscape(address.AddrStr)), 302)
@@ -852,6 +920,61 @@ func (s *PublicServer) explorerMempool(w http.ResponseWriter, r *http.Request) (
 	return mempoolTpl, data, nil
 }
 
+func (s *PublicServer) explorerOpReturns(w http.ResponseWriter, r *http.Request) (tpl, *TemplateData, error) {
+	var opReturns *api.OpReturns
+	var err error
+	s.metrics.ExplorerViews.With(common.Labels{"action": "opreturn"}).Inc()
+	if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
+		page, ec := strconv.Atoi(r.URL.Query().Get("page"))
+		if ec != nil {
+			page = 0
+		}
+		opReturns, err = s.api.GetOpReturns(r.URL.Path[i+1:], page, opReturnsOnPage)
+		if err != nil {
+			return errorTpl, nil, err
+		}
+	}
+	data := s.newTemplateData()
+	data.OpReturns = opReturns
+	data.Page = opReturns.Page
+	data.PagingRange, data.PrevPage, data.NextPage = getPagingRange(opReturns.Page, opReturns.TotalPages)
+	return opReturnsTpl, data, nil
+}
+
+func (s *PublicServer) explorerRichList(w http.ResponseWriter, r *http.Request) (tpl, *TemplateData, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "richlist"}).Inc()
+	page, ec := strconv.Atoi(r.URL.Query().Get("page"))
+	if ec != nil {
+		page = 0
+	}
+	richList, err := s.api.GetRichList(page, richListOnPage)
+	if err != nil {
+		return errorTpl, nil, err
+	}
+	data := s.newTemplateData()
+	data.RichList = richList
+	data.Page = richList.Page
+	data.PagingRange, data.PrevPage, data.NextPage = getPagingRange(richList.Page, richList.TotalPages)
+	return richListTpl, data, nil
+}
+
+func (s *PublicServer) explorerTokenHolders(w http.ResponseWriter, r *http.Request) (tpl, *TemplateData, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "tokenholders"}).Inc()
+	page, ec := strconv.Atoi(r.URL.Query().Get("page"))
+	if ec != nil {
+		page = 0
+	}
+	tokenHolders, err := s.api.GetTokenHolders(r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:], page, richListOnPage)
+	if err != nil {
+		return errorTpl, nil, err
+	}
+	data := s.newTemplateData()
+	data.TokenHolders = tokenHolders
+	data.Page = tokenHolders.Page
+	data.PagingRange, data.PrevPage, data.NextPage = getPagingRange(tokenHolders.Page, tokenHolders.TotalPages)
+	return tokenHoldersTpl, data, nil
+}
+
 func getPagingRange(page int, total int) ([]int, int, int) {
 	// total==-1 means total is unknown, show only prev/next buttons
 	if total >= 0 && total < 2 {
@@ -961,11 +1084,14 @@ func (s *PublicServer) apiTx(r *http.Request, apiVersion int) (interface{}, erro
 			return nil, api.NewAPIError("Parameter 'spending' cannot be converted to boolean", true)
 		}
 	}
-	tx, err = s.api.GetTransaction(txid, spendingTxs, false)
-	if err == nil && apiVersion == apiV1 {
+	if apiVersion == apiV1 {
+		tx, err = s.api.GetTransaction(txid, spendingTxs, false)
+		if err != nil {
+			return nil, err
+		}
 		return s.api.TxToV1(tx), nil
 	}
-	return tx, err
+	return s.api.GetTransactionWithMempoolFees(txid, spendingTxs)
 }
 
 func (s *PublicServer) apiTxSpecific(r *http.Request, apiVersion int) (interface{}, error) {
@@ -1043,15 +1169,20 @@ func (s *PublicServer) apiUtxo(r *http.Request, apiVersion int) (interface{}, er
 				return nil, api.NewAPIError("Parameter 'confirmed' cannot be converted to boolean", true)
 			}
 		}
+		var pendingSpent bool
+		pendingSpent, err = getPendingSpentQueryParam(r)
+		if err != nil {
+			return nil, err
+		}
 		gap, ec := strconv.Atoi(r.URL.Query().Get("gap"))
 		if ec != nil {
 			gap = 0
 		}
-		utxo, err = s.api.GetXpubUtxo(desc, onlyConfirmed, gap)
+		utxo, err = s.api.GetXpubUtxo(desc, onlyConfirmed, pendingSpent, gap)
 		if err == nil {
 			s.metrics.ExplorerViews.With(common.Labels{"action": "api-xpub-utxo"}).Inc()
 		} else {
-			utxo, err = s.api.GetAddressUtxo(desc, onlyConfirmed)
+			utxo, err = s.api.GetAddressUtxo(desc, onlyConfirmed, pendingSpent)
 			s.metrics.ExplorerViews.With(common.Labels{"action": "api-address-utxo"}).Inc()
 		}
 		if err == nil && apiVersion == apiV1 {
@@ -1061,48 +1192,194 @@ func (s *PublicServer) apiUtxo(r *http.Request, apiVersion int) (interface{}, er
 	return utxo, err
 }
 
+// getPendingSpentQueryParam returns the value of the parameter pendingSpent, which requests also the utxos spent in mempool
+func getPendingSpentQueryParam(r *http.Request) (bool, error) {
+	p := r.URL.Query().Get("pendingSpent")
+	if len(p) == 0 {
+		return false, nil
+	}
+	pendingSpent, err := strconv.ParseBool(p)
+	if err != nil {
+		return false, api.NewAPIError("Parameter 'pendingSpent' cannot be converted to boolean", true)
+	}
+	return pendingSpent, nil
+}
+
+type balanceHistoryParams struct {
+	fromTimestamp int64
+	toTimestamp   int64
+	fiatArray     []string
+	gap           int
+	groupBy       uint32
+}
+
+func getBalanceHistoryQueryParams(r *http.Request) (*balanceHistoryParams, error) {
+	var p balanceHistoryParams
+	var err error
+	gap, ec := strconv.Atoi(r.URL.Query().Get("gap"))
+	if ec != nil {
+		gap = 0
+	}
+	p.gap = gap
+	from := r.URL.Query().Get("from")
+	if from != "" {
+		p.fromTimestamp, err = strconv.ParseInt(from, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	to := r.URL.Query().Get("to")
+	if to != "" {
+		p.toTimestamp, err = strconv.ParseInt(to, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	groupBy, err := strconv.ParseUint(r.URL.Query().Get("groupBy"), 10, 32)
+	if err != nil || groupBy == 0 {
+		groupBy = 3600
+	}
+	p.groupBy = uint32(groupBy)
+	fiat := r.URL.Query().Get("fiatcurrency")
+	if fiat != "" {
+		p.fiatArray = []string{fiat}
+	}
+	return &p, nil
+}
+
 func (s *PublicServer) apiBalanceHistory(r *http.Request, apiVersion int) (interface{}, error) {
 	var history []api.BalanceHistory
-	var fromTimestamp, toTimestamp int64
 	var err error
 	if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
-		gap, ec := strconv.Atoi(r.URL.Query().Get("gap"))
+		p, err := getBalanceHistoryQueryParams(r)
+		if err != nil {
+			return history, err
+		}
+		history, err = s.api.GetXpubBalanceHistory(r.URL.Path[i+1:], p.fromTimestamp, p.toTimestamp, p.fiatArray, p.gap, p.groupBy)
+		if err == nil {
+			s.metrics.ExplorerViews.With(common.Labels{"action": "api-xpub-balancehistory"}).Inc()
+		} else {
+			history, err = s.api.GetBalanceHistory(r.URL.Path[i+1:], p.fromTimestamp, p.toTimestamp, p.fiatArray, p.groupBy)
+			s.metrics.ExplorerViews.With(common.Labels{"action": "api-address-balancehistory"}).Inc()
+		}
+		return history, err
+	}
+	return history, err
+}
+
+// getBalanceAtQueryParams parses the height or timestamp of the balance-at query, exactly one of them must be specified
+func getBalanceAtQueryParams(r *http.Request) (height uint32, timestamp int64, gap int, err error) {
+	gap, ec := strconv.Atoi(r.URL.Query().Get("gap"))
+	if ec != nil {
+		gap = 0
+	}
+	h := r.URL.Query().Get("height")
+	t := r.URL.Query().Get("timestamp")
+	if (h == "") == (t == "") {
+		return 0, 0, 0, api.NewAPIError("Specify either height or timestamp", true)
+	}
+	if h != "" {
+		hi, ec := strconv.ParseUint(h, 10, 32)
 		if ec != nil {
-			gap = 0
+			return 0, 0, 0, api.NewAPIError("Invalid height", true)
 		}
-		from := r.URL.Query().Get("from")
-		if from != "" {
-			fromTimestamp, err = strconv.ParseInt(from, 10, 64)
-			if err != nil {
-				return history, err
-			}
+		return uint32(hi), 0, gap, nil
+	}
+	timestamp, ec = strconv.ParseInt(t, 10, 64)
+	if ec != nil || timestamp <= 0 {
+		return 0, 0, 0, api.NewAPIError("Invalid timestamp", true)
+	}
+	return 0, timestamp, gap, nil
+}
+
+func (s *PublicServer) apiBalanceAt(r *http.Request, apiVersion int) (interface{}, error) {
+	var balance *api.BalanceAt
+	var err error
+	if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
+		height, timestamp, gap, err := getBalanceAtQueryParams(r)
+		if err != nil {
+			return nil, err
+		}
+		balance, err = s.api.GetXpubBalanceAt(r.URL.Path[i+1:], height, timestamp, gap)
+		if err == nil {
+			s.metrics.ExplorerViews.With(common.Labels{"action": "api-xpub-balance-at"}).Inc()
+		} else {
+			balance, err = s.api.GetBalanceAt(r.URL.Path[i+1:], height, timestamp)
+			s.metrics.ExplorerViews.With(common.Labels{"action": "api-address-balance-at"}).Inc()
+		}
+		return balance, err
+	}
+	return balance, err
+}
+
+type resultDeleteWatchGroup struct {
+	Deleted bool `json:"deleted"`
+}
+
+// apiWatchGroup handles the watch group endpoints:
+// POST group/ creates a group, GET group/<id>, group/<id>/utxo and group/<id>/balancehistory query it, DELETE group/<id> removes it
+func (s *PublicServer) apiWatchGroup(r *http.Request, apiVersion int) (interface{}, error) {
+	var param string
+	if i := strings.LastIndex(r.URL.Path, "group/"); i > 0 {
+		param = strings.TrimSuffix(r.URL.Path[i+6:], "/")
+	}
+	if r.Method == http.MethodPost {
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-group-create"}).Inc()
+		var req struct {
+			Descriptors []string `json:"descriptors"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, api.NewAPIError("Invalid watch group data", true)
+		}
+		return s.api.CreateWatchGroup(req.Descriptors, getIP(r))
+	}
+	var action string
+	if i := strings.IndexByte(param, '/'); i >= 0 {
+		param, action = param[:i], param[i+1:]
+	}
+	if len(param) == 0 {
+		return nil, api.NewAPIError("Missing watch group id", true)
+	}
+	if r.Method == http.MethodDelete {
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-group-delete"}).Inc()
+		if err := s.api.DeleteWatchGroup(param); err != nil {
+			return nil, err
 		}
-		to := r.URL.Query().Get("to")
-		if to != "" {
-			toTimestamp, err = strconv.ParseInt(to, 10, 64)
+		return resultDeleteWatchGroup{Deleted: true}, nil
+	}
+	switch action {
+	case "":
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-group"}).Inc()
+		page, pageSize, details, filter, _, gap := s.getAddressQueryParams(r, api.AccountDetailsTxidHistory, txsInAPI)
+		return s.api.GetWatchGroupAddress(param, page, pageSize, details, filter, gap)
+	case "utxo":
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-group-utxo"}).Inc()
+		onlyConfirmed := false
+		if c := r.URL.Query().Get("confirmed"); len(c) > 0 {
+			var err error
+			onlyConfirmed, err = strconv.ParseBool(c)
 			if err != nil {
-				return history, err
+				return nil, api.NewAPIError("Parameter 'confirmed' cannot be converted to boolean", true)
 			}
 		}
-		var groupBy uint64
-		groupBy, err = strconv.ParseUint(r.URL.Query().Get("groupBy"), 10, 32)
-		if err != nil || groupBy == 0 {
-			groupBy = 3600
+		pendingSpent, err := getPendingSpentQueryParam(r)
+		if err != nil {
+			return nil, err
 		}
-		fiat := r.URL.Query().Get("fiatcurrency")
-		var fiatArray []string
-		if fiat != "" {
-			fiatArray = []string{fiat}
+		gap, ec := strconv.Atoi(r.URL.Query().Get("gap"))
+		if ec != nil {
+			gap = 0
 		}
-		history, err = s.api.GetXpubBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, gap, uint32(groupBy))
-		if err == nil {
-			s.metrics.ExplorerViews.With(common.Labels{"action": "api-xpub-balancehistory"}).Inc()
-		} else {
-			history, err = s.api.GetBalanceHistory(r.URL.Path[i+1:], fromTimestamp, toTimestamp, fiatArray, uint32(groupBy))
-			s.metrics.ExplorerViews.With(common.Labels{"action": "api-address-balancehistory"}).Inc()
+		return s.api.GetWatchGroupUtxo(param, onlyConfirmed, pendingSpent, gap)
+	case "balancehistory":
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-group-balancehistory"}).Inc()
+		p, err := getBalanceHistoryQueryParams(r)
+		if err != nil {
+			return nil, err
 		}
+		return s.api.GetWatchGroupBalanceHistory(param, p.fromTimestamp, p.toTimestamp, p.fiatArray, p.gap, p.groupBy)
 	}
-	return history, err
+	return nil, api.NewAPIError(fmt.Sprintf("Unknown watch group action '%v'", action), true)
 }
 
 func (s *PublicServer) apiBlock(r *http.Request, apiVersion int) (interface{}, error) {
@@ -1137,11 +1414,161 @@ func (s *PublicServer) apiFeeStats(r *http.Request, apiVersion int) (interface{}
 	var err error
 	s.metrics.ExplorerViews.With(common.Labels{"action": "api-feestats"}).Inc()
 	if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
-		feeStats, err = s.api.GetFeeStats(r.URL.Path[i+1:])
+		bid := r.URL.Path[i+1:]
+		if len(bid) == 0 {
+			return s.apiFeeStatsRange(r)
+		}
+		feeStats, err = s.api.GetFeeStats(bid)
 	}
 	return feeStats, err
 }
 
+func (s *PublicServer) apiFeeStatsRange(r *http.Request) (interface{}, error) {
+	f := r.URL.Query().Get("from")
+	if len(f) == 0 {
+		return nil, api.NewAPIError("Missing parameter 'from'", true)
+	}
+	from, err := strconv.Atoi(f)
+	if err != nil {
+		return nil, api.NewAPIError("Parameter 'from' is not a number", true)
+	}
+	var to int
+	if t := r.URL.Query().Get("to"); len(t) > 0 {
+		if to, err = strconv.Atoi(t); err != nil {
+			return nil, api.NewAPIError("Parameter 'to' is not a number", true)
+		}
+	} else {
+		bestHeight, _, err := s.db.GetBestBlock()
+		if err != nil {
+			return nil, err
+		}
+		to = int(bestHeight)
+	}
+	return s.api.GetFeeStatsRange(from, to)
+}
+
+func (s *PublicServer) apiSupplyStats(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-supply"}).Inc()
+	bestHeight, _, err := s.db.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	to := int(bestHeight)
+	if t := r.URL.Query().Get("to"); len(t) > 0 {
+		if to, err = strconv.Atoi(t); err != nil {
+			return nil, api.NewAPIError("Parameter 'to' is not a number", true)
+		}
+	}
+	// without parameters, return the statistics of the best block
+	from := to
+	if f := r.URL.Query().Get("from"); len(f) > 0 {
+		if from, err = strconv.Atoi(f); err != nil {
+			return nil, api.NewAPIError("Parameter 'from' is not a number", true)
+		}
+	}
+	step := 1
+	if st := r.URL.Query().Get("step"); len(st) > 0 {
+		if step, err = strconv.Atoi(st); err != nil {
+			return nil, api.NewAPIError("Parameter 'step' is not a number", true)
+		}
+	}
+	return s.api.GetSupplyStatsRange(from, to, step)
+}
+
+func (s *PublicServer) apiBlockFilter(r *http.Request, apiVersion int) (interface{}, error) {
+	var filter *api.BlockFilter
+	var err error
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-blockfilter"}).Inc()
+	if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
+		filter, err = s.api.GetBlockFilter(r.URL.Path[i+1:])
+	}
+	return filter, err
+}
+
+func (s *PublicServer) apiBlockFilterHeaders(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-blockfilterheaders"}).Inc()
+	f := r.URL.Query().Get("from")
+	if len(f) == 0 {
+		return nil, api.NewAPIError("Missing parameter 'from'", true)
+	}
+	from, err := strconv.Atoi(f)
+	if err != nil {
+		return nil, api.NewAPIError("Parameter 'from' is not a number", true)
+	}
+	var to int
+	if t := r.URL.Query().Get("to"); len(t) > 0 {
+		if to, err = strconv.Atoi(t); err != nil {
+			return nil, api.NewAPIError("Parameter 'to' is not a number", true)
+		}
+	} else {
+		bestHeight, _, err := s.db.GetBestBlock()
+		if err != nil {
+			return nil, err
+		}
+		to = int(bestHeight)
+	}
+	return s.api.GetBlockFilterHeaders(from, to)
+}
+
+func (s *PublicServer) apiMempool(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-mempool"}).Inc()
+	return s.api.GetMempoolInfo()
+}
+
+func (s *PublicServer) apiMempoolStats(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-mempoolstats"}).Inc()
+	return s.api.GetMempoolStats()
+}
+
+func (s *PublicServer) apiOpReturns(r *http.Request, apiVersion int) (interface{}, error) {
+	var opReturns *api.OpReturns
+	var err error
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-opreturn"}).Inc()
+	if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
+		page, ec := strconv.Atoi(r.URL.Query().Get("page"))
+		if ec != nil {
+			page = 0
+		}
+		pageSize, ec := strconv.Atoi(r.URL.Query().Get("pageSize"))
+		if ec != nil || pageSize <= 0 || pageSize > opReturnsInAPI {
+			pageSize = opReturnsInAPI
+		}
+		opReturns, err = s.api.GetOpReturns(r.URL.Path[i+1:], page, pageSize)
+	}
+	return opReturns, err
+}
+
+func (s *PublicServer) apiChainTips(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-chaintips"}).Inc()
+	return s.api.GetChainTips()
+}
+
+func (s *PublicServer) apiRichList(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-richlist"}).Inc()
+	page, ec := strconv.Atoi(r.URL.Query().Get("page"))
+	if ec != nil {
+		page = 0
+	}
+	pageSize, ec := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if ec != nil || pageSize <= 0 || pageSize > richListInAPI {
+		pageSize = richListInAPI
+	}
+	return s.api.GetRichList(page, pageSize)
+}
+
+func (s *PublicServer) apiTokenHolders(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-tokenholders"}).Inc()
+	page, ec := strconv.Atoi(r.URL.Query().Get("page"))
+	if ec != nil {
+		page = 0
+	}
+	pageSize, ec := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if ec != nil || pageSize <= 0 || pageSize > richListInAPI {
+		pageSize = richListInAPI
+	}
+	return s.api.GetTokenHolders(r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:], page, pageSize)
+}
+
 type resultSendTransaction struct {
 	Result string `json:"result"`
 }
@@ -1172,6 +1599,126 @@ func (s *PublicServer) apiSendTx(r *http.Request, apiVersion int) (interface{},
 	return nil, api.NewAPIError("Missing tx blob", true)
 }
 
+// getParamsV1 returns the parameters of the legacy api v1 request, passed in the query, in the form or in the json body
+func getParamsV1(r *http.Request) (map[string]string, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, api.NewAPIError("Invalid request parameters", true)
+	}
+	params := make(map[string]string, len(r.Form))
+	for k := range r.Form {
+		params[k] = r.Form.Get(k)
+	}
+	if r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, api.NewAPIError("Invalid json body", true)
+		}
+		for k, v := range body {
+			if _, found := params[k]; !found {
+				params[k] = fmt.Sprint(v)
+			}
+		}
+	}
+	return params, nil
+}
+
+// apiAddressesV1 handles the legacy api v1 requests addrs/{addrs}/utxo, addrs/{addrs}/txs and their POST variants addrs/utxo and addrs/txs
+func (s *PublicServer) apiAddressesV1(r *http.Request, apiVersion int) (interface{}, error) {
+	var addrs, action string
+	if i := strings.LastIndex(r.URL.Path, "addrs/"); i > 0 {
+		addrs = r.URL.Path[i+6:]
+	}
+	if i := strings.LastIndexByte(addrs, '/'); i >= 0 {
+		action = addrs[i+1:]
+		addrs = addrs[:i]
+	} else {
+		action = addrs
+		addrs = ""
+	}
+	params, err := getParamsV1(r)
+	if err != nil {
+		return nil, err
+	}
+	if addrs == "" {
+		addrs = params["addrs"]
+	}
+	if addrs == "" {
+		return nil, api.NewAPIError("Missing addrs", true)
+	}
+	addresses := strings.Split(addrs, ",")
+	switch action {
+	case "utxo":
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-addrs-utxo"}).Inc()
+		return s.api.GetAddressesUtxoV1(addresses)
+	case "txs":
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-addrs-txs"}).Inc()
+		from, ec := strconv.Atoi(params["from"])
+		if ec != nil {
+			from = 0
+		}
+		to, ec := strconv.Atoi(params["to"])
+		if ec != nil {
+			to = from + 10
+		}
+		return s.api.GetAddressesTxsV1(addresses, from, to)
+	}
+	return nil, api.NewAPIError(fmt.Sprintf("Unknown addrs action '%v'", action), true)
+}
+
+// apiTxsV1 handles the legacy api v1 request txs?block=HASH&pageNum=N or txs?address=ADDR&pageNum=N
+func (s *PublicServer) apiTxsV1(r *http.Request, apiVersion int) (interface{}, error) {
+	pageNum, ec := strconv.Atoi(r.URL.Query().Get("pageNum"))
+	if ec != nil || pageNum < 0 {
+		pageNum = 0
+	}
+	if block := r.URL.Query().Get("block"); block != "" {
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-txs-block"}).Inc()
+		return s.api.GetBlockTxsV1(block, pageNum)
+	}
+	if address := r.URL.Query().Get("address"); address != "" {
+		s.metrics.ExplorerViews.With(common.Labels{"action": "api-txs-address"}).Inc()
+		return s.api.GetAddressTxsV1(address, pageNum)
+	}
+	return nil, api.NewAPIError("Block hash or address expected", true)
+}
+
+func (s *PublicServer) apiStatusV1(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-status"}).Inc()
+	return s.api.GetStatusV1(r.URL.Query().Get("q"))
+}
+
+func (s *PublicServer) apiSyncV1(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-sync"}).Inc()
+	return s.api.GetSyncV1(), nil
+}
+
+func (s *PublicServer) apiRawTxV1(r *http.Request, apiVersion int) (interface{}, error) {
+	var txid string
+	if i := strings.LastIndexByte(r.URL.Path, '/'); i > 0 {
+		txid = r.URL.Path[i+1:]
+	}
+	if len(txid) == 0 {
+		return nil, api.NewAPIError("Missing txid", true)
+	}
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-rawtx"}).Inc()
+	return s.api.GetRawTxV1(txid)
+}
+
+func (s *PublicServer) apiMessageVerifyV1(r *http.Request, apiVersion int) (interface{}, error) {
+	s.metrics.ExplorerViews.With(common.Labels{"action": "api-messages-verify"}).Inc()
+	params, err := getParamsV1(r)
+	if err != nil {
+		return nil, err
+	}
+	address, okA := params["address"]
+	signature, okS := params["signature"]
+	message, okM := params["message"]
+	if !okA || !okS || !okM {
+		return nil, api.NewAPIError("Missing parameters (expected \"address\", \"signature\" and \"message\")", true)
+	}
+	return s.api.VerifyMessageV1(address, signature, message)
+}
+
 // apiTickersList returns a list of available FiatRates currencies
 func (s *PublicServer) apiTickersList(r *http.Request, apiVersion int) (interface{}, error) {
 	s.metrics.ExplorerViews.With(common.Labels{"action": "api-tickers-list"}).Inc()
@@ -1279,7 +1826,11 @@ func (s *PublicServer) apiEstimateFee(r *http.Request, apiVersion int) (interfac
 				}
 			}
 			var fee big.Int
-			fee, err = s.chain.EstimateSmartFee(blocks, conservative)
+			if s.chainParser.GetChainType() == bchain.ChainBitcoinType {
+				fee, err = s.api.BitcoinTypeEstimateFee(blocks, conservative)
+			} else {
+				fee, err = s.chain.EstimateSmartFee(blocks, conservative)
+			}
 			if err != nil {
 				fee, err = s.chain.EstimateFee(blocks)
 				if err != nil {
@@ -3,8 +3,11 @@
 package server
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -24,6 +27,10 @@ import (
 	"github.com/trezor/blockbook/common"
 	"github.com/trezor/blockbook/db"
 	"github.com/trezor/blockbook/tests/dbtestdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func TestMain(m *testing.M) {
@@ -36,6 +43,8 @@ func TestMain(m *testing.M) {
 	os.Exit(c)
 }
 
+const testWatchGroupID = "0123456789abcdef0123456789abcdef"
+
 func setupRocksDB(t *testing.T, parser bchain.BlockChainParser) (*db.RocksDB, *common.InternalState, string) {
 	tmp, err := ioutil.TempDir("", "testdb")
 	if err != nil {
@@ -50,6 +59,9 @@ func setupRocksDB(t *testing.T, parser bchain.BlockChainParser) (*db.RocksDB, *c
 		t.Fatal(err)
 	}
 	d.SetInternalState(is)
+	if err := d.InitBlockFilters(true); err != nil {
+		t.Fatal(err)
+	}
 	block1 := dbtestdata.GetTestBitcoinTypeBlock1(parser)
 	// setup internal state BlockTimes
 	for i := uint32(0); i < block1.Height; i++ {
@@ -59,6 +71,16 @@ func setupRocksDB(t *testing.T, parser bchain.BlockChainParser) (*db.RocksDB, *c
 	if err := d.ConnectBlock(block1); err != nil {
 		t.Fatal(err)
 	}
+	// build the rich list, the OP_RETURN index and the script hash index after the first block, the second block updates them
+	if err := d.InitRichList(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.InitOpReturnIndex(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.InitScriptHashIndex(true, nil); err != nil {
+		t.Fatal(err)
+	}
 	block2 := dbtestdata.GetTestBitcoinTypeBlock2(parser)
 	if err := d.ConnectBlock(block2); err != nil {
 		t.Fatal(err)
@@ -66,6 +88,14 @@ func setupRocksDB(t *testing.T, parser bchain.BlockChainParser) (*db.RocksDB, *c
 	if err := InitTestFiatRates(d); err != nil {
 		t.Fatal(err)
 	}
+	if err := d.StoreWatchGroup(&db.WatchGroup{
+		ID:          testWatchGroupID,
+		Descriptors: []string{dbtestdata.Xpub, dbtestdata.Addr4, dbtestdata.Addr5},
+		Created:     1521595678,
+		LastUsed:    time.Now().Unix(),
+	}); err != nil {
+		t.Fatal(err)
+	}
 	is.FinishedSync(block2.Height)
 	return d, is, tmp
 }
@@ -154,6 +184,14 @@ func newPostRequest(u string, body string) *http.Request {
 	return r
 }
 
+func newDeleteRequest(u string) *http.Request {
+	r, err := http.NewRequest("DELETE", u, nil)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	return r
+}
+
 func insertFiatRate(date string, rates map[string]float64, d *db.RocksDB) error {
 	convertedDate, err := db.FiatRatesConvertDate(date)
 	if err != nil {
@@ -305,6 +343,18 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 				`</html>`,
 			},
 		},
+		{
+			name:        "explorerRichList",
+			r:           newGetRequest(ts.URL + "/richlist"),
+			status:      http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			body: []string{
+				`<a class="navbar-brand" href="/">Fake Coin Explorer</a>`,
+				`<h1>Rich List`,
+				`<td>1</td><td class="ellipsis"><a href="/address/mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL">mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL</a></td><td>9172.83951061 FAKE</td><td>74.2122%</td><td>1</td><td><a href="/block/225494">225494</a></td>`,
+				`</html>`,
+			},
+		},
 		{
 			name:        "explorerIndex",
 			r:           newGetRequest(ts.URL + "/"),
@@ -335,6 +385,19 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 				`</html>`,
 			},
 		},
+		{
+			name:        "explorerSearch OP_RETURN",
+			r:           newGetRequest(ts.URL + "/search?q=OP_RETURN+2020f1"),
+			status:      http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			body: []string{
+				`<a class="navbar-brand" href="/">Fake Coin Explorer</a>`,
+				`<h1>OP_RETURN`,
+				`<span class="data">2020f1</span>`,
+				`<td class="ellipsis"><a href="/tx/7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25">7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25</a></td><td class="ellipsis">2020f1686f6a20</td><td><a href="/block/225494">225494</a></td><td>1</td>`,
+				`</html>`,
+			},
+		},
 		{
 			name:        "explorerSearch block hash",
 			r:           newGetRequest(ts.URL + "/search?q=00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6"),
@@ -502,7 +565,7 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 			status:      http.StatusOK,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vin":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":2,"n":0,"addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true,"value":"9876"}],"vout":[{"value":"9000","n":0,"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"value":"9000","valueIn":"9876","fees":"876"}`,
+				`{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vin":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":2,"n":0,"addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true,"value":"9876"}],"vout":[{"value":"9000","n":0,"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"vsize":371,"feeRate":2.36,"value":"9000","valueIn":"9876","fees":"876"}`,
 			},
 		},
 		{
@@ -529,7 +592,144 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 			status:      http.StatusOK,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"txCount":3,"totalFeesSat":"1284","averageFeePerKb":1398,"decilesFeePerKb":[155,155,155,155,1679,1679,1679,2361,2361,2361,2361]}`,
+				`{"height":225494,"time":1521595678,"txCount":3,"totalFeesSat":"1284","totalVSize":977,"averageFeePerKb":1398,"minFeePerKb":155,"maxFeePerKb":2361,"decilesFeePerKb":[155,155,155,155,1679,1679,1679,2361,2361,2361,2361],"segwitTxCount":0,"taprootTxCount":0}`,
+			},
+		},
+		{
+			name:        "apiFeeStats range",
+			r:           newGetRequest(ts.URL + "/api/v2/feestats/?from=225493&to=225494"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"from":225493,"to":225494,"feeStats":[{"height":225493,"time":1521515026,"txCount":0,"totalFeesSat":"0","totalVSize":0,"averageFeePerKb":0,"minFeePerKb":0,"maxFeePerKb":0,"decilesFeePerKb":[0,0,0,0,0,0,0,0,0,0,0],"segwitTxCount":0,"taprootTxCount":0},{"height":225494,"time":1521595678,"txCount":3,"totalFeesSat":"1284","totalVSize":977,"averageFeePerKb":1398,"minFeePerKb":155,"maxFeePerKb":2361,"decilesFeePerKb":[155,155,155,155,1679,1679,1679,2361,2361,2361,2361],"segwitTxCount":0,"taprootTxCount":0}]}`,
+			},
+		},
+		{
+			name:        "apiFeeStats range missing from",
+			r:           newGetRequest(ts.URL + "/api/v2/feestats/?to=225494"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Missing parameter 'from'"}`,
+			},
+		},
+		{
+			name:        "apiSupplyStats",
+			r:           newGetRequest(ts.URL + "/api/v2/supply/"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"from":225494,"to":225494,"step":1,"supplyStats":[{"height":225494,"time":1521595678,"issuedSat":"1236027953737","burnedSat":"0","utxoSetSat":"1236027953737","utxos":8,"outputs":13,"spentOutputs":5}]}`,
+			},
+		},
+		{
+			name:        "apiSupplyStats range",
+			r:           newGetRequest(ts.URL + "/api/v2/supply/?from=225000&to=225494&step=1"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"from":225000,"to":225494,"step":1,"supplyStats":[{"height":225493,"time":1521515026,"issuedSat":"1234667924690","burnedSat":"0","utxoSetSat":"1234667924690","utxos":6,"outputs":6,"spentOutputs":0},{"height":225494,"time":1521595678,"issuedSat":"1236027953737","burnedSat":"0","utxoSetSat":"1236027953737","utxos":8,"outputs":13,"spentOutputs":5}]}`,
+			},
+		},
+		{
+			name:        "apiSupplyStats invalid step",
+			r:           newGetRequest(ts.URL + "/api/v2/supply/?from=225493&step=0"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Invalid step"}`,
+			},
+		},
+		{
+			name:        "apiBlockFilter",
+			r:           newGetRequest(ts.URL + "/api/v2/blockfilter/225494"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"height":225494,"hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","filterHash":"d639b3fefcd335e9d769391635810475de10f1ae86dc16b182780621b2acc893","filterType":"basic","filter":"09ea6890f708b5824e9724de06a5539aa7624e22b784875628"}`,
+			},
+		},
+		{
+			name:        "apiBlockFilter hash",
+			r:           newGetRequest(ts.URL + "/api/v2/blockfilter/0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"height":225493,"hash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","filterHash":"b47f8db8e97909a9f48c69fc0a5512e1d07bf8ea042432e2bbbb89ee1f9d5dde","filterType":"basic","filter":"0503a28c0bf22c1aa04f72dc5ffec0"}`,
+			},
+		},
+		{
+			name:        "apiBlockFilterHeaders",
+			r:           newGetRequest(ts.URL + "/api/v2/blockfilterheaders/?from=225493"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"from":225493,"to":225494,"headers":[{"height":225493,"hash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","filterHash":"b47f8db8e97909a9f48c69fc0a5512e1d07bf8ea042432e2bbbb89ee1f9d5dde"},{"height":225494,"hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","filterHash":"d639b3fefcd335e9d769391635810475de10f1ae86dc16b182780621b2acc893"}]}`,
+			},
+		},
+		{
+			name:        "apiBlockFilterHeaders missing from",
+			r:           newGetRequest(ts.URL + "/api/v2/blockfilterheaders/?to=225494"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Missing parameter 'from'"}`,
+			},
+		},
+		{
+			name:        "apiOpReturns",
+			r:           newGetRequest(ts.URL + "/api/v2/opreturn/2020?pageSize=10"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":1,"itemsOnPage":10,"prefix":"2020","outputs":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","vout":2,"height":225494,"confirmations":1,"data":"2020f1686f6a20"}]}`,
+			},
+		},
+		{
+			name:        "apiOpReturns no match",
+			r:           newGetRequest(ts.URL + "/api/v2/opreturn/2021"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":1,"itemsOnPage":1000,"prefix":"2021","outputs":[]}`,
+			},
+		},
+		{
+			name:        "apiOpReturns invalid prefix",
+			r:           newGetRequest(ts.URL + "/api/v2/opreturn/xyz"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Prefix is not a hex string"}`,
+			},
+		},
+		{
+			name:        "apiMempool",
+			r:           newGetRequest(ts.URL + "/api/v2/mempool/"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"size":0,"vsize":0,"totalFees":"0","feeHistogram":[{"feePerVByte":0,"count":0,"vsize":0},{"feePerVByte":1,"count":0,"vsize":0},{"feePerVByte":2,"count":0,"vsize":0},{"feePerVByte":3,"count":0,"vsize":0},{"feePerVByte":4,"count":0,"vsize":0},{"feePerVByte":5,"count":0,"vsize":0},{"feePerVByte":6,"count":0,"vsize":0},{"feePerVByte":8,"count":0,"vsize":0},{"feePerVByte":10,"count":0,"vsize":0},{"feePerVByte":12,"count":0,"vsize":0},{"feePerVByte":15,"count":0,"vsize":0},{"feePerVByte":20,"count":0,"vsize":0},{"feePerVByte":30,"count":0,"vsize":0},{"feePerVByte":40,"count":0,"vsize":0},{"feePerVByte":50,"count":0,"vsize":0},{"feePerVByte":60,"count":0,"vsize":0},{"feePerVByte":70,"count":0,"vsize":0},{"feePerVByte":80,"count":0,"vsize":0},{"feePerVByte":90,"count":0,"vsize":0},{"feePerVByte":100,"count":0,"vsize":0},{"feePerVByte":125,"count":0,"vsize":0},{"feePerVByte":150,"count":0,"vsize":0},{"feePerVByte":175,"count":0,"vsize":0},{"feePerVByte":200,"count":0,"vsize":0},{"feePerVByte":250,"count":0,"vsize":0},{"feePerVByte":300,"count":0,"vsize":0},{"feePerVByte":350,"count":0,"vsize":0},{"feePerVByte":400,"count":0,"vsize":0},{"feePerVByte":500,"count":0,"vsize":0},{"feePerVByte":600,"count":0,"vsize":0},{"feePerVByte":700,"count":0,"vsize":0},{"feePerVByte":800,"count":0,"vsize":0},{"feePerVByte":900,"count":0,"vsize":0},{"feePerVByte":1000,"count":0,"vsize":0},{"feePerVByte":1200,"count":0,"vsize":0},{"feePerVByte":1400,"count":0,"vsize":0},{"feePerVByte":1700,"count":0,"vsize":0},{"feePerVByte":2000,"count":0,"vsize":0}]}`,
+			},
+		},
+		{
+			name:        "apiMempoolStats",
+			r:           newGetRequest(ts.URL + "/api/v2/mempoolstats/"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"time":`,
+				`,"size":0,"vsize":0,"totalFees":"0","feeHistogram":[{"feePerVByte":0,"count":0,"vsize":0},{"feePerVByte":1,"count":0,"vsize":0},`,
+				`{"feePerVByte":2000,"count":0,"vsize":0}],"projectedBlocks":[]}`,
+			},
+		},
+		{
+			name:        "apiChainTips",
+			r:           newGetRequest(ts.URL + "/api/v2/chaintips/"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"tips":[{"height":225494,"hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","branchLen":0,"status":"active"}]}`,
 			},
 		},
 		{
@@ -691,7 +891,7 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 			status:      http.StatusOK,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"page":1,"totalPages":1,"itemsOnPage":1000,"address":"mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw","balance":"0","totalReceived":"1234567890123","totalSent":"1234567890123","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":2,"transactions":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","vin":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","n":0,"addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"],"isAddress":true,"isOwn":true,"value":"1234567890123"},{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vout":1,"n":1,"addresses":["mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"],"isAddress":true,"value":"12345"}],"vout":[{"value":"317283951061","n":0,"spent":true,"hex":"76a914ccaaaf374e1b06cb83118453d102587b4273d09588ac","addresses":["mzB8cYrfRwFRFAGTDzV8LkUQy5BQicxGhX"],"isAddress":true},{"value":"917283951061","n":1,"hex":"76a9148d802c045445df49613f6a70ddd2e48526f3701f88ac","addresses":["mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL"],"isAddress":true},{"value":"0","n":2,"hex":"6a072020f1686f6a20","addresses":["OP_RETURN 2020f1686f6a20"],"isAddress":false}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"value":"1234567902122","valueIn":"1234567902468","fees":"346"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"1234567890123","n":0,"spent":true,"hex":"76a914a08eae93007f22668ab5e4a9c83c8cd1c325e3e088ac","addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"],"isAddress":true,"isOwn":true},{"value":"1","n":1,"spent":true,"hex":"a91452724c5178682f70e0ba31c6ec0633755a3b41d987","addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true},{"value":"9876","n":2,"spent":true,"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true}],"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockHeight":225493,"confirmations":2,"blockTime":1521515026,"value":"1234567900000","valueIn":"0","fees":"0"}]}`,
+				`{"page":1,"totalPages":1,"itemsOnPage":1000,"address":"mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw","balance":"0","totalReceived":"1234567890123","totalSent":"1234567890123","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":2,"transactions":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","vin":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","n":0,"addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"],"isAddress":true,"isOwn":true,"value":"1234567890123"},{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vout":1,"n":1,"addresses":["mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"],"isAddress":true,"value":"12345"}],"vout":[{"value":"317283951061","n":0,"spent":true,"hex":"76a914ccaaaf374e1b06cb83118453d102587b4273d09588ac","addresses":["mzB8cYrfRwFRFAGTDzV8LkUQy5BQicxGhX"],"isAddress":true},{"value":"917283951061","n":1,"hex":"76a9148d802c045445df49613f6a70ddd2e48526f3701f88ac","addresses":["mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL"],"isAddress":true},{"value":"0","n":2,"hex":"6a072020f1686f6a20","addresses":["OP_RETURN 2020f1686f6a20"],"isAddress":false}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"vsize":206,"feeRate":1.68,"value":"1234567902122","valueIn":"1234567902468","fees":"346"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"1234567890123","n":0,"spent":true,"hex":"76a914a08eae93007f22668ab5e4a9c83c8cd1c325e3e088ac","addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"],"isAddress":true,"isOwn":true},{"value":"1","n":1,"spent":true,"hex":"a91452724c5178682f70e0ba31c6ec0633755a3b41d987","addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true},{"value":"9876","n":2,"spent":true,"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true}],"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockHeight":225493,"confirmations":2,"blockTime":1521515026,"value":"1234567900000","valueIn":"0","fees":"0"}]}`,
 			},
 		},
 		{
@@ -772,7 +972,7 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 			status:      http.StatusOK,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"page":1,"totalPages":1,"itemsOnPage":3,"address":"upub5E1xjDmZ7Hhej6LPpS8duATdKXnRYui7bDYj6ehfFGzWDZtmCmQkZhc3Zb7kgRLtHWd16QFxyP86JKL3ShZEBFX88aciJ3xyocuyhZZ8g6q","balance":"118641975500","totalReceived":"118641975501","totalSent":"1","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":2,"transactions":[{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vin":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","n":0,"addresses":["mzB8cYrfRwFRFAGTDzV8LkUQy5BQicxGhX"],"isAddress":true,"value":"317283951061"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":1,"n":1,"addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true,"isOwn":true,"value":"1"}],"vout":[{"value":"118641975500","n":0,"hex":"a91495e9fbe306449c991d314afe3c3567d5bf78efd287","addresses":["2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu"],"isAddress":true,"isOwn":true},{"value":"198641975500","n":1,"hex":"76a9143f8ba3fda3ba7b69f5818086e12223c6dd25e3c888ac","addresses":["mmJx9Y8ayz9h14yd9fgCW1bUKoEpkBAquP"],"isAddress":true}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"value":"317283951000","valueIn":"317283951062","fees":"62"}],"usedTokens":2,"tokens":[{"type":"XPUBAddress","name":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","path":"m/49'/1'/33'/0/0","transfers":2,"decimals":8,"balance":"0","totalReceived":"1","totalSent":"1"},{"type":"XPUBAddress","name":"2MsYfbi6ZdVXLDNrYAQ11ja9Sd3otMk4Pmj","path":"m/49'/1'/33'/0/1","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MuAZNAjLSo6RLFad2fvHSfgqBD7BoEVy4T","path":"m/49'/1'/33'/0/2","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NEqKzw3BosGnBE9by5uaDy5QgwjHac4Zbg","path":"m/49'/1'/33'/0/3","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mw7vJNC8zUK6VNN4CEjtoTYmuNPLewxZzV","path":"m/49'/1'/33'/0/4","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N1kvo97NFASPXiwephZUxE9PRXunjTxEc4","path":"m/49'/1'/33'/0/5","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MzSBtRWHbBjeUcu3H5VRDqkvz5sfmDxJKo","path":"m/49'/1'/33'/1/0","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MtShtAJYb1afWduUTwF1SixJjan7urZKke","path":"m/49'/1'/33'/1/1","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N3cP668SeqyBEr9gnB4yQEmU3VyxeRYith","path":"m/49'/1'/33'/1/2","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu","path":"m/49'/1'/33'/1/3","transfers":1,"decimals":8,"balance":"118641975500","totalReceived":"118641975500","totalSent":"0"},{"type":"XPUBAddress","name":"2NEzatauNhf9kPTwwj6ZfYKjUdy52j4hVUL","path":"m/49'/1'/33'/1/4","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N4RjsDp4LBpkNqyF91aNjgpF9CwDwBkJZq","path":"m/49'/1'/33'/1/5","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8XygTmQc4NoBBPEy3yybnfCYhsxFtzPDY","path":"m/49'/1'/33'/1/6","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N5BjBomZvb48sccK2vwLMiQ5ETKp1fdPVn","path":"m/49'/1'/33'/1/7","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MybMwbZRPCGU3SMWPwQCpDkbcQFw5Hbwen","path":"m/49'/1'/33'/1/8","transfers":0,"decimals":8}]}`,
+				`{"page":1,"totalPages":1,"itemsOnPage":3,"address":"upub5E1xjDmZ7Hhej6LPpS8duATdKXnRYui7bDYj6ehfFGzWDZtmCmQkZhc3Zb7kgRLtHWd16QFxyP86JKL3ShZEBFX88aciJ3xyocuyhZZ8g6q","balance":"118641975500","totalReceived":"118641975501","totalSent":"1","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":2,"transactions":[{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vin":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","n":0,"addresses":["mzB8cYrfRwFRFAGTDzV8LkUQy5BQicxGhX"],"isAddress":true,"value":"317283951061"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":1,"n":1,"addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true,"isOwn":true,"value":"1"}],"vout":[{"value":"118641975500","n":0,"hex":"a91495e9fbe306449c991d314afe3c3567d5bf78efd287","addresses":["2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu"],"isAddress":true,"isOwn":true},{"value":"198641975500","n":1,"hex":"76a9143f8ba3fda3ba7b69f5818086e12223c6dd25e3c888ac","addresses":["mmJx9Y8ayz9h14yd9fgCW1bUKoEpkBAquP"],"isAddress":true}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"vsize":400,"feeRate":0.16,"value":"317283951000","valueIn":"317283951062","fees":"62"}],"usedTokens":2,"tokens":[{"type":"XPUBAddress","name":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","path":"m/49'/1'/33'/0/0","transfers":2,"decimals":8,"balance":"0","totalReceived":"1","totalSent":"1"},{"type":"XPUBAddress","name":"2MsYfbi6ZdVXLDNrYAQ11ja9Sd3otMk4Pmj","path":"m/49'/1'/33'/0/1","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MuAZNAjLSo6RLFad2fvHSfgqBD7BoEVy4T","path":"m/49'/1'/33'/0/2","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NEqKzw3BosGnBE9by5uaDy5QgwjHac4Zbg","path":"m/49'/1'/33'/0/3","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mw7vJNC8zUK6VNN4CEjtoTYmuNPLewxZzV","path":"m/49'/1'/33'/0/4","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N1kvo97NFASPXiwephZUxE9PRXunjTxEc4","path":"m/49'/1'/33'/0/5","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MzSBtRWHbBjeUcu3H5VRDqkvz5sfmDxJKo","path":"m/49'/1'/33'/1/0","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MtShtAJYb1afWduUTwF1SixJjan7urZKke","path":"m/49'/1'/33'/1/1","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N3cP668SeqyBEr9gnB4yQEmU3VyxeRYith","path":"m/49'/1'/33'/1/2","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu","path":"m/49'/1'/33'/1/3","transfers":1,"decimals":8,"balance":"118641975500","totalReceived":"118641975500","totalSent":"0"},{"type":"XPUBAddress","name":"2NEzatauNhf9kPTwwj6ZfYKjUdy52j4hVUL","path":"m/49'/1'/33'/1/4","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N4RjsDp4LBpkNqyF91aNjgpF9CwDwBkJZq","path":"m/49'/1'/33'/1/5","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8XygTmQc4NoBBPEy3yybnfCYhsxFtzPDY","path":"m/49'/1'/33'/1/6","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N5BjBomZvb48sccK2vwLMiQ5ETKp1fdPVn","path":"m/49'/1'/33'/1/7","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MybMwbZRPCGU3SMWPwQCpDkbcQFw5Hbwen","path":"m/49'/1'/33'/1/8","transfers":0,"decimals":8}]}`,
 			},
 		},
 		{
@@ -802,6 +1002,24 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 				`[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","vout":1,"value":"917283951061","height":225494,"confirmations":1}]`,
 			},
 		},
+		{
+			name:        "apiUtxo v2 pendingSpent",
+			r:           newGetRequest(ts.URL + "/api/v2/utxo/mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL?pendingSpent=true"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","vout":1,"value":"917283951061","height":225494,"confirmations":1}]`,
+			},
+		},
+		{
+			name:        "apiUtxo v2 invalid pendingSpent",
+			r:           newGetRequest(ts.URL + "/api/v2/utxo/mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL?pendingSpent=maybe"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Parameter 'pendingSpent' cannot be converted to boolean"}`,
+			},
+		},
 		{
 			name:        "apiUtxo v2 xpub",
 			r:           newGetRequest(ts.URL + "/api/v2/utxo/" + dbtestdata.Xpub),
@@ -893,145 +1111,497 @@ func httpTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 			},
 		},
 		{
-			name:        "apiSendTx",
-			r:           newGetRequest(ts.URL + "/api/v2/sendtx/1234567890"),
-			status:      http.StatusBadRequest,
+			name:        "apiBalanceAt Addr2 height=225493",
+			r:           newGetRequest(ts.URL + "/api/v2/balance-at/mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz?height=225493"),
+			status:      http.StatusOK,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"error":"Invalid data"}`,
+				`{"address":"mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz","height":225493,"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockTime":1521515026,"txs":1,"received":"24690","sent":"0","balance":"24690","utxos":[{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vout":2,"value":"12345","height":225493,"confirmations":1},{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vout":1,"value":"12345","height":225493,"confirmations":1}]}`,
 			},
 		},
 		{
-			name:        "apiSendTx POST",
-			r:           newPostRequest(ts.URL+"/api/v2/sendtx/", "123456"),
+			name:        "apiBalanceAt Addr2 height=225494",
+			r:           newGetRequest(ts.URL + "/api/v2/balance-at/mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz?height=225494"),
 			status:      http.StatusOK,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"result":"9876"}`,
+				`{"address":"mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz","height":225494,"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockTime":1521595678,"txs":2,"received":"24690","sent":"12345","balance":"12345","utxos":[{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vout":2,"value":"12345","height":225493,"confirmations":2}]}`,
 			},
 		},
 		{
-			name:        "apiSendTx POST empty",
-			r:           newPostRequest(ts.URL+"/api/v2/sendtx", ""),
-			status:      http.StatusBadRequest,
+			name:        "apiBalanceAt Addr5 timestamp=1521590400",
+			r:           newGetRequest(ts.URL + "/api/v2/balance-at/2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1?timestamp=1521590400"),
+			status:      http.StatusOK,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"error":"Missing tx blob"}`,
+				`{"address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","height":225493,"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockTime":1521515026,"txs":1,"received":"9876","sent":"0","balance":"9876","utxos":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":2,"value":"9876","height":225493,"confirmations":1}]}`,
 			},
 		},
 		{
-			name:        "apiEstimateFee",
-			r:           newGetRequest(ts.URL + "/api/estimatefee/123?conservative=false"),
+			name:        "apiBalanceAt xpub timestamp=1521590400",
+			r:           newGetRequest(ts.URL + "/api/v2/balance-at/" + dbtestdata.Xpub + "?timestamp=1521590400"),
 			status:      http.StatusOK,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"result":"0.00012299"}`,
+				`{"address":"upub5E1xjDmZ7Hhej6LPpS8duATdKXnRYui7bDYj6ehfFGzWDZtmCmQkZhc3Zb7kgRLtHWd16QFxyP86JKL3ShZEBFX88aciJ3xyocuyhZZ8g6q","height":225493,"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockTime":1521515026,"txs":1,"received":"1","sent":"0","balance":"1","utxos":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":1,"value":"1","height":225493,"confirmations":1,"address":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","path":"m/49'/1'/33'/0/0"}]}`,
 			},
 		},
 		{
-			name:        "apiGetBlock",
-			r:           newGetRequest(ts.URL + "/api/v2/block/225493"),
+			name:        "apiBalanceAt xpub height=225494",
+			r:           newGetRequest(ts.URL + "/api/v2/balance-at/" + dbtestdata.Xpub + "?height=225494"),
 			status:      http.StatusOK,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"page":1,"totalPages":1,"itemsOnPage":1000,"hash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","nextBlockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","height":225493,"confirmations":2,"size":1234567,"time":1521515026,"version":0,"merkleRoot":"","nonce":"","bits":"","difficulty":"","txCount":2,"txs":[{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vin":[],"vout":[{"value":"100000000","n":0,"addresses":["mfcWp7DB6NuaZsExybTTXpVgWz559Np4Ti"],"isAddress":true},{"value":"12345","n":1,"spent":true,"addresses":["mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"],"isAddress":true},{"value":"12345","n":2,"addresses":["mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"],"isAddress":true}],"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockHeight":225493,"confirmations":2,"blockTime":1521515026,"value":"100024690","valueIn":"0","fees":"0"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"1234567890123","n":0,"spent":true,"addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"],"isAddress":true},{"value":"1","n":1,"spent":true,"addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true},{"value":"9876","n":2,"spent":true,"addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true}],"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockHeight":225493,"confirmations":2,"blockTime":1521515026,"value":"1234567900000","valueIn":"0","fees":"0"}]}`,
+				`{"address":"upub5E1xjDmZ7Hhej6LPpS8duATdKXnRYui7bDYj6ehfFGzWDZtmCmQkZhc3Zb7kgRLtHWd16QFxyP86JKL3ShZEBFX88aciJ3xyocuyhZZ8g6q","height":225494,"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockTime":1521595678,"txs":3,"received":"118641975501","sent":"1","balance":"118641975500","utxos":[{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vout":0,"value":"118641975500","height":225494,"confirmations":1,"address":"2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu","path":"m/49'/1'/33'/1/3"}]}`,
 			},
 		},
 		{
-			name:        "apiGetRawBlock",
-			r:           newGetRequest(ts.URL + "/api/v2/rawblock/225493"),
-			status:      http.StatusOK,
+			name:        "apiBalanceAt Addr2 timestamp before first block",
+			r:           newGetRequest(ts.URL + "/api/v2/balance-at/mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz?timestamp=1500000000"),
+			status:      http.StatusBadRequest,
 			contentType: "application/json; charset=utf-8",
 			body: []string{
-				`{"hex":"00e0ff3fd42677a86f1515bafcf9802c1765e02226655a9b97fd44132602000000000000"}`,
+				`{"error":"Block 225492 not found"}`,
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			resp, err := http.DefaultClient.Do(tt.r)
-			if err != nil {
-				t.Fatal(err)
-			}
-			defer resp.Body.Close()
-			if resp.StatusCode != tt.status {
-				t.Errorf("StatusCode = %v, want %v", resp.StatusCode, tt.status)
-			}
-			if resp.Header["Content-Type"][0] != tt.contentType {
-				t.Errorf("Content-Type = %v, want %v", resp.Header["Content-Type"][0], tt.contentType)
-			}
-			bb, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				t.Fatal(err)
-			}
-			b := string(bb)
-			for _, c := range tt.body {
-				if !strings.Contains(b, c) {
-					t.Errorf("got %v, want to contain %v", b, c)
-					break
-				}
-			}
-		})
-	}
-}
-
-func socketioTestsBitcoinType(t *testing.T, ts *httptest.Server) {
-	type socketioReq struct {
-		Method string        `json:"method"`
-		Params []interface{} `json:"params"`
-	}
-
-	url := strings.Replace(ts.URL, "http://", "ws://", 1) + "/socket.io/"
-	s, err := gosocketio.Dial(url, transport.GetDefaultWebsocketTransport())
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer s.Close()
-
-	tests := []struct {
-		name string
-		req  socketioReq
-		want string
-	}{
 		{
-			name: "socketio getInfo",
-			req:  socketioReq{"getInfo", []interface{}{}},
-			want: `{"result":{"blocks":225494,"testnet":true,"network":"fakecoin","subversion":"/Fakecoin:0.0.1/","coin_name":"Fakecoin","about":"Blockbook - blockchain indexer for Trezor wallet https://trezor.io/. Do not use for any other purpose."}}`,
+			name:        "apiBalanceAt Addr2 height and timestamp",
+			r:           newGetRequest(ts.URL + "/api/v2/balance-at/mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz?height=225494&timestamp=1521590400"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Specify either height or timestamp"}`,
+			},
 		},
 		{
-			name: "socketio estimateFee",
-			req:  socketioReq{"estimateFee", []interface{}{17}},
-			want: `{"result":0.000034}`,
+			name:        "apiRichList",
+			r:           newGetRequest(ts.URL + "/api/v2/richlist/?pageSize=2"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":4,"itemsOnPage":2,"totalAddresses":7,"totalBalance":"1236027953737","addresses":[{"rank":1,"address":"mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL","balance":"917283951061","sharePercent":74.21223349259124,"txs":1,"lastHeight":225494},{"rank":2,"address":"mmJx9Y8ayz9h14yd9fgCW1bUKoEpkBAquP","balance":"198641975500","sharePercent":16.07099377481124,"txs":1,"lastHeight":225494}]}`,
+			},
 		},
 		{
-			name: "socketio estimateSmartFee",
-			req:  socketioReq{"estimateSmartFee", []interface{}{19, true}},
-			want: `{"result":0.000019}`,
+			name:        "apiRichList page=4",
+			r:           newGetRequest(ts.URL + "/api/v2/richlist/?pageSize=2&page=4"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":4,"totalPages":4,"itemsOnPage":2,"totalAddresses":7,"totalBalance":"1236027953737","addresses":[{"rank":7,"address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","balance":"9000","sharePercent":7.281388720044276e-7,"txs":2,"lastHeight":225494}]}`,
+			},
 		},
 		{
-			name: "socketio getAddressTxids",
-			req: socketioReq{"getAddressTxids", []interface{}{
-				[]string{"mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"},
-				map[string]interface{}{
-					"start":        2000000,
-					"end":          0,
-					"queryMempool": false,
-				},
-			}},
-			want: `{"result":["7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840"]}`,
+			name:        "apiTokenHolders not enabled",
+			r:           newGetRequest(ts.URL + "/api/v2/tokenholders/0x4af4114F73d1c1C903aC9E0361b379D1291808A2"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Token holders are not enabled"}`,
+			},
 		},
 		{
-			name: "socketio getAddressTxids limited range",
-			req: socketioReq{"getAddressTxids", []interface{}{
-				[]string{"mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"},
-				map[string]interface{}{
-					"start":        225494,
-					"end":          225494,
-					"queryMempool": false,
-				},
-			}},
-			want: `{"result":["7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25"]}`,
+			name:        "apiAddressesUtxo v1",
+			r:           newGetRequest(ts.URL + "/api/v1/addrs/mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL,2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1/utxo"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`[{"address":"mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL","txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","vout":1,"scriptPubKey":"76a9148d802c045445df49613f6a70ddd2e48526f3701f88ac","amount":9172.83951061,"satoshis":917283951061,"height":225494,"confirmations":1},{"address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vout":0,"scriptPubKey":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","amount":0.00009,"satoshis":9000,"height":225494,"confirmations":1}]`,
+			},
+		},
+		{
+			name:        "apiAddressesUtxo v1 POST",
+			r:           newPostFormRequest(ts.URL+"/api/v1/addrs/utxo", "addrs", "2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`[{"address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vout":0,"scriptPubKey":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","amount":0.00009,"satoshis":9000,"height":225494,"confirmations":1}]`,
+			},
+		},
+		{
+			name:        "apiAddressesTxs v1",
+			r:           newGetRequest(ts.URL + "/api/v1/addrs/mtR97eM2HPWVM6c8FGLGcukgaHHQv7THoL,2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1/txs?from=1&to=3"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"totalItems":3,"from":1,"to":3,"items":[{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vin":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":2,"n":0,"scriptSig":{},"addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"value":"0.00009876"}],"vout":[{"value":"0.00009","n":0,"scriptPubKey":{"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"]},"spent":false}],"blockhash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockheight":225494,"confirmations":1,"time":1521595678,"blocktime":1521595678,"valueOut":"0.00009","valueIn":"0.00009876","fees":"0.00000876","hex":""},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"12345.67890123","n":0,"scriptPubKey":{"hex":"76a914a08eae93007f22668ab5e4a9c83c8cd1c325e3e088ac","addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"]},"spent":true},{"value":"0.00000001","n":1,"scriptPubKey":{"hex":"a91452724c5178682f70e0ba31c6ec0633755a3b41d987","addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"]},"spent":true},{"value":"0.00009876","n":2,"scriptPubKey":{"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"]},"spent":true}],"blockhash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockheight":225493,"confirmations":2,"time":1521515026,"blocktime":1521515026,"valueOut":"12345.679","valueIn":"0","fees":"0","hex":""}]}`,
+			},
+		},
+		{
+			name:        "apiAddressesTxs v1 POST",
+			r:           newPostFormRequest(ts.URL+"/api/v1/addrs/txs", "addrs", "2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1", "from", "1"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"totalItems":2,"from":1,"to":2,"items":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"12345.67890123","n":0,"scriptPubKey":{"hex":"76a914a08eae93007f22668ab5e4a9c83c8cd1c325e3e088ac","addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"]},"spent":true},{"value":"0.00000001","n":1,"scriptPubKey":{"hex":"a91452724c5178682f70e0ba31c6ec0633755a3b41d987","addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"]},"spent":true},{"value":"0.00009876","n":2,"scriptPubKey":{"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"]},"spent":true}],"blockhash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockheight":225493,"confirmations":2,"time":1521515026,"blocktime":1521515026,"valueOut":"12345.679","valueIn":"0","fees":"0","hex":""}]}`,
+			},
+		},
+		{
+			name:        "apiAddressesTxs v1 range too big",
+			r:           newGetRequest(ts.URL + "/api/v1/addrs/2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1/txs?from=10&to=61"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"\"from\" (10) and \"to\" (61) range should be less than or equal to 50"}`,
+			},
+		},
+		{
+			name:        "apiTxs v1 block",
+			r:           newGetRequest(ts.URL + "/api/v1/txs?block=0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"pagesTotal":1,"txs":[{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vin":[],"vout":[{"value":"1","n":0,"scriptPubKey":{"addresses":["mfcWp7DB6NuaZsExybTTXpVgWz559Np4Ti"]},"spent":false},{"value":"0.00012345","n":1,"scriptPubKey":{"addresses":["mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"]},"spent":true},{"value":"0.00012345","n":2,"scriptPubKey":{"addresses":["mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"]},"spent":false}],"blockhash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockheight":225493,"confirmations":2,"time":1521515026,"blocktime":1521515026,"valueOut":"1.0002469","valueIn":"0","fees":"0","hex":""},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"12345.67890123","n":0,"scriptPubKey":{"addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"]},"spent":true},{"value":"0.00000001","n":1,"scriptPubKey":{"addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"]},"spent":true},{"value":"0.00009876","n":2,"scriptPubKey":{"addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"]},"spent":true}],"blockhash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockheight":225493,"confirmations":2,"time":1521515026,"blocktime":1521515026,"valueOut":"12345.679","valueIn":"0","fees":"0","hex":""}]}`,
+			},
+		},
+		{
+			name:        "apiTxs v1 address",
+			r:           newGetRequest(ts.URL + "/api/v1/txs?address=2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1&pageNum=0"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"pagesTotal":1,"txs":[{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vin":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":2,"n":0,"scriptSig":{},"addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"value":"0.00009876"}],"vout":[{"value":"0.00009","n":0,"scriptPubKey":{"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"]},"spent":false}],"blockhash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockheight":225494,"confirmations":1,"time":1521595678,"blocktime":1521595678,"valueOut":"0.00009","valueIn":"0.00009876","fees":"0.00000876","hex":""},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"12345.67890123","n":0,"scriptPubKey":{"hex":"76a914a08eae93007f22668ab5e4a9c83c8cd1c325e3e088ac","addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"]},"spent":true},{"value":"0.00000001","n":1,"scriptPubKey":{"hex":"a91452724c5178682f70e0ba31c6ec0633755a3b41d987","addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"]},"spent":true},{"value":"0.00009876","n":2,"scriptPubKey":{"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"]},"spent":true}],"blockhash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockheight":225493,"confirmations":2,"time":1521515026,"blocktime":1521515026,"valueOut":"12345.679","valueIn":"0","fees":"0","hex":""}]}`,
+			},
+		},
+		{
+			name:        "apiStatus v1 getInfo",
+			r:           newGetRequest(ts.URL + "/api/v1/status?q=getInfo"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"info":{"version":1001,"protocolversion":0,"blocks":2,"timeoffset":0,"connections":0,"proxy":"","difficulty":0,"testnet":true,"relayfee":0.00001,"errors":"","network":"fakecoin"}}`,
+			},
+		},
+		{
+			name:        "apiStatus v1 getLastBlockHash",
+			r:           newGetRequest(ts.URL + "/api/v1/status?q=getLastBlockHash"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"syncTipHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","lastblockhash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6"}`,
+			},
+		},
+		{
+			name:        "apiSync v1",
+			r:           newGetRequest(ts.URL + "/api/v1/sync"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"status":"finished","blockChainHeight":2,"syncPercentage":100,"height":225494,"error":null,"type":"bitcore node"}`,
+			},
+		},
+		{
+			name:        "apiRawTx v1",
+			r:           newGetRequest(ts.URL + "/api/v1/rawtx/05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"rawtx":"010000000001012720b597ef06045c935960342b0bbc45aab5fd5642017282f5110216caaa2364010000002322002069dae530beb09a05d46d0b2aee98645b15bb5d1e808a386b5ef0c48aed5531cbffffffff021ec403000000000017a914203c9dbd3ffbd1a790fc1609fb430efa5cbe516d87061523000000000017a91465dfc5c16e80b86b589df3f85dacd43f5c5b4a8f8704004730440220783e9349fc48f22aa0064acf32bc255eafa761eb9fa8f90a504986713c52dc3702206fc6a1a42f74ea0b416b35671770c0d26fc453668e6107edc271f11e629cda1001483045022100b82ef510c7eec61f39bee3e73a19df451fb8cca842b66bc94696d6a095dd8e96022071767bf8e4859de06cd5caf75e833e284328570ea1caa88bc93478a8d0fa9ac90147522103958c08660082c9ce90399ded0da7c3b39ed20a7767160f12428191e005aa42572102b1e6d8187f54d83d1ffd70508e24c5bd3603bccb2346d8c6677434169de8bc2652ae00000000"}`,
+			},
+		},
+		{
+			name:        "apiMessageVerify v1",
+			r:           newGetRequest(ts.URL + "/api/v1/messages/verify?address=mrcNu71ztWjAQA6ww9kHiW3zBWSQidHXTQ&signature=H5UCCwfLZImdUOL5vMEYjeFvW2BETOdUnImWEy2qTsHZZ%2BNkvXzcsFCscX3EaubZG5dbDbIoOR5oOMA3BKzhlb4%3D&message=Blockbook+test+message"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"result":true}`,
+			},
+		},
+		{
+			name:        "apiMessageVerify v1 POST other message",
+			r:           newPostFormRequest(ts.URL+"/api/v1/messages/verify", "address", "mrcNu71ztWjAQA6ww9kHiW3zBWSQidHXTQ", "signature", "H5UCCwfLZImdUOL5vMEYjeFvW2BETOdUnImWEy2qTsHZZ+NkvXzcsFCscX3EaubZG5dbDbIoOR5oOMA3BKzhlb4=", "message", "Blockbook test message 2"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"result":false}`,
+			},
+		},
+		{
+			name:        "apiMessageVerify v1 missing parameters",
+			r:           newGetRequest(ts.URL + "/api/v1/messages/verify?address=mrcNu71ztWjAQA6ww9kHiW3zBWSQidHXTQ"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Missing parameters (expected \"address\", \"signature\" and \"message\")"}`,
+			},
+		},
+		{
+			name:        "apiSendTx",
+			r:           newGetRequest(ts.URL + "/api/v2/sendtx/1234567890"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Invalid data"}`,
+			},
+		},
+		{
+			name:        "apiSendTx POST",
+			r:           newPostRequest(ts.URL+"/api/v2/sendtx/", "123456"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"result":"9876"}`,
+			},
+		},
+		{
+			name:        "apiSendTx POST empty",
+			r:           newPostRequest(ts.URL+"/api/v2/sendtx", ""),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Missing tx blob"}`,
+			},
+		},
+		{
+			name:        "apiEstimateFee",
+			r:           newGetRequest(ts.URL + "/api/estimatefee/123?conservative=false"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"result":"0.00012299"}`,
+			},
+		},
+		{
+			name:        "apiGetBlock",
+			r:           newGetRequest(ts.URL + "/api/v2/block/225493"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":1,"itemsOnPage":1000,"hash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","nextBlockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","height":225493,"confirmations":2,"size":1234567,"time":1521515026,"version":0,"merkleRoot":"","nonce":"","bits":"","difficulty":"","txCount":2,"supply":{"height":225493,"time":1521515026,"issuedSat":"1234667924690","burnedSat":"0","utxoSetSat":"1234667924690","utxos":6,"outputs":6,"spentOutputs":0},"txs":[{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vin":[],"vout":[{"value":"100000000","n":0,"addresses":["mfcWp7DB6NuaZsExybTTXpVgWz559Np4Ti"],"isAddress":true},{"value":"12345","n":1,"spent":true,"addresses":["mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"],"isAddress":true},{"value":"12345","n":2,"addresses":["mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"],"isAddress":true}],"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockHeight":225493,"confirmations":2,"blockTime":1521515026,"value":"100024690","valueIn":"0","fees":"0"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"1234567890123","n":0,"spent":true,"addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"],"isAddress":true},{"value":"1","n":1,"spent":true,"addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true},{"value":"9876","n":2,"spent":true,"addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true}],"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockHeight":225493,"confirmations":2,"blockTime":1521515026,"value":"1234567900000","valueIn":"0","fees":"0"}]}`,
+			},
+		},
+		{
+			name:        "apiGetRawBlock",
+			r:           newGetRequest(ts.URL + "/api/v2/rawblock/225493"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"hex":"00e0ff3fd42677a86f1515bafcf9802c1765e02226655a9b97fd44132602000000000000"}`,
+			},
+		},
+		{
+			name:        "apiWatchGroup",
+			r:           newGetRequest(ts.URL + "/api/v2/group/" + testWatchGroupID + "?details=txids&tokens=used"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"page":1,"totalPages":1,"itemsOnPage":1000,"address":"0123456789abcdef0123456789abcdef","balance":"118641984500","totalReceived":"118641994377","totalSent":"9877","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":3,"txids":["05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75"],"usedTokens":3,"tokens":[{"type":"XPUBAddress","name":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","path":"m/49'/1'/33'/0/0","transfers":2,"decimals":8,"balance":"0","totalReceived":"1","totalSent":"1"},{"type":"XPUBAddress","name":"2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu","path":"m/49'/1'/33'/1/3","transfers":1,"decimals":8,"balance":"118641975500","totalReceived":"118641975500","totalSent":"0"},{"type":"XPUBAddress","name":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","transfers":2,"decimals":8,"balance":"9000","totalReceived":"18876","totalSent":"9876"}]}`,
+			},
+		},
+		{
+			name:        "apiWatchGroup utxo",
+			r:           newGetRequest(ts.URL + "/api/v2/group/" + testWatchGroupID + "/utxo?confirmed=true"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`[{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vout":0,"value":"9000","height":225494,"confirmations":1,"address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"},{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vout":0,"value":"118641975500","height":225494,"confirmations":1,"address":"2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu","path":"m/49'/1'/33'/1/3"}]`,
+			},
+		},
+		{
+			name:        "apiWatchGroup balancehistory",
+			r:           newGetRequest(ts.URL + "/api/v2/group/" + testWatchGroupID + "/balancehistory?from=1521504000&fiatcurrency=usd"),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`[{"time":1521514800,"txs":1,"received":"9877","sent":"0","sentToSelf":"0","rates":{"usd":2001}},{"time":1521594000,"txs":2,"received":"118641984500","sent":"9877","sentToSelf":"118641984500","rates":{"usd":2003}}]`,
+			},
+		},
+		{
+			name:        "apiWatchGroup not found",
+			r:           newGetRequest(ts.URL + "/api/v2/group/fedcba9876543210fedcba9876543210"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Watch group 'fedcba9876543210fedcba9876543210' not found"}`,
+			},
+		},
+		{
+			name:        "apiWatchGroup create",
+			r:           newPostRequest(ts.URL+"/api/v2/group/", `{"descriptors":["`+dbtestdata.Addr3+`","`+dbtestdata.Addr3+`","`+dbtestdata.Xpub+`"]}`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"id":"`,
+				`"descriptors":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw","upub5E1xjDmZ7Hhej6LPpS8duATdKXnRYui7bDYj6ehfFGzWDZtmCmQkZhc3Zb7kgRLtHWd16QFxyP86JKL3ShZEBFX88aciJ3xyocuyhZZ8g6q"]`,
+			},
+		},
+		{
+			name:        "apiWatchGroup create dedup parsed descriptor",
+			r:           newPostRequest(ts.URL+"/api/v2/group/", `{"descriptors":["`+dbtestdata.Xpub+`","sh(wpkh(`+dbtestdata.Xpub+`/<0;1>/*))"]}`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`"descriptors":["upub5E1xjDmZ7Hhej6LPpS8duATdKXnRYui7bDYj6ehfFGzWDZtmCmQkZhc3Zb7kgRLtHWd16QFxyP86JKL3ShZEBFX88aciJ3xyocuyhZZ8g6q"]`,
+			},
+		},
+		{
+			name:        "apiWatchGroup create invalid descriptor",
+			r:           newPostRequest(ts.URL+"/api/v2/group/", `{"descriptors":["`+dbtestdata.Addr3+`","invalid"]}`),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Invalid descriptor 'invalid'"}`,
+			},
+		},
+		{
+			name:        "apiWatchGroup delete not found",
+			r:           newDeleteRequest(ts.URL + "/api/v2/group/fedcba9876543210fedcba9876543210"),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Watch group 'fedcba9876543210fedcba9876543210' not found"}`,
+			},
+		},
+		{
+			name:        "apiGraphQL address",
+			r:           newPostRequest(ts.URL+"/api/graphql", `{"query":"{address(descriptor:\"`+dbtestdata.Addr4+`\"){address balance txs txids transactions{txid vin{n prevTx{txid blockHeight}}}}}"}`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"data":{"address":{"address":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","balance":"0","txs":2,"txids":["3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75"],"transactions":[{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vin":[{"n":0,"prevTx":{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","blockHeight":225494}},{"n":1,"prevTx":{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","blockHeight":225493}}]},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[]}]}}}`,
+			},
+		},
+		{
+			name:        "apiGraphQL transaction",
+			r:           newGetRequest(ts.URL + "/api/graphql?query=" + url.QueryEscape(`query($txid:String!){transaction(txid:$txid){txid blockHeight confirmations value fees vout{n value addresses spent}}}`) + "&variables=" + url.QueryEscape(`{"txid":"`+dbtestdata.TxidB2T2+`"}`)),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"data":{"transaction":{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","blockHeight":225494,"confirmations":1,"value":"317283951000","fees":"62","vout":[{"n":0,"value":"118641975500","addresses":["2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu"],"spent":false},{"n":1,"value":"198641975500","addresses":["mmJx9Y8ayz9h14yd9fgCW1bUKoEpkBAquP"],"spent":false}]}}}`,
+			},
+		},
+		{
+			name:        "apiGraphQL block",
+			r:           newPostRequest(ts.URL+"/api/graphql", `{"query":"{block(id:\"225494\",pageSize:2){hash height txCount totalPages transactions{txid}}}"}`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"data":{"block":{"hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","height":225494,"txCount":4,"totalPages":2,"transactions":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25"},{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71"}]}}}`,
+			},
+		},
+		{
+			name:        "apiGraphQL utxos",
+			r:           newPostRequest(ts.URL+"/api/graphql", `{"query":"{utxos(descriptor:\"`+dbtestdata.Addr1+`\"){txid vout value confirmations tx{blockHeight}}}"}`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"data":{"utxos":[{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vout":0,"value":"100000000","confirmations":2,"tx":{"blockHeight":225493}}]}}`,
+			},
+		},
+		{
+			name:        "apiGraphQL invalid address",
+			r:           newPostRequest(ts.URL+"/api/graphql", `{"query":"{address(descriptor:\"invalid\"){balance}}"}`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"errors":[{"message":"Invalid address, decoded address is of unknown format","path":["address"]}],"data":{"address":null}}`,
+			},
+		},
+		{
+			name:        "apiGraphQL max depth",
+			r:           newPostRequest(ts.URL+"/api/graphql", `{"query":"{transaction(txid:\"`+dbtestdata.TxidB2T2+`\"){vin{prevTx{vin{prevTx{vin{prevTx{vin{prevTx{txid}}}}}}}}}}"}`),
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"errors":[{"message":"Field \"prevTx\" has depth 9 that exceeds max depth 8"`,
+			},
+		},
+		{
+			name:        "apiGraphQL missing query",
+			r:           newPostRequest(ts.URL+"/api/graphql", `{}`),
+			status:      http.StatusBadRequest,
+			contentType: "application/json; charset=utf-8",
+			body: []string{
+				`{"error":"Missing query"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.DefaultClient.Do(tt.r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("StatusCode = %v, want %v", resp.StatusCode, tt.status)
+			}
+			if resp.Header["Content-Type"][0] != tt.contentType {
+				t.Errorf("Content-Type = %v, want %v", resp.Header["Content-Type"][0], tt.contentType)
+			}
+			bb, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := string(bb)
+			for _, c := range tt.body {
+				if !strings.Contains(b, c) {
+					t.Errorf("got %v, want to contain %v", b, c)
+					break
+				}
+			}
+		})
+	}
+}
+
+func socketioTestsBitcoinType(t *testing.T, ts *httptest.Server) {
+	type socketioReq struct {
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+	}
+
+	url := strings.Replace(ts.URL, "http://", "ws://", 1) + "/socket.io/"
+	s, err := gosocketio.Dial(url, transport.GetDefaultWebsocketTransport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	tests := []struct {
+		name string
+		req  socketioReq
+		want string
+	}{
+		{
+			name: "socketio getInfo",
+			req:  socketioReq{"getInfo", []interface{}{}},
+			want: `{"result":{"blocks":225494,"testnet":true,"network":"fakecoin","subversion":"/Fakecoin:0.0.1/","coin_name":"Fakecoin","about":"Blockbook - blockchain indexer for Trezor wallet https://trezor.io/. Do not use for any other purpose."}}`,
+		},
+		{
+			name: "socketio estimateFee",
+			req:  socketioReq{"estimateFee", []interface{}{17}},
+			want: `{"result":0.000034}`,
+		},
+		{
+			name: "socketio estimateSmartFee",
+			req:  socketioReq{"estimateSmartFee", []interface{}{19, true}},
+			want: `{"result":0.000019}`,
+		},
+		{
+			name: "socketio getAddressTxids",
+			req: socketioReq{"getAddressTxids", []interface{}{
+				[]string{"mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"},
+				map[string]interface{}{
+					"start":        2000000,
+					"end":          0,
+					"queryMempool": false,
+				},
+			}},
+			want: `{"result":["7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840"]}`,
+		},
+		{
+			name: "socketio getAddressTxids limited range",
+			req: socketioReq{"getAddressTxids", []interface{}{
+				[]string{"mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz"},
+				map[string]interface{}{
+					"start":        225494,
+					"end":          225494,
+					"queryMempool": false,
+				},
+			}},
+			want: `{"result":["7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25"]}`,
 		},
 		{
 			name: "socketio getAddressHistory",
@@ -1124,7 +1694,7 @@ func websocketTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 					"details":    "txs",
 				},
 			},
-			want: `{"id":"2","data":{"page":1,"totalPages":1,"itemsOnPage":25,"address":"upub5E1xjDmZ7Hhej6LPpS8duATdKXnRYui7bDYj6ehfFGzWDZtmCmQkZhc3Zb7kgRLtHWd16QFxyP86JKL3ShZEBFX88aciJ3xyocuyhZZ8g6q","balance":"118641975500","totalReceived":"118641975501","totalSent":"1","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":2,"transactions":[{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vin":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","n":0,"addresses":["mzB8cYrfRwFRFAGTDzV8LkUQy5BQicxGhX"],"isAddress":true,"value":"317283951061"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":1,"n":1,"addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true,"isOwn":true,"value":"1"}],"vout":[{"value":"118641975500","n":0,"hex":"a91495e9fbe306449c991d314afe3c3567d5bf78efd287","addresses":["2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu"],"isAddress":true,"isOwn":true},{"value":"198641975500","n":1,"hex":"76a9143f8ba3fda3ba7b69f5818086e12223c6dd25e3c888ac","addresses":["mmJx9Y8ayz9h14yd9fgCW1bUKoEpkBAquP"],"isAddress":true}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"value":"317283951000","valueIn":"317283951062","fees":"62"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"1234567890123","n":0,"spent":true,"hex":"76a914a08eae93007f22668ab5e4a9c83c8cd1c325e3e088ac","addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"],"isAddress":true},{"value":"1","n":1,"spent":true,"hex":"a91452724c5178682f70e0ba31c6ec0633755a3b41d987","addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true,"isOwn":true},{"value":"9876","n":2,"spent":true,"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true}],"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockHeight":225493,"confirmations":2,"blockTime":1521515026,"value":"1234567900000","valueIn":"0","fees":"0"}],"usedTokens":2,"tokens":[{"type":"XPUBAddress","name":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","path":"m/49'/1'/33'/0/0","transfers":2,"decimals":8,"balance":"0","totalReceived":"1","totalSent":"1"},{"type":"XPUBAddress","name":"2MsYfbi6ZdVXLDNrYAQ11ja9Sd3otMk4Pmj","path":"m/49'/1'/33'/0/1","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MuAZNAjLSo6RLFad2fvHSfgqBD7BoEVy4T","path":"m/49'/1'/33'/0/2","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NEqKzw3BosGnBE9by5uaDy5QgwjHac4Zbg","path":"m/49'/1'/33'/0/3","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mw7vJNC8zUK6VNN4CEjtoTYmuNPLewxZzV","path":"m/49'/1'/33'/0/4","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N1kvo97NFASPXiwephZUxE9PRXunjTxEc4","path":"m/49'/1'/33'/0/5","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MuWrWMzoBt8VDFNvPmpJf42M1GTUs85fPx","path":"m/49'/1'/33'/0/6","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MuVZ2Ca6Da9zmYynt49Rx7uikAgubGcymF","path":"m/49'/1'/33'/0/7","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MzRGWDUmrPP9HwYu4B43QGCTLwoop5cExa","path":"m/49'/1'/33'/0/8","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N5C9EEWJzyBXhpyPHqa3UNed73Amsi5b3L","path":"m/49'/1'/33'/0/9","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MzNawz2zjwq1L85GDE3YydEJGJYfXxaWkk","path":"m/49'/1'/33'/0/10","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N7NdeuAMgL57WE7QCeV2gTWi2Um8iAu5dA","path":"m/49'/1'/33'/0/11","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8JQEP6DSHEZHNsSDPA1gHMUq9YFndhkfV","path":"m/49'/1'/33'/0/12","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mvbn3YXqKZVpQKugaoQrfjSYPvz76RwZkC","path":"m/49'/1'/33'/0/13","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8MRNxCfwUY9TSW27X9ooGYtqgrGCfLRHx","path":"m/49'/1'/33'/0/14","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N6HvwrHC113KYZAmCtJ9XJNWgaTcnFunCM","path":"m/49'/1'/33'/0/15","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NEo3oNyHUoi7rmRWee7wki37jxPWsWCopJ","path":"m/49'/1'/33'/0/16","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mzm5KY8qdFbDHsQfy4akXbFvbR3FAwDuVo","path":"m/49'/1'/33'/0/17","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NGMwftmQCogp6XZNGvgiybz3WZysvsJzqC","path":"m/49'/1'/33'/0/18","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N3fJrrefndYjLGycvFFfYgevpZtcRKCkRD","path":"m/49'/1'/33'/0/19","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N1T7TnHBwfdpBoyw53EGUL7vuJmb2mU6jF","path":"m/49'/1'/33'/0/20","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MzSBtRWHbBjeUcu3H5VRDqkvz5sfmDxJKo","path":"m/49'/1'/33'/1/0","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MtShtAJYb1afWduUTwF1SixJjan7urZKke","path":"m/49'/1'/33'/1/1","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N3cP668SeqyBEr9gnB4yQEmU3VyxeRYith","path":"m/49'/1'/33'/1/2","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu","path":"m/49'/1'/33'/1/3","transfers":1,"decimals":8,"balance":"118641975500","totalReceived":"118641975500","totalSent":"0"},{"type":"XPUBAddress","name":"2NEzatauNhf9kPTwwj6ZfYKjUdy52j4hVUL","path":"m/49'/1'/33'/1/4","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N4RjsDp4LBpkNqyF91aNjgpF9CwDwBkJZq","path":"m/49'/1'/33'/1/5","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8XygTmQc4NoBBPEy3yybnfCYhsxFtzPDY","path":"m/49'/1'/33'/1/6","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N5BjBomZvb48sccK2vwLMiQ5ETKp1fdPVn","path":"m/49'/1'/33'/1/7","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MybMwbZRPCGU3SMWPwQCpDkbcQFw5Hbwen","path":"m/49'/1'/33'/1/8","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N7HexL4dyAQc7Th4iqcCW4hZuyiZsLWf74","path":"m/49'/1'/33'/1/9","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NF6X5FDGWrQj4nQrfP6hA77zB5WAc1DGup","path":"m/49'/1'/33'/1/10","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N4ZRPdvc7BVioBTohy4F6QtxreqcjNj26b","path":"m/49'/1'/33'/1/11","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mtfho1rLmevh4qTnkYWxZEFCWteDMtTcUF","path":"m/49'/1'/33'/1/12","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NFUCphKYvmMcNZRZrF261mRX6iADVB9Qms","path":"m/49'/1'/33'/1/13","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N5kBNMB8qgxE4Y4f8J19fScsE49J4aNvoJ","path":"m/49'/1'/33'/1/14","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NANWCaefhCKdXMcW8NbZnnrFRDvhJN2wPy","path":"m/49'/1'/33'/1/15","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NFHw7Yo2Bz8D2wGAYHW9qidbZFLpfJ72qB","path":"m/49'/1'/33'/1/16","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NBDSsBgy5PpFniLCb1eAFHcSxgxwPSDsZa","path":"m/49'/1'/33'/1/17","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NDWCSQHogc7sCuc2WoYt9PX2i2i6a5k6dX","path":"m/49'/1'/33'/1/18","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8vNyDP7iSDjm3BKpXrbDjAxyphqfvnJz8","path":"m/49'/1'/33'/1/19","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N4tFKLurSbMusAyq1tv4tzymVjveAFV1Vb","path":"m/49'/1'/33'/1/20","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NBx5WwjAr2cH6Yqrp3Vsf957HtRKwDUVdX","path":"m/49'/1'/33'/1/21","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NBu1seHTaFhQxbcW5L5BkZzqFLGmZqpxsa","path":"m/49'/1'/33'/1/22","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NCDLoea22jGsXuarfT1n2QyCUh6RFhAPnT","path":"m/49'/1'/33'/1/23","transfers":0,"decimals":8}]}}`,
+			want: `{"id":"2","data":{"page":1,"totalPages":1,"itemsOnPage":25,"address":"upub5E1xjDmZ7Hhej6LPpS8duATdKXnRYui7bDYj6ehfFGzWDZtmCmQkZhc3Zb7kgRLtHWd16QFxyP86JKL3ShZEBFX88aciJ3xyocuyhZZ8g6q","balance":"118641975500","totalReceived":"118641975501","totalSent":"1","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":2,"transactions":[{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vin":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","n":0,"addresses":["mzB8cYrfRwFRFAGTDzV8LkUQy5BQicxGhX"],"isAddress":true,"value":"317283951061"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":1,"n":1,"addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true,"isOwn":true,"value":"1"}],"vout":[{"value":"118641975500","n":0,"hex":"a91495e9fbe306449c991d314afe3c3567d5bf78efd287","addresses":["2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu"],"isAddress":true,"isOwn":true},{"value":"198641975500","n":1,"hex":"76a9143f8ba3fda3ba7b69f5818086e12223c6dd25e3c888ac","addresses":["mmJx9Y8ayz9h14yd9fgCW1bUKoEpkBAquP"],"isAddress":true}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"vsize":400,"feeRate":0.16,"value":"317283951000","valueIn":"317283951062","fees":"62"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vin":[],"vout":[{"value":"1234567890123","n":0,"spent":true,"hex":"76a914a08eae93007f22668ab5e4a9c83c8cd1c325e3e088ac","addresses":["mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw"],"isAddress":true},{"value":"1","n":1,"spent":true,"hex":"a91452724c5178682f70e0ba31c6ec0633755a3b41d987","addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true,"isOwn":true},{"value":"9876","n":2,"spent":true,"hex":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","addresses":["2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"],"isAddress":true}],"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockHeight":225493,"confirmations":2,"blockTime":1521515026,"value":"1234567900000","valueIn":"0","fees":"0"}],"usedTokens":2,"tokens":[{"type":"XPUBAddress","name":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","path":"m/49'/1'/33'/0/0","transfers":2,"decimals":8,"balance":"0","totalReceived":"1","totalSent":"1"},{"type":"XPUBAddress","name":"2MsYfbi6ZdVXLDNrYAQ11ja9Sd3otMk4Pmj","path":"m/49'/1'/33'/0/1","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MuAZNAjLSo6RLFad2fvHSfgqBD7BoEVy4T","path":"m/49'/1'/33'/0/2","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NEqKzw3BosGnBE9by5uaDy5QgwjHac4Zbg","path":"m/49'/1'/33'/0/3","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mw7vJNC8zUK6VNN4CEjtoTYmuNPLewxZzV","path":"m/49'/1'/33'/0/4","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N1kvo97NFASPXiwephZUxE9PRXunjTxEc4","path":"m/49'/1'/33'/0/5","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MuWrWMzoBt8VDFNvPmpJf42M1GTUs85fPx","path":"m/49'/1'/33'/0/6","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MuVZ2Ca6Da9zmYynt49Rx7uikAgubGcymF","path":"m/49'/1'/33'/0/7","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MzRGWDUmrPP9HwYu4B43QGCTLwoop5cExa","path":"m/49'/1'/33'/0/8","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N5C9EEWJzyBXhpyPHqa3UNed73Amsi5b3L","path":"m/49'/1'/33'/0/9","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MzNawz2zjwq1L85GDE3YydEJGJYfXxaWkk","path":"m/49'/1'/33'/0/10","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N7NdeuAMgL57WE7QCeV2gTWi2Um8iAu5dA","path":"m/49'/1'/33'/0/11","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8JQEP6DSHEZHNsSDPA1gHMUq9YFndhkfV","path":"m/49'/1'/33'/0/12","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mvbn3YXqKZVpQKugaoQrfjSYPvz76RwZkC","path":"m/49'/1'/33'/0/13","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8MRNxCfwUY9TSW27X9ooGYtqgrGCfLRHx","path":"m/49'/1'/33'/0/14","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N6HvwrHC113KYZAmCtJ9XJNWgaTcnFunCM","path":"m/49'/1'/33'/0/15","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NEo3oNyHUoi7rmRWee7wki37jxPWsWCopJ","path":"m/49'/1'/33'/0/16","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mzm5KY8qdFbDHsQfy4akXbFvbR3FAwDuVo","path":"m/49'/1'/33'/0/17","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NGMwftmQCogp6XZNGvgiybz3WZysvsJzqC","path":"m/49'/1'/33'/0/18","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N3fJrrefndYjLGycvFFfYgevpZtcRKCkRD","path":"m/49'/1'/33'/0/19","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N1T7TnHBwfdpBoyw53EGUL7vuJmb2mU6jF","path":"m/49'/1'/33'/0/20","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MzSBtRWHbBjeUcu3H5VRDqkvz5sfmDxJKo","path":"m/49'/1'/33'/1/0","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MtShtAJYb1afWduUTwF1SixJjan7urZKke","path":"m/49'/1'/33'/1/1","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N3cP668SeqyBEr9gnB4yQEmU3VyxeRYith","path":"m/49'/1'/33'/1/2","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu","path":"m/49'/1'/33'/1/3","transfers":1,"decimals":8,"balance":"118641975500","totalReceived":"118641975500","totalSent":"0"},{"type":"XPUBAddress","name":"2NEzatauNhf9kPTwwj6ZfYKjUdy52j4hVUL","path":"m/49'/1'/33'/1/4","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N4RjsDp4LBpkNqyF91aNjgpF9CwDwBkJZq","path":"m/49'/1'/33'/1/5","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8XygTmQc4NoBBPEy3yybnfCYhsxFtzPDY","path":"m/49'/1'/33'/1/6","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N5BjBomZvb48sccK2vwLMiQ5ETKp1fdPVn","path":"m/49'/1'/33'/1/7","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2MybMwbZRPCGU3SMWPwQCpDkbcQFw5Hbwen","path":"m/49'/1'/33'/1/8","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N7HexL4dyAQc7Th4iqcCW4hZuyiZsLWf74","path":"m/49'/1'/33'/1/9","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NF6X5FDGWrQj4nQrfP6hA77zB5WAc1DGup","path":"m/49'/1'/33'/1/10","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N4ZRPdvc7BVioBTohy4F6QtxreqcjNj26b","path":"m/49'/1'/33'/1/11","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2Mtfho1rLmevh4qTnkYWxZEFCWteDMtTcUF","path":"m/49'/1'/33'/1/12","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NFUCphKYvmMcNZRZrF261mRX6iADVB9Qms","path":"m/49'/1'/33'/1/13","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N5kBNMB8qgxE4Y4f8J19fScsE49J4aNvoJ","path":"m/49'/1'/33'/1/14","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NANWCaefhCKdXMcW8NbZnnrFRDvhJN2wPy","path":"m/49'/1'/33'/1/15","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NFHw7Yo2Bz8D2wGAYHW9qidbZFLpfJ72qB","path":"m/49'/1'/33'/1/16","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NBDSsBgy5PpFniLCb1eAFHcSxgxwPSDsZa","path":"m/49'/1'/33'/1/17","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NDWCSQHogc7sCuc2WoYt9PX2i2i6a5k6dX","path":"m/49'/1'/33'/1/18","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N8vNyDP7iSDjm3BKpXrbDjAxyphqfvnJz8","path":"m/49'/1'/33'/1/19","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2N4tFKLurSbMusAyq1tv4tzymVjveAFV1Vb","path":"m/49'/1'/33'/1/20","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NBx5WwjAr2cH6Yqrp3Vsf957HtRKwDUVdX","path":"m/49'/1'/33'/1/21","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NBu1seHTaFhQxbcW5L5BkZzqFLGmZqpxsa","path":"m/49'/1'/33'/1/22","transfers":0,"decimals":8},{"type":"XPUBAddress","name":"2NCDLoea22jGsXuarfT1n2QyCUh6RFhAPnT","path":"m/49'/1'/33'/1/23","transfers":0,"decimals":8}]}}`,
 		},
 		{
 			name: "websocket getAccountInfo address",
@@ -1178,7 +1748,7 @@ func websocketTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 					"txid": dbtestdata.TxidB2T2,
 				},
 			},
-			want: `{"id":"7","data":{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vin":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","n":0,"addresses":["mzB8cYrfRwFRFAGTDzV8LkUQy5BQicxGhX"],"isAddress":true,"value":"317283951061"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":1,"n":1,"addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true,"value":"1"}],"vout":[{"value":"118641975500","n":0,"hex":"a91495e9fbe306449c991d314afe3c3567d5bf78efd287","addresses":["2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu"],"isAddress":true},{"value":"198641975500","n":1,"hex":"76a9143f8ba3fda3ba7b69f5818086e12223c6dd25e3c888ac","addresses":["mmJx9Y8ayz9h14yd9fgCW1bUKoEpkBAquP"],"isAddress":true}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"value":"317283951000","valueIn":"317283951062","fees":"62"}}`,
+			want: `{"id":"7","data":{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vin":[{"txid":"7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","n":0,"addresses":["mzB8cYrfRwFRFAGTDzV8LkUQy5BQicxGhX"],"isAddress":true,"value":"317283951061"},{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":1,"n":1,"addresses":["2MzmAKayJmja784jyHvRUW1bXPget1csRRG"],"isAddress":true,"value":"1"}],"vout":[{"value":"118641975500","n":0,"hex":"a91495e9fbe306449c991d314afe3c3567d5bf78efd287","addresses":["2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu"],"isAddress":true},{"value":"198641975500","n":1,"hex":"76a9143f8ba3fda3ba7b69f5818086e12223c6dd25e3c888ac","addresses":["mmJx9Y8ayz9h14yd9fgCW1bUKoEpkBAquP"],"isAddress":true}],"blockHash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","blockHeight":225494,"confirmations":1,"blockTime":1521595678,"vsize":400,"feeRate":0.16,"value":"317283951000","valueIn":"317283951062","fees":"62"}}`,
 		},
 		{
 			name: "websocket getTransaction",
@@ -1508,6 +2078,73 @@ func websocketTestsBitcoinType(t *testing.T, ts *httptest.Server) {
 			},
 			want: `{"id":"39","data":{"subscribed":false,"message":"unsubscribeNewTransaction not enabled, use -enablesubnewtx flag to enable."}}`,
 		},
+		{
+			name: "websocket getWatchGroupInfo",
+			req: websocketReq{
+				Method: "getWatchGroupInfo",
+				Params: map[string]interface{}{
+					"id":      testWatchGroupID,
+					"details": "basic",
+				},
+			},
+			want: `{"id":"40","data":{"address":"0123456789abcdef0123456789abcdef","balance":"118641984500","totalReceived":"118641994377","totalSent":"9877","unconfirmedBalance":"0","unconfirmedTxs":0,"txs":5,"usedTokens":3}}`,
+		},
+		{
+			name: "websocket getWatchGroupUtxo",
+			req: websocketReq{
+				Method: "getWatchGroupUtxo",
+				Params: map[string]interface{}{
+					"id": testWatchGroupID,
+				},
+			},
+			want: `{"id":"41","data":[{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vout":0,"value":"9000","height":225494,"confirmations":1,"address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"},{"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vout":0,"value":"118641975500","height":225494,"confirmations":1,"address":"2N6utyMZfPNUb1Bk8oz7p2JqJrXkq83gegu","path":"m/49'/1'/33'/1/3"}]}`,
+		},
+		{
+			name: "websocket deleteWatchGroup not found",
+			req: websocketReq{
+				Method: "deleteWatchGroup",
+				Params: map[string]interface{}{
+					"id": "fedcba9876543210fedcba9876543210",
+				},
+			},
+			want: `{"id":"42","data":{"error":{"message":"Watch group 'fedcba9876543210fedcba9876543210' not found"}}}`,
+		},
+		{
+			name: "websocket subscribeMempoolStats",
+			req: websocketReq{
+				Method: "subscribeMempoolStats",
+			},
+			want: `{"id":"43","data":{"subscribed":true}}`,
+		},
+		{
+			name: "websocket unsubscribeMempoolStats",
+			req: websocketReq{
+				Method: "unsubscribeMempoolStats",
+			},
+			want: `{"id":"44","data":{"subscribed":false}}`,
+		},
+		{
+			name: "websocket getBalanceAt Addr2 height=225493",
+			req: websocketReq{
+				Method: "getBalanceAt",
+				Params: map[string]interface{}{
+					"descriptor": "mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz",
+					"height":     225493,
+				},
+			},
+			want: `{"id":"45","data":{"address":"mtGXQvBowMkBpnhLckhxhbwYK44Gs9eEtz","height":225493,"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockTime":1521515026,"txs":1,"received":"24690","sent":"0","balance":"24690","utxos":[{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vout":2,"value":"12345","height":225493,"confirmations":1},{"txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","vout":1,"value":"12345","height":225493,"confirmations":1}]}}`,
+		},
+		{
+			name: "websocket getBalanceAt xpub timestamp=1521590400",
+			req: websocketReq{
+				Method: "getBalanceAt",
+				Params: map[string]interface{}{
+					"descriptor": dbtestdata.Xpub,
+					"timestamp":  1521590400,
+				},
+			},
+			want: `{"id":"46","data":{"address":"upub5E1xjDmZ7Hhej6LPpS8duATdKXnRYui7bDYj6ehfFGzWDZtmCmQkZhc3Zb7kgRLtHWd16QFxyP86JKL3ShZEBFX88aciJ3xyocuyhZZ8g6q","height":225493,"blockHash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","blockTime":1521515026,"txs":1,"received":"1","sent":"0","balance":"1","utxos":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":1,"value":"1","height":225493,"confirmations":1,"address":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","path":"m/49'/1'/33'/0/0"}]}}`,
+		},
 	}
 
 	// send all requests at once
@@ -1568,4 +2205,489 @@ func Test_PublicServer_BitcoinType(t *testing.T) {
 	httpTestsBitcoinType(t, ts)
 	socketioTestsBitcoinType(t, ts)
 	websocketTestsBitcoinType(t, ts)
+	electrumTestsBitcoinType(t, s)
+	grpcTestsBitcoinType(t, s)
+	s.ConnectEsploraInterface()
+	esploraTestsBitcoinType(t, ts)
+}
+
+// the expected responses follow the format of the responses of Esplora for the test transactions
+func esploraTestsBitcoinType(t *testing.T, ts *httptest.Server) {
+	tests := []struct {
+		name        string
+		r           *http.Request
+		status      int
+		contentType string
+		body        string
+	}{
+		{
+			name:        "esplora tx",
+			r:           newGetRequest(ts.URL + "/esplora/tx/05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","version":0,"locktime":0,"vin":[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","vout":2,"prevout":{"scriptpubkey":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","scriptpubkey_asm":"OP_HASH160 OP_PUSHBYTES_20 e921fc4912a315078f370d959f2c4f7b6d2a683c OP_EQUAL","scriptpubkey_type":"p2sh","scriptpubkey_address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","value":9876},"scriptsig":"","scriptsig_asm":"","is_coinbase":false,"sequence":0}],"vout":[{"scriptpubkey":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","scriptpubkey_asm":"OP_HASH160 OP_PUSHBYTES_20 e921fc4912a315078f370d959f2c4f7b6d2a683c OP_EQUAL","scriptpubkey_type":"p2sh","scriptpubkey_address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","value":9000}],"size":0,"weight":1484,"fee":876,"status":{"confirmed":true,"block_height":225494,"block_hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","block_time":1521595678}}`,
+		},
+		{
+			name:        "esplora tx coinbase",
+			r:           newGetRequest(ts.URL + "/esplora/tx/fdd824a780cbb718eeb766eb05d83fdefc793a27082cd5e67f856d69798cf7db"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"txid":"fdd824a780cbb718eeb766eb05d83fdefc793a27082cd5e67f856d69798cf7db","version":0,"locktime":0,"vin":[{"txid":"0000000000000000000000000000000000000000000000000000000000000000","vout":4294967295,"prevout":null,"scriptsig":"03bf1e1504aede765b726567696f6e312f50726f6a65637420425443506f6f6c2f01000001bf7e000000000000","scriptsig_asm":"OP_PUSHBYTES_3 bf1e15 OP_PUSHBYTES_4 aede765b OP_2SWAP OP_VERIF OP_ELSE OP_VERIFY OP_3DUP OP_2DUP OP_PUSHBYTES_49 <push past end>","is_coinbase":true,"sequence":0}],"vout":[{"scriptpubkey":"76a914d03c0d863d189b23b061a95ad32940b65837609f88ac","scriptpubkey_asm":"OP_DUP OP_HASH160 OP_PUSHBYTES_20 d03c0d863d189b23b061a95ad32940b65837609f OP_EQUALVERIFY OP_CHECKSIG","scriptpubkey_type":"p2pkh","scriptpubkey_address":"mzVznVsCHkVHX9UN8WPFASWUUHtxnNn4Jj","value":1360030331},{"scriptpubkey":"","scriptpubkey_asm":"","scriptpubkey_type":"empty","value":0}],"size":0,"weight":1200,"fee":0,"status":{"confirmed":true,"block_height":225494,"block_hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","block_time":1521595678}}`,
+		},
+		{
+			name:        "esplora tx not found",
+			r:           newGetRequest(ts.URL + "/esplora/tx/1234567890123456789012345678901234567890123456789012345678901234"),
+			status:      http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8",
+			body:        `Transaction not found`,
+		},
+		{
+			name:        "esplora tx invalid",
+			r:           newGetRequest(ts.URL + "/esplora/tx/1234/status"),
+			status:      http.StatusBadRequest,
+			contentType: "text/plain; charset=utf-8",
+			body:        `Invalid hex string`,
+		},
+		{
+			name:        "esplora tx status",
+			r:           newGetRequest(ts.URL + "/esplora/tx/7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25/status"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"confirmed":true,"block_height":225494,"block_hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","block_time":1521595678}`,
+		},
+		{
+			name:        "esplora tx hex",
+			r:           newGetRequest(ts.URL + "/esplora/tx/05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07/hex"),
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        `010000000001012720b597ef06045c935960342b0bbc45aab5fd5642017282f5110216caaa2364010000002322002069dae530beb09a05d46d0b2aee98645b15bb5d1e808a386b5ef0c48aed5531cbffffffff021ec403000000000017a914203c9dbd3ffbd1a790fc1609fb430efa5cbe516d87061523000000000017a91465dfc5c16e80b86b589df3f85dacd43f5c5b4a8f8704004730440220783e9349fc48f22aa0064acf32bc255eafa761eb9fa8f90a504986713c52dc3702206fc6a1a42f74ea0b416b35671770c0d26fc453668e6107edc271f11e629cda1001483045022100b82ef510c7eec61f39bee3e73a19df451fb8cca842b66bc94696d6a095dd8e96022071767bf8e4859de06cd5caf75e833e284328570ea1caa88bc93478a8d0fa9ac90147522103958c08660082c9ce90399ded0da7c3b39ed20a7767160f12428191e005aa42572102b1e6d8187f54d83d1ffd70508e24c5bd3603bccb2346d8c6677434169de8bc2652ae00000000`,
+		},
+		{
+			name:        "esplora tx outspends",
+			r:           newGetRequest(ts.URL + "/esplora/tx/7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25/outspends"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `[{"spent":true,"txid":"3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","vin":0,"status":{"confirmed":true,"block_height":225494,"block_hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","block_time":1521595678}},{"spent":false},{"spent":false}]`,
+		},
+		{
+			name:        "esplora tx outspend",
+			r:           newGetRequest(ts.URL + "/esplora/tx/7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25/outspend/1"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"spent":false}`,
+		},
+		{
+			name:        "esplora tx merkle-proof",
+			r:           newGetRequest(ts.URL + "/esplora/tx/05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07/merkle-proof"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"block_height":225494,"merkle":["fdd824a780cbb718eeb766eb05d83fdefc793a27082cd5e67f856d69798cf7db","ca8b83277505d907b6e5b7c259198d2c4775b47567968b1b3b138422f21cf15b"],"pos":2}`,
+		},
+		{
+			name:        "esplora post tx",
+			r:           newPostRequest(ts.URL+"/esplora/tx", "123456"),
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        `9876`,
+		},
+		{
+			name:        "esplora address",
+			r:           newGetRequest(ts.URL + "/esplora/address/2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","chain_stats":{"funded_txo_count":2,"funded_txo_sum":18876,"spent_txo_count":1,"spent_txo_sum":9876,"tx_count":2},"mempool_stats":{"funded_txo_count":0,"funded_txo_sum":0,"spent_txo_count":0,"spent_txo_sum":0,"tx_count":0}}`,
+		},
+		{
+			name:        "esplora address invalid",
+			r:           newGetRequest(ts.URL + "/esplora/address/xyz"),
+			status:      http.StatusBadRequest,
+			contentType: "text/plain; charset=utf-8",
+			body:        `Invalid Bitcoin address`,
+		},
+		{
+			name:        "esplora scripthash",
+			r:           newGetRequest(ts.URL + "/esplora/scripthash/18789beff0b083eec158e6e5384035b33e34d0bd08aa3cbda50b96f4eac380bb"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"scripthash":"18789beff0b083eec158e6e5384035b33e34d0bd08aa3cbda50b96f4eac380bb","chain_stats":{"funded_txo_count":2,"funded_txo_sum":18876,"spent_txo_count":1,"spent_txo_sum":9876,"tx_count":2},"mempool_stats":{"funded_txo_count":0,"funded_txo_sum":0,"spent_txo_count":0,"spent_txo_sum":0,"tx_count":0}}`,
+		},
+		{
+			name:        "esplora address txs chain after last seen",
+			r:           newGetRequest(ts.URL + "/esplora/address/2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1/txs/chain/05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `[{"txid":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","version":0,"locktime":0,"vin":[],"vout":[{"scriptpubkey":"76a914a08eae93007f22668ab5e4a9c83c8cd1c325e3e088ac","scriptpubkey_asm":"OP_DUP OP_HASH160 OP_PUSHBYTES_20 a08eae93007f22668ab5e4a9c83c8cd1c325e3e0 OP_EQUALVERIFY OP_CHECKSIG","scriptpubkey_type":"p2pkh","scriptpubkey_address":"mv9uLThosiEnGRbVPS7Vhyw6VssbVRsiAw","value":1234567890123},{"scriptpubkey":"a91452724c5178682f70e0ba31c6ec0633755a3b41d987","scriptpubkey_asm":"OP_HASH160 OP_PUSHBYTES_20 52724c5178682f70e0ba31c6ec0633755a3b41d9 OP_EQUAL","scriptpubkey_type":"p2sh","scriptpubkey_address":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","value":1},{"scriptpubkey":"a914e921fc4912a315078f370d959f2c4f7b6d2a683c87","scriptpubkey_asm":"OP_HASH160 OP_PUSHBYTES_20 e921fc4912a315078f370d959f2c4f7b6d2a683c OP_EQUAL","scriptpubkey_type":"p2sh","scriptpubkey_address":"2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1","value":9876}],"size":0,"weight":0,"fee":0,"status":{"confirmed":true,"block_height":225493,"block_hash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","block_time":1521515026}}]`,
+		},
+		{
+			name:        "esplora address txs mempool",
+			r:           newGetRequest(ts.URL + "/esplora/address/2NEVv9LJmAnY99W1pFoc5UJjVdypBqdnvu1/txs/mempool"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `[]`,
+		},
+		{
+			name:        "esplora scripthash utxo",
+			r:           newGetRequest(ts.URL + "/esplora/scripthash/18789beff0b083eec158e6e5384035b33e34d0bd08aa3cbda50b96f4eac380bb/utxo"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `[{"txid":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","vout":0,"status":{"confirmed":true,"block_height":225494,"block_hash":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","block_time":1521595678},"value":9000}]`,
+		},
+		{
+			name:        "esplora block",
+			r:           newGetRequest(ts.URL + "/esplora/block/00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"id":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6","height":225494,"version":0,"timestamp":1521595678,"tx_count":4,"size":2345678,"merkle_root":"","previousblockhash":"0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997","mediantime":1521595678,"nonce":0,"bits":0,"difficulty":0}`,
+		},
+		{
+			name:        "esplora block status",
+			r:           newGetRequest(ts.URL + "/esplora/block/0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997/status"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"in_best_chain":true,"height":225493,"next_best":"00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6"}`,
+		},
+		{
+			name:        "esplora block txids",
+			r:           newGetRequest(ts.URL + "/esplora/block/00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6/txids"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `["7c3be24063f268aaa1ed81b64776798f56088757641a34fb156c4f51ed2e9d25","3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","fdd824a780cbb718eeb766eb05d83fdefc793a27082cd5e67f856d69798cf7db"]`,
+		},
+		{
+			name:        "esplora block txid",
+			r:           newGetRequest(ts.URL + "/esplora/block/00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6/txid/3"),
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        `fdd824a780cbb718eeb766eb05d83fdefc793a27082cd5e67f856d69798cf7db`,
+		},
+		{
+			name:        "esplora block txs out of range",
+			r:           newGetRequest(ts.URL + "/esplora/block/00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6/txs/25"),
+			status:      http.StatusBadRequest,
+			contentType: "text/plain; charset=utf-8",
+			body:        `start index out of range`,
+		},
+		{
+			name:        "esplora block-height",
+			r:           newGetRequest(ts.URL + "/esplora/block-height/225493"),
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        `0000000076fbbed90fd75b0e18856aa35baa984e9c9d444cf746ad85e94e2997`,
+		},
+		{
+			name:        "esplora block-height not found",
+			r:           newGetRequest(ts.URL + "/esplora/block-height/1"),
+			status:      http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8",
+			body:        `Block not found`,
+		},
+		{
+			name:        "esplora blocks tip height",
+			r:           newGetRequest(ts.URL + "/esplora/blocks/tip/height"),
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        `225494`,
+		},
+		{
+			name:        "esplora blocks tip hash",
+			r:           newGetRequest(ts.URL + "/esplora/blocks/tip/hash"),
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        `00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6`,
+		},
+		{
+			name:        "esplora mempool",
+			r:           newGetRequest(ts.URL + "/esplora/mempool"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"count":0,"vsize":0,"total_fee":0,"fee_histogram":[]}`,
+		},
+		{
+			name:        "esplora fee-estimates",
+			r:           newGetRequest(ts.URL + "/esplora/fee-estimates"),
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"1":0.1,"10":1,"1008":100.8,"11":1.1,"12":1.2,"13":1.3,"14":1.4,"144":14.4,"15":1.5,"16":1.6,"17":1.7,"18":1.8,"19":1.9,"2":0.2,"20":2,"21":2.1,"22":2.2,"23":2.3,"24":2.4,"25":2.5,"3":0.3,"4":0.4,"5":0.5,"504":50.4,"6":0.6,"7":0.7,"8":0.8,"9":0.9}`,
+		},
+		{
+			name:        "esplora unknown path",
+			r:           newGetRequest(ts.URL + "/esplora/unknown"),
+			status:      http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8",
+			body:        `Not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.DefaultClient.Do(tt.r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("StatusCode = %v, want %v", resp.StatusCode, tt.status)
+			}
+			if resp.Header.Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %v, want %v", resp.Header.Get("Content-Type"), tt.contentType)
+			}
+			bb, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(bb)); got != tt.body {
+				t.Errorf("got %v, want %v", got, tt.body)
+			}
+		})
+	}
+}
+
+func electrumTestsBitcoinType(t *testing.T, ps *PublicServer) {
+	s, err := NewElectrumServer("localhost:12346", "", ps.db, ps.chain, ps.mempool, ps.txCache, ps.metrics, ps.is)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, conn := net.Pipe()
+	go s.serveConn(conn)
+	defer client.Close()
+	r := bufio.NewReader(client)
+
+	tests := []struct {
+		name string
+		req  string
+		want string
+	}{
+		{
+			name: "electrum server.version",
+			req:  `{"jsonrpc":"2.0","id":1,"method":"server.version","params":["test","1.4"]}`,
+			want: `{"jsonrpc":"2.0","id":1,"result":["Blockbook unknown","1.4"]}`,
+		},
+		{
+			name: "electrum unknown method",
+			req:  `{"jsonrpc":"2.0","id":"a","method":"server.unknown","params":[]}`,
+			want: `{"jsonrpc":"2.0","id":"a","error":{"code":-32601,"message":"unknown method \"server.unknown\""}}`,
+		},
+		{
+			name: "electrum invalid json",
+			req:  `{"jsonrpc":"2.0","id":`,
+			want: `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`,
+		},
+		{
+			name: "electrum blockchain.scripthash.get_balance",
+			req:  `{"jsonrpc":"2.0","id":2,"method":"blockchain.scripthash.get_balance","params":["18789beff0b083eec158e6e5384035b33e34d0bd08aa3cbda50b96f4eac380bb"]}`,
+			want: `{"jsonrpc":"2.0","id":2,"result":{"confirmed":9000,"unconfirmed":0}}`,
+		},
+		{
+			name: "electrum blockchain.scripthash.get_history",
+			req:  `{"jsonrpc":"2.0","id":3,"method":"blockchain.scripthash.get_history","params":["18789beff0b083eec158e6e5384035b33e34d0bd08aa3cbda50b96f4eac380bb"]}`,
+			want: `{"jsonrpc":"2.0","id":3,"result":[{"tx_hash":"effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75","height":225493},{"tx_hash":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","height":225494}]}`,
+		},
+		{
+			name: "electrum blockchain.scripthash.listunspent",
+			req:  `{"jsonrpc":"2.0","id":4,"method":"blockchain.scripthash.listunspent","params":["18789beff0b083eec158e6e5384035b33e34d0bd08aa3cbda50b96f4eac380bb"]}`,
+			want: `{"jsonrpc":"2.0","id":4,"result":[{"tx_hash":"05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07","tx_pos":0,"height":225494,"value":9000}]}`,
+		},
+		{
+			name: "electrum blockchain.scripthash.subscribe",
+			req:  `{"jsonrpc":"2.0","id":5,"method":"blockchain.scripthash.subscribe","params":["897a4f1e4ed271debf48b8990dc436ceafb973d3e9c1bd7beeb4450c0b12971b"]}`,
+			want: `{"jsonrpc":"2.0","id":5,"result":"a6a82c0815cf39c0e24c97ccbc9998c8552353ed18ca7241413f79aae1f709eb"}`,
+		},
+		{
+			name: "electrum blockchain.scripthash.subscribe unknown script hash",
+			req:  `{"jsonrpc":"2.0","id":6,"method":"blockchain.scripthash.subscribe","params":["0000000000000000000000000000000000000000000000000000000000000000"]}`,
+			want: `{"jsonrpc":"2.0","id":6,"result":null}`,
+		},
+		{
+			name: "electrum blockchain.scripthash.get_history invalid script hash",
+			req:  `{"jsonrpc":"2.0","id":7,"method":"blockchain.scripthash.get_history","params":["1234"]}`,
+			want: `{"jsonrpc":"2.0","id":7,"error":{"code":-32602,"message":"invalid script hash 1234"}}`,
+		},
+		{
+			name: "electrum blockchain.transaction.get",
+			req:  `{"jsonrpc":"2.0","id":8,"method":"blockchain.transaction.get","params":["05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07"]}`,
+			want: `{"jsonrpc":"2.0","id":8,"result":"010000000001012720b597ef06045c935960342b0bbc45aab5fd5642017282f5110216caaa2364010000002322002069dae530beb09a05d46d0b2aee98645b15bb5d1e808a386b5ef0c48aed5531cbffffffff021ec403000000000017a914203c9dbd3ffbd1a790fc1609fb430efa5cbe516d87061523000000000017a91465dfc5c16e80b86b589df3f85dacd43f5c5b4a8f8704004730440220783e9349fc48f22aa0064acf32bc255eafa761eb9fa8f90a504986713c52dc3702206fc6a1a42f74ea0b416b35671770c0d26fc453668e6107edc271f11e629cda1001483045022100b82ef510c7eec61f39bee3e73a19df451fb8cca842b66bc94696d6a095dd8e96022071767bf8e4859de06cd5caf75e833e284328570ea1caa88bc93478a8d0fa9ac90147522103958c08660082c9ce90399ded0da7c3b39ed20a7767160f12428191e005aa42572102b1e6d8187f54d83d1ffd70508e24c5bd3603bccb2346d8c6677434169de8bc2652ae00000000"}`,
+		},
+		{
+			name: "electrum blockchain.transaction.get_merkle",
+			req:  `{"jsonrpc":"2.0","id":9,"method":"blockchain.transaction.get_merkle","params":["05e2e48aeabdd9b75def7b48d756ba304713c2aba7b522bf9dbc893fc4231b07",225494]}`,
+			want: `{"jsonrpc":"2.0","id":9,"result":{"block_height":225494,"merkle":["fdd824a780cbb718eeb766eb05d83fdefc793a27082cd5e67f856d69798cf7db","ca8b83277505d907b6e5b7c259198d2c4775b47567968b1b3b138422f21cf15b"],"pos":2}}`,
+		},
+		{
+			name: "electrum blockchain.transaction.broadcast",
+			req:  `{"jsonrpc":"2.0","id":10,"method":"blockchain.transaction.broadcast","params":["123456"]}`,
+			want: `{"jsonrpc":"2.0","id":10,"result":"9876"}`,
+		},
+		{
+			name: "electrum blockchain.transaction.broadcast invalid",
+			req:  `{"jsonrpc":"2.0","id":11,"method":"blockchain.transaction.broadcast","params":["abcd"]}`,
+			want: `{"jsonrpc":"2.0","id":11,"error":{"code":1,"message":"Invalid data"}}`,
+		},
+		{
+			name: "electrum blockchain.estimatefee",
+			req:  `{"jsonrpc":"2.0","id":12,"method":"blockchain.estimatefee","params":[2]}`,
+			want: `{"jsonrpc":"2.0","id":12,"result":0.000002}`,
+		},
+		{
+			name: "electrum blockchain.headers.subscribe",
+			req:  `{"jsonrpc":"2.0","id":13,"method":"blockchain.headers.subscribe","params":[]}`,
+			want: `{"jsonrpc":"2.0","id":13,"result":{"height":225494,"hex":"00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000001eb5b15a0000000000000000"}}`,
+		},
+		{
+			name: "electrum batch",
+			req:  `[{"jsonrpc":"2.0","id":14,"method":"server.ping"},{"jsonrpc":"2.0","id":15,"method":"blockchain.relayfee"}]`,
+			want: `[{"jsonrpc":"2.0","id":14,"result":null},{"jsonrpc":"2.0","id":15,"result":0.00001}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.Write([]byte(tt.req + "\n")); err != nil {
+				t.Fatal(err)
+			}
+			line, err := r.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(line); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	t.Run("electrum blockchain.scripthash.get_history too large", func(t *testing.T) {
+		defer func(limit int) { electrumMaxHistory = limit }(electrumMaxHistory)
+		electrumMaxHistory = 1
+		req := `{"jsonrpc":"2.0","id":16,"method":"blockchain.scripthash.get_history","params":["18789beff0b083eec158e6e5384035b33e34d0bd08aa3cbda50b96f4eac380bb"]}`
+		if _, err := client.Write([]byte(req + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"jsonrpc":"2.0","id":16,"error":{"code":1,"message":"history too large"}}`
+		if got := strings.TrimSpace(line); got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func grpcTestsBitcoinType(t *testing.T, ps *PublicServer) {
+	s, err := NewGrpcServer("localhost:12347", "", ps.db, ps.chain, ps.mempool, ps.txCache, ps.metrics, ps.is)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	go s.server.Serve(lis)
+	defer s.Close()
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.Dial()
+	}), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := bchain.NewBlockbookClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		want     string
+		wantCode codes.Code
+	}{
+		{
+			name: "grpc GetAccountUtxo",
+			call: func() (interface{}, error) {
+				return client.GetAccountUtxo(ctx, &bchain.AccountUtxoRequest{Descriptor_: dbtestdata.Addr1})
+			},
+			want: `{"Utxos":[{"Txid":"00b2c06055e5e90e9c82bd4181fde310104391a7fa4f289b1704e5d90caa3840","Value":"100000000","Height":225493,"Confirmations":2}]}`,
+		},
+		{
+			name: "grpc GetAccountInfo",
+			call: func() (interface{}, error) {
+				return client.GetAccountInfo(ctx, &bchain.AccountInfoRequest{Descriptor_: dbtestdata.Addr4, Details: "txids"})
+			},
+			want: `{"Page":1,"TotalPages":1,"ItemsOnPage":25,"Address":"2MzmAKayJmja784jyHvRUW1bXPget1csRRG","Balance":"0","TotalReceived":"1","TotalSent":"1","UnconfirmedBalance":"0","Txs":2,"Txids":["3d90d15ed026dc45e19ffb52875ed18fa9e8012ad123d7f7212176e2b0ebdb71","effd9ef509383d536b1c8af5bf434c8efbf521a4f2befd4022bbd68694b4ac75"]}`,
+		},
+		{
+			name: "grpc GetAccountInfo invalid address",
+			call: func() (interface{}, error) {
+				return client.GetAccountInfo(ctx, &bchain.AccountInfoRequest{Descriptor_: "invalid"})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "grpc SendTransaction",
+			call: func() (interface{}, error) {
+				return client.SendTransaction(ctx, &bchain.SendTransactionRequest{Hex: "123456"})
+			},
+			want: `{"Txid":"9876"}`,
+		},
+		{
+			name: "grpc SendTransaction invalid",
+			call: func() (interface{}, error) {
+				return client.SendTransaction(ctx, &bchain.SendTransactionRequest{Hex: "abcd"})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call()
+			if tt.wantCode != codes.OK {
+				if status.Code(err) != tt.wantCode {
+					t.Errorf("got error %v, want code %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %v, want %v", string(b), tt.want)
+			}
+		})
+	}
+
+	t.Run("grpc GetTransaction", func(t *testing.T) {
+		tx, err := client.GetTransaction(ctx, &bchain.TransactionRequest{Txid: dbtestdata.TxidB2T2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.Txid != dbtestdata.TxidB2T2 || tx.BlockHeight != 225494 || len(tx.Vin) != 2 || len(tx.Vout) != 2 || tx.Vout[1].Value != "198641975500" {
+			t.Errorf("unexpected transaction %+v", tx)
+		}
+	})
+
+	t.Run("grpc SubscribeNewBlock", func(t *testing.T) {
+		stream, err := client.SubscribeNewBlock(ctx, &bchain.SubscribeNewBlockRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// wait until the subscription is registered by the server
+		for i := 0; ; i++ {
+			s.newBlockSubscriptionsLock.Lock()
+			n := len(s.newBlockSubscriptions)
+			s.newBlockSubscriptionsLock.Unlock()
+			if n > 0 {
+				break
+			}
+			if i == 100 {
+				t.Fatal("timeout while waiting for the subscription")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		s.OnNewBlock("00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6", 225495)
+		b, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Height != 225495 || b.Hash != "00000000eb0443fd7dc4a1ed5c686a8e995057805f9a161d9a5a77a95e72b7b6" {
+			t.Errorf("unexpected block %+v", b)
+		}
+	})
 }
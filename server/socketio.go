@@ -171,6 +171,8 @@ func (s *SocketIoServer) onMessage(c *gosocketio.Channel, req map[string]json.Ra
 		}
 		s.metrics.SocketIOPendingRequests.With((common.Labels{"method": method})).Dec()
 	}()
+	s.db.BeginRead()
+	defer s.db.EndRead()
 	t := time.Now()
 	params := req["params"]
 	s.metrics.SocketIOPendingRequests.With((common.Labels{"method": method})).Inc()
@@ -78,6 +78,8 @@ type WebsocketServer struct {
 	addressSubscriptionsLock        sync.Mutex
 	fiatRatesSubscriptions          map[string]map[*websocketChannel]string
 	fiatRatesSubscriptionsLock      sync.Mutex
+	mempoolStatsSubscriptions       map[*websocketChannel]string
+	mempoolStatsSubscriptionsLock   sync.Mutex
 }
 
 // NewWebsocketServer creates new websocket interface to blockbook and returns its handle
@@ -110,6 +112,7 @@ func NewWebsocketServer(db *db.RocksDB, chain bchain.BlockChain, mempool bchain.
 		newTransactionSubscriptions: make(map[*websocketChannel]string),
 		addressSubscriptions:        make(map[string]map[*websocketChannel]string),
 		fiatRatesSubscriptions:      make(map[string]map[*websocketChannel]string),
+		mempoolStatsSubscriptions:   make(map[*websocketChannel]string),
 	}
 	return s, nil
 }
@@ -260,6 +263,7 @@ func (s *WebsocketServer) onDisconnect(c *websocketChannel) {
 	s.unsubscribeNewTransaction(c)
 	s.unsubscribeAddresses(c)
 	s.unsubscribeFiatRates(c)
+	s.unsubscribeMempoolStats(c)
 	glog.Info("Client disconnected ", c.id, ", ", c.ip)
 	s.metrics.WebsocketClients.Dec()
 }
@@ -287,11 +291,12 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 	},
 	"getAccountUtxo": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		r := struct {
-			Descriptor string `json:"descriptor"`
+			Descriptor   string `json:"descriptor"`
+			PendingSpent bool   `json:"pendingSpent"`
 		}{}
 		err = json.Unmarshal(req.Params, &r)
 		if err == nil {
-			rv, err = s.getAccountUtxo(r.Descriptor)
+			rv, err = s.getAccountUtxo(r.Descriptor, r.PendingSpent)
 		}
 		return
 	},
@@ -322,6 +327,29 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 		}
 		return
 	},
+	"getBalanceAt": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r := struct {
+			Descriptor string  `json:"descriptor"`
+			Height     *uint32 `json:"height"`
+			Timestamp  int64   `json:"timestamp"`
+			Gap        int     `json:"gap"`
+		}{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			if (r.Height == nil) == (r.Timestamp <= 0) {
+				return nil, api.NewAPIError("Specify either height or timestamp", true)
+			}
+			var height uint32
+			if r.Height != nil {
+				height = *r.Height
+			}
+			rv, err = s.api.GetXpubBalanceAt(r.Descriptor, height, r.Timestamp, r.Gap)
+			if err != nil {
+				rv, err = s.api.GetBalanceAt(r.Descriptor, height, r.Timestamp)
+			}
+		}
+		return
+	},
 	"getTransaction": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		r := struct {
 			Txid string `json:"txid"`
@@ -390,6 +418,15 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 	"unsubscribeFiatRates": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		return s.unsubscribeFiatRates(c)
 	},
+	"subscribeMempoolStats": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		return s.subscribeMempoolStats(c, req)
+	},
+	"unsubscribeMempoolStats": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		return s.unsubscribeMempoolStats(c)
+	},
+	"getMempoolStats": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		return s.api.GetMempoolStats()
+	},
 	"ping": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
 		r := struct{}{}
 		return r, nil
@@ -425,6 +462,71 @@ var requestHandlers = map[string]func(*WebsocketServer, *websocketChannel, *webs
 		}
 		return
 	},
+	"createWatchGroup": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r := struct {
+			Descriptors []string `json:"descriptors"`
+		}{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			rv, err = s.api.CreateWatchGroup(r.Descriptors, c.ip)
+		}
+		return
+	},
+	"deleteWatchGroup": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r := struct {
+			ID string `json:"id"`
+		}{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			if err = s.api.DeleteWatchGroup(r.ID); err == nil {
+				rv = resultDeleteWatchGroup{Deleted: true}
+			}
+		}
+		return
+	},
+	"getWatchGroupInfo": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r, err := unmarshalGetAccountInfoRequest(req.Params)
+		if err == nil {
+			rv, err = s.getWatchGroupInfo(r)
+		}
+		return
+	},
+	"getWatchGroupUtxo": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r := struct {
+			ID           string `json:"id"`
+			Gap          int    `json:"gap"`
+			PendingSpent bool   `json:"pendingSpent"`
+		}{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			rv, err = s.api.GetWatchGroupUtxo(r.ID, false, r.PendingSpent, r.Gap)
+		}
+		return
+	},
+	"getWatchGroupBalanceHistory": func(s *WebsocketServer, c *websocketChannel, req *websocketReq) (rv interface{}, err error) {
+		r := struct {
+			ID         string   `json:"id"`
+			From       int64    `json:"from"`
+			To         int64    `json:"to"`
+			Currencies []string `json:"currencies"`
+			Gap        int      `json:"gap"`
+			GroupBy    uint32   `json:"groupBy"`
+		}{}
+		err = json.Unmarshal(req.Params, &r)
+		if err == nil {
+			if r.From <= 0 {
+				r.From = 0
+			}
+			if r.To <= 0 {
+				r.To = 0
+			}
+			if r.GroupBy <= 0 {
+				r.GroupBy = 3600
+			}
+			rv, err = s.api.GetWatchGroupBalanceHistory(r.ID, r.From, r.To, r.Currencies, r.Gap, r.GroupBy)
+		}
+		return
+	},
 }
 
 func (s *WebsocketServer) onRequest(c *websocketChannel, req *websocketReq) {
@@ -447,6 +549,8 @@ func (s *WebsocketServer) onRequest(c *websocketChannel, req *websocketReq) {
 		}
 		s.metrics.WebsocketPendingRequests.With((common.Labels{"method": req.Method})).Dec()
 	}()
+	s.db.BeginRead()
+	defer s.db.EndRead()
 	t := time.Now()
 	s.metrics.WebsocketPendingRequests.With((common.Labels{"method": req.Method})).Inc()
 	defer s.metrics.WebsocketReqDuration.With(common.Labels{"method": req.Method}).Observe(float64(time.Since(t)) / 1e3) // in microseconds
@@ -472,6 +576,7 @@ func (s *WebsocketServer) onRequest(c *websocketChannel, req *websocketReq) {
 
 type accountInfoReq struct {
 	Descriptor     string `json:"descriptor"`
+	ID             string `json:"id"`
 	Details        string `json:"details"`
 	Tokens         string `json:"tokens"`
 	PageSize       int    `json:"pageSize"`
@@ -491,7 +596,7 @@ func unmarshalGetAccountInfoRequest(params []byte) (*accountInfoReq, error) {
 	return &r, nil
 }
 
-func (s *WebsocketServer) getAccountInfo(req *accountInfoReq) (res *api.Address, err error) {
+func accountInfoReqOptions(req *accountInfoReq) (api.AccountDetails, *api.AddressFilter) {
 	var opt api.AccountDetails
 	switch req.Details {
 	case "tokens":
@@ -526,23 +631,33 @@ func (s *WebsocketServer) getAccountInfo(req *accountInfoReq) (res *api.Address,
 	if req.PageSize == 0 {
 		req.PageSize = txsOnPage
 	}
-	a, err := s.api.GetXpubAddress(req.Descriptor, req.Page, req.PageSize, opt, &filter, req.Gap)
+	return opt, &filter
+}
+
+func (s *WebsocketServer) getAccountInfo(req *accountInfoReq) (res *api.Address, err error) {
+	opt, filter := accountInfoReqOptions(req)
+	a, err := s.api.GetXpubAddress(req.Descriptor, req.Page, req.PageSize, opt, filter, req.Gap)
 	if err != nil {
-		return s.api.GetAddress(req.Descriptor, req.Page, req.PageSize, opt, &filter)
+		return s.api.GetAddress(req.Descriptor, req.Page, req.PageSize, opt, filter)
 	}
 	return a, nil
 }
 
-func (s *WebsocketServer) getAccountUtxo(descriptor string) (interface{}, error) {
-	utxo, err := s.api.GetXpubUtxo(descriptor, false, 0)
+func (s *WebsocketServer) getWatchGroupInfo(req *accountInfoReq) (res *api.Address, err error) {
+	opt, filter := accountInfoReqOptions(req)
+	return s.api.GetWatchGroupAddress(req.ID, req.Page, req.PageSize, opt, filter, req.Gap)
+}
+
+func (s *WebsocketServer) getAccountUtxo(descriptor string, pendingSpent bool) (interface{}, error) {
+	utxo, err := s.api.GetXpubUtxo(descriptor, false, pendingSpent, 0)
 	if err != nil {
-		return s.api.GetAddressUtxo(descriptor, false)
+		return s.api.GetAddressUtxo(descriptor, false, pendingSpent)
 	}
 	return utxo, nil
 }
 
 func (s *WebsocketServer) getTransaction(txid string) (interface{}, error) {
-	return s.api.GetTransaction(txid, false, false)
+	return s.api.GetTransactionWithMempoolFees(txid, false)
 }
 
 func (s *WebsocketServer) getTransactionSpecific(txid string) (interface{}, error) {
@@ -829,6 +944,25 @@ func (s *WebsocketServer) unsubscribeFiatRates(c *websocketChannel) (res interfa
 	return &subscriptionResponse{false}, nil
 }
 
+func (s *WebsocketServer) subscribeMempoolStats(c *websocketChannel, req *websocketReq) (res interface{}, err error) {
+	if s.chainParser.GetChainType() != bchain.ChainBitcoinType {
+		return &subscriptionResponseMessage{false, "subscribeMempoolStats not supported for this coin"}, nil
+	}
+	s.mempoolStatsSubscriptionsLock.Lock()
+	defer s.mempoolStatsSubscriptionsLock.Unlock()
+	s.mempoolStatsSubscriptions[c] = req.ID
+	s.metrics.WebsocketSubscribes.With((common.Labels{"method": "subscribeMempoolStats"})).Set(float64(len(s.mempoolStatsSubscriptions)))
+	return &subscriptionResponse{true}, nil
+}
+
+func (s *WebsocketServer) unsubscribeMempoolStats(c *websocketChannel) (res interface{}, err error) {
+	s.mempoolStatsSubscriptionsLock.Lock()
+	defer s.mempoolStatsSubscriptionsLock.Unlock()
+	delete(s.mempoolStatsSubscriptions, c)
+	s.metrics.WebsocketSubscribes.With((common.Labels{"method": "subscribeMempoolStats"})).Set(float64(len(s.mempoolStatsSubscriptions)))
+	return &subscriptionResponse{false}, nil
+}
+
 func (s *WebsocketServer) onNewBlockAsync(hash string, height uint32) {
 	s.newBlockSubscriptionsLock.Lock()
 	defer s.newBlockSubscriptionsLock.Unlock()
@@ -941,6 +1075,8 @@ func (s *WebsocketServer) getNewTxSubscriptions(tx *bchain.MempoolTx) map[string
 }
 
 func (s *WebsocketServer) onNewTxAsync(tx *bchain.MempoolTx, subscribed map[string]struct{}) {
+	s.db.BeginRead()
+	defer s.db.EndRead()
 	atx, err := s.api.GetTransactionFromMempoolTx(tx)
 	if err != nil {
 		glog.Error("GetTransactionFromMempoolTx error ", err, " for ", tx.Txid)
@@ -960,6 +1096,160 @@ func (s *WebsocketServer) OnNewTx(tx *bchain.MempoolTx) {
 	}
 }
 
+func (s *WebsocketServer) onReplacedTxAsync(tx *bchain.ReplacedMempoolTx, subscribed []string) {
+	for _, stringAddressDescriptor := range subscribed {
+		addr, _, err := s.chainParser.GetAddressesFromAddrDesc(bchain.AddressDescriptor(stringAddressDescriptor))
+		if err != nil {
+			glog.Error("GetAddressesFromAddrDesc error ", err, " for ", stringAddressDescriptor)
+			continue
+		}
+		if len(addr) != 1 {
+			continue
+		}
+		data := struct {
+			Address    string `json:"address"`
+			ReplacedTx string `json:"replacedTx"`
+			ReplacedBy string `json:"replacedBy"`
+		}{
+			Address:    addr[0],
+			ReplacedTx: tx.Txid,
+			ReplacedBy: tx.ReplacedBy,
+		}
+		s.addressSubscriptionsLock.Lock()
+		as, ok := s.addressSubscriptions[stringAddressDescriptor]
+		if ok {
+			for c, id := range as {
+				c.DataOut(&websocketRes{
+					ID:   id,
+					Data: &data,
+				})
+			}
+			glog.Info("broadcasting replaced tx ", tx.Txid, ", addr ", addr[0], " to ", len(as), " channels")
+		}
+		s.addressSubscriptionsLock.Unlock()
+	}
+}
+
+// OnReplacedTx is a callback that broadcasts info about a replaced mempool tx affecting subscribed address
+func (s *WebsocketServer) OnReplacedTx(tx *bchain.ReplacedMempoolTx) {
+	s.addressSubscriptionsLock.Lock()
+	var subscribed []string
+	for _, addrDesc := range tx.AddrDescs {
+		if as, ok := s.addressSubscriptions[string(addrDesc)]; ok && len(as) > 0 {
+			subscribed = append(subscribed, string(addrDesc))
+		}
+	}
+	s.addressSubscriptionsLock.Unlock()
+	if len(subscribed) > 0 {
+		go s.onReplacedTxAsync(tx, subscribed)
+	}
+}
+
+func (s *WebsocketServer) sendOnReorgBlock(reorg *bchain.ChainReorg) {
+	txids := make([]string, len(reorg.Txs))
+	for i := range reorg.Txs {
+		txids[i] = reorg.Txs[i].Txid
+	}
+	data := struct {
+		Reorg *api.ChainReorg `json:"reorg"`
+	}{
+		Reorg: &api.ChainReorg{
+			ForkHeight:         reorg.ForkHeight,
+			DisconnectedHashes: reorg.DisconnectedHashes,
+			Txids:              txids,
+		},
+	}
+	s.newBlockSubscriptionsLock.Lock()
+	defer s.newBlockSubscriptionsLock.Unlock()
+	for c, id := range s.newBlockSubscriptions {
+		c.DataOut(&websocketRes{
+			ID:   id,
+			Data: &data,
+		})
+	}
+	glog.Info("broadcasting reorg at height ", reorg.ForkHeight, " to ", len(s.newBlockSubscriptions), " channels")
+}
+
+func (s *WebsocketServer) sendOnReorgAddr(reorg *bchain.ChainReorg) {
+	s.addressSubscriptionsLock.Lock()
+	defer s.addressSubscriptionsLock.Unlock()
+	if len(s.addressSubscriptions) == 0 {
+		return
+	}
+	affected := make(map[string][]string)
+	var order []string
+	for i := range reorg.Txs {
+		for _, addrDesc := range reorg.Txs[i].AddrDescs {
+			sad := string(addrDesc)
+			if as, ok := s.addressSubscriptions[sad]; ok && len(as) > 0 {
+				if _, found := affected[sad]; !found {
+					order = append(order, sad)
+				}
+				affected[sad] = append(affected[sad], reorg.Txs[i].Txid)
+			}
+		}
+	}
+	for _, sad := range order {
+		addr, _, err := s.chainParser.GetAddressesFromAddrDesc(bchain.AddressDescriptor(sad))
+		if err != nil {
+			glog.Error("GetAddressesFromAddrDesc error ", err, " for ", sad)
+			continue
+		}
+		if len(addr) != 1 {
+			continue
+		}
+		data := struct {
+			Address string          `json:"address"`
+			Reorg   *api.ChainReorg `json:"reorg"`
+		}{
+			Address: addr[0],
+			Reorg: &api.ChainReorg{
+				ForkHeight: reorg.ForkHeight,
+				Txids:      affected[sad],
+			},
+		}
+		as := s.addressSubscriptions[sad]
+		for c, id := range as {
+			c.DataOut(&websocketRes{
+				ID:   id,
+				Data: &data,
+			})
+		}
+		glog.Info("broadcasting reorg at height ", reorg.ForkHeight, ", addr ", addr[0], " to ", len(as), " channels")
+	}
+}
+
+// OnReorg is a callback that broadcasts info about blocks and transactions disconnected by a chain reorganization
+// to the clients subscribed to new blocks and to the affected addresses
+// the notification is sent synchronously so that it is delivered before the notifications about the new blocks
+func (s *WebsocketServer) OnReorg(reorg *bchain.ChainReorg) {
+	s.sendOnReorgBlock(reorg)
+	s.sendOnReorgAddr(reorg)
+}
+
+func (s *WebsocketServer) onNewMempoolStatsAsync(stats *bchain.MempoolStats) {
+	data := s.api.MempoolStatsFromBchain(stats)
+	s.mempoolStatsSubscriptionsLock.Lock()
+	defer s.mempoolStatsSubscriptionsLock.Unlock()
+	for c, id := range s.mempoolStatsSubscriptions {
+		c.DataOut(&websocketRes{
+			ID:   id,
+			Data: data,
+		})
+	}
+	glog.Info("broadcasting mempool stats to ", len(s.mempoolStatsSubscriptions), " channels")
+}
+
+// OnNewMempoolStats is a callback that broadcasts mempool statistics computed in mempool resync to subscribed clients
+func (s *WebsocketServer) OnNewMempoolStats(stats *bchain.MempoolStats) {
+	s.mempoolStatsSubscriptionsLock.Lock()
+	subscribed := len(s.mempoolStatsSubscriptions)
+	s.mempoolStatsSubscriptionsLock.Unlock()
+	if subscribed > 0 {
+		go s.onNewMempoolStatsAsync(stats)
+	}
+}
+
 func (s *WebsocketServer) broadcastTicker(currency string, rates map[string]float64) {
 	as, ok := s.fiatRatesSubscriptions[currency]
 	if ok && len(as) > 0 {
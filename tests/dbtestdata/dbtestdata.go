@@ -188,6 +188,7 @@ func GetTestBitcoinTypeBlock2(parser bchain.BlockChainParser) *bchain.Block {
 				Blocktime:     1521595678,
 				Time:          1521595678,
 				Confirmations: 1,
+				VSize:         206,
 			},
 			{
 				Txid: TxidB2T2,
@@ -222,6 +223,7 @@ func GetTestBitcoinTypeBlock2(parser bchain.BlockChainParser) *bchain.Block {
 				Blocktime:     1521595678,
 				Time:          1521595678,
 				Confirmations: 1,
+				VSize:         400,
 			},
 			// transaction from the same address in the previous block
 			{
@@ -245,6 +247,7 @@ func GetTestBitcoinTypeBlock2(parser bchain.BlockChainParser) *bchain.Block {
 				Blocktime:     1521595678,
 				Time:          1521595678,
 				Confirmations: 1,
+				VSize:         371,
 			},
 			// mining transaction
 			{
@@ -271,6 +274,7 @@ func GetTestBitcoinTypeBlock2(parser bchain.BlockChainParser) *bchain.Block {
 				Blocktime:     1521595678,
 				Time:          1521595678,
 				Confirmations: 1,
+				VSize:         300,
 			},
 		},
 	}